@@ -0,0 +1,250 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ad
+
+import (
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun"
+	"github.com/lei006/gomath/la"
+)
+
+// DualSs defines a scalar function f(s) of a scalar argument written with dual numbers
+type DualSs func(s Dual) Dual
+
+// DualSv defines a scalar function f(v) of a vector argument written with dual numbers
+type DualSv func(v []Dual) Dual
+
+// DualVv defines a vector function f(v) of a vector argument written with dual numbers
+//
+//	Output:
+//	  f -- output vector (pre-allocated)
+type DualVv func(f, v []Dual)
+
+// HyperSv defines a scalar function f(v) of a vector argument written with hyper-dual numbers
+type HyperSv func(v []HyperDual) HyperDual
+
+// scalar functions ////////////////////////////////////////////////////////////////////////////////
+
+// Deriv computes f(x) and df/dx(x)
+func Deriv(f DualSs, x float64) (fx, dfdx float64) {
+	r := f(Dual{x, 1})
+	return r.V, r.D
+}
+
+// Ss returns the fun.Ss version of f
+func (f DualSs) Ss() fun.Ss {
+	return func(x float64) float64 {
+		return f(Dual{x, 0}).V
+	}
+}
+
+// D1 returns df/dx as a fun.Ss
+func (f DualSs) D1() fun.Ss {
+	return func(x float64) float64 {
+		return f(Dual{x, 1}).D
+	}
+}
+
+// gradients ///////////////////////////////////////////////////////////////////////////////////////
+
+// Gradient computes f(x) and the gradient g = df/dx with n = len(x) forward passes
+//
+//	Output:
+//	  g -- gradient (pre-allocated)
+func Gradient(g []float64, f DualSv, x []float64) (fx float64) {
+	xd := make([]Dual, len(x))
+	for i, xi := range x {
+		xd[i] = Dual{xi, 0}
+	}
+	for i := range x {
+		xd[i].D = 1
+		r := f(xd)
+		fx, g[i] = r.V, r.D
+		xd[i].D = 0
+	}
+	if len(x) == 0 {
+		fx = f(xd).V
+	}
+	return
+}
+
+// Sv returns the fun.Sv version of f
+func (f DualSv) Sv() fun.Sv {
+	return func(x la.Vector) float64 {
+		xd := make([]Dual, len(x))
+		for i, xi := range x {
+			xd[i] = Dual{xi, 0}
+		}
+		return f(xd).V
+	}
+}
+
+// Grad returns the gradient of f as a fun.Vv; e.g. to be used as opt.Problem.Gfcn
+func (f DualSv) Grad() fun.Vv {
+	return func(g, x la.Vector) {
+		Gradient(g, f, x)
+	}
+}
+
+// Jacobians ///////////////////////////////////////////////////////////////////////////////////////
+
+// Jacobian computes f(x) and the (dense) Jacobian J = df/dx with n = len(x) forward passes
+//
+//	Input:
+//	  f -- vector function with m = len(fx) outputs
+//	  x -- input vector
+//	Output:
+//	  fx -- f(x) (pre-allocated) [may be nil]
+//	  J  -- Jacobian matrix (pre-allocated m×n)
+func Jacobian(J *la.Matrix, fx []float64, f DualVv, x []float64) {
+	jacobian(func(i, j int, v float64) { J.Set(i, j, v) }, J.M, fx, f, x)
+}
+
+// JacobianT computes f(x) and the Jacobian J = df/dx in triplet format with n = len(x) forward
+// passes. All m⋅n entries are stored, including zeros, as in num.Jacobian; thus, the number of
+// entries J.Len() does not change with x, as required by the sparse solvers in la
+//
+//	Input:
+//	  m -- number of outputs
+//	  f -- vector function
+//	  x -- input vector
+//	Output:
+//	  fx -- f(x) (pre-allocated) [may be nil]
+//	  J  -- Jacobian triplet; initialised here with max = m⋅n if J.Max() == 0
+func JacobianT(J *la.Triplet, m int, fx []float64, f DualVv, x []float64) {
+	if J.Max() == 0 {
+		J.Init(m, len(x), m*len(x))
+	}
+	J.Start()
+	jacobian(func(i, j int, v float64) { J.Put(i, j, v) }, m, fx, f, x)
+}
+
+// Vv returns the fun.Vv version of f with m outputs
+func (f DualVv) Vv(m int) fun.Vv {
+	return func(fx, x la.Vector) {
+		xd := make([]Dual, len(x))
+		for i, xi := range x {
+			xd[i] = Dual{xi, 0}
+		}
+		fd := make([]Dual, m)
+		f(fd, xd)
+		for i := range fd {
+			fx[i] = fd[i].V
+		}
+	}
+}
+
+// Mv returns the (dense) Jacobian of f with m outputs as a fun.Mv; e.g. to be used as
+// opt.Problem.Hfcn when f is a gradient
+func (f DualVv) Mv(m int) fun.Mv {
+	return func(J *la.Matrix, x la.Vector) {
+		jacobian(func(i, j int, v float64) { J.Set(i, j, v) }, m, nil, f, x)
+	}
+}
+
+// Tv returns the Jacobian of f with m outputs in triplet format as a fun.Tv; e.g. for num.NlSolver
+func (f DualVv) Tv(m int) fun.Tv {
+	return func(J *la.Triplet, x la.Vector) {
+		JacobianT(J, m, nil, f, x)
+	}
+}
+
+// Hessians ////////////////////////////////////////////////////////////////////////////////////////
+
+// Hessian computes f(x), the gradient g = df/dx and the Hessian H = d²f/dxdx with n(n+1)/2
+// passes of hyper-dual numbers
+//
+//	Output:
+//	  g -- gradient (pre-allocated) [may be nil]
+//	  H -- Hessian (pre-allocated n×n)
+func Hessian(H *la.Matrix, g []float64, f HyperSv, x []float64) (fx float64) {
+	n := len(x)
+	xh := make([]HyperDual, n)
+	for i, xi := range x {
+		xh[i] = HyperDual{V: xi}
+	}
+	if n == 0 {
+		return f(xh).V
+	}
+	for i := 0; i < n; i++ {
+		xh[i].D1 = 1
+		for j := i; j < n; j++ {
+			xh[j].D2 = 1
+			r := f(xh)
+			fx = r.V
+			H.Set(i, j, r.D12)
+			H.Set(j, i, r.D12)
+			if i == j && g != nil {
+				g[i] = r.D1
+			}
+			xh[j].D2 = 0
+		}
+		xh[i].D1 = 0
+	}
+	return
+}
+
+// Sv returns the fun.Sv version of f
+func (f HyperSv) Sv() fun.Sv {
+	return func(x la.Vector) float64 {
+		xh := make([]HyperDual, len(x))
+		for i, xi := range x {
+			xh[i] = HyperDual{V: xi}
+		}
+		return f(xh).V
+	}
+}
+
+// Grad returns the gradient of f as a fun.Vv; e.g. to be used as opt.Problem.Gfcn
+func (f HyperSv) Grad() fun.Vv {
+	return func(g, x la.Vector) {
+		xh := make([]HyperDual, len(x))
+		for i, xi := range x {
+			xh[i] = HyperDual{V: xi}
+		}
+		for i := range x {
+			xh[i].D1 = 1
+			g[i] = f(xh).D1
+			xh[i].D1 = 0
+		}
+	}
+}
+
+// Hess returns the Hessian of f as a fun.Mv; e.g. to be used as opt.Problem.Hfcn
+func (f HyperSv) Hess() fun.Mv {
+	return func(H *la.Matrix, x la.Vector) {
+		Hessian(H, nil, f, x)
+	}
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// jacobian runs the forward passes and calls put for each (i, j) entry
+func jacobian(put func(i, j int, v float64), m int, fx []float64, f DualVv, x []float64) {
+	if fx != nil && len(fx) != m {
+		chk.Panic("length of fx must be equal to the number of outputs %d. %d is invalid\n", m, len(fx))
+	}
+	xd := make([]Dual, len(x))
+	for i, xi := range x {
+		xd[i] = Dual{xi, 0}
+	}
+	fd := make([]Dual, m)
+	for j := range x {
+		xd[j].D = 1
+		f(fd, xd)
+		for i := 0; i < m; i++ {
+			put(i, j, fd[i].D)
+		}
+		xd[j].D = 0
+	}
+	if fx != nil {
+		if len(x) == 0 {
+			f(fd, xd)
+		}
+		for i := range fd {
+			fx[i] = fd[i].V
+		}
+	}
+}
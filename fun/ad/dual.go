@@ -0,0 +1,321 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ad implements forward-mode automatic differentiation with dual and hyper-dual numbers
+package ad
+
+import (
+	"math"
+
+	"github.com/lei006/gomath/fun"
+)
+
+// Dual implements a dual number a + b ε with ε² = 0. The real part holds the value of a function
+// and the dual part holds its directional derivative
+//
+//	f(a + b ε) = f(a) + f'(a) b ε
+//
+//	Since Go has no operator overloading, the arithmetic operations and the elementary functions
+//	(mirroring the math and fun packages) are implemented as methods; e.g.
+//
+//	  f(x) = x₀ sin(x₁) + 1   ⇒   x[0].Mul(x[1].Sin()).AddC(1)
+//
+//	References:
+//	  [1] Griewank A, Walther A (2008) Evaluating Derivatives: Principles and Techniques of
+//	      Algorithmic Differentiation. 2nd Edition. SIAM. 438p
+type Dual struct {
+	V float64 // value (real part)
+	D float64 // derivative (dual part)
+}
+
+// NewDual returns a new dual number
+func NewDual(v, d float64) Dual {
+	return Dual{v, d}
+}
+
+// Const returns a dual number representing a constant (zero derivative)
+func Const(v float64) Dual {
+	return Dual{v, 0}
+}
+
+// Var returns a dual number representing an independent variable (unit derivative)
+func Var(v float64) Dual {
+	return Dual{v, 1}
+}
+
+// arithmetic //////////////////////////////////////////////////////////////////////////////////////
+
+// Neg returns -a
+func (a Dual) Neg() Dual {
+	return Dual{-a.V, -a.D}
+}
+
+// Add returns a + b
+func (a Dual) Add(b Dual) Dual {
+	return Dual{a.V + b.V, a.D + b.D}
+}
+
+// Sub returns a - b
+func (a Dual) Sub(b Dual) Dual {
+	return Dual{a.V - b.V, a.D - b.D}
+}
+
+// Mul returns a ⋅ b
+func (a Dual) Mul(b Dual) Dual {
+	return Dual{a.V * b.V, a.D*b.V + a.V*b.D}
+}
+
+// Div returns a / b
+func (a Dual) Div(b Dual) Dual {
+	return Dual{a.V / b.V, (a.D*b.V - a.V*b.D) / (b.V * b.V)}
+}
+
+// AddC returns a + c
+func (a Dual) AddC(c float64) Dual {
+	return Dual{a.V + c, a.D}
+}
+
+// MulC returns c ⋅ a
+func (a Dual) MulC(c float64) Dual {
+	return Dual{c * a.V, c * a.D}
+}
+
+// Inv returns 1 / a
+func (a Dual) Inv() Dual {
+	return a.chain(1.0/a.V, -1.0/(a.V*a.V))
+}
+
+// powers, exponentials and logarithms /////////////////////////////////////////////////////////////
+
+// Pow2 returns a²
+func (a Dual) Pow2() Dual {
+	return a.chain(a.V*a.V, 2.0*a.V)
+}
+
+// Pow3 returns a³
+func (a Dual) Pow3() Dual {
+	return a.chain(a.V*a.V*a.V, 3.0*a.V*a.V)
+}
+
+// PowC returns aᶜ
+func (a Dual) PowC(c float64) Dual {
+	if c == 0 {
+		return Dual{1, 0}
+	}
+	return a.chain(math.Pow(a.V, c), c*math.Pow(a.V, c-1))
+}
+
+// Pow returns aᵇ = exp(b log(a)) for a > 0
+func (a Dual) Pow(b Dual) Dual {
+	v := math.Pow(a.V, b.V)
+	d := b.V * math.Pow(a.V, b.V-1) * a.D
+	if b.D != 0 {
+		d += v * math.Log(a.V) * b.D
+	}
+	return Dual{v, d}
+}
+
+// Sqrt returns √a
+func (a Dual) Sqrt() Dual {
+	s := math.Sqrt(a.V)
+	return a.chain(s, 0.5/s)
+}
+
+// Cbrt returns ∛a
+func (a Dual) Cbrt() Dual {
+	c := math.Cbrt(a.V)
+	return a.chain(c, 1.0/(3.0*c*c))
+}
+
+// Exp returns exp(a)
+func (a Dual) Exp() Dual {
+	e := math.Exp(a.V)
+	return a.chain(e, e)
+}
+
+// Expm1 returns exp(a) - 1
+func (a Dual) Expm1() Dual {
+	return a.chain(math.Expm1(a.V), math.Exp(a.V))
+}
+
+// Log returns the natural logarithm of a
+func (a Dual) Log() Dual {
+	return a.chain(math.Log(a.V), 1.0/a.V)
+}
+
+// Log1p returns log(1 + a)
+func (a Dual) Log1p() Dual {
+	return a.chain(math.Log1p(a.V), 1.0/(1.0+a.V))
+}
+
+// Log2 returns the binary logarithm of a
+func (a Dual) Log2() Dual {
+	return a.chain(math.Log2(a.V), 1.0/(a.V*math.Ln2))
+}
+
+// Log10 returns the decimal logarithm of a
+func (a Dual) Log10() Dual {
+	return a.chain(math.Log10(a.V), 1.0/(a.V*math.Ln10))
+}
+
+// trigonometric and hyperbolic functions //////////////////////////////////////////////////////////
+
+// Sin returns sin(a)
+func (a Dual) Sin() Dual {
+	s, c := math.Sincos(a.V)
+	return a.chain(s, c)
+}
+
+// Cos returns cos(a)
+func (a Dual) Cos() Dual {
+	s, c := math.Sincos(a.V)
+	return a.chain(c, -s)
+}
+
+// Tan returns tan(a)
+func (a Dual) Tan() Dual {
+	t := math.Tan(a.V)
+	return a.chain(t, 1.0+t*t)
+}
+
+// Asin returns asin(a)
+func (a Dual) Asin() Dual {
+	return a.chain(math.Asin(a.V), 1.0/math.Sqrt(1.0-a.V*a.V))
+}
+
+// Acos returns acos(a)
+func (a Dual) Acos() Dual {
+	return a.chain(math.Acos(a.V), -1.0/math.Sqrt(1.0-a.V*a.V))
+}
+
+// Atan returns atan(a)
+func (a Dual) Atan() Dual {
+	return a.chain(math.Atan(a.V), 1.0/(1.0+a.V*a.V))
+}
+
+// Atan2 returns atan2(a, b); i.e. the angle of the point (b, a)
+func (a Dual) Atan2(b Dual) Dual {
+	r2 := a.V*a.V + b.V*b.V
+	return Dual{math.Atan2(a.V, b.V), (b.V*a.D - a.V*b.D) / r2}
+}
+
+// Sinh returns sinh(a)
+func (a Dual) Sinh() Dual {
+	return a.chain(math.Sinh(a.V), math.Cosh(a.V))
+}
+
+// Cosh returns cosh(a)
+func (a Dual) Cosh() Dual {
+	return a.chain(math.Cosh(a.V), math.Sinh(a.V))
+}
+
+// Tanh returns tanh(a)
+func (a Dual) Tanh() Dual {
+	t := math.Tanh(a.V)
+	return a.chain(t, 1.0-t*t)
+}
+
+// Asinh returns asinh(a)
+func (a Dual) Asinh() Dual {
+	return a.chain(math.Asinh(a.V), 1.0/math.Sqrt(a.V*a.V+1.0))
+}
+
+// Acosh returns acosh(a)
+func (a Dual) Acosh() Dual {
+	return a.chain(math.Acosh(a.V), 1.0/math.Sqrt(a.V*a.V-1.0))
+}
+
+// Atanh returns atanh(a)
+func (a Dual) Atanh() Dual {
+	return a.chain(math.Atanh(a.V), 1.0/(1.0-a.V*a.V))
+}
+
+// other functions /////////////////////////////////////////////////////////////////////////////////
+
+// Abs returns |a|. The derivative at a = 0 is taken as zero
+func (a Dual) Abs() Dual {
+	return a.chain(math.Abs(a.V), fun.Sign(a.V))
+}
+
+// Hypot returns √(a² + b²)
+func (a Dual) Hypot(b Dual) Dual {
+	h := math.Hypot(a.V, b.V)
+	if h == 0 {
+		return Dual{0, 0}
+	}
+	return Dual{h, (a.V*a.D + b.V*b.D) / h}
+}
+
+// Erf returns the error function erf(a)
+func (a Dual) Erf() Dual {
+	return a.chain(math.Erf(a.V), 2.0/math.SqrtPi*math.Exp(-a.V*a.V))
+}
+
+// Erfc returns the complementary error function erfc(a)
+func (a Dual) Erfc() Dual {
+	return a.chain(math.Erfc(a.V), -2.0/math.SqrtPi*math.Exp(-a.V*a.V))
+}
+
+// Max returns the maximum of a and b (with the derivative of the selected argument)
+func (a Dual) Max(b Dual) Dual {
+	if b.V > a.V {
+		return b
+	}
+	return a
+}
+
+// Min returns the minimum of a and b (with the derivative of the selected argument)
+func (a Dual) Min(b Dual) Dual {
+	if b.V < a.V {
+		return b
+	}
+	return a
+}
+
+// Sign returns fun.Sign(a) (with zero derivative)
+func (a Dual) Sign() Dual {
+	return Dual{fun.Sign(a.V), 0}
+}
+
+// Heav returns fun.Heav(a) (with zero derivative)
+func (a Dual) Heav() Dual {
+	return Dual{fun.Heav(a.V), 0}
+}
+
+// Ramp returns fun.Ramp(a) with derivative fun.Heav(a)
+func (a Dual) Ramp() Dual {
+	return a.chain(fun.Ramp(a.V), fun.Heav(a.V))
+}
+
+// Sramp returns the smooth ramp fun.Sramp(a, β)
+func (a Dual) Sramp(β float64) Dual {
+	return a.chain(fun.Sramp(a.V, β), fun.SrampD1(a.V, β))
+}
+
+// Logistic returns the logistic function fun.Logistic(a)
+func (a Dual) Logistic() Dual {
+	return a.chain(fun.Logistic(a.V), fun.LogisticD1(a.V))
+}
+
+// Sabs returns the smooth absolute value fun.Sabs(a, eps)
+func (a Dual) Sabs(eps float64) Dual {
+	return a.chain(fun.Sabs(a.V, eps), fun.SabsD1(a.V, eps))
+}
+
+// Sinc returns fun.Sinc(a) = sin(a)/a
+func (a Dual) Sinc() Dual {
+	if math.Abs(a.V) < 1e-4 {
+		x2 := a.V * a.V
+		return a.chain(1.0-x2/6.0+x2*x2/120.0, a.V*(-1.0/3.0+x2/30.0))
+	}
+	s, c := math.Sincos(a.V)
+	return a.chain(s/a.V, (c*a.V-s)/(a.V*a.V))
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// chain applies the chain rule with the value f(a) and the derivative f'(a)
+func (a Dual) chain(f, df float64) Dual {
+	return Dual{f, df * a.D}
+}
@@ -0,0 +1,358 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ad
+
+import (
+	"math"
+
+	"github.com/lei006/gomath/fun"
+)
+
+// HyperDual implements a hyper-dual number a + b ε₁ + c ε₂ + d ε₁ε₂ with ε₁² = ε₂² = 0 and
+// ε₁ε₂ ≠ 0. With x = x₀ + eᵢ ε₁ + eⱼ ε₂, exact first and second derivatives are obtained
+//
+//	f(x) = f(x₀) + ∂f/∂xᵢ ε₁ + ∂f/∂xⱼ ε₂ + ∂²f/∂xᵢ∂xⱼ ε₁ε₂
+//
+//	The methods are the same as the ones of Dual. For a scalar function with value f, first
+//	derivative f' and second derivative f'', the rule is:
+//
+//	  f(a) = f(a.V) + f' a.D1 ε₁ + f' a.D2 ε₂ + (f' a.D12 + f'' a.D1 a.D2) ε₁ε₂
+//
+//	References:
+//	  [1] Fike JA, Alonso JJ (2011) The development of hyper-dual numbers for exact second-derivative
+//	      calculations, 49th AIAA Aerospace Sciences Meeting, AIAA 2011-886
+type HyperDual struct {
+	V   float64 // value
+	D1  float64 // ε₁ part (first derivative along direction 1)
+	D2  float64 // ε₂ part (first derivative along direction 2)
+	D12 float64 // ε₁ε₂ part (second derivative)
+}
+
+// NewHyperDual returns a new hyper-dual number
+func NewHyperDual(v, d1, d2, d12 float64) HyperDual {
+	return HyperDual{v, d1, d2, d12}
+}
+
+// ConstH returns a hyper-dual number representing a constant
+func ConstH(v float64) HyperDual {
+	return HyperDual{V: v}
+}
+
+// arithmetic //////////////////////////////////////////////////////////////////////////////////////
+
+// Neg returns -a
+func (a HyperDual) Neg() HyperDual {
+	return HyperDual{-a.V, -a.D1, -a.D2, -a.D12}
+}
+
+// Add returns a + b
+func (a HyperDual) Add(b HyperDual) HyperDual {
+	return HyperDual{a.V + b.V, a.D1 + b.D1, a.D2 + b.D2, a.D12 + b.D12}
+}
+
+// Sub returns a - b
+func (a HyperDual) Sub(b HyperDual) HyperDual {
+	return HyperDual{a.V - b.V, a.D1 - b.D1, a.D2 - b.D2, a.D12 - b.D12}
+}
+
+// Mul returns a ⋅ b
+func (a HyperDual) Mul(b HyperDual) HyperDual {
+	return HyperDual{
+		a.V * b.V,
+		a.D1*b.V + a.V*b.D1,
+		a.D2*b.V + a.V*b.D2,
+		a.D12*b.V + a.D1*b.D2 + a.D2*b.D1 + a.V*b.D12,
+	}
+}
+
+// Div returns a / b
+func (a HyperDual) Div(b HyperDual) HyperDual {
+	return a.Mul(b.Inv())
+}
+
+// AddC returns a + c
+func (a HyperDual) AddC(c float64) HyperDual {
+	return HyperDual{a.V + c, a.D1, a.D2, a.D12}
+}
+
+// MulC returns c ⋅ a
+func (a HyperDual) MulC(c float64) HyperDual {
+	return HyperDual{c * a.V, c * a.D1, c * a.D2, c * a.D12}
+}
+
+// Inv returns 1 / a
+func (a HyperDual) Inv() HyperDual {
+	i := 1.0 / a.V
+	return a.chain(i, -i*i, 2.0*i*i*i)
+}
+
+// powers, exponentials and logarithms /////////////////////////////////////////////////////////////
+
+// Pow2 returns a²
+func (a HyperDual) Pow2() HyperDual {
+	return a.chain(a.V*a.V, 2.0*a.V, 2.0)
+}
+
+// Pow3 returns a³
+func (a HyperDual) Pow3() HyperDual {
+	return a.chain(a.V*a.V*a.V, 3.0*a.V*a.V, 6.0*a.V)
+}
+
+// PowC returns aᶜ
+func (a HyperDual) PowC(c float64) HyperDual {
+	if c == 0 {
+		return HyperDual{V: 1}
+	}
+	if c == 1 {
+		return a
+	}
+	return a.chain(math.Pow(a.V, c), c*math.Pow(a.V, c-1), c*(c-1)*math.Pow(a.V, c-2))
+}
+
+// Pow returns aᵇ = exp(b log(a)) for a > 0
+func (a HyperDual) Pow(b HyperDual) HyperDual {
+	if b.D1 == 0 && b.D2 == 0 && b.D12 == 0 {
+		return a.PowC(b.V)
+	}
+	return b.Mul(a.Log()).Exp()
+}
+
+// Sqrt returns √a
+func (a HyperDual) Sqrt() HyperDual {
+	s := math.Sqrt(a.V)
+	return a.chain(s, 0.5/s, -0.25/(s*a.V))
+}
+
+// Cbrt returns ∛a
+func (a HyperDual) Cbrt() HyperDual {
+	c := math.Cbrt(a.V)
+	d := 1.0 / (3.0 * c * c)
+	return a.chain(c, d, -2.0*d/(3.0*a.V))
+}
+
+// Exp returns exp(a)
+func (a HyperDual) Exp() HyperDual {
+	e := math.Exp(a.V)
+	return a.chain(e, e, e)
+}
+
+// Expm1 returns exp(a) - 1
+func (a HyperDual) Expm1() HyperDual {
+	e := math.Exp(a.V)
+	return a.chain(math.Expm1(a.V), e, e)
+}
+
+// Log returns the natural logarithm of a
+func (a HyperDual) Log() HyperDual {
+	return a.chain(math.Log(a.V), 1.0/a.V, -1.0/(a.V*a.V))
+}
+
+// Log1p returns log(1 + a)
+func (a HyperDual) Log1p() HyperDual {
+	b := 1.0 + a.V
+	return a.chain(math.Log1p(a.V), 1.0/b, -1.0/(b*b))
+}
+
+// Log2 returns the binary logarithm of a
+func (a HyperDual) Log2() HyperDual {
+	return a.chain(math.Log2(a.V), 1.0/(a.V*math.Ln2), -1.0/(a.V*a.V*math.Ln2))
+}
+
+// Log10 returns the decimal logarithm of a
+func (a HyperDual) Log10() HyperDual {
+	return a.chain(math.Log10(a.V), 1.0/(a.V*math.Ln10), -1.0/(a.V*a.V*math.Ln10))
+}
+
+// trigonometric and hyperbolic functions //////////////////////////////////////////////////////////
+
+// Sin returns sin(a)
+func (a HyperDual) Sin() HyperDual {
+	s, c := math.Sincos(a.V)
+	return a.chain(s, c, -s)
+}
+
+// Cos returns cos(a)
+func (a HyperDual) Cos() HyperDual {
+	s, c := math.Sincos(a.V)
+	return a.chain(c, -s, -c)
+}
+
+// Tan returns tan(a)
+func (a HyperDual) Tan() HyperDual {
+	t := math.Tan(a.V)
+	d := 1.0 + t*t
+	return a.chain(t, d, 2.0*t*d)
+}
+
+// Asin returns asin(a)
+func (a HyperDual) Asin() HyperDual {
+	q := 1.0 - a.V*a.V
+	return a.chain(math.Asin(a.V), 1.0/math.Sqrt(q), a.V/(q*math.Sqrt(q)))
+}
+
+// Acos returns acos(a)
+func (a HyperDual) Acos() HyperDual {
+	q := 1.0 - a.V*a.V
+	return a.chain(math.Acos(a.V), -1.0/math.Sqrt(q), -a.V/(q*math.Sqrt(q)))
+}
+
+// Atan returns atan(a)
+func (a HyperDual) Atan() HyperDual {
+	q := 1.0 + a.V*a.V
+	return a.chain(math.Atan(a.V), 1.0/q, -2.0*a.V/(q*q))
+}
+
+// Atan2 returns atan2(a, b); i.e. the angle of the point (b, a)
+func (a HyperDual) Atan2(b HyperDual) HyperDual {
+	r2 := a.V*a.V + b.V*b.V
+	r4 := r2 * r2
+	fa, fb := b.V/r2, -a.V/r2
+	faa := -2.0 * a.V * b.V / r4
+	fab := (a.V*a.V - b.V*b.V) / r4
+	return chain2(math.Atan2(a.V, b.V), a, b, fa, fb, faa, fab, -faa)
+}
+
+// Sinh returns sinh(a)
+func (a HyperDual) Sinh() HyperDual {
+	s, c := math.Sinh(a.V), math.Cosh(a.V)
+	return a.chain(s, c, s)
+}
+
+// Cosh returns cosh(a)
+func (a HyperDual) Cosh() HyperDual {
+	s, c := math.Sinh(a.V), math.Cosh(a.V)
+	return a.chain(c, s, c)
+}
+
+// Tanh returns tanh(a)
+func (a HyperDual) Tanh() HyperDual {
+	t := math.Tanh(a.V)
+	d := 1.0 - t*t
+	return a.chain(t, d, -2.0*t*d)
+}
+
+// Asinh returns asinh(a)
+func (a HyperDual) Asinh() HyperDual {
+	q := a.V*a.V + 1.0
+	return a.chain(math.Asinh(a.V), 1.0/math.Sqrt(q), -a.V/(q*math.Sqrt(q)))
+}
+
+// Acosh returns acosh(a)
+func (a HyperDual) Acosh() HyperDual {
+	q := a.V*a.V - 1.0
+	return a.chain(math.Acosh(a.V), 1.0/math.Sqrt(q), -a.V/(q*math.Sqrt(q)))
+}
+
+// Atanh returns atanh(a)
+func (a HyperDual) Atanh() HyperDual {
+	q := 1.0 - a.V*a.V
+	return a.chain(math.Atanh(a.V), 1.0/q, 2.0*a.V/(q*q))
+}
+
+// other functions /////////////////////////////////////////////////////////////////////////////////
+
+// Abs returns |a|. The derivatives at a = 0 are taken as zero
+func (a HyperDual) Abs() HyperDual {
+	return a.chain(math.Abs(a.V), fun.Sign(a.V), 0)
+}
+
+// Hypot returns √(a² + b²)
+func (a HyperDual) Hypot(b HyperDual) HyperDual {
+	h := math.Hypot(a.V, b.V)
+	if h == 0 {
+		return HyperDual{}
+	}
+	h3 := h * h * h
+	return chain2(h, a, b, a.V/h, b.V/h, b.V*b.V/h3, -a.V*b.V/h3, a.V*a.V/h3)
+}
+
+// Erf returns the error function erf(a)
+func (a HyperDual) Erf() HyperDual {
+	d := 2.0 / math.SqrtPi * math.Exp(-a.V*a.V)
+	return a.chain(math.Erf(a.V), d, -2.0*a.V*d)
+}
+
+// Erfc returns the complementary error function erfc(a)
+func (a HyperDual) Erfc() HyperDual {
+	d := -2.0 / math.SqrtPi * math.Exp(-a.V*a.V)
+	return a.chain(math.Erfc(a.V), d, -2.0*a.V*d)
+}
+
+// Max returns the maximum of a and b (with the derivatives of the selected argument)
+func (a HyperDual) Max(b HyperDual) HyperDual {
+	if b.V > a.V {
+		return b
+	}
+	return a
+}
+
+// Min returns the minimum of a and b (with the derivatives of the selected argument)
+func (a HyperDual) Min(b HyperDual) HyperDual {
+	if b.V < a.V {
+		return b
+	}
+	return a
+}
+
+// Sign returns fun.Sign(a) (with zero derivatives)
+func (a HyperDual) Sign() HyperDual {
+	return HyperDual{V: fun.Sign(a.V)}
+}
+
+// Heav returns fun.Heav(a) (with zero derivatives)
+func (a HyperDual) Heav() HyperDual {
+	return HyperDual{V: fun.Heav(a.V)}
+}
+
+// Ramp returns fun.Ramp(a) with first derivative fun.Heav(a)
+func (a HyperDual) Ramp() HyperDual {
+	return a.chain(fun.Ramp(a.V), fun.Heav(a.V), 0)
+}
+
+// Sramp returns the smooth ramp fun.Sramp(a, β)
+func (a HyperDual) Sramp(β float64) HyperDual {
+	return a.chain(fun.Sramp(a.V, β), fun.SrampD1(a.V, β), fun.SrampD2(a.V, β))
+}
+
+// Logistic returns the logistic function fun.Logistic(a)
+func (a HyperDual) Logistic() HyperDual {
+	g := fun.Logistic(a.V)
+	d := g * (1.0 - g)
+	return a.chain(g, d, d*(1.0-2.0*g))
+}
+
+// Sabs returns the smooth absolute value fun.Sabs(a, eps)
+func (a HyperDual) Sabs(eps float64) HyperDual {
+	return a.chain(fun.Sabs(a.V, eps), fun.SabsD1(a.V, eps), fun.SabsD2(a.V, eps))
+}
+
+// Sinc returns fun.Sinc(a) = sin(a)/a
+func (a HyperDual) Sinc() HyperDual {
+	x := a.V
+	if math.Abs(x) < 1e-4 {
+		x2 := x * x
+		return a.chain(1.0-x2/6.0+x2*x2/120.0, x*(-1.0/3.0+x2/30.0), -1.0/3.0+x2/10.0)
+	}
+	s, c := math.Sincos(x)
+	return a.chain(s/x, (c*x-s)/(x*x), ((2.0-x*x)*s-2.0*x*c)/(x*x*x))
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// chain applies the chain rule with the value f(a) and the first and second derivatives of f at a
+func (a HyperDual) chain(f, df, d2f float64) HyperDual {
+	return HyperDual{f, df * a.D1, df * a.D2, df*a.D12 + d2f*a.D1*a.D2}
+}
+
+// chain2 applies the chain rule to a function f(a,b) of two arguments with the first (fa, fb) and
+// second (faa, fab, fbb) partial derivatives
+func chain2(f float64, a, b HyperDual, fa, fb, faa, fab, fbb float64) HyperDual {
+	return HyperDual{
+		f,
+		fa*a.D1 + fb*b.D1,
+		fa*a.D2 + fb*b.D2,
+		fa*a.D12 + fb*b.D12 + faa*a.D1*a.D2 + fab*(a.D1*b.D2+a.D2*b.D1) + fbb*b.D1*b.D2,
+	}
+}
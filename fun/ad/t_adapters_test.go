@@ -0,0 +1,137 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/la"
+)
+
+func TestAdapters01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Adapters01. scalar functions and gradients")
+
+	// f(x) = x sin(x)
+	f := DualSs(func(x Dual) Dual { return x.Mul(x.Sin()) })
+	chk.Float64(tst, "Ss", 1e-15, f.Ss()(0.4), 0.4*math.Sin(0.4))
+	chk.Float64(tst, "D1", 1e-15, f.D1()(0.4), math.Sin(0.4)+0.4*math.Cos(0.4))
+
+	// Rosenbrock function: f(x) = (1 - x₀)² + 100 (x₁ - x₀²)²
+	rosen := DualSv(func(x []Dual) Dual {
+		return x[0].Neg().AddC(1).Pow2().Add(x[1].Sub(x[0].Pow2()).Pow2().MulC(100))
+	})
+	x := la.Vector{-1.2, 1}
+	g := la.NewVector(2)
+	fx := Gradient(g, rosen, x)
+	chk.Float64(tst, "f(x)", 1e-14, fx, 24.2)
+	chk.Array(tst, "g(x)", 1e-13, g, []float64{-215.6, -88})
+	chk.Float64(tst, "Sv", 1e-14, rosen.Sv()(x), 24.2)
+	gfcn := rosen.Grad()
+	gfcn(g, la.Vector{1, 1})
+	chk.Array(tst, "g(1,1)", 1e-15, g, []float64{0, 0})
+}
+
+func TestAdapters02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Adapters02. Jacobians")
+
+	// f₀ = x₀² x₁ ;  f₁ = 5 x₀ + sin(x₁) ;  f₂ = exp(x₂)
+	f := DualVv(func(f, x []Dual) {
+		f[0] = x[0].Pow2().Mul(x[1])
+		f[1] = x[0].MulC(5).Add(x[1].Sin())
+		f[2] = x[2].Exp()
+	})
+	x := []float64{1.5, 0.3, -1}
+	fana := []float64{1.5 * 1.5 * 0.3, 7.5 + math.Sin(0.3), math.Exp(-1)}
+	Jana := [][]float64{
+		{2 * 1.5 * 0.3, 1.5 * 1.5, 0},
+		{5, math.Cos(0.3), 0},
+		{0, 0, math.Exp(-1)},
+	}
+
+	// dense
+	J := la.NewMatrix(3, 3)
+	fx := make([]float64, 3)
+	Jacobian(J, fx, f, x)
+	chk.Array(tst, "f(x)", 1e-15, fx, fana)
+	chk.Deep2(tst, "J(x)", 1e-15, J.GetDeep2(), Jana)
+
+	// triplet: zeros are stored too
+	T := new(la.Triplet)
+	JacobianT(T, 3, nil, f, x)
+	chk.Int(tst, "len(T)", T.Len(), 9)
+	chk.Deep2(tst, "T(x)", 1e-15, T.ToDense().GetDeep2(), Jana)
+	tfcn := f.Tv(3)
+	tfcn(T, la.Vector{0, 0, 0})
+	chk.Int(tst, "len(T(0))", T.Len(), 9)
+	chk.Deep2(tst, "T(0)", 1e-15, T.ToDense().GetDeep2(), [][]float64{{0, 0, 0}, {5, 1, 0}, {0, 0, 1}})
+
+	// all entries are zero at x = 0: the triplet can still be used by a sparse solver
+	sq := DualVv(func(f, x []Dual) {
+		f[0] = x[0].Pow2()
+		f[1] = x[0].Mul(x[1])
+	})
+	T0 := new(la.Triplet)
+	sqfcn := sq.Tv(2)
+	sqfcn(T0, la.Vector{0, 0})
+	chk.Int(tst, "len(T0(0))", T0.Len(), 4)
+	chk.Deep2(tst, "T0(0)", 1e-15, T0.ToDense().GetDeep2(), [][]float64{{0, 0}, {0, 0}})
+	sqfcn(T0, la.Vector{1, 2})
+	chk.Int(tst, "len(T0(x))", T0.Len(), 4)
+	chk.Deep2(tst, "T0(x)", 1e-15, T0.ToDense().GetDeep2(), [][]float64{{2, 0}, {2, 1}})
+	sol := la.NewSparseSolver("umfpack")
+	defer sol.Free()
+	sol.Init(T0, nil)
+	sol.Fact()
+	b := la.NewVector(2)
+	sol.Solve(b, la.Vector{4, 5})
+	chk.Array(tst, "T0(x)⁻¹⋅r", 1e-15, b, []float64{2, 1})
+
+	// closures
+	v := la.NewVector(3)
+	f.Vv(3)(v, x)
+	chk.Array(tst, "Vv", 1e-15, v, fana)
+	f.Mv(3)(J, x)
+	chk.Deep2(tst, "Mv", 1e-15, J.GetDeep2(), Jana)
+}
+
+func TestAdapters03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Adapters03. Hessians")
+
+	// f(x) = x₀² x₁³ + exp(x₀ x₂) + log(x₁)
+	f := HyperSv(func(x []HyperDual) HyperDual {
+		return x[0].Pow2().Mul(x[1].Pow3()).Add(x[0].Mul(x[2]).Exp()).Add(x[1].Log())
+	})
+	x := []float64{0.5, 2, -1}
+	e := math.Exp(-0.5)
+	fana := 0.25*8 + e + math.Log(2)
+	gana := []float64{2*0.5*8 - e, 3*0.25*4 + 0.5, 0.5 * e}
+	Hana := [][]float64{
+		{2*8 + e, 6 * 0.5 * 4, (1 - 0.5) * e},
+		{6 * 0.5 * 4, 6*0.25*2 - 0.25, 0},
+		{(1 - 0.5) * e, 0, 0.25 * e},
+	}
+	H := la.NewMatrix(3, 3)
+	g := make([]float64, 3)
+	fx := Hessian(H, g, f, x)
+	chk.Float64(tst, "f(x)", 1e-15, fx, fana)
+	chk.Array(tst, "g(x)", 1e-15, g, gana)
+	chk.Deep2(tst, "H(x)", 1e-14, H.GetDeep2(), Hana)
+
+	// closures
+	chk.Float64(tst, "Sv", 1e-15, f.Sv()(x), fana)
+	f.Grad()(g, x)
+	chk.Array(tst, "Grad", 1e-15, g, gana)
+	H.Fill(0)
+	f.Hess()(H, x)
+	chk.Deep2(tst, "Hess", 1e-14, H.GetDeep2(), Hana)
+}
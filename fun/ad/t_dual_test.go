@@ -0,0 +1,138 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun"
+)
+
+// elementary functions written for both Dual and HyperDual
+var testFcns = []struct {
+	name string
+	x    []float64
+	f    func(x float64) float64
+	fd   func(a Dual) Dual
+	fh   func(a HyperDual) HyperDual
+}{
+	{"inv", []float64{-2, 0.5, 3}, func(x float64) float64 { return 1 / x }, Dual.Inv, HyperDual.Inv},
+	{"pow2", []float64{-2, 0.5, 3}, func(x float64) float64 { return x * x }, Dual.Pow2, HyperDual.Pow2},
+	{"pow3", []float64{-2, 0.5, 3}, func(x float64) float64 { return x * x * x }, Dual.Pow3, HyperDual.Pow3},
+	{"powc", []float64{0.5, 3}, func(x float64) float64 { return math.Pow(x, 2.5) },
+		func(a Dual) Dual { return a.PowC(2.5) }, func(a HyperDual) HyperDual { return a.PowC(2.5) }},
+	{"sqrt", []float64{0.5, 3}, math.Sqrt, Dual.Sqrt, HyperDual.Sqrt},
+	{"cbrt", []float64{-2, 0.5, 3}, math.Cbrt, Dual.Cbrt, HyperDual.Cbrt},
+	{"exp", []float64{-2, 0.5, 3}, math.Exp, Dual.Exp, HyperDual.Exp},
+	{"expm1", []float64{-2, 0.5, 3}, math.Expm1, Dual.Expm1, HyperDual.Expm1},
+	{"log", []float64{0.5, 3}, math.Log, Dual.Log, HyperDual.Log},
+	{"log1p", []float64{-0.5, 3}, math.Log1p, Dual.Log1p, HyperDual.Log1p},
+	{"log2", []float64{0.5, 3}, math.Log2, Dual.Log2, HyperDual.Log2},
+	{"log10", []float64{0.5, 3}, math.Log10, Dual.Log10, HyperDual.Log10},
+	{"sin", []float64{-2, 0.5, 3}, math.Sin, Dual.Sin, HyperDual.Sin},
+	{"cos", []float64{-2, 0.5, 3}, math.Cos, Dual.Cos, HyperDual.Cos},
+	{"tan", []float64{-1, 0.5, 1.2}, math.Tan, Dual.Tan, HyperDual.Tan},
+	{"asin", []float64{-0.7, 0.5}, math.Asin, Dual.Asin, HyperDual.Asin},
+	{"acos", []float64{-0.7, 0.5}, math.Acos, Dual.Acos, HyperDual.Acos},
+	{"atan", []float64{-2, 0.5, 3}, math.Atan, Dual.Atan, HyperDual.Atan},
+	{"sinh", []float64{-2, 0.5, 3}, math.Sinh, Dual.Sinh, HyperDual.Sinh},
+	{"cosh", []float64{-2, 0.5, 3}, math.Cosh, Dual.Cosh, HyperDual.Cosh},
+	{"tanh", []float64{-2, 0.5, 3}, math.Tanh, Dual.Tanh, HyperDual.Tanh},
+	{"asinh", []float64{-2, 0.5, 3}, math.Asinh, Dual.Asinh, HyperDual.Asinh},
+	{"acosh", []float64{1.5, 3}, math.Acosh, Dual.Acosh, HyperDual.Acosh},
+	{"atanh", []float64{-0.7, 0.5}, math.Atanh, Dual.Atanh, HyperDual.Atanh},
+	{"abs", []float64{-2, 0.5, 3}, math.Abs, Dual.Abs, HyperDual.Abs},
+	{"erf", []float64{-2, 0.5, 3}, math.Erf, Dual.Erf, HyperDual.Erf},
+	{"erfc", []float64{-2, 0.5, 3}, math.Erfc, Dual.Erfc, HyperDual.Erfc},
+	{"ramp", []float64{-2, 0.5, 3}, fun.Ramp, Dual.Ramp, HyperDual.Ramp},
+	{"sramp", []float64{-2, 0.5, 3}, func(x float64) float64 { return fun.Sramp(x, 2) },
+		func(a Dual) Dual { return a.Sramp(2) }, func(a HyperDual) HyperDual { return a.Sramp(2) }},
+	{"logistic", []float64{-2, 0.5, 3}, fun.Logistic, Dual.Logistic, HyperDual.Logistic},
+	{"sabs", []float64{-2, 0.05, 3}, func(x float64) float64 { return fun.Sabs(x, 0.1) },
+		func(a Dual) Dual { return a.Sabs(0.1) }, func(a HyperDual) HyperDual { return a.Sabs(0.1) }},
+	{"sinc", []float64{-2, 1e-5, 0.5, 3}, fun.Sinc, Dual.Sinc, HyperDual.Sinc},
+}
+
+func TestDual01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Dual01. arithmetic")
+
+	a, b := Var(2), Const(3)
+	chk.Float64(tst, "(a+b).V ", 1e-15, a.Add(b).V, 5)
+	chk.Float64(tst, "(a+b).D ", 1e-15, a.Add(b).D, 1)
+	chk.Float64(tst, "(a-b).D ", 1e-15, a.Sub(b).D, 1)
+	chk.Float64(tst, "(b-a).D ", 1e-15, b.Sub(a).D, -1)
+	chk.Float64(tst, "(a*a).D ", 1e-15, a.Mul(a).D, 4)
+	chk.Float64(tst, "(b/a).D ", 1e-15, b.Div(a).D, -0.75)
+	chk.Float64(tst, "(-a).D  ", 1e-15, a.Neg().D, -1)
+	chk.Float64(tst, "(3a+1).V", 1e-15, a.MulC(3).AddC(1).V, 7)
+	chk.Float64(tst, "(3a+1).D", 1e-15, a.MulC(3).AddC(1).D, 3)
+
+	// f(x) = xˣ  ⇒  f'(x) = xˣ (log(x) + 1)
+	fx, dfdx := Deriv(func(x Dual) Dual { return x.Pow(x) }, 1.5)
+	chk.Float64(tst, "xˣ     ", 1e-15, fx, math.Pow(1.5, 1.5))
+	chk.Float64(tst, "d(xˣ)dx", 1e-14, dfdx, math.Pow(1.5, 1.5)*(math.Log(1.5)+1))
+
+	// bivariate functions
+	x, y := NewDual(0.3, 2), NewDual(-0.8, -1)
+	r := x.Atan2(y)
+	chk.Float64(tst, "atan2.V", 1e-15, r.V, math.Atan2(0.3, -0.8))
+	chk.Float64(tst, "atan2.D", 1e-15, r.D, (-0.8*2-0.3*(-1))/(0.09+0.64))
+	r = x.Hypot(y)
+	chk.Float64(tst, "hypot.D", 1e-15, r.D, (0.3*2+0.8)/math.Hypot(0.3, 0.8))
+	chk.Float64(tst, "max.D", 1e-15, x.Max(y).D, 2)
+	chk.Float64(tst, "min.D", 1e-15, x.Min(y).D, -1)
+}
+
+func TestDual02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Dual02. elementary functions")
+
+	for _, c := range testFcns {
+		for _, x := range c.x {
+			r := c.fd(Var(x))
+			chk.Float64(tst, c.name+": value", 1e-15, r.V, c.f(x))
+			chk.DerivScaSca(tst, c.name, 1e-8, r.D, x, 1e-3, chk.Verbose, c.f)
+		}
+	}
+}
+
+func TestHyperDual01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("HyperDual01. elementary functions")
+
+	for _, c := range testFcns {
+		for _, x := range c.x {
+			r := c.fh(NewHyperDual(x, 1, 1, 0))
+			chk.Float64(tst, c.name+": value", 1e-15, r.V, c.f(x))
+			chk.Float64(tst, c.name+": D1 == D2", 1e-15, r.D1, r.D2)
+			chk.Float64(tst, c.name+": D1 == dual", 1e-15, r.D1, c.fd(Var(x)).D)
+			chk.DerivScaSca(tst, c.name+"''", 1e-7, r.D12, x, 1e-3, chk.Verbose, func(s float64) float64 {
+				return c.fd(Var(s)).D
+			})
+		}
+	}
+
+	// bivariate: f(x,y) = atan2(x,y) + hypot(x,y) + xʸ
+	f := func(x, y HyperDual) HyperDual { return x.Atan2(y).Add(x.Hypot(y)).Add(x.Pow(y)) }
+	fxy := func(x, y float64) float64 { return math.Atan2(x, y) + math.Hypot(x, y) + math.Pow(x, y) }
+	x0, y0 := 0.7, 1.3
+	dfdx := func(y float64) float64 {
+		return f(NewHyperDual(x0, 1, 0, 0), NewHyperDual(y, 0, 0, 0)).D1
+	}
+	dfdy := func(y float64) float64 {
+		return f(NewHyperDual(x0, 0, 0, 0), NewHyperDual(y, 1, 0, 0)).D1
+	}
+	chk.DerivScaSca(tst, "df/dy", 1e-8, dfdy(y0), y0, 1e-3, chk.Verbose, func(y float64) float64 { return fxy(x0, y) })
+	rxy := f(NewHyperDual(x0, 1, 0, 0), NewHyperDual(y0, 0, 1, 0))
+	ryy := f(NewHyperDual(x0, 0, 0, 0), NewHyperDual(y0, 1, 1, 0))
+	chk.DerivScaSca(tst, "d²f/dxdy", 1e-8, rxy.D12, y0, 1e-3, chk.Verbose, dfdx)
+	chk.DerivScaSca(tst, "d²f/dy² ", 1e-8, ryy.D12, y0, 1e-3, chk.Verbose, dfdy)
+}
@@ -0,0 +1,19 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ad
+
+import (
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+func init() {
+	io.Verbose = false
+}
+
+func verbose() {
+	io.Verbose = true
+	chk.Verbose = true
+}
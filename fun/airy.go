@@ -0,0 +1,69 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import "math"
+
+// AiryAi returns the Airy function Ai(x)
+func AiryAi(x float64) float64 {
+	ai, _, _, _ := Airy(x)
+	return ai
+}
+
+// AiryBi returns the Airy function Bi(x)
+func AiryBi(x float64) float64 {
+	_, bi, _, _ := Airy(x)
+	return bi
+}
+
+// Airy returns the Airy functions Ai(x) and Bi(x) and their derivatives Ai'(x) and Bi'(x)
+//
+//	The functions are computed from the Bessel functions of order 1/3 and 2/3 with z = ⅔|x|^(3/2);
+//	e.g. for x > 0:
+//
+//	  Ai(x) = (1/π) √(x/3) K(1/3)(z)
+//
+//	and for x < 0:
+//
+//	  Ai(x) = ½ √|x| [J(1/3)(z) - Y(1/3)(z)/√3]
+//
+//	Reference:
+//	[1] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes: The Art of
+//	     Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+func Airy(x float64) (ai, bi, aip, bip float64) {
+	const (
+		onovrt = 0.5773502691896258 // 1/√3
+		thr    = 1.0 / 3.0
+		twothr = 2.0 / 3.0
+	)
+	if math.IsNaN(x) {
+		return math.NaN(), math.NaN(), math.NaN(), math.NaN()
+	}
+	absx := math.Abs(x)
+	rootx := math.Sqrt(absx)
+	z := twothr * absx * rootx
+	switch {
+	case x > 0:
+		i, k, _, _ := ModBesselIK(thr, z)
+		ai = rootx * onovrt * k / math.Pi
+		bi = rootx * (k/math.Pi + 2.0*onovrt*i)
+		i, k, _, _ = ModBesselIK(twothr, z)
+		aip = -x * onovrt * k / math.Pi
+		bip = x * (k/math.Pi + 2.0*onovrt*i)
+	case x < 0:
+		j, y, _, _ := BesselJY(thr, z)
+		ai = 0.5 * rootx * (j - onovrt*y)
+		bi = -0.5 * rootx * (y + onovrt*j)
+		j, y, _, _ = BesselJY(twothr, z)
+		aip = 0.5 * absx * (onovrt*y + j)
+		bip = 0.5 * absx * (onovrt*j - y)
+	default:
+		ai = 0.3550280538878172
+		bi = ai / onovrt
+		aip = -0.2588194037928068
+		bip = -aip / onovrt
+	}
+	return
+}
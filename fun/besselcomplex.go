@@ -0,0 +1,401 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Bessel functions of real order and complex argument
+//
+//	The modified Bessel functions Iν(z) and Kν(z) are computed in the right half-plane with the
+//	complex version of the method by Temme (|z| < 2) and Steed (|z| ≥ 2) [1,2] or the Hankel
+//	asymptotic expansion for very large |z| [3]. The left half-plane is reached by analytic
+//	continuation and Jν, Yν, H⁽¹⁾ν and H⁽²⁾ν are obtained from Iν and Kν by rotation, as in the
+//	algorithm by Amos [4]. The principal branches (cut along the negative real axis) are returned.
+//
+//	References
+//	[1] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes: The Art of
+//	     Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+//	[2] Thompson IJ, Barnett AR (1987) Modified Bessel functions Iν(z) and Kν(z) of real order and
+//	     complex argument, to selected accuracy. Computer Physics Communications, 47:245-257
+//	[3] Olver FWJ, Lozier DW, Boisvert RF, Clark CW (2010) NIST Handbook of Mathematical
+//	     Functions. Cambridge University Press. Chapter 10
+//	[4] Amos DE (1986) Algorithm 644: A portable package for Bessel functions of a complex
+//	     argument and nonnegative order. ACM Transactions on Mathematical Software, 12(3):265-273
+
+// BesselJC returns the Bessel function of the first kind Jν(z) of real order ν and complex z
+func BesselJC(ν float64, z complex128) complex128 {
+	j, _, _, _ := BesselJYC(ν, z)
+	return j
+}
+
+// BesselYC returns the Bessel function of the second kind Yν(z) of real order ν and complex z
+func BesselYC(ν float64, z complex128) complex128 {
+	_, y, _, _ := BesselJYC(ν, z)
+	return y
+}
+
+// BesselJYC returns the Bessel functions Jν(z) and Yν(z) and their derivatives J'ν(z) and Y'ν(z)
+// for real order ν (negative orders are computed by reflection) and complex z
+//
+//	Jν(z) = exp(+iνπ/2) Iν(-i z)                    Im(z) ≥ 0
+//	Jν(z) = exp(-iνπ/2) Iν(+i z)                    Im(z) < 0
+//	Yν(z) = -i (H⁽¹⁾ν(z) - Jν(z)) = -i (Jν(z) - H⁽²⁾ν(z))
+//
+//	Special cases:
+//	  Jν(0) and Yν(0) as in BesselJY
+func BesselJYC(ν float64, z complex128) (j, y, jp, yp complex128) {
+	if cmplx.IsNaN(z) || math.IsNaN(ν) {
+		n := cmplx.NaN()
+		return n, n, n, n
+	}
+	if ν < 0 {
+		μ := -ν
+		j, y, jp, yp = BesselJYC(μ, z)
+		if z == 0 {
+			jr, yr, jpr, ypr := BesselJY(ν, 0)
+			return complex(jr, 0), complex(yr, 0), complex(jpr, 0), complex(ypr, 0)
+		}
+		c, s := besselCosSinPi(μ)
+		cc, sc := complex(c, 0), complex(s, 0)
+		return cc*j - sc*y, sc*j + cc*y, cc*jp - sc*yp, sc*jp + cc*yp
+	}
+	if z == 0 {
+		jr, yr, jpr, ypr := BesselJY(ν, 0)
+		return complex(jr, 0), complex(yr, 0), complex(jpr, 0), complex(ypr, 0)
+	}
+	var h, hp complex128
+	j, h, jp, hp = besselJHC(ν, z)
+	if imag(z) >= 0 {
+		y, yp = -1i*(h-j), -1i*(hp-jp)
+	} else {
+		y, yp = -1i*(j-h), -1i*(jp-hp)
+	}
+	return
+}
+
+// HankelH1 returns the Hankel function of the first kind H⁽¹⁾ν(z) = Jν(z) + i Yν(z) of real
+// order ν and complex z
+//
+//	H⁽¹⁾-ν(z) = exp(iνπ) H⁽¹⁾ν(z)
+func HankelH1(ν float64, z complex128) complex128 {
+	if cmplx.IsNaN(z) || math.IsNaN(ν) {
+		return cmplx.NaN()
+	}
+	if z == 0 {
+		return cmplx.Inf()
+	}
+	if ν < 0 {
+		c, s := besselCosSinPi(-ν)
+		return complex(c, s) * HankelH1(-ν, z)
+	}
+	j, h, _, _ := besselJHC(ν, z)
+	if imag(z) >= 0 {
+		return h
+	}
+	return 2*j - h
+}
+
+// HankelH2 returns the Hankel function of the second kind H⁽²⁾ν(z) = Jν(z) - i Yν(z) of real
+// order ν and complex z
+//
+//	H⁽²⁾-ν(z) = exp(-iνπ) H⁽²⁾ν(z)
+func HankelH2(ν float64, z complex128) complex128 {
+	if cmplx.IsNaN(z) || math.IsNaN(ν) {
+		return cmplx.NaN()
+	}
+	if z == 0 {
+		return cmplx.Inf()
+	}
+	if ν < 0 {
+		c, s := besselCosSinPi(-ν)
+		return complex(c, -s) * HankelH2(-ν, z)
+	}
+	j, h, _, _ := besselJHC(ν, z)
+	if imag(z) < 0 {
+		return h
+	}
+	return 2*j - h
+}
+
+// ModBesselIC returns the modified Bessel function of the first kind Iν(z) of real order ν ≥ 0
+// and complex z
+func ModBesselIC(ν float64, z complex128) complex128 {
+	i, _, _, _ := ModBesselIKC(ν, z)
+	return i
+}
+
+// ModBesselKC returns the modified Bessel function of the second kind Kν(z) of real order ν ≥ 0
+// and complex z
+func ModBesselKC(ν float64, z complex128) complex128 {
+	_, k, _, _ := ModBesselIKC(ν, z)
+	return k
+}
+
+// ModBesselIKC returns the modified Bessel functions Iν(z) and Kν(z) and their derivatives I'ν(z)
+// and K'ν(z) for real order ν ≥ 0 and complex z
+//
+//	For Re(z) < 0, with w = -z and m = ±1 (the sign of Im(z)):
+//
+//	  Iν(z) = exp(+imνπ) Iν(w)
+//	  Kν(z) = exp(-imνπ) Kν(w) - iπm Iν(w)
+//
+//	Special cases:
+//	  Iν(0) and Kν(0) as in ModBesselIK
+//	  ν < 0 ⇒ NaN
+func ModBesselIKC(ν float64, z complex128) (i, k, ip, kp complex128) {
+	if ν < 0 || cmplx.IsNaN(z) || math.IsNaN(ν) {
+		n := cmplx.NaN()
+		return n, n, n, n
+	}
+	if z == 0 {
+		ir, kr, ipr, kpr := ModBesselIK(ν, 0)
+		return complex(ir, 0), complex(kr, 0), complex(ipr, 0), complex(kpr, 0)
+	}
+	if real(z) < 0 {
+		m := 1.0
+		if imag(z) < 0 {
+			m = -1.0
+		}
+		i, k, ip, kp = ModBesselIKC(ν, -z)
+		c, s := besselCosSinPi(ν)
+		ep, em := complex(c, m*s), complex(c, -m*s)
+		iπm := complex(0, math.Pi*m)
+		return ep * i, em*k - iπm*i, -ep * ip, -(em*kp - iπm*ip)
+	}
+	if cmplx.Abs(z) > 1000 && cmplx.Abs(z) > ν*ν {
+		return besselIKasympC(ν, z)
+	}
+	return besselIKtemmeC(ν, z)
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// besselCosSinPi returns cos(νπ) and sin(νπ), exact for integer and half-integer ν
+func besselCosSinPi(ν float64) (c, s float64) {
+	c, s = math.Cos(ν*math.Pi), math.Sin(ν*math.Pi)
+	if ν == math.Floor(ν) {
+		s = 0
+		c = NegOnePowN(int(ν))
+	} else if ν-0.5 == math.Floor(ν-0.5) {
+		c = 0
+		s = NegOnePowN(int(ν - 0.5))
+	}
+	return
+}
+
+// besselJHC computes Jν(z), J'ν(z) and H⁽¹⁾ν(z), H⁽¹⁾'ν(z) if Im(z) ≥ 0 or H⁽²⁾ν(z), H⁽²⁾'ν(z)
+// otherwise, for ν ≥ 0 and z ≠ 0. Only the right half-plane is needed for Iν and Kν
+func besselJHC(ν float64, z complex128) (j, h, jp, hp complex128) {
+	c, s := besselCosSinPi(0.5 * ν)
+	if imag(z) >= 0 {
+		e := complex(c, s) // exp(iνπ/2)
+		i, k, ip, kp := ModBesselIKC(ν, complex(imag(z), -real(z)))
+		j, jp = e*i, -1i*e*ip
+		f := complex(0, -2/math.Pi) / e
+		h, hp = f*k, -1i*f*kp
+		return
+	}
+	e := complex(c, -s) // exp(-iνπ/2)
+	i, k, ip, kp := ModBesselIKC(ν, complex(-imag(z), real(z)))
+	j, jp = e*i, 1i*e*ip
+	f := complex(0, 2/math.Pi) / e
+	h, hp = f*k, 1i*f*kp
+	return
+}
+
+// besselIKtemmeC implements the method by Temme and Steed for Iν and Kν with ν ≥ 0, z ≠ 0 and
+// Re(z) ≥ 0. See besselIKtemme
+func besselIKtemmeC(ν float64, z complex128) (io, ko, ipo, kpo complex128) {
+
+	// CF1 by modified Lentz's method
+	nl := int(ν + 0.5)
+	μ := ν - float64(nl)
+	μ2 := μ * μ
+	zi := 1.0 / z
+	zi2 := 2.0 * zi
+	h := complex(ν, 0) * zi
+	if cmplx.Abs(h) < besselFpMin {
+		h = besselFpMin
+	}
+	b := zi2 * complex(ν, 0)
+	var d complex128
+	c := h
+	var i int
+	for i = 0; i < besselMaxIt; i++ {
+		b += zi2
+		d = b + d
+		if cmplx.Abs(d) < besselFpMin {
+			d = besselFpMin // avoid zero denominators; e.g. ν = 1 and z = 2i
+		}
+		d = 1.0 / d
+		c = b + 1.0/c
+		if cmplx.Abs(c) < besselFpMin {
+			c = besselFpMin
+		}
+		del := c * d
+		h *= del
+		if cmplx.Abs(del-1.0) <= 2*besselEps {
+			break
+		}
+	}
+
+	// downward recurrence
+	var ril complex128 = besselFpMin
+	ripl := h * ril
+	ril1, rip1 := ril, ripl
+	fact := complex(ν, 0) * zi
+	for l := nl - 1; l >= 0; l-- {
+		ritemp := fact*ril + ripl
+		fact -= zi
+		ripl = fact*ritemp + ril
+		ril = ritemp
+	}
+	f := ripl / ril
+
+	// Kμ and K(μ+1)
+	var rkmu, rk1 complex128
+	if cmplx.Abs(z) < besselXmin {
+		z2 := 0.5 * z
+		pimu := math.Pi * μ
+		fr := 1.0
+		if math.Abs(pimu) >= besselEps {
+			fr = pimu / math.Sin(pimu)
+		}
+		d = -cmplx.Log(z2)
+		e := complex(μ, 0) * d
+		fact2 := complex(1, 0)
+		if cmplx.Abs(e) >= besselEps {
+			fact2 = cmplx.Sinh(e) / e
+		}
+		gam1, gam2, gampl, gammi := besselGammas(μ)
+		ff := complex(fr, 0) * (complex(gam1, 0)*cmplx.Cosh(e) + complex(gam2, 0)*fact2*d)
+		sum := ff
+		e = cmplx.Exp(e)
+		p := 0.5 * e / complex(gampl, 0)
+		q := 0.5 / (e * complex(gammi, 0))
+		c = 1.0
+		d = z2 * z2
+		sum1 := p
+		for i = 1; i <= besselMaxIt; i++ {
+			fi := float64(i)
+			ff = (complex(fi, 0)*ff + p + q) / complex(fi*fi-μ2, 0)
+			c *= d / complex(fi, 0)
+			p /= complex(fi-μ, 0)
+			q /= complex(fi+μ, 0)
+			del := c * ff
+			sum += del
+			sum1 += c * (p - complex(fi, 0)*ff)
+			if cmplx.Abs(del) < cmplx.Abs(sum)*besselEps {
+				break
+			}
+		}
+		rkmu = sum
+		rk1 = sum1 * zi2
+	} else {
+
+		// CF2 by Steed's method
+		b = 2.0 * (1.0 + z)
+		d = 1.0 / b
+		delh := d
+		h = d
+		var q1, q2 complex128 = 0, 1
+		a1 := 0.25 - μ2
+		q := complex(a1, 0)
+		cr := a1
+		a := -a1
+		s := 1.0 + q*delh
+		for i = 1; i < besselMaxIt; i++ {
+			fi := float64(i)
+			a -= 2 * fi
+			cr = -a * cr / (fi + 1.0)
+			qnew := (q1 - b*q2) / complex(a, 0)
+			q1 = q2
+			q2 = qnew
+			q += complex(cr, 0) * qnew
+			b += 2.0
+			d = 1.0 / (b + complex(a, 0)*d)
+			delh = (b*d - 1.0) * delh
+			h += delh
+			dels := q * delh
+			s += dels
+			if cmplx.Abs(dels/s) <= besselEps {
+				break
+			}
+		}
+		h = complex(a1, 0) * h
+		rkmu = cmplx.Sqrt(math.Pi/(2.0*z)) * cmplx.Exp(-z) / s
+		rk1 = rkmu * (complex(μ+0.5, 0) + z - h) * zi
+	}
+
+	// scale and recur K upwards
+	rkmup := complex(μ, 0)*zi*rkmu - rk1
+	rimu := zi / (f*rkmu - rkmup)
+	io = (rimu * ril1) / ril
+	ipo = (rimu * rip1) / ril
+	for i = 1; i <= nl; i++ {
+		rktemp := complex(μ+float64(i), 0)*zi2*rk1 + rkmu
+		rkmu = rk1
+		rk1 = rktemp
+	}
+	ko = rkmu
+	kpo = complex(ν, 0)*zi*rkmu - rk1
+	return
+}
+
+// besselIKasympC computes Iν(z) and Kν(z) with the Hankel asymptotic expansion for large |z| and
+// Re(z) ≥ 0 [3]
+//
+//	Kν(z) ~ √(π/(2z)) exp(-z) Σ aₖ(ν) / zᵏ
+//	Iν(z) ~ exp(z) / √(2πz) Σ (-1)ᵏ aₖ(ν) / zᵏ ± i exp(±iνπ) exp(-z) / √(2πz) Σ aₖ(ν) / zᵏ
+//
+//	where the upper (lower) signs are taken for Im(z) ≥ 0 (Im(z) < 0)
+func besselIKasympC(ν float64, z complex128) (i, k, ip, kp complex128) {
+
+	// sums and their derivatives with respect to z
+	μ := 4.0 * ν * ν
+	zi := 1.0 / z
+	var sp, sm, tp, tm complex128 = 1, 1, 0, 0
+	a := 1.0
+	zk := complex(1, 0)
+	prev := math.Inf(1)
+	for n := 1; n < 100; n++ {
+		fn := float64(n)
+		a *= (μ - (2*fn-1)*(2*fn-1)) / (8 * fn)
+		zk *= zi
+		term := complex(a, 0) * zk
+		if cmplx.Abs(term) > prev {
+			break
+		}
+		prev = cmplx.Abs(term)
+		dterm := complex(-fn, 0) * term * zi
+		if n%2 == 0 {
+			sp, sm, tp, tm = sp+term, sm+term, tp+dterm, tm+dterm
+		} else {
+			sp, sm, tp, tm = sp+term, sm-term, tp+dterm, tm-dterm
+		}
+		if prev < besselEps*cmplx.Abs(sp) {
+			break
+		}
+	}
+
+	// results
+	pref := 1.0 / cmplx.Sqrt(2*math.Pi*z)
+	ez, emz := cmplx.Exp(z), cmplx.Exp(-z)
+	hz := 0.5 * zi
+	k = math.Pi * pref * emz * sp
+	kp = math.Pi * pref * emz * (tp - sp*(1+hz))
+	σ := 1.0
+	if imag(z) < 0 {
+		σ = -1.0
+	}
+	c, s := besselCosSinPi(ν)
+	g := complex(0, σ) * complex(c, σ*s) * pref * emz
+	i = pref*ez*sm + g*sp
+	ip = pref*ez*(tm+sm*(1-hz)) + g*(tp-sp*(1+hz))
+	return
+}
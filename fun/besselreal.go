@@ -0,0 +1,603 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import "math"
+
+// Bessel functions of real order
+//
+//	References
+//	[1] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes: The Art of
+//	     Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+//	[2] Temme NM (1976) On the numerical evaluation of the ordinary Bessel function of the second
+//	     kind. Journal of Computational Physics, 21:343-350
+//	[3] Abramowitz M, Stegun IA (1972) Handbook of Mathematical Functions with Formulas, Graphs,
+//	     and Mathematical Tables. U.S. Department of Commerce, NIST
+
+// BesselJ returns the Bessel function of the first kind Jν(x) of real order ν and x ≥ 0
+//
+//	Special cases:
+//	  J0(0) = 1, Jν(0) = 0 for ν > 0
+//	  Jν(x<0) = NaN
+func BesselJ(ν, x float64) float64 {
+	j, _, _, _ := BesselJY(ν, x)
+	return j
+}
+
+// BesselY returns the Bessel function of the second kind Yν(x) of real order ν and x ≥ 0
+//
+//	Special cases:
+//	  Yν(0) = -Inf (for ν ≥ 0)
+//	  Yν(x<0) = NaN
+func BesselY(ν, x float64) float64 {
+	_, y, _, _ := BesselJY(ν, x)
+	return y
+}
+
+// BesselJY returns the Bessel functions Jν(x) and Yν(x) and their derivatives J'ν(x) and Y'ν(x)
+// for real order ν (negative orders are computed by reflection) and x ≥ 0
+//
+//	The method by Temme [2] (x < 2) and Steed (x ≥ 2) is employed [1]; however, Jν and J'ν are
+//	computed by the power series [3, 9.1.10] for x < 2. The Hankel asymptotic expansion [3] is
+//	used for very large x.
+func BesselJY(ν, x float64) (j, y, jp, yp float64) {
+	if x < 0 || math.IsNaN(x) || math.IsNaN(ν) {
+		return math.NaN(), math.NaN(), math.NaN(), math.NaN()
+	}
+	if ν < 0 {
+		μ := -ν
+		j, y, jp, yp = BesselJY(μ, x)
+		if x == 0 {
+			if μ == math.Floor(μ) {
+				s := NegOnePowN(int(μ))
+				return s * j, s * y, s * jp, s * yp
+			}
+			return math.Inf(+1), math.Inf(+1), math.Inf(-1), math.Inf(-1)
+		}
+		c, s := math.Cos(μ*math.Pi), math.Sin(μ*math.Pi)
+		if μ == math.Floor(μ) {
+			s = 0
+			c = NegOnePowN(int(μ))
+		} else if μ-0.5 == math.Floor(μ-0.5) {
+			c = 0
+		}
+		return c*j - s*y, s*j + c*y, c*jp - s*yp, s*jp + c*yp
+	}
+	if x == 0 {
+		switch {
+		case ν == 0:
+			return 1, math.Inf(-1), 0, math.Inf(+1)
+		case ν == 1:
+			return 0, math.Inf(-1), 0.5, math.Inf(+1)
+		case ν < 1:
+			return 0, math.Inf(-1), math.Inf(+1), math.Inf(+1)
+		}
+		return 0, math.Inf(-1), 0, math.Inf(+1)
+	}
+	if x > 1000 && x > ν*ν {
+		return besselJYasymp(ν, x)
+	}
+	if x < besselXmin {
+		return besselJYseries(ν, x)
+	}
+	return besselJYtemme(ν, x)
+}
+
+// ModBesselI returns the modified Bessel function of the first kind Iν(x) of real order ν ≥ 0
+// and x ≥ 0
+func ModBesselI(ν, x float64) float64 {
+	i, _, _, _ := ModBesselIK(ν, x)
+	return i
+}
+
+// ModBesselK returns the modified Bessel function of the second kind Kν(x) of real order ν ≥ 0
+// and x ≥ 0
+func ModBesselK(ν, x float64) float64 {
+	_, k, _, _ := ModBesselIK(ν, x)
+	return k
+}
+
+// ModBesselIK returns the modified Bessel functions Iν(x) and Kν(x) and their derivatives I'ν(x)
+// and K'ν(x) for real order ν ≥ 0 and x ≥ 0
+//
+//	The method by Temme (x < 2) and Steed (x ≥ 2) is employed [1]; however, Iν and I'ν are
+//	computed by the power series [3, 9.6.10] for x < 2.
+//
+//	Special cases:
+//	  Iν(0) = 1 if ν = 0 and 0 otherwise; Kν(0) = +Inf
+//	  ν < 0 or x < 0 ⇒ NaN
+func ModBesselIK(ν, x float64) (i, k, ip, kp float64) {
+	if x < 0 || ν < 0 || math.IsNaN(x) || math.IsNaN(ν) {
+		return math.NaN(), math.NaN(), math.NaN(), math.NaN()
+	}
+	if x == 0 {
+		switch {
+		case ν == 0:
+			return 1, math.Inf(+1), 0, math.Inf(-1)
+		case ν == 1:
+			return 0, math.Inf(+1), 0.5, math.Inf(-1)
+		case ν < 1:
+			return 0, math.Inf(+1), math.Inf(+1), math.Inf(-1)
+		}
+		return 0, math.Inf(+1), 0, math.Inf(-1)
+	}
+	if x < besselXmin {
+		return besselIKseries(ν, x)
+	}
+	return besselIKtemme(ν, x)
+}
+
+// SphBesselJ returns the spherical Bessel function of the first kind jn(x) = √(π/(2x)) J(n+½)(x)
+func SphBesselJ(n int, x float64) float64 {
+	j, _ := sphBesselJY(n, x)
+	return j
+}
+
+// SphBesselY returns the spherical Bessel function of the second kind yn(x) = √(π/(2x)) Y(n+½)(x)
+func SphBesselY(n int, x float64) float64 {
+	_, y := sphBesselJY(n, x)
+	return y
+}
+
+// sphBesselJY computes the spherical Bessel functions jn(x) and yn(x)
+func sphBesselJY(n int, x float64) (j, y float64) {
+	if n < 0 || x < 0 || math.IsNaN(x) {
+		return math.NaN(), math.NaN()
+	}
+	if x == 0 {
+		if n == 0 {
+			return 1, math.Inf(-1)
+		}
+		return 0, math.Inf(-1)
+	}
+	jν, yν, _, _ := BesselJY(float64(n)+0.5, x)
+	f := math.Sqrt(math.Pi / (2.0 * x))
+	return f * jν, f * yν
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// constants for the Bessel functions of real order
+const (
+	besselMaxIt = 10000
+	besselEps   = 2.220446049250313e-16               // machine epsilon
+	besselFpMin = 2.2250738585072014e-308 / besselEps // smallest normal / epsilon
+	besselXmin  = 2.0
+)
+
+// Chebyshev coefficients to compute Γ1 and Γ2 in Temme's method [1]
+var (
+	besselC1 = []float64{-1.142022680371168e0, 6.5165112670737e-3, 3.087090173086e-4, -3.4706269649e-6, 6.9437664e-9, 3.67795e-11, -1.356e-13}
+	besselC2 = []float64{1.843740587300905e0, -7.68528408447867e-2, 1.2719271366546e-3, -4.9717367042e-6, -3.31261198e-8, 2.423096e-10, -1.702e-13, -1.49e-15}
+)
+
+// besselChebev evaluates a Chebyshev series
+func besselChebev(c []float64, x float64) float64 {
+	d, dd := 0.0, 0.0
+	for j := len(c) - 1; j > 0; j-- {
+		d, dd = 2.0*x*d-dd+c[j], d
+	}
+	return x*d - dd + 0.5*c[0]
+}
+
+// besselGammas computes Γ1, Γ2, 1/Γ(1+μ) and 1/Γ(1-μ) for |μ| ≤ ½ as in Temme's method
+func besselGammas(μ float64) (gam1, gam2, gampl, gammi float64) {
+	xx := 8.0*μ*μ - 1.0
+	gam1 = besselChebev(besselC1, xx)
+	gam2 = besselChebev(besselC2, xx)
+	gampl = gam2 - μ*gam1
+	gammi = gam2 + μ*gam1
+	return
+}
+
+// besselJYtemme implements the method by Temme and Steed for Jν and Yν with ν ≥ 0 and x > 0
+func besselJYtemme(ν, x float64) (jo, yo, jpo, ypo float64) {
+
+	// number of downward recurrences
+	var nl int
+	if x < besselXmin {
+		nl = int(ν + 0.5)
+	} else {
+		nl = max(0, int(ν-x+1.5))
+	}
+	μ := ν - float64(nl)
+	μ2 := μ * μ
+	xi := 1.0 / x
+	xi2 := 2.0 * xi
+	w := xi2 / math.Pi
+
+	// CF1 by modified Lentz's method
+	isign := 1.0
+	h := ν * xi
+	if h < besselFpMin {
+		h = besselFpMin
+	}
+	b := xi2 * ν
+	d, c := 0.0, h
+	var i int
+	for i = 0; i < besselMaxIt; i++ {
+		b += xi2
+		d = b - d
+		if math.Abs(d) < besselFpMin {
+			d = besselFpMin
+		}
+		c = b - 1.0/c
+		if math.Abs(c) < besselFpMin {
+			c = besselFpMin
+		}
+		d = 1.0 / d
+		del := c * d
+		h *= del
+		if d < 0 {
+			isign = -isign
+		}
+		if math.Abs(del-1.0) <= besselEps {
+			break
+		}
+	}
+	if i >= besselMaxIt {
+		return besselJYasymp(ν, x)
+	}
+
+	// downward recurrence
+	rjl := isign * besselFpMin
+	rjpl := h * rjl
+	rjl1, rjp1 := rjl, rjpl
+	fact := ν * xi
+	for l := nl - 1; l >= 0; l-- {
+		rjtemp := fact*rjl + rjpl
+		fact -= xi
+		rjpl = fact*rjtemp - rjl
+		rjl = rjtemp
+	}
+	if rjl == 0 {
+		rjl = besselEps
+	}
+	f := rjpl / rjl
+
+	// Jμ, Yμ, Y(μ+1)
+	var rjmu, rymu, rymup, ry1 float64
+	if x < besselXmin {
+		x2 := 0.5 * x
+		pimu := math.Pi * μ
+		fact = 1.0
+		if math.Abs(pimu) >= besselEps {
+			fact = pimu / math.Sin(pimu)
+		}
+		d = -math.Log(x2)
+		e := μ * d
+		fact2 := 1.0
+		if math.Abs(e) >= besselEps {
+			fact2 = math.Sinh(e) / e
+		}
+		gam1, gam2, gampl, gammi := besselGammas(μ)
+		ff := 2.0 / math.Pi * fact * (gam1*math.Cosh(e) + gam2*fact2*d)
+		e = math.Exp(e)
+		p := e / (gampl * math.Pi)
+		q := 1.0 / (e * math.Pi * gammi)
+		pimu2 := 0.5 * pimu
+		fact3 := 1.0
+		if math.Abs(pimu2) >= besselEps {
+			fact3 = math.Sin(pimu2) / pimu2
+		}
+		r := math.Pi * pimu2 * fact3 * fact3
+		c = 1.0
+		d = -x2 * x2
+		sum := ff + r*q
+		sum1 := p
+		for i = 1; i <= besselMaxIt; i++ {
+			fi := float64(i)
+			ff = (fi*ff + p + q) / (fi*fi - μ2)
+			c *= d / fi
+			p /= fi - μ
+			q /= fi + μ
+			del := c * (ff + r*q)
+			sum += del
+			sum1 += c*p - fi*del
+			if math.Abs(del) < (1.0+math.Abs(sum))*besselEps {
+				break
+			}
+		}
+		rymu = -sum
+		ry1 = -sum1 * xi2
+		rymup = μ*xi*rymu - ry1
+		rjmu = w / (rymup - f*rymu)
+	} else {
+
+		// CF2 by Steed's method
+		a := 0.25 - μ2
+		p := -0.5 * xi
+		q := 1.0
+		br := 2.0 * x
+		bi := 2.0
+		fact = a * xi / (p*p + q*q)
+		cr := br + q*fact
+		ci := bi + p*fact
+		den := br*br + bi*bi
+		dr := br / den
+		di := -bi / den
+		dlr := cr*dr - ci*di
+		dli := cr*di + ci*dr
+		p, q = p*dlr-q*dli, p*dli+q*dlr
+		for i = 1; i < besselMaxIt; i++ {
+			a += float64(2 * i)
+			bi += 2.0
+			dr = a*dr + br
+			di = a*di + bi
+			if math.Abs(dr)+math.Abs(di) < besselFpMin {
+				dr = besselFpMin
+			}
+			fact = a / (cr*cr + ci*ci)
+			cr = br + cr*fact
+			ci = bi - ci*fact
+			if math.Abs(cr)+math.Abs(ci) < besselFpMin {
+				cr = besselFpMin
+			}
+			den = dr*dr + di*di
+			dr /= den
+			di /= -den
+			dlr = cr*dr - ci*di
+			dli = cr*di + ci*dr
+			p, q = p*dlr-q*dli, p*dli+q*dlr
+			if math.Abs(dlr-1.0)+math.Abs(dli) <= besselEps {
+				break
+			}
+		}
+		gam := (p - f) / q
+		rjmu = math.Copysign(math.Sqrt(w/((p-f)*gam+q)), rjl)
+		rymu = rjmu * gam
+		rymup = rymu * (p + q/gam)
+		ry1 = μ*xi*rymu - rymup
+	}
+
+	// scale and recur Y upwards
+	fact = rjmu / rjl
+	jo = rjl1 * fact
+	jpo = rjp1 * fact
+	for i = 1; i <= nl; i++ {
+		rytemp := (μ+float64(i))*xi2*ry1 - rymu
+		rymu = ry1
+		ry1 = rytemp
+	}
+	yo = rymu
+	ypo = ν*xi*rymu - ry1
+	return
+}
+
+// besselSeries computes Jν and J'ν (sgn = -1) or Iν and I'ν (sgn = +1) with ν ≥ 0 by the power
+// series [3, 9.1.10 and 9.6.10]
+//
+//	Jν(x) = (x/2)ᵛ Σ (-x²/4)ᵏ / (k! Γ(ν+k+1))    J'ν(x) = (ν/x) Jν(x) - J(ν+1)(x)
+//	Iν(x) = (x/2)ᵛ Σ (+x²/4)ᵏ / (k! Γ(ν+k+1))    I'ν(x) = (ν/x) Iν(x) + I(ν+1)(x)
+//
+//	The factors (x/2)ᵛ/Γ(ν+1) are computed in log space for ν ≥ 170 where Γ(ν+1) overflows.
+//	For x < 2, the ratio of consecutive terms is smaller than 1/k²; thus, the series converges
+//	quickly and there is no significant cancellation. Conversely, Temme's method computes Jν and
+//	Iν by a downward recurrence and the Wronskian, which lose all accuracy for small x.
+func besselSeries(ν, x, sgn float64) (f, fp float64) {
+	x2 := 0.5 * x
+	z := sgn * x2 * x2
+	var lead, dlead float64 // (x/2)ᵛ/Γ(ν+1) and (ν/x)⋅(x/2)ᵛ/Γ(ν+1) = ½(x/2)ᵛ⁻¹/Γ(ν)
+	if ν < 170 {
+		lead = math.Pow(x2, ν) / math.Gamma(ν+1.0)
+		if ν > 0 {
+			dlead = 0.5 * math.Pow(x2, ν-1.0) / math.Gamma(ν)
+		}
+	} else {
+		lx := math.Log(x2)
+		lg1, _ := math.Lgamma(ν + 1.0)
+		lg, _ := math.Lgamma(ν)
+		lead = math.Exp(ν*lx - lg1)
+		dlead = 0.5 * math.Exp((ν-1.0)*lx-lg)
+	}
+	s0, s1 := besselSeriesSum(ν, z), besselSeriesSum(ν+1.0, z)
+	f = lead * s0
+	fp = dlead*s0 + sgn*lead*x2/(ν+1.0)*s1
+	return
+}
+
+// besselSeriesSum computes Σ zᵏ / (k! (ν+1)ₖ) where (ν+1)ₖ is the rising factorial
+func besselSeriesSum(ν, z float64) (sum float64) {
+	t := 1.0
+	sum = 1.0
+	for k := 1; k <= besselMaxIt; k++ {
+		fk := float64(k)
+		t *= z / (fk * (ν + fk))
+		sum += t
+		if math.Abs(t) <= besselEps*math.Abs(sum) {
+			break
+		}
+	}
+	return
+}
+
+// besselJYseries computes Jν, Yν and derivatives for x < 2. Jν and J'ν are given by the power
+// series; Yν and Y'ν are computed by Temme's method, whose upward recurrence may overflow
+func besselJYseries(ν, x float64) (j, y, jp, yp float64) {
+	j, jp = besselSeries(ν, x, -1)
+	_, y, _, yp = besselJYtemme(ν, x)
+	if math.IsNaN(y) {
+		y = math.Inf(-1)
+	}
+	if math.IsNaN(yp) {
+		yp = math.Inf(+1)
+	}
+	return
+}
+
+// besselJYasymp computes Jν, Yν and derivatives using Hankel's asymptotic expansion for large x [3]
+func besselJYasymp(ν, x float64) (j, y, jp, yp float64) {
+	μ := 4.0 * ν * ν
+	χ := x - (0.5*ν+0.25)*math.Pi
+	P, Q, R, S := 1.0, 0.0, 1.0, 0.0 // P,Q for Jν and Yν; R,S for the derivatives
+	ak := 1.0
+	for k := 1; k < 60; k++ {
+		fk := float64(k)
+		bk := ak * (μ + 4.0*fk*fk - 1.0) / (fk * 8.0 * x)
+		ak *= (μ - (2.0*fk-1.0)*(2.0*fk-1.0)) / (fk * 8.0 * x)
+		sgn := NegOnePowN(k / 2)
+		if k%2 == 1 {
+			Q += sgn * ak
+			S += sgn * bk
+		} else {
+			P += sgn * ak
+			R += sgn * bk
+		}
+		if math.Abs(ak) < besselEps && math.Abs(bk) < besselEps {
+			break
+		}
+	}
+	f := math.Sqrt(2.0 / (math.Pi * x))
+	s, c := math.Sincos(χ)
+	j = f * (P*c - Q*s)
+	y = f * (P*s + Q*c)
+	jp = -f * (R*s + S*c)
+	yp = f * (R*c - S*s)
+	return
+}
+
+// besselIKtemme implements the method by Temme and Steed for Iν and Kν with ν ≥ 0 and x > 0
+func besselIKtemme(ν, x float64) (io, ko, ipo, kpo float64) {
+
+	// CF1 by modified Lentz's method
+	nl := int(ν + 0.5)
+	μ := ν - float64(nl)
+	μ2 := μ * μ
+	xi := 1.0 / x
+	xi2 := 2.0 * xi
+	h := ν * xi
+	if h < besselFpMin {
+		h = besselFpMin
+	}
+	b := xi2 * ν
+	d, c := 0.0, h
+	var i int
+	for i = 0; i < besselMaxIt; i++ {
+		b += xi2
+		d = 1.0 / (b + d)
+		c = b + 1.0/c
+		del := c * d
+		h *= del
+		if math.Abs(del-1.0) <= besselEps {
+			break
+		}
+	}
+
+	// downward recurrence
+	ril := besselFpMin
+	ripl := h * ril
+	ril1, rip1 := ril, ripl
+	fact := ν * xi
+	for l := nl - 1; l >= 0; l-- {
+		ritemp := fact*ril + ripl
+		fact -= xi
+		ripl = fact*ritemp + ril
+		ril = ritemp
+	}
+	f := ripl / ril
+
+	// Kμ and K(μ+1)
+	var rkmu, rk1 float64
+	if x < besselXmin {
+		x2 := 0.5 * x
+		pimu := math.Pi * μ
+		fact = 1.0
+		if math.Abs(pimu) >= besselEps {
+			fact = pimu / math.Sin(pimu)
+		}
+		d = -math.Log(x2)
+		e := μ * d
+		fact2 := 1.0
+		if math.Abs(e) >= besselEps {
+			fact2 = math.Sinh(e) / e
+		}
+		gam1, gam2, gampl, gammi := besselGammas(μ)
+		ff := fact * (gam1*math.Cosh(e) + gam2*fact2*d)
+		sum := ff
+		e = math.Exp(e)
+		p := 0.5 * e / gampl
+		q := 0.5 / (e * gammi)
+		c = 1.0
+		d = x2 * x2
+		sum1 := p
+		for i = 1; i <= besselMaxIt; i++ {
+			fi := float64(i)
+			ff = (fi*ff + p + q) / (fi*fi - μ2)
+			c *= d / fi
+			p /= fi - μ
+			q /= fi + μ
+			del := c * ff
+			sum += del
+			sum1 += c * (p - fi*ff)
+			if math.Abs(del) < math.Abs(sum)*besselEps {
+				break
+			}
+		}
+		rkmu = sum
+		rk1 = sum1 * xi2
+	} else {
+
+		// CF2 by Steed's method
+		b = 2.0 * (1.0 + x)
+		d = 1.0 / b
+		delh := d
+		h = d
+		q1, q2 := 0.0, 1.0
+		a1 := 0.25 - μ2
+		q := a1
+		c = a1
+		a := -a1
+		s := 1.0 + q*delh
+		for i = 1; i < besselMaxIt; i++ {
+			fi := float64(i)
+			a -= 2 * fi
+			c = -a * c / (fi + 1.0)
+			qnew := (q1 - b*q2) / a
+			q1 = q2
+			q2 = qnew
+			q += c * qnew
+			b += 2.0
+			d = 1.0 / (b + a*d)
+			delh = (b*d - 1.0) * delh
+			h += delh
+			dels := q * delh
+			s += dels
+			if math.Abs(dels/s) <= besselEps {
+				break
+			}
+		}
+		h = a1 * h
+		rkmu = math.Sqrt(math.Pi/(2.0*x)) * math.Exp(-x) / s
+		rk1 = rkmu * (μ + x + 0.5 - h) * xi
+	}
+
+	// scale and recur K upwards
+	rkmup := μ*xi*rkmu - rk1
+	rimu := xi / (f*rkmu - rkmup)
+	io = (rimu * ril1) / ril
+	ipo = (rimu * rip1) / ril
+	for i = 1; i <= nl; i++ {
+		rktemp := (μ+float64(i))*xi2*rk1 + rkmu
+		rkmu = rk1
+		rk1 = rktemp
+	}
+	ko = rkmu
+	kpo = ν*xi*rkmu - rk1
+	return
+}
+
+// besselIKseries computes Iν, Kν and derivatives for x < 2. Iν and I'ν are given by the power
+// series; Kν and K'ν are computed by Temme's method, whose upward recurrence may overflow
+func besselIKseries(ν, x float64) (i, k, ip, kp float64) {
+	i, ip = besselSeries(ν, x, +1)
+	_, k, _, kp = besselIKtemme(ν, x)
+	if math.IsNaN(k) {
+		k = math.Inf(+1)
+	}
+	if math.IsNaN(kp) {
+		kp = math.Inf(-1)
+	}
+	return
+}
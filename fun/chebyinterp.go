@@ -39,6 +39,10 @@ type ChebyInterp struct {
 	// constants
 	EstimationN int // N to use when estimating CoefP [default=128]
 
+	// domain
+	Xmin float64 // [default=-1] lower bound of the physical interval mapped onto [-1,1] (see F)
+	Xmax float64 // [default=+1] upper bound of the physical interval mapped onto [-1,1] (see F)
+
 	// derived
 	X     []float64 // points. NOTE: mirrowed version of Chebyshev X; i.e. from +1 to -1
 	Wb    []float64 // weights for Gaussian quadrature
@@ -90,6 +94,10 @@ func NewChebyInterp(N int, gaussChebyshev bool) (o *ChebyInterp) {
 	// constants
 	o.EstimationN = 128
 
+	// domain
+	o.Xmin = -1
+	o.Xmax = +1
+
 	// roots or points
 	var xx []float64
 	if gaussChebyshev {
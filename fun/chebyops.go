@@ -0,0 +1,294 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"github.com/lei006/gomath/chk"
+)
+
+// Chebfun-style operations with ChebyInterp
+//
+//	The functions and methods in this file work with the coefficients CoefI of the interpolant
+//	and with the physical coordinate x ∈ [Xmin, Xmax], which is mapped onto ξ ∈ [-1, 1] by
+//
+//	       2 x - (Xmin + Xmax)
+//	  ξ = —————————————————————
+//	          Xmax - Xmin
+//
+//	References:
+//	  [1] Trefethen LN (2013) Approximation Theory and Approximation Practice. SIAM. 305p
+//	  [2] Battles Z, Trefethen LN (2004) An extension of MATLAB to continuous functions and
+//	      operators, SIAM J. Sci. Comput., 25(5):1743-1770
+
+// NewChebyInterpAdapt returns a new ChebyInterp (Gauss-Lobatto) of f on [xmin, xmax] with
+// degree selected adaptively from the decay of the Chebyshev coefficients
+//
+//	The degree is doubled (N = 16, 32, 64, ...) until the last max(3, N/8) coefficients are
+//	smaller than tol⋅max|cₖ|. Then, the series is chopped after the last coefficient greater than
+//	tol⋅max|cₖ|. Note that, after chopping, CoefI are the coefficients of a truncated series and
+//	not of the interpolant at the new X points.
+//
+//	tol  -- relative tolerance; use 0 for default value [=1e-13]
+//	nmax -- maximum degree; use 0 for default value [=4096]
+//
+//	NOTE: an error is returned if the coefficients do not decay for N ≤ nmax. In this case, the
+//	      interpolant with the largest N is returned anyway
+func NewChebyInterpAdapt(f Ss, xmin, xmax, tol float64, nmax int) (o *ChebyInterp, err error) {
+	if xmax <= xmin {
+		return nil, chk.Err("xmax must be greater than xmin. [%g, %g] is invalid\n", xmin, xmax)
+	}
+	if tol <= 0 {
+		tol = 1e-13
+	}
+	if nmax <= 0 {
+		nmax = 4096
+	}
+	fmap := func(ξ float64) float64 {
+		return f(0.5 * ((xmax-xmin)*ξ + xmax + xmin))
+	}
+	for n := 16; ; n *= 2 {
+		if n > nmax {
+			n = nmax
+		}
+		o = NewChebyInterp(n, false)
+		o.Xmin, o.Xmax = xmin, xmax
+		o.CalcCoefI(fmap)
+		if m, ok := chebyChop(o.CoefI, tol); ok {
+			return NewChebyInterpCoef(o.CoefI[:m+1], xmin, xmax), nil
+		}
+		if n == nmax {
+			break
+		}
+	}
+	return o, chk.Err("Chebyshev coefficients did not converge with N ≤ %d\n", nmax)
+}
+
+// NewChebyInterpCoef returns a new ChebyInterp (Gauss-Lobatto) on [xmin, xmax] with given
+// coefficients CoefI
+//
+//	NOTE: the minimum degree is N = 1
+func NewChebyInterpCoef(coef []float64, xmin, xmax float64) (o *ChebyInterp) {
+	n := len(coef) - 1
+	if n < 1 {
+		n = 1
+	}
+	o = NewChebyInterp(n, false)
+	o.Xmin, o.Xmax = xmin, xmax
+	copy(o.CoefI, coef)
+	return
+}
+
+// F evaluates the interpolant at the physical coordinate x ∈ [Xmin, Xmax] with Clenshaw's
+// algorithm
+//
+//	NOTE: CoefI coefficients must be computed first
+func (o *ChebyInterp) F(x float64) float64 {
+	return o.clenshaw(o.toRef(x))
+}
+
+// Deriv returns the interpolant of the derivative dI{f}/dx (physical coordinate)
+func (o *ChebyInterp) Deriv() (d *ChebyInterp) {
+	p := o.GetPoly().Deriv().Scale(2.0 / (o.Xmax - o.Xmin))
+	return NewChebyInterpCoef(p.C, o.Xmin, o.Xmax)
+}
+
+// Integral computes the definite integral ∫ I{f}(x) dx over [Xmin, Xmax] (Clenshaw-Curtis)
+//
+//	 1                 │ 2/(1-k²)   if k is even
+//	 ∫ T_k(ξ) dξ  =    ┤
+//	-1                 │ 0          if k is odd
+func (o *ChebyInterp) Integral() (res float64) {
+	for k := 0; k < len(o.CoefI); k += 2 {
+		res += 2.0 * o.CoefI[k] / float64(1-k*k)
+	}
+	return res * (o.Xmax - o.Xmin) / 2.0
+}
+
+// Integ returns the indefinite integral F(x) = ∫ I{f}(x) dx from Xmin to x
+func (o *ChebyInterp) Integ() (F *ChebyInterp) {
+	p := o.GetPoly().Integ(-1).Scale((o.Xmax - o.Xmin) / 2.0)
+	return NewChebyInterpCoef(p.C, o.Xmin, o.Xmax)
+}
+
+// Roots returns all real roots of I{f} within [Xmin, Xmax] in ascending order
+//
+//	The roots are the eigenvalues of the colleague matrix (see Poly.Roots). A root of
+//	multiplicity m is perturbed into a cluster of m eigenvalues with radius of about ε^(1/m);
+//	e.g. a double root becomes a complex conjugate pair or two close real roots. Thus:
+//
+//	 (1) an eigenvalue z is taken as real if |Im(z)| ≤ 100⋅ε^(1/m)⋅max(1,|z|), where m ≤ 3 is
+//	     the number of eigenvalues within 2.5⋅|Im(z)| of z (including z itself)
+//	 (2) consecutive real roots ξ₁ < ξ₂ are merged into their mean if they belong to the same
+//	     cluster or if ξ₂ - ξ₁ ≤ 2√(δ/|a|), where a = p''(ξₘ)/2 at the midpoint ξₘ and
+//	     δ = 100⋅ε⋅Σ|cₖ| is the rounding level of p; i.e. if they cannot be distinguished from
+//	     a double root shifted by rounding errors
+//
+//	Hence, multiple roots are returned once.
+func (o *ChebyInterp) Roots() (roots []float64) {
+	const tolMin, tolRe, ε = 1e-8, 1e-12, 2.220446049250313e-16 // ε: machine epsilon
+	p := o.GetPoly()
+	eig := p.Roots()
+
+	// real eigenvalues with the radii of their clusters
+	type cand struct{ ξ, rad float64 }
+	var cands []cand
+	for _, z := range eig {
+		ξ, rad := real(z), 2.5*math.Abs(imag(z))
+		if ξ < -1.0-tolRe || ξ > 1.0+tolRe {
+			continue
+		}
+		m := 0
+		for _, w := range eig {
+			if cmplx.Abs(w-z) <= rad*(1.0+1e-8) && m < 3 {
+				m++
+			}
+		}
+		tolIm := math.Max(tolMin, 100.0*math.Pow(ε, 1.0/float64(m))*math.Max(1, cmplx.Abs(z)))
+		if math.Abs(imag(z)) > tolIm {
+			continue
+		}
+		cands = append(cands, cand{math.Max(-1, math.Min(1, ξ)), rad + 4.0*ε})
+	}
+	sort.Slice(cands, func(i, j int) bool { return cands[i].ξ < cands[j].ξ })
+
+	// merge multiple roots
+	δ := 0.0
+	for _, c := range o.CoefI {
+		δ += math.Abs(c)
+	}
+	δ *= 100.0 * ε
+	d2p := p.Deriv().Deriv()
+	same := func(a, b cand) bool {
+		dist := b.ξ - a.ξ
+		if dist <= math.Max(a.rad, b.rad) {
+			return true
+		}
+		curv := math.Abs(d2p.F(0.5*(a.ξ+b.ξ))) / 2.0
+		return dist*dist*curv <= 4.0*δ
+	}
+	for i := 0; i < len(cands); {
+		sum := cands[i].ξ
+		j := i + 1
+		for j < len(cands) && same(cands[j-1], cands[j]) {
+			sum += cands[j].ξ
+			j++
+		}
+		roots = append(roots, o.toPhys(sum/float64(j-i)))
+		i = j
+	}
+	return
+}
+
+// Max returns the global maximum of I{f} over [Xmin, Xmax] and its location
+func (o *ChebyInterp) Max() (fmax, xmax float64) {
+	fmax = math.Inf(-1)
+	for _, x := range o.criticalPoints() {
+		if fx := o.F(x); fx > fmax {
+			fmax, xmax = fx, x
+		}
+	}
+	return
+}
+
+// Min returns the global minimum of I{f} over [Xmin, Xmax] and its location
+func (o *ChebyInterp) Min() (fmin, xmin float64) {
+	fmin = math.Inf(+1)
+	for _, x := range o.criticalPoints() {
+		if fx := o.F(x); fx < fmin {
+			fmin, xmin = fx, x
+		}
+	}
+	return
+}
+
+// Add returns the interpolant of I{f} + I{g}
+//
+//	NOTE: the domains of both interpolants must be the same
+func (o *ChebyInterp) Add(g *ChebyInterp) (r *ChebyInterp) {
+	o.checkDomain(g)
+	return NewChebyInterpCoef(o.GetPoly().Add(g.GetPoly()).C, o.Xmin, o.Xmax)
+}
+
+// Sub returns the interpolant of I{f} - I{g}
+//
+//	NOTE: the domains of both interpolants must be the same
+func (o *ChebyInterp) Sub(g *ChebyInterp) (r *ChebyInterp) {
+	o.checkDomain(g)
+	return NewChebyInterpCoef(o.GetPoly().Sub(g.GetPoly()).C, o.Xmin, o.Xmax)
+}
+
+// Mul returns the interpolant of I{f} ⋅ I{g}; the degree of the result is the sum of degrees
+//
+//	NOTE: the domains of both interpolants must be the same
+func (o *ChebyInterp) Mul(g *ChebyInterp) (r *ChebyInterp) {
+	o.checkDomain(g)
+	return NewChebyInterpCoef(o.GetPoly().Mul(g.GetPoly()).C, o.Xmin, o.Xmax)
+}
+
+// Scale returns the interpolant of α ⋅ I{f}
+func (o *ChebyInterp) Scale(α float64) (r *ChebyInterp) {
+	return NewChebyInterpCoef(o.GetPoly().Scale(α).C, o.Xmin, o.Xmax)
+}
+
+// GetPoly returns the polynomial in the Chebyshev basis with coefficients CoefI
+//
+//	NOTE: the polynomial is written in terms of the reference coordinate ξ ∈ [-1, 1]
+func (o *ChebyInterp) GetPoly() *Poly {
+	return NewPolyCheby(o.CoefI...)
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// toRef maps x ∈ [Xmin, Xmax] onto ξ ∈ [-1, 1]
+func (o *ChebyInterp) toRef(x float64) float64 {
+	return (2.0*x - o.Xmin - o.Xmax) / (o.Xmax - o.Xmin)
+}
+
+// toPhys maps ξ ∈ [-1, 1] onto x ∈ [Xmin, Xmax]
+func (o *ChebyInterp) toPhys(ξ float64) float64 {
+	return 0.5 * ((o.Xmax-o.Xmin)*ξ + o.Xmax + o.Xmin)
+}
+
+// clenshaw evaluates Σ CoefI_k T_k(ξ)
+func (o *ChebyInterp) clenshaw(ξ float64) float64 {
+	var b1, b2 float64
+	for k := len(o.CoefI) - 1; k > 0; k-- {
+		b1, b2 = o.CoefI[k]+2.0*ξ*b1-b2, b1
+	}
+	return o.CoefI[0] + ξ*b1 - b2
+}
+
+// criticalPoints returns the endpoints and the roots of the derivative
+func (o *ChebyInterp) criticalPoints() []float64 {
+	return append([]float64{o.Xmin, o.Xmax}, o.Deriv().Roots()...)
+}
+
+// checkDomain panics if the domains of o and g are different
+func (o *ChebyInterp) checkDomain(g *ChebyInterp) {
+	if o.Xmin != g.Xmin || o.Xmax != g.Xmax {
+		chk.Panic("domains must be the same. [%g, %g] != [%g, %g]\n", o.Xmin, o.Xmax, g.Xmin, g.Xmax)
+	}
+}
+
+// chebyChop finds the index m of the last significant coefficient. ok is true if the trailing
+// max(3, N/8) coefficients are not significant, i.e. |cₖ| ≤ tol⋅max|cᵢ|
+func chebyChop(c []float64, tol float64) (m int, ok bool) {
+	n := len(c) - 1
+	thr := tol * maxAbsSlice(c)
+	m = n
+	for m > 0 && math.Abs(c[m]) <= thr {
+		m--
+	}
+	plateau := n / 8
+	if plateau < 3 {
+		plateau = 3
+	}
+	ok = n-m >= plateau
+	return
+}
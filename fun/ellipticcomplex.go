@@ -0,0 +1,249 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/lei006/gomath/chk"
+)
+
+// Elliptic integrals of complex arguments
+//
+//	The symmetric integrals of Carlson are computed with the duplication algorithms of [1], which
+//	are valid for complex arguments. The principal branches of the square roots are employed and
+//	the arguments must not lie on the negative real axis, except where noted. The Legendre forms
+//	are obtained from the Carlson forms as in the real case [2] after shifting Re(φ) to [-π/2, π/2]
+//	with the quasi-periodicity relations; e.g. F(φ + mπ, k) = F(φ, k) + 2 m K(k).
+//
+//	References
+//	[1] Carlson BC (1995) Numerical computation of real or complex elliptic integrals. Numerical
+//	     Algorithms, 10:13-26
+//	[2] Olver FWJ, Lozier DW, Boisvert RF, Clark CW (2010) NIST Handbook of Mathematical
+//	     Functions. Cambridge University Press. Chapter 19
+
+// Elliptic1C computes the Legendre elliptic integral of the first kind F(φ,k) for complex φ and k
+//
+//	F(φ, k) = sin(φ) Rf(cos²(φ), 1 - k² sin²(φ), 1)      |Re(φ)| ≤ π/2
+func Elliptic1C(φ, k complex128) complex128 {
+	m, φ := ellipticShiftC(φ)
+	s, c := cmplx.Sin(φ), cmplx.Cos(φ)
+	k2 := k * k
+	res := s * CarlsonRfC(c*c, 1-k2*s*s, 1)
+	if m != 0 {
+		res += complex(2*m, 0) * CarlsonRfC(0, 1-k2, 1)
+	}
+	return res
+}
+
+// Elliptic2C computes the Legendre elliptic integral of the second kind E(φ,k) for complex φ and k
+//
+//	E(φ, k) = sin(φ) Rf(c, q, 1) - k² sin³(φ) Rd(c, q, 1) / 3      |Re(φ)| ≤ π/2
+//
+//	where c = cos²(φ) and q = 1 - k² sin²(φ)
+func Elliptic2C(φ, k complex128) complex128 {
+	m, φ := ellipticShiftC(φ)
+	s, c := cmplx.Sin(φ), cmplx.Cos(φ)
+	k2 := k * k
+	cc, q := c*c, 1-k2*s*s
+	res := s * (CarlsonRfC(cc, q, 1) - k2*s*s*CarlsonRdC(cc, q, 1)/3)
+	if m != 0 {
+		res += complex(2*m, 0) * (CarlsonRfC(0, 1-k2, 1) - k2*CarlsonRdC(0, 1-k2, 1)/3)
+	}
+	return res
+}
+
+// Elliptic3C computes the Legendre elliptic integral of the third kind Π(n,φ,k) for complex n, φ
+// and k. The sign convention on n is the same as in Elliptic3
+//
+//	Π(n, φ, k) = sin(φ) (Rf(c, q, 1) + n sin²(φ) Rj(c, q, 1, 1 - n sin²(φ)) / 3)      |Re(φ)| ≤ π/2
+//
+//	where c = cos²(φ) and q = 1 - k² sin²(φ)
+func Elliptic3C(n, φ, k complex128) complex128 {
+	m, φ := ellipticShiftC(φ)
+	s, c := cmplx.Sin(φ), cmplx.Cos(φ)
+	k2 := k * k
+	cc, q, t := c*c, 1-k2*s*s, n*s*s
+	res := s * (CarlsonRfC(cc, q, 1) + t*CarlsonRjC(cc, q, 1, 1-t)/3)
+	if m != 0 {
+		res += complex(2*m, 0) * (CarlsonRfC(0, 1-k2, 1) + n*CarlsonRjC(0, 1-k2, 1, 1-n)/3)
+	}
+	return res
+}
+
+// CarlsonRfC computes Carlson's elliptic integral of the first kind Rf(x,y,z) for complex
+// arguments [1]. At most one of x, y, z can be zero
+//
+//	                ∞
+//	            1  ⌠                 dt
+//	Rf(x,y,z) = —  │  ——————————————————————————
+//	            2  ⌡  √((t + x) (t + y) (t + z))
+//	               0
+func CarlsonRfC(x, y, z complex128) complex128 {
+	if x == 0 && y == 0 || x == 0 && z == 0 || y == 0 && z == 0 {
+		chk.Panic("cannot compute Carlson's Rf function with x=%v, y=%v, z=%v. At most one can be zero", x, y, z)
+	}
+	a0 := (x + y + z) / 3
+	Q := math.Pow(3*ellipticTolC, -1.0/6.0) * maxAbsDiffC(a0, x, y, z)
+	a, f := a0, 1.0
+	var it int
+	for it = 0; it < ellipticMaxItC; it++ {
+		if f*Q < cmplx.Abs(a) {
+			break
+		}
+		λ := carlsonLambdaC(x, y, z)
+		x, y, z, a = 0.25*(x+λ), 0.25*(y+λ), 0.25*(z+λ), 0.25*(a+λ)
+		f *= 0.25
+	}
+	if it == ellipticMaxItC {
+		chk.Panic("CarlsonRfC failed to converge after %d iterations", it)
+	}
+	X := (a - x) / a // = 4⁻ᵐ (A₀ - x₀) / Aₘ
+	Y := (a - y) / a
+	Z := -X - Y
+	E2 := X*Y - Z*Z
+	E3 := X * Y * Z
+	return (1 - E2/10 + E3/14 + E2*E2/24 - 3*E2*E3/44) / cmplx.Sqrt(a)
+}
+
+// CarlsonRdC computes Carlson's elliptic integral of the second kind Rd(x,y,z) = Rj(x,y,z,z) for
+// complex arguments [1]. At most one of x, y can be zero and z must be non-zero
+func CarlsonRdC(x, y, z complex128) complex128 {
+	if x == 0 && y == 0 || z == 0 {
+		chk.Panic("cannot compute Carlson's Rd function with x=%v, y=%v, z=%v. At most one of x,y can be zero and z must be non-zero", x, y, z)
+	}
+	a0 := (x + y + 3*z) / 5
+	Q := math.Pow(0.25*ellipticTolC, -1.0/6.0) * maxAbsDiffC(a0, x, y, z)
+	a, f := a0, 1.0
+	var sum complex128
+	var it int
+	for it = 0; it < ellipticMaxItC; it++ {
+		if f*Q < cmplx.Abs(a) {
+			break
+		}
+		λ := carlsonLambdaC(x, y, z)
+		sum += complex(f, 0) / (cmplx.Sqrt(z) * (z + λ))
+		x, y, z, a = 0.25*(x+λ), 0.25*(y+λ), 0.25*(z+λ), 0.25*(a+λ)
+		f *= 0.25
+	}
+	if it == ellipticMaxItC {
+		chk.Panic("CarlsonRdC failed to converge after %d iterations", it)
+	}
+	X := (a - x) / a
+	Y := (a - y) / a
+	Z := -(X + Y) / 3
+	E2 := X*Y - 6*Z*Z
+	E3 := (3*X*Y - 8*Z*Z) * Z
+	E4 := 3 * (X*Y - Z*Z) * Z * Z
+	E5 := X * Y * Z * Z * Z
+	return complex(f, 0)*carlsonSeriesC(E2, E3, E4, E5)/(a*cmplx.Sqrt(a)) + 3*sum
+}
+
+// CarlsonRjC computes Carlson's elliptic integral of the third kind Rj(x,y,z,p) for complex
+// arguments [1]. At most one of x, y, z can be zero and p must be non-zero. If x, y and z are
+// real and non-negative and p is real and negative, the Cauchy principal value is returned
+//
+//	                  ∞
+//	              3  ⌠                      dt
+//	Rj(x,y,z,p) = —  │  —————————————————————————————————————
+//	              2  ⌡  (t + p) √((t + x) (t + y) (t + z))
+//	                 0
+func CarlsonRjC(x, y, z, p complex128) complex128 {
+	if isRealNonNeg(x) && isRealNonNeg(y) && isRealNonNeg(z) && imag(p) == 0 && real(p) < 0 {
+		return complex(CarlsonRj(real(x), real(y), real(z), real(p)), 0)
+	}
+	if x == 0 && y == 0 || x == 0 && z == 0 || y == 0 && z == 0 || p == 0 {
+		chk.Panic("cannot compute Carlson's Rj function with x=%v, y=%v, z=%v, p=%v. At most one of x,y,z can be zero and p must be non-zero", x, y, z, p)
+	}
+	a0 := (x + y + z + 2*p) / 5
+	δ := (p - x) * (p - y) * (p - z)
+	Q := math.Pow(0.25*ellipticTolC, -1.0/6.0) * math.Max(maxAbsDiffC(a0, x, y, z), cmplx.Abs(a0-p))
+	a, f, f3 := a0, 1.0, 1.0
+	var sum complex128
+	var it int
+	for it = 0; it < ellipticMaxItC; it++ {
+		if f*Q < cmplx.Abs(a) {
+			break
+		}
+		sx, sy, sz, sp := cmplx.Sqrt(x), cmplx.Sqrt(y), cmplx.Sqrt(z), cmplx.Sqrt(p)
+		λ := sx*sy + sx*sz + sy*sz
+		d := (sp + sx) * (sp + sy) * (sp + sz)
+		e := complex(f3, 0) * δ / (d * d)
+		sum += complex(f, 0) * CarlsonRcC(1, 1+e) / d
+		x, y, z, p, a = 0.25*(x+λ), 0.25*(y+λ), 0.25*(z+λ), 0.25*(p+λ), 0.25*(a+λ)
+		f *= 0.25
+		f3 *= 1.0 / 64.0
+	}
+	if it == ellipticMaxItC {
+		chk.Panic("CarlsonRjC failed to converge after %d iterations", it)
+	}
+	X := (a - x) / a
+	Y := (a - y) / a
+	Z := (a - z) / a
+	P := -(X + Y + Z) / 2
+	E2 := X*Y + X*Z + Y*Z - 3*P*P
+	E3 := X*Y*Z + 2*E2*P + 4*P*P*P
+	E4 := (2*X*Y*Z + E2*P + 3*P*P*P) * P
+	E5 := X * Y * Z * P * P
+	return complex(f, 0)*carlsonSeriesC(E2, E3, E4, E5)/(a*cmplx.Sqrt(a)) + 6*sum
+}
+
+// CarlsonRcC computes Carlson's degenerate elliptic integral Rc(x,y) = Rf(x,y,y) for complex
+// arguments. y must be non-zero. If y is real and negative, the Cauchy principal value is returned
+func CarlsonRcC(x, y complex128) complex128 {
+	if y == 0 {
+		chk.Panic("cannot compute Carlson's Rc function with x=%v, y=%v. y must be non-zero", x, y)
+	}
+	if imag(y) == 0 && real(y) < 0 {
+		return cmplx.Sqrt(x/(x-y)) * CarlsonRfC(x-y, -y, -y)
+	}
+	return CarlsonRfC(x, y, y)
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// constants for the elliptic integrals of complex arguments
+const (
+	ellipticTolC   = 1e-16 // relative error bound r in [1]
+	ellipticMaxItC = 100
+)
+
+// ellipticShiftC returns m and ψ = φ - mπ such that -π/2 ≤ Re(ψ) ≤ π/2. On the lines
+// Re(ψ) = ±π/2, the side on which cos²(ψ) approaches the negative real axis from above is taken,
+// i.e. the one consistent with the principal square root
+func ellipticShiftC(φ complex128) (m float64, ψ complex128) {
+	m = math.Round(real(φ) / math.Pi)
+	ψ = φ - complex(m*math.Pi, 0)
+	if real(ψ) == math.Pi/2 && imag(ψ) > 0 {
+		m++
+		ψ -= complex(math.Pi, 0)
+	} else if real(ψ) == -math.Pi/2 && imag(ψ) < 0 {
+		m--
+		ψ += complex(math.Pi, 0)
+	}
+	return
+}
+
+// carlsonLambdaC computes λ = √x √y + √x √z + √y √z
+func carlsonLambdaC(x, y, z complex128) complex128 {
+	sx, sy, sz := cmplx.Sqrt(x), cmplx.Sqrt(y), cmplx.Sqrt(z)
+	return sx*sy + sx*sz + sy*sz
+}
+
+// carlsonSeriesC computes the series of Rd and Rj in [1]
+func carlsonSeriesC(E2, E3, E4, E5 complex128) complex128 {
+	return 1 - 3*E2/14 + E3/6 + 9*E2*E2/88 - 3*E4/22 - 9*E2*E3/52 + 3*E5/26
+}
+
+// maxAbsDiffC returns max(|a - x|, |a - y|, |a - z|)
+func maxAbsDiffC(a, x, y, z complex128) float64 {
+	return math.Max(math.Max(cmplx.Abs(a-x), cmplx.Abs(a-y)), cmplx.Abs(a-z))
+}
+
+// isRealNonNeg tells whether z is real and non-negative
+func isRealNonNeg(z complex128) bool {
+	return imag(z) == 0 && real(z) >= 0
+}
@@ -0,0 +1,361 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import "math"
+
+// Jacobi elliptic functions, complete elliptic integrals, nome and theta functions
+//
+//	The modulus k is employed (and not the parameter m = k²), as in Elliptic1, Elliptic2 and
+//	Elliptic3. The complementary modulus is k' = √(1 - k²).
+//
+//	References:
+//	[1] Abramowitz M, Stegun IA (1972) Handbook of Mathematical Functions with Formulas, Graphs,
+//	    and Mathematical Tables. U.S. Department of Commerce, NIST
+//	[2] Olver FWJ et al. (2010) NIST Handbook of Mathematical Functions. Cambridge University
+//	    Press. Chapters 19, 20 and 22
+
+// constants for the AGM iterations
+const (
+	agmMaxIt = 64
+	agmEps   = 2.220446049250313e-16
+)
+
+// EllipticK computes the complete elliptic integral of the first kind K(k) = F(π/2, k) with the
+// arithmetic-geometric mean (AGM) [1]
+//
+//	                 π/2
+//	                ⌠          dt               π
+//	      K(k)  =   │  ___________________ = ————————————
+//	                │     _______________    2 AGM(1,k')
+//	                ⌡   \╱ 1 - k² sin²(t)
+//	               0
+//
+//	Special cases:
+//	  K(0) = π/2
+//	  K(±1) = +Inf
+//	  |k| > 1 ⇒ NaN
+func EllipticK(k float64) float64 {
+	k = math.Abs(k)
+	if math.IsNaN(k) || k > 1 {
+		return math.NaN()
+	}
+	if k == 1 {
+		return math.Inf(+1)
+	}
+	a, _ := agmSeq(1, ellipticKp(k), nil)
+	return math.Pi / (2.0 * a)
+}
+
+// EllipticE computes the complete elliptic integral of the second kind E(k) = E(π/2, k) with the
+// arithmetic-geometric mean (AGM) [1]
+//
+//	                 π/2
+//	                ⌠     _______________               ∞   n-1  2
+//	      E(k)  =   │   \╱ 1 - k² sin²(t)  dt = K(k) (1 - Σ  2    cₙ )
+//	                ⌡                                  n=0
+//	               0
+//
+//	where cₙ = (aₙ₋₁ - bₙ₋₁)/2 are computed in the AGM sequence with c₀ = k.
+//
+//	Special cases:
+//	  E(0) = π/2
+//	  E(±1) = 1
+//	  |k| > 1 ⇒ NaN
+func EllipticE(k float64) float64 {
+	k = math.Abs(k)
+	if math.IsNaN(k) || k > 1 {
+		return math.NaN()
+	}
+	if k == 1 {
+		return 1
+	}
+	sum := 0.5 * k * k
+	a, _ := agmSeq(1, ellipticKp(k), func(n int, c float64) {
+		sum += math.Ldexp(c*c, n-1)
+	})
+	return math.Pi / (2.0 * a) * (1.0 - sum)
+}
+
+// EllipticKE computes both complete elliptic integrals K(k) and E(k) with a single AGM sequence
+func EllipticKE(k float64) (K, E float64) {
+	k = math.Abs(k)
+	if math.IsNaN(k) || k > 1 {
+		return math.NaN(), math.NaN()
+	}
+	if k == 1 {
+		return math.Inf(+1), 1
+	}
+	sum := 0.5 * k * k
+	a, _ := agmSeq(1, ellipticKp(k), func(n int, c float64) {
+		sum += math.Ldexp(c*c, n-1)
+	})
+	K = math.Pi / (2.0 * a)
+	E = K * (1.0 - sum)
+	return
+}
+
+// EllipticPi computes the complete elliptic integral of the third kind Π(n,k) = Π(n, π/2, k) with
+// the AGM method of [2, Eq. 19.8.6]. The sign convention on n is the same as in Elliptic3.
+//
+//	                    π/2
+//	                   ⌠                  dt
+//	      Π(n, k)  =   │  ___________________________________
+//	                   │                     _______________
+//	                   ⌡   (1 - n sin²(t)) \╱ 1 - k² sin²(t)
+//	                  0
+//	where:
+//	         n < 1  and  |k| < 1
+//
+//	Special cases:
+//	  Π(0,k) = K(k)
+//	  n ≥ 1 or |k| ≥ 1 ⇒ +Inf if n = 1 or |k| = 1; NaN otherwise
+func EllipticPi(n, k float64) float64 {
+	k = math.Abs(k)
+	if math.IsNaN(n) || math.IsNaN(k) || k > 1 || n > 1 {
+		return math.NaN()
+	}
+	if k == 1 || n == 1 {
+		return math.Inf(+1)
+	}
+	if n == 0 {
+		return EllipticK(k)
+	}
+	a, g := 1.0, ellipticKp(k)
+	p2 := 1.0 - n
+	p := math.Sqrt(p2)
+	Q, sum := 1.0, 1.0
+	for it := 0; it < agmMaxIt; it++ {
+		ag := a * g
+		ε := (p2 - ag) / (p2 + ag)
+		p = (p2 + ag) / (2.0 * p)
+		p2 = p * p
+		a, g = 0.5*(a+g), math.Sqrt(ag)
+		Q *= 0.5 * ε
+		sum += Q
+		if math.Abs(Q) <= agmEps*math.Abs(sum) && math.Abs(a-g) <= agmEps*a {
+			break
+		}
+	}
+	return math.Pi / (4.0 * a) * (2.0 + n/(1.0-n)*sum)
+}
+
+// Jacobi computes the Jacobi elliptic functions sn(u,k), cn(u,k), dn(u,k) and the amplitude
+// φ = am(u,k) such that u = F(φ,k), sn = sin(φ) and cn = cos(φ)
+//
+//	The descending Landen transformation (AGM) of [1, 16.4] is employed:
+//
+//	  φₙ = 2ⁿ aₙ u   and   φₘ₋₁ = (φₘ + asin(cₘ sin(φₘ) / aₘ)) / 2
+//
+//	  dn = √(k'² + k² cn²)
+//
+//	where 0 ≤ |k| ≤ 1 and any real u is accepted.
+//
+//	Special cases:
+//	  k = 0 ⇒ sn = sin(u),  cn = cos(u),  dn = 1,       am = u
+//	  k = 1 ⇒ sn = tanh(u), cn = sech(u), dn = sech(u), am = gd(u)
+func Jacobi(u, k float64) (sn, cn, dn, am float64) {
+	k = math.Abs(k)
+	if math.IsNaN(u) || math.IsNaN(k) || k > 1 {
+		nan := math.NaN()
+		return nan, nan, nan, nan
+	}
+	if k == 0 {
+		sn, cn = math.Sincos(u)
+		return sn, cn, 1, u
+	}
+	if k == 1 {
+		sn = math.Tanh(u)
+		cn = 1.0 / math.Cosh(u)
+		return sn, cn, cn, 2.0 * math.Atan(math.Tanh(0.5*u))
+	}
+	var aa, cc [agmMaxIt]float64
+	kp := ellipticKp(k)
+	a, b, c := 1.0, kp, k
+	nn := 0
+	for ; nn < agmMaxIt-1; nn++ {
+		aa[nn], cc[nn] = a, c
+		if math.Abs(c) <= agmEps*a {
+			break
+		}
+		a, b, c = 0.5*(a+b), math.Sqrt(a*b), 0.5*(a-b)
+	}
+	φ := math.Ldexp(aa[nn]*u, nn)
+	for m := nn; m > 0; m-- {
+		φ = 0.5 * (φ + math.Asin(cc[m]*math.Sin(φ)/aa[m]))
+	}
+	sn, cn = math.Sincos(φ)
+	dn = math.Sqrt(kp*kp + k*k*cn*cn)
+	return sn, cn, dn, φ
+}
+
+// JacobiSn returns the Jacobi elliptic function sn(u,k)
+func JacobiSn(u, k float64) float64 {
+	sn, _, _, _ := Jacobi(u, k)
+	return sn
+}
+
+// JacobiCn returns the Jacobi elliptic function cn(u,k)
+func JacobiCn(u, k float64) float64 {
+	_, cn, _, _ := Jacobi(u, k)
+	return cn
+}
+
+// JacobiDn returns the Jacobi elliptic function dn(u,k)
+func JacobiDn(u, k float64) float64 {
+	_, _, dn, _ := Jacobi(u, k)
+	return dn
+}
+
+// JacobiAm returns the amplitude φ = am(u,k), i.e. the inverse of u = F(φ,k)
+func JacobiAm(u, k float64) float64 {
+	_, _, _, am := Jacobi(u, k)
+	return am
+}
+
+// EllipticNome returns the nome q(k) = exp(-π K(k')/K(k)) for 0 ≤ |k| < 1
+//
+//	Special cases:
+//	  q(0) = 0
+//	  q(±1) = 1
+func EllipticNome(k float64) float64 {
+	k = math.Abs(k)
+	if math.IsNaN(k) || k > 1 {
+		return math.NaN()
+	}
+	if k == 0 {
+		return 0
+	}
+	if k == 1 {
+		return 1
+	}
+	return math.Exp(-math.Pi * EllipticK(ellipticKp(k)) / EllipticK(k))
+}
+
+// JacobiTheta returns the Jacobi theta function θⱼ(z,q) for j = 1, 2, 3, 4 and nome 0 ≤ q < 1 [2]
+//
+//	                ∞     n  (n+½)²                           ∞   (n+½)²
+//	  θ₁(z,q) = 2   Σ  (-1)  q       sin((2n+1)z)   θ₂ = 2   Σ  q       cos((2n+1)z)
+//	               n=0                                       n=0
+//
+//	                    ∞   n²                                   ∞     n  n²
+//	  θ₃(z,q) = 1 + 2   Σ  q   cos(2nz)             θ₄ = 1 + 2   Σ  (-1)  q   cos(2nz)
+//	                   n=1                                      n=1
+//
+//	The Jacobi elliptic functions are recovered from the theta functions; e.g.
+//
+//	  sn(u,k) = θ₃(0,q) θ₁(v,q) / (θ₂(0,q) θ₄(v,q))   with   v = πu/(2K)
+//
+//	The series are summed directly for q ≤ exp(-π) only; for larger q (in particular q → 1), the
+//	modular transformation τ → -1/τ [2, Eqs. 20.7.30-20.7.33] is employed (see jacobiThetaModular)
+func JacobiTheta(j int, z, q float64) float64 {
+	if j < 1 || j > 4 {
+		return math.NaN()
+	}
+	if math.IsNaN(z) || math.IsNaN(q) || q < 0 || q >= 1 {
+		return math.NaN()
+	}
+	if q == 0 {
+		switch j {
+		case 1, 2:
+			return 0
+		default:
+			return 1
+		}
+	}
+	lq := math.Log(q)
+	if lq > -math.Pi {
+		return jacobiThetaModular(j, z, -lq/math.Pi)
+	}
+	var sum float64
+	switch j {
+	case 1, 2:
+		for n := 0; n < 1000; n++ {
+			h := float64(n) + 0.5
+			t := math.Exp(h * h * lq)
+			if j == 1 {
+				sum += NegOnePowN(n) * t * math.Sin(2.0*h*z)
+			} else {
+				sum += t * math.Cos(2.0*h*z)
+			}
+			if t <= agmEps*math.Abs(sum) || t == 0 {
+				break
+			}
+		}
+		return 2.0 * sum
+	default:
+		sum = 1.0
+		for n := 1; n < 1000; n++ {
+			fn := float64(n)
+			t := math.Exp(fn * fn * lq)
+			if j == 4 {
+				sum += 2.0 * NegOnePowN(n) * t * math.Cos(2.0*fn*z)
+			} else {
+				sum += 2.0 * t * math.Cos(2.0*fn*z)
+			}
+			if t <= agmEps*math.Abs(sum) {
+				break
+			}
+		}
+		return sum
+	}
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// jacobiThetaModular computes θⱼ(z,q) with q = exp(-πt) by the modular transformation of the
+// theta functions [2, Eqs. 20.7.30-20.7.33]. The transformed series are written as sums of
+// Gaussians that converge quickly for t < 1 and do not overflow:
+//
+//	                    ∞                                        ∞      n
+//	  θ₃(z,q) = 1/√t    Σ  exp(-(z - nπ)²/(πt))    θ₂ = 1/√t    Σ  (-1)  exp(-(z - nπ)²/(πt))
+//	                  n=-∞                                      n=-∞
+//
+//	and θ₄, θ₁ are given by the same sums with nπ replaced by (n+½)π
+func jacobiThetaModular(j int, z, t float64) float64 {
+	shift := 0.0
+	if j == 1 || j == 4 {
+		shift = 0.5
+	}
+	term := func(n float64) float64 {
+		d := z - (n+shift)*math.Pi
+		v := math.Exp(-d * d / (math.Pi * t))
+		if (j == 1 || j == 2) && math.Mod(n, 2) != 0 {
+			return -v
+		}
+		return v
+	}
+	n0 := math.Round(z/math.Pi - shift) // largest term
+	sum := term(n0)
+	for k := 1.0; k < 1000; k++ {
+		tp, tm := term(n0+k), term(n0-k)
+		sum += tp + tm
+		if math.Abs(tp)+math.Abs(tm) <= agmEps*math.Abs(sum) {
+			break
+		}
+	}
+	return sum / math.Sqrt(t)
+}
+
+// ellipticKp returns the complementary modulus k' = √(1 - k²) avoiding cancellation near k = 1
+func ellipticKp(k float64) float64 {
+	return math.Sqrt((1.0 - k) * (1.0 + k))
+}
+
+// agmSeq runs the arithmetic-geometric mean sequence starting at (a, b) and returns the limits
+// (aₙ, bₙ). The callback, if not nil, receives cₙ = (aₙ₋₁ - bₙ₋₁)/2 for n ≥ 1.
+func agmSeq(a, b float64, cb func(n int, c float64)) (float64, float64) {
+	for n := 1; n <= agmMaxIt; n++ {
+		c := 0.5 * (a - b)
+		a, b = 0.5*(a+b), math.Sqrt(a*b)
+		if cb != nil {
+			cb(n, c)
+		}
+		if math.Abs(c) <= agmEps*a {
+			break
+		}
+	}
+	return a, b
+}
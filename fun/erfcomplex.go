@@ -0,0 +1,118 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Faddeeva computes the Faddeeva function w(z) (scaled complementary error function)
+//
+//	w(z) = exp(-z²) erfc(-i z)
+//
+//	The rational approximation of Weideman [1] with N = 64 terms is employed for Im(z) ≥ 0; the
+//	lower half-plane is obtained with w(z) = 2 exp(-z²) - w(-z).
+//
+//	References:
+//	  [1] Weideman JAC (1994) Computation of the complex error function, SIAM Journal on
+//	      Numerical Analysis, 31(5):1497-1518
+func Faddeeva(z complex128) complex128 {
+	if imag(z) < 0 {
+		return 2*cmplx.Exp(-z*z) - Faddeeva(-z)
+	}
+	L := complex(faddeevaL, 0)
+	iz := complex(-imag(z), real(z)) // i⋅z
+	Z := (L + iz) / (L - iz)
+	var p complex128
+	for n := len(faddeevaCoef) - 1; n >= 0; n-- {
+		p = p*Z + complex(faddeevaCoef[n], 0)
+	}
+	d := 1 / (L - iz)
+	return 2*p*d*d + complex(1/math.SqrtPi, 0)*d
+}
+
+// ErfC computes the error function erf(z) of a complex argument
+//
+//	          2     z
+//	erf(z) = ———  ∫  exp(-t²) dt
+//	         √π    0
+//
+//	NOTE: this is not the complementary error function; see ErfcC
+func ErfC(z complex128) complex128 {
+	if cmplx.Abs(z) < 0.5 {
+		return erfSeriesC(z)
+	}
+	return 1 - ErfcC(z)
+}
+
+// ErfcC computes the complementary error function erfc(z) = 1 - erf(z) of a complex argument
+//
+//	erfc(z) = exp(-z²) w(i z)      for Re(z) ≥ 0
+//	erfc(z) = 2 - erfc(-z)         for Re(z) < 0
+func ErfcC(z complex128) complex128 {
+	if real(z) < 0 {
+		return 2 - ErfcC(-z)
+	}
+	if cmplx.Abs(z) < 0.5 {
+		return 1 - erfSeriesC(z)
+	}
+	return cmplx.Exp(-z*z) * Faddeeva(complex(-imag(z), real(z)))
+}
+
+// ErfcxC computes the scaled complementary error function erfcx(z) = exp(z²) erfc(z) = w(i z)
+func ErfcxC(z complex128) complex128 {
+	return Faddeeva(complex(-imag(z), real(z)))
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// erfSeriesC computes erf(z) with the Maclaurin series (for small |z|)
+//
+//	erf(z) = 2/√π Σ (-1)ⁿ z²ⁿ⁺¹ / (n! (2n+1))
+func erfSeriesC(z complex128) complex128 {
+	z2 := z * z
+	t := z
+	sum := z
+	for n := 1; n < 100; n++ {
+		t *= -z2 / complex(float64(n), 0)
+		del := t / complex(float64(2*n+1), 0)
+		sum += del
+		if cmplx.Abs(del) < 1e-17*cmplx.Abs(sum) {
+			break
+		}
+	}
+	return complex(2/math.SqrtPi, 0) * sum
+}
+
+// faddeevaN is the number of terms in Weideman's approximation
+const faddeevaN = 64
+
+// faddeevaL is the optimal parameter L = √(N/√2) of Weideman's approximation
+var faddeevaL = math.Sqrt(faddeevaN / math.Sqrt2)
+
+// faddeevaCoef holds the coefficients aₙ (n = 1...N) of Weideman's approximation
+//
+//	aₙ = (1/2M) Σ f(tₖ) cos(π k n / M)    k = -M+1...M-1    M = 2N
+//
+//	with f(t) = exp(-t²) (L² + t²) and tₖ = L tan(π k / (2M))
+var faddeevaCoef = func() (a []float64) {
+	M := 2 * faddeevaN
+	L := faddeevaL
+	f := make([]float64, M)
+	for k := 0; k < M; k++ {
+		t := L * math.Tan(0.5*float64(k)*math.Pi/float64(M))
+		f[k] = math.Exp(-t*t) * (L*L + t*t)
+	}
+	a = make([]float64, faddeevaN)
+	for n := 1; n <= faddeevaN; n++ {
+		sum := f[0]
+		for k := 1; k < M; k++ {
+			sum += 2 * f[k] * math.Cos(math.Pi*float64(k*n)/float64(M))
+		}
+		a[n-1] = sum / float64(2*M)
+	}
+	return
+}()
@@ -0,0 +1,254 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import "math"
+
+// Exponential and Fresnel integrals
+//
+//	Reference
+//	[1] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes: The Art of
+//	     Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+
+// constants for exponential and Fresnel integrals
+const (
+	expintMaxIt = 100
+	expintEuler = 0.5772156649015329 // Euler-Mascheroni constant γ
+	expintEps   = 2.220446049250313e-16
+	expintBig   = math.MaxFloat64 * expintEps
+)
+
+// ExpIntE1 returns the exponential integral E₁(x) for x > 0
+//
+//	         ∞  -t
+//	E₁(x) =  ∫ e   / t dt
+//	         x
+func ExpIntE1(x float64) float64 {
+	return ExpIntEn(1, x)
+}
+
+// ExpIntEn returns the exponential integral Eₙ(x) for n ≥ 0 and x ≥ 0 [1]
+//
+//	         ∞  -xt   n
+//	Eₙ(x) =  ∫ e   / t  dt
+//	         1
+//
+//	Special cases:
+//	  E₀(0) = E₁(0) = +Inf
+//	  Eₙ(0) = 1/(n-1)  for n > 1
+//	  n < 0 or x < 0 ⇒ NaN
+func ExpIntEn(n int, x float64) float64 {
+	if n < 0 || x < 0 || math.IsNaN(x) {
+		return math.NaN()
+	}
+	nm1 := n - 1
+	if x == 0 {
+		if n == 0 || n == 1 {
+			return math.Inf(+1)
+		}
+		return 1.0 / float64(nm1)
+	}
+	if n == 0 {
+		return math.Exp(-x) / x
+	}
+	if math.IsInf(x, +1) {
+		return 0
+	}
+
+	// continued fraction (Lentz's method)
+	if x > 1.0 {
+		b := x + float64(n)
+		c := expintBig
+		d := 1.0 / b
+		h := d
+		for i := 1; i <= 10*expintMaxIt; i++ {
+			a := -float64(i) * float64(nm1+i)
+			b += 2.0
+			d = 1.0 / (a*d + b)
+			c = b + a/c
+			del := c * d
+			h *= del
+			if math.Abs(del-1.0) <= expintEps {
+				break
+			}
+		}
+		return h * math.Exp(-x)
+	}
+
+	// series
+	var ans float64
+	if nm1 != 0 {
+		ans = 1.0 / float64(nm1)
+	} else {
+		ans = -math.Log(x) - expintEuler
+	}
+	fact := 1.0
+	for i := 1; i <= expintMaxIt; i++ {
+		fact *= -x / float64(i)
+		var del float64
+		if i != nm1 {
+			del = -fact / float64(i-nm1)
+		} else {
+			psi := -expintEuler
+			for ii := 1; ii <= nm1; ii++ {
+				psi += 1.0 / float64(ii)
+			}
+			del = fact * (-math.Log(x) + psi)
+		}
+		ans += del
+		if math.Abs(del) < math.Abs(ans)*expintEps {
+			break
+		}
+	}
+	return ans
+}
+
+// ExpIntEi returns the exponential integral Ei(x) [1]
+//
+//	            x   t
+//	Ei(x) = PV  ∫  e / t dt     and   Ei(x) = -E₁(-x)  for x < 0
+//	          -∞
+//
+//	Special cases:
+//	  Ei(0) = -Inf
+//	  Ei(+Inf) = +Inf
+func ExpIntEi(x float64) float64 {
+	if math.IsNaN(x) {
+		return math.NaN()
+	}
+	if x == 0 {
+		return math.Inf(-1)
+	}
+	if x < 0 {
+		return -ExpIntE1(-x)
+	}
+	if math.IsInf(x, +1) {
+		return x
+	}
+	if x < 2.2250738585072014e-308/expintEps {
+		return math.Log(x) + expintEuler
+	}
+
+	// power series
+	if x <= -math.Log(expintEps) {
+		sum, fact := 0.0, 1.0
+		for k := 1; k <= expintMaxIt; k++ {
+			fact *= x / float64(k)
+			term := fact / float64(k)
+			sum += term
+			if term < expintEps*sum {
+				break
+			}
+		}
+		return sum + math.Log(x) + expintEuler
+	}
+
+	// asymptotic series
+	sum, term := 0.0, 1.0
+	for k := 1; k <= expintMaxIt; k++ {
+		prev := term
+		term *= float64(k) / x
+		if term < expintEps {
+			break
+		}
+		if term < prev {
+			sum += term
+		} else {
+			sum -= prev
+			break
+		}
+	}
+	return math.Exp(x) * (1.0 + sum) / x
+}
+
+// FresnelS returns the Fresnel sine integral S(x) = ∫₀ˣ sin(πt²/2) dt
+func FresnelS(x float64) float64 {
+	s, _ := Fresnel(x)
+	return s
+}
+
+// FresnelC returns the Fresnel cosine integral C(x) = ∫₀ˣ cos(πt²/2) dt
+func FresnelC(x float64) float64 {
+	_, c := Fresnel(x)
+	return c
+}
+
+// Fresnel returns the Fresnel integrals S(x) and C(x) [1]
+//
+//	        x      π t²                x      π t²
+//	S(x) =  ∫ sin(————) dt     C(x) =  ∫ cos(————) dt
+//	        0       2                  0       2
+//
+//	The power series is employed for |x| ≤ 1.5 and the continued fraction for the complementary
+//	error function erfc otherwise.
+func Fresnel(x float64) (s, c float64) {
+	const xmin = 1.5
+	if math.IsNaN(x) {
+		return math.NaN(), math.NaN()
+	}
+	ax := math.Abs(x)
+	switch {
+	case ax < 1.4916681462400413e-154: // sqrt(smallest normal)
+		s, c = 0, ax
+
+	case ax <= xmin:
+		sum, sums, sumc := 0.0, 0.0, ax
+		sign := 1.0
+		fact := 0.5 * math.Pi * ax * ax
+		odd := true
+		term := ax
+		n := 3.0
+		for k := 1; k <= expintMaxIt; k++ {
+			term *= fact / float64(k)
+			sum += sign * term / n
+			test := math.Abs(sum) * expintEps
+			if odd {
+				sign = -sign
+				sums = sum
+				sum = sumc
+			} else {
+				sumc = sum
+				sum = sums
+			}
+			if term < test {
+				break
+			}
+			odd = !odd
+			n += 2.0
+		}
+		s, c = sums, sumc
+
+	case math.IsInf(ax, +1):
+		s, c = 0.5, 0.5
+
+	default:
+		pix2 := math.Pi * ax * ax
+		b := complex(1.0, -pix2)
+		cc := complex(expintBig, 0)
+		d := 1.0 / b
+		h := d
+		n := -1.0
+		for k := 2; k <= expintMaxIt; k++ {
+			n += 2.0
+			a := complex(-n*(n+1.0), 0)
+			b += 4.0
+			d = 1.0 / (a*d + b)
+			cc = b + a/cc
+			del := cc * d
+			h *= del
+			if math.Abs(real(del)-1.0)+math.Abs(imag(del)) <= expintEps {
+				break
+			}
+		}
+		h *= complex(ax, -ax)
+		sn, cs := math.Sincos(0.5 * pix2)
+		cs2 := complex(0.5, 0.5) * (1.0 - complex(cs, sn)*h)
+		s, c = imag(cs2), real(cs2)
+	}
+	if x < 0 {
+		s, c = -s, -c
+	}
+	return
+}
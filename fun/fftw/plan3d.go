@@ -0,0 +1,117 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fftw
+
+/*
+#include "fftw3.h"
+*/
+import "C"
+
+import "unsafe"
+
+// Plan3d implements the FFTW3 plan structure; i.e. a "plan" to compute direct or inverse 3D FTs
+//
+//	Computes:
+//	                  N2-1 N1-1 N0-1                 -i 2 π k2 l2 / N2    -i 2 π k1 l1 / N1    -i 2 π k0 l0 / N0
+//	  X[l0,l1,l2] =    Σ    Σ    Σ  x[k0,k1,k2] ⋅ e                  ⋅ e                  ⋅ e
+//	                  k2=0 k1=0 k0=0
+type Plan3d struct {
+	p    C.fftw_plan  // FFTW "plan" structure
+	n0   int          // length along first dimension
+	n1   int          // length along second dimension
+	n2   int          // length along third dimension
+	data []complex128 // input (row-major 3D array)
+}
+
+// NewPlan3d allocates a new "plan" to compute 3D Fourier Transforms
+//
+//	N0, N1, N2 -- dimensions
+//	data       -- [modified] data is a complex array of length N0*N1*N2 (row-major 3D array)
+//	inverse    -- will perform inverse transform; otherwise will perform direct
+//	              Note: both transforms are non-normalized;
+//	              i.e. the user will have to multiply by (1/n) if computing inverse transforms
+//	measure    -- use the FFTW_MEASURE flag for better optimization analysis (slower initialization times)
+//	              Note: using this flag with given "data" as input will cause the allocation
+//	              of a temporary array and the execution of two copy commands with size len(data)
+//
+//	NOTE: (1) the user must remember to call Free to deallocate FFTW data
+//	      (2) data will be overwritten
+//
+//	A = data is a ROW-MAJOR 3D array; i.e. the last index varies fastest
+//
+//	l = (n1⋅i + j)⋅n2 + k       i = l // (n1⋅n2)      j = (l // n2) % n1      k = l % n2
+func NewPlan3d(N0, N1, N2 int, data []complex128, inverse, measure bool) (o *Plan3d) {
+
+	// allocate new object
+	o = new(Plan3d)
+	o.n0 = N0
+	o.n1 = N1
+	o.n2 = N2
+	o.data = data
+
+	// set flags
+	var sign C.int = C.FFTW_FORWARD
+	var flag C.uint = C.FFTW_ESTIMATE
+	if inverse {
+		sign = C.FFTW_BACKWARD
+	}
+	if measure {
+		flag = C.FFTW_MEASURE
+	}
+
+	// the measure flag will change the input; thus a temporary is required
+	var temp []complex128
+	if measure {
+		temp = make([]complex128, len(data))
+		copy(temp, data)
+	}
+
+	// set FFTW plan
+	d := (*C.fftw_complex)(unsafe.Pointer(&o.data[0]))
+	o.p = C.fftw_plan_dft_3d(C.int(N0), C.int(N1), C.int(N2), d, d, sign, flag)
+
+	// fix data (changed by 'measure')
+	if measure {
+		copy(data, temp)
+	}
+	return
+}
+
+// Free frees internal FFTW data
+func (o *Plan3d) Free() {
+	if o.p != nil {
+		C.fftw_destroy_plan(o.p)
+	}
+}
+
+// Set sets data value located at "i,j,k". NOTE: this method does not check for out-of-range indices
+func (o *Plan3d) Set(i, j, k int, v complex128) {
+	o.data[(o.n1*i+j)*o.n2+k] = v
+}
+
+// Get gets data value located at "i,j,k". NOTE: this method does not check for out-of-range indices
+func (o *Plan3d) Get(i, j, k int) (v complex128) {
+	return o.data[(o.n1*i+j)*o.n2+k]
+}
+
+// Execute performs the Fourier transform
+func (o *Plan3d) Execute() {
+	C.fftw_execute(o.p)
+}
+
+// GetSlice gets the output array as a nested slice
+func (o *Plan3d) GetSlice() (out [][][]complex128) {
+	out = make([][][]complex128, o.n0)
+	for i := 0; i < o.n0; i++ {
+		out[i] = make([][]complex128, o.n1)
+		for j := 0; j < o.n1; j++ {
+			out[i][j] = make([]complex128, o.n2)
+			for k := 0; k < o.n2; k++ {
+				out[i][j][k] = o.Get(i, j, k)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,138 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fftw
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+func TestThreeDver01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ThreeDver01.")
+
+	for _, measure := range []bool{false, true} {
+
+		// allocate input data
+		N0, N1, N2 := 2, 3, 4
+		x := make([]complex128, N0*N1*N2)
+
+		// allocate plan
+		plan := NewPlan3d(N0, N1, N2, x, false, measure)
+		defer plan.Free()
+
+		// set input data
+		x3 := make([][][]complex128, N0)
+		l := 0
+		for i := 0; i < N0; i++ {
+			x3[i] = make([][]complex128, N1)
+			for j := 0; j < N1; j++ {
+				x3[i][j] = make([]complex128, N2)
+				for k := 0; k < N2; k++ {
+					x3[i][j][k] = complex(float64(l), float64(l+1)*float64(l%3))
+					plan.Set(i, j, k, x3[i][j][k])
+					l++
+				}
+			}
+		}
+
+		// check input data
+		chk.Complex128(tst, "x[1,2,3]", 1e-17, x[N0*N1*N2-1], x3[1][2][3])
+		chk.Complex128(tst, "Get(1,0,2)", 1e-17, plan.Get(1, 0, 2), x3[1][0][2])
+
+		// perform Fourier transform
+		plan.Execute()
+		io.Pf("X = %v\n", x)
+
+		// check output
+		X := plan.GetSlice()
+		Xref := dft3d(x3)
+		for i := 0; i < N0; i++ {
+			chk.Deep2c(tst, io.Sf("X[%d]", i), 1e-12, X[i], Xref[i])
+		}
+	}
+}
+
+func TestThreeDver02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ThreeDver02. inverse")
+
+	// allocate input data
+	N0, N1, N2 := 8, 4, 6
+	N := N0 * N1 * N2
+	x := make([]complex128, N)
+	plan := NewPlan3d(N0, N1, N2, x, false, false)
+	defer plan.Free()
+	iplan := NewPlan3d(N0, N1, N2, x, true, false)
+	defer iplan.Free()
+
+	// set input data: product of cosines ⇒ 2³ spikes with magnitude N/8
+	for i := 0; i < N0; i++ {
+		for j := 0; j < N1; j++ {
+			for k := 0; k < N2; k++ {
+				cx := math.Cos(2 * math.Pi * float64(i) / float64(N0))
+				cy := math.Cos(2 * math.Pi * float64(j) / float64(N1))
+				cz := math.Cos(2 * math.Pi * float64(2*k) / float64(N2))
+				plan.Set(i, j, k, complex(cx*cy*cz, 0))
+			}
+		}
+	}
+	x0 := make([]complex128, N)
+	copy(x0, x)
+
+	// perform direct transform and check
+	plan.Execute()
+	for i := 0; i < N0; i++ {
+		for j := 0; j < N1; j++ {
+			for k := 0; k < N2; k++ {
+				if (i == 1 || i == N0-1) && (j == 1 || j == N1-1) && (k == 2 || k == N2-2) {
+					chk.Complex128(tst, "nonzero freq", 1e-12, plan.Get(i, j, k), complex(float64(N)/8.0, 0))
+				} else if e := cmplx.Abs(plan.Get(i, j, k)); e > 1e-12 {
+					tst.Errorf("zero freq failed. e = %v\n", e)
+				}
+			}
+		}
+	}
+
+	// perform inverse transform and check
+	iplan.Execute()
+	for l := 0; l < N; l++ {
+		x[l] /= complex(float64(N), 0)
+	}
+	chk.ArrayC(tst, "x", 1e-14, x, x0)
+}
+
+// solution ////////////////////////////////////////////////////////////////////////////////////////
+
+// dft3d compute the discrete Fourier Transform of x (very slow: for testing only)
+func dft3d(x [][][]complex128) (X [][][]complex128) {
+	N0, N1, N2 := len(x), len(x[0]), len(x[0][0])
+	X = make([][][]complex128, N0)
+	for l0 := 0; l0 < N0; l0++ {
+		X[l0] = make([][]complex128, N1)
+		for l1 := 0; l1 < N1; l1++ {
+			X[l0][l1] = make([]complex128, N2)
+			for l2 := 0; l2 < N2; l2++ {
+				for k0 := 0; k0 < N0; k0++ {
+					for k1 := 0; k1 < N1; k1++ {
+						for k2 := 0; k2 < N2; k2++ {
+							a := 2.0 * math.Pi * float64(k0*l0) / float64(N0)
+							b := 2.0 * math.Pi * float64(k1*l1) / float64(N1)
+							c := 2.0 * math.Pi * float64(k2*l2) / float64(N2)
+							X[l0][l1][l2] += x[k0][k1][k2] * expmix(a) * expmix(b) * expmix(c)
+						}
+					}
+				}
+			}
+		}
+	}
+	return
+}
@@ -107,15 +107,7 @@ func NewFourierInterp(N int, smoothing string) (o *FourierInterp) {
 	}
 
 	// compute smoothing coefficients
-	σ := func(k float64) float64 { return 1.0 }
-	switch smoothing {
-	case "lanc":
-		σ = func(k float64) float64 { return Sinc(2 * k * π / n) }
-	case "rcos":
-		σ = func(k float64) float64 { return (1.0 + math.Cos(2*k*π/n)) / 2.0 }
-	case "ces":
-		σ = func(k float64) float64 { return 1.0 - math.Abs(k)/(1.0+n/2.0) }
-	}
+	σ := fourierSmoothing(smoothing, o.N)
 	for j := 0; j < o.N; j++ {
 		o.S[j] = complex(σ(o.K[j]), 0)
 	}
@@ -365,3 +357,19 @@ func (o *FourierInterp) CalcJ(k float64) int {
 	}
 	return int(k)
 }
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// fourierSmoothing returns the smoothing function σ(k) for N terms. See NewFourierInterp
+func fourierSmoothing(smoothing string, N int) func(k float64) float64 {
+	n := float64(N)
+	switch smoothing {
+	case "lanc":
+		return func(k float64) float64 { return Sinc(2 * k * π / n) }
+	case "rcos":
+		return func(k float64) float64 { return (1.0 + math.Cos(2*k*π/n)) / 2.0 }
+	case "ces":
+		return func(k float64) float64 { return 1.0 - math.Abs(k)/(1.0+n/2.0) }
+	}
+	return func(k float64) float64 { return 1.0 }
+}
@@ -0,0 +1,386 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun/fftw"
+	"github.com/lei006/gomath/la"
+)
+
+// FourierInterpNd performs interpolation using truncated Fourier series on 2D or 3D periodic boxes
+//
+//	           N₀/2-1  N₁/2-1  N₂/2-1
+//	             ————    ————    ————                  +i (κ₀ x₀ + κ₁ x₁ + κ₂ x₂)
+//	  I{f}(x) =  \       \       \      A[k₀,k₁,k₂] ⋅ e                              κᵢ = 2 π kᵢ / Lᵢ
+//	             /       /       /
+//	             ————    ————    ————
+//	           k₀=-N₀/2 k₁=-N₁/2 k₂=-N₂/2                                            xᵢ ϵ [0, Lᵢ]
+//
+//	  where the coefficients A are computed with the (forward) FFT of the values U at the grid
+//	  points xᵢ[j] = Lᵢ j / Nᵢ; i.e. this is the tensor-product version of FourierInterp.
+//
+//	The values at grid points are stored in row-major order; i.e. the last index varies fastest:
+//
+//	  U[(j₀⋅N₁ + j₁)⋅N₂ + j₂] = f(x₀[j₀], x₁[j₁], x₂[j₂])
+//
+//	Create a new object with NewFourierInterp2d(...) or NewFourierInterp3d(...) AND deallocate
+//	memory with Free()
+//
+//	Reference:
+//	  [1] Canuto C, Hussaini MY, Quarteroni A, Zang TA (2006) Spectral Methods: Fundamentals in
+//	      Single Domains. Springer. 563p
+type FourierInterpNd struct {
+
+	// main
+	Ndim int         // space dimension: 2 or 3
+	N    []int       // number of grid points (terms) along each direction. must be even
+	L    []float64   // lengths of the periodic box [0, L₀] × [0, L₁] × [0, L₂]
+	X    [][]float64 // grid coordinates along each direction: X[i][j] = L[i]⋅j/N[i]
+	K    [][]float64 // k values along each direction: j = 0...N-1 ⇒ k = 0...N/2-1, -N/2...-1
+	A    la.VectorC  // coefficients for interpolation. from FFT
+	S    [][]float64 // smoothing coefficients along each direction
+
+	// computed (U may be set externally)
+	U    la.Vector   // values of f(x) at grid points
+	Du   la.Vector   // mixed derivative computed by CalcD
+	Grad []la.Vector // gradient at grid points: Grad[i] = ∂I{f}/∂xᵢ
+	Lap  la.Vector   // Laplacian at grid points: Lap = Σ ∂²I{f}/∂xᵢ²
+
+	// FFTW
+	planA fourierPlan // "plan" to compute the A coefficients
+	planW fourierPlan // "plan" to compute derivatives (inverse transform of work)
+
+	// workspace
+	ntot    int            // total number of grid points
+	strides []int          // strides of each direction in the row-major arrays
+	work    la.VectorC     // spectral coefficients of derivatives
+	ex      [][]complex128 // σ(k)⋅exp(i κ xᵢ) along each direction (see calcExp)
+}
+
+// fourierPlan defines the methods of FFTW plans used by FourierInterpNd
+type fourierPlan interface {
+	Execute()
+	Free()
+}
+
+// NewFourierInterp2d allocates a new FourierInterpNd object for a 2D periodic box
+//
+//	N0, N1 -- number of terms along each direction. must be even; ideally power of 2
+//	L      -- lengths of the box [optional]; nil ⇒ [2π, 2π]
+//
+//	smoothing -- type of smoothing (applied along each direction). See NewFourierInterp
+//
+//	NOTE: remember to call Free in the end to release memory allocated by FFTW; e.g.
+//	      defer o.Free()
+func NewFourierInterp2d(N0, N1 int, L []float64, smoothing string) (o *FourierInterpNd) {
+	o = newFourierInterpNd([]int{N0, N1}, L, smoothing)
+	o.planA = fftw.NewPlan2d(N0, N1, o.A, false, false)
+	o.planW = fftw.NewPlan2d(N0, N1, o.work, true, false)
+	return
+}
+
+// NewFourierInterp3d allocates a new FourierInterpNd object for a 3D periodic box
+//
+//	N0, N1, N2 -- number of terms along each direction. must be even; ideally power of 2
+//	L          -- lengths of the box [optional]; nil ⇒ [2π, 2π, 2π]
+//
+//	smoothing -- type of smoothing (applied along each direction). See NewFourierInterp
+//
+//	NOTE: remember to call Free in the end to release memory allocated by FFTW; e.g.
+//	      defer o.Free()
+func NewFourierInterp3d(N0, N1, N2 int, L []float64, smoothing string) (o *FourierInterpNd) {
+	o = newFourierInterpNd([]int{N0, N1, N2}, L, smoothing)
+	o.planA = fftw.NewPlan3d(N0, N1, N2, o.A, false, false)
+	o.planW = fftw.NewPlan3d(N0, N1, N2, o.work, true, false)
+	return
+}
+
+// Free releases resources allocated for FFTW
+func (o *FourierInterpNd) Free() {
+	if o.planA != nil {
+		o.planA.Free()
+	}
+	if o.planW != nil {
+		o.planW.Free()
+	}
+}
+
+// Npoints returns the total number of grid points
+func (o *FourierInterpNd) Npoints() int {
+	return o.ntot
+}
+
+// Node computes the coordinates of the grid point with row-major index idx
+//
+//	x -- [output] coordinates (pre-allocated with len(x) == Ndim)
+func (o *FourierInterpNd) Node(x []float64, idx int) {
+	for i := 0; i < o.Ndim; i++ {
+		x[i] = o.X[i][(idx/o.strides[i])%o.N[i]]
+	}
+}
+
+// CalcU calculates f(x) at grid points (to be used later with CalcA)
+func (o *FourierInterpNd) CalcU(f Sv) {
+	if len(o.U) != o.ntot {
+		o.U = la.NewVector(o.ntot)
+	}
+	x := la.NewVector(o.Ndim)
+	for idx := 0; idx < o.ntot; idx++ {
+		o.Node(x, idx)
+		o.U[idx] = f(x)
+	}
+}
+
+// CalcA calculates the coefficients A of the interpolation using (fwd) FFT
+//
+//	NOTE: remember to set U (or call CalcU) first
+func (o *FourierInterpNd) CalcA() {
+	n := float64(o.ntot)
+	for idx := 0; idx < o.ntot; idx++ {
+		o.A[idx] = complex(o.U[idx]/n, 0)
+	}
+	o.planA.Execute()
+}
+
+// Dealias removes the aliasing error from the coefficients A using the 2/3-rule; i.e. the
+// coefficients with 3⋅|kᵢ| ≥ Nᵢ along any direction are set to zero
+//
+//	In a pseudo-spectral computation of the product w = u⋅v, both u and v are first truncated by
+//	Dealias; then, the product computed at grid points is transformed (CalcA) and truncated again
+//	(Dealias). The remaining coefficients are free of aliasing errors.
+//
+//	NOTE: remember to call CalcA first
+func (o *FourierInterpNd) Dealias() {
+	for idx := 0; idx < o.ntot; idx++ {
+		for i := 0; i < o.Ndim; i++ {
+			k := o.K[i][(idx/o.strides[i])%o.N[i]]
+			if 3*int(math.Abs(k)) >= o.N[i] {
+				o.A[idx] = 0
+				break
+			}
+		}
+	}
+}
+
+// CalcUfromA calculates the values U at grid points from the coefficients A using the inverse
+// FFT (with smoothing or not); e.g. after calling Dealias
+func (o *FourierInterpNd) CalcUfromA() {
+	if len(o.U) != o.ntot {
+		o.U = la.NewVector(o.ntot)
+	}
+	o.inverse(o.U, func(idx int) complex128 { return 1 })
+}
+
+// I computes the interpolation (with smoothing or not) at any point x
+//
+//	NOTE: remember to call CalcA first
+func (o *FourierInterpNd) I(x []float64) float64 {
+	o.calcExp(x)
+	var res complex128
+	for idx := 0; idx < o.ntot; idx++ {
+		res += o.A[idx] * o.expProd(idx)
+	}
+	return real(res)
+}
+
+// G computes the interpolation and its gradient (with smoothing or not) at any point x
+//
+//	grad -- [output] gradient (pre-allocated with len(grad) == Ndim)
+//
+//	NOTE: remember to call CalcA first
+func (o *FourierInterpNd) G(grad, x []float64) (res float64) {
+	o.calcExp(x)
+	var r complex128
+	g := make([]complex128, o.Ndim)
+	for idx := 0; idx < o.ntot; idx++ {
+		a := o.A[idx] * o.expProd(idx)
+		r += a
+		for i := 0; i < o.Ndim; i++ {
+			g[i] += complex(0, o.kappa(i, idx)) * a
+		}
+	}
+	for i := 0; i < o.Ndim; i++ {
+		grad[i] = real(g[i])
+	}
+	return real(r)
+}
+
+// CalcD calculates a mixed derivative of the interpolated function at grid points using the FFT
+// (with smoothing or not)
+//
+//	                   p₀+p₁+p₂
+//	                  ∂        I{f}
+//	          Du = ————————————————         (at grid points)
+//	                 p₀     p₁     p₂
+//	               ∂x₀   ∂x₁   ∂x₂
+//
+//	 INPUT:
+//	    p -- derivative orders along each direction; len(p) == Ndim
+//
+//	 OUTPUT:
+//	    Du will contain the results
+//
+//	NOTE: remember to call CalcA first
+func (o *FourierInterpNd) CalcD(p []int) {
+	if len(p) != o.Ndim {
+		chk.Panic("len(p) must be equal to Ndim = %d. %d is invalid\n", o.Ndim, len(p))
+	}
+	if len(o.Du) != o.ntot {
+		o.Du = la.NewVector(o.ntot)
+	}
+	o.inverse(o.Du, func(idx int) complex128 {
+		c := complex(1, 0)
+		for i := 0; i < o.Ndim; i++ {
+			c *= ImagXpowN(o.kappa(i, idx), p[i])
+		}
+		return c
+	})
+}
+
+// CalcGrad calculates the gradient of the interpolated function at grid points using the FFT
+// (with smoothing or not)
+//
+//	OUTPUT: the results will be stored in Grad
+//
+//	NOTE: remember to call CalcA first
+func (o *FourierInterpNd) CalcGrad() {
+	if len(o.Grad) != o.Ndim {
+		o.Grad = make([]la.Vector, o.Ndim)
+		for i := 0; i < o.Ndim; i++ {
+			o.Grad[i] = la.NewVector(o.ntot)
+		}
+	}
+	for i := 0; i < o.Ndim; i++ {
+		dir := i
+		o.inverse(o.Grad[i], func(idx int) complex128 { return complex(0, o.kappa(dir, idx)) })
+	}
+}
+
+// CalcLap calculates the Laplacian of the interpolated function at grid points using the FFT
+// (with smoothing or not)
+//
+//	OUTPUT: the results will be stored in Lap
+//
+//	NOTE: remember to call CalcA first
+func (o *FourierInterpNd) CalcLap() {
+	if len(o.Lap) != o.ntot {
+		o.Lap = la.NewVector(o.ntot)
+	}
+	o.inverse(o.Lap, func(idx int) complex128 {
+		var κ2 float64
+		for i := 0; i < o.Ndim; i++ {
+			κ := o.kappa(i, idx)
+			κ2 += κ * κ
+		}
+		return complex(-κ2, 0)
+	})
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// newFourierInterpNd allocates the common data of FourierInterpNd (without FFTW plans)
+func newFourierInterpNd(N []int, L []float64, smoothing string) (o *FourierInterpNd) {
+
+	// check
+	ndim := len(N)
+	for i := 0; i < ndim; i++ {
+		if N[i] < 2 || N[i]%2 != 0 {
+			chk.Panic("N must be even. N[%d]=%d is invalid\n", i, N[i])
+		}
+	}
+	if L == nil {
+		L = make([]float64, ndim)
+		for i := 0; i < ndim; i++ {
+			L[i] = 2.0 * math.Pi
+		}
+	}
+	if len(L) != ndim {
+		chk.Panic("len(L) must be equal to %d. %d is invalid\n", ndim, len(L))
+	}
+
+	// allocate
+	o = new(FourierInterpNd)
+	o.Ndim = ndim
+	o.N = N
+	o.L = L
+	o.X = make([][]float64, ndim)
+	o.K = make([][]float64, ndim)
+	o.S = make([][]float64, ndim)
+	o.ex = make([][]complex128, ndim)
+	o.strides = make([]int, ndim)
+	o.ntot = 1
+	for i := ndim - 1; i >= 0; i-- {
+		o.strides[i] = o.ntot
+		o.ntot *= N[i]
+	}
+
+	// coordinates, k values and smoothing coefficients
+	for i := 0; i < ndim; i++ {
+		n := N[i]
+		σ := fourierSmoothing(smoothing, n)
+		o.X[i] = make([]float64, n)
+		o.K[i] = make([]float64, n)
+		o.S[i] = make([]float64, n)
+		o.ex[i] = make([]complex128, n)
+		for j := 0; j < n; j++ {
+			o.X[i][j] = L[i] * float64(j) / float64(n)
+			o.K[i][j] = float64(j - (j/(n/2))*n)
+			o.S[i][j] = σ(o.K[i][j])
+		}
+	}
+
+	// arrays for FFT
+	o.A = la.NewVectorC(o.ntot)
+	o.work = la.NewVectorC(o.ntot)
+	return
+}
+
+// kappa returns the wavenumber κ = 2π k / L along direction i for the row-major index idx
+func (o *FourierInterpNd) kappa(i, idx int) float64 {
+	return 2.0 * math.Pi * o.K[i][(idx/o.strides[i])%o.N[i]] / o.L[i]
+}
+
+// smoothing returns the product of smoothing coefficients for the row-major index idx
+func (o *FourierInterpNd) smoothing(idx int) (σ float64) {
+	σ = 1
+	for i := 0; i < o.Ndim; i++ {
+		σ *= o.S[i][(idx/o.strides[i])%o.N[i]]
+	}
+	return
+}
+
+// inverse computes res = Re(IFFT(m ⋅ σ ⋅ A)) where m is a multiplier
+func (o *FourierInterpNd) inverse(res la.Vector, m func(idx int) complex128) {
+	for idx := 0; idx < o.ntot; idx++ {
+		o.work[idx] = m(idx) * complex(o.smoothing(idx), 0) * o.A[idx]
+	}
+	o.planW.Execute()
+	for idx := 0; idx < o.ntot; idx++ {
+		res[idx] = real(o.work[idx])
+	}
+}
+
+// calcExp computes σ(k)⋅exp(i κ xᵢ) along each direction
+func (o *FourierInterpNd) calcExp(x []float64) {
+	for i := 0; i < o.Ndim; i++ {
+		for j := 0; j < o.N[i]; j++ {
+			κ := 2.0 * math.Pi * o.K[i][j] / o.L[i]
+			o.ex[i][j] = complex(o.S[i][j], 0) * cmplx.Exp(complex(0, κ*x[i]))
+		}
+	}
+}
+
+// expProd returns the product of the factors computed by calcExp for the row-major index idx
+func (o *FourierInterpNd) expProd(idx int) (res complex128) {
+	res = 1
+	for i := 0; i < o.Ndim; i++ {
+		res *= o.ex[i][(idx/o.strides[i])%o.N[i]]
+	}
+	return
+}
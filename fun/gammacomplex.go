@@ -0,0 +1,127 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/lei006/gomath/la"
+)
+
+// Cc defines a complex function f(z) of a complex argument z (complex complex)
+//
+//	Input:
+//	  z -- input complex number
+//	Returns:
+//	  complex number
+type Cc func(z complex128) complex128
+
+// MapC applies f to all components of z; i.e. res[i] = f(z[i])
+//
+//	NOTE: res must be pre-allocated and may be the same as z
+func MapC(res la.VectorC, f Cc, z la.VectorC) {
+	for i, zi := range z {
+		res[i] = f(zi)
+	}
+}
+
+// GammaC computes the Gamma function Γ(z) of a complex argument
+//
+//	Γ(z) = exp(LogGammaC(z))
+//
+//	Special cases:
+//	  Γ(0) and Γ(-n) = NaN (poles); see also math.Gamma for real arguments
+func GammaC(z complex128) complex128 {
+	if imag(z) == 0 && real(z) <= 0 && real(z) == math.Floor(real(z)) {
+		return complex(math.NaN(), math.NaN())
+	}
+	if imag(z) == 0 {
+		return complex(math.Gamma(real(z)), 0)
+	}
+	return cmplx.Exp(LogGammaC(z))
+}
+
+// LogGammaC computes the principal branch of the logarithm of the Gamma function of a complex
+// argument; i.e. the analytic continuation of log Γ(x), x > 0, with a branch cut along the
+// negative real axis. Note that exp(LogGammaC(z)) = Γ(z), but LogGammaC(z) ≠ log(Γ(z)) in general.
+//
+//	The Stirling series is employed after shifting z with the recurrence
+//
+//	  log Γ(z) = log Γ(z+n) - Σ log(z+k)    k = 0...n-1
+//
+//	which holds for the principal branches [1]. For Re(z) < 0, the reflection formula is used
+//	with the branch correction 2πi⋅m, m = ±⌊Re(z)/2 + 1/4⌋ (with the sign of Im(z)) [1].
+//
+//	References:
+//	  [1] Hare DEG (1997) Computing the principal branch of log-Gamma, Journal of Algorithms,
+//	      25:221-236
+func LogGammaC(z complex128) complex128 {
+
+	// poles
+	x, y := real(z), imag(z)
+	if y == 0 && x <= 0 && x == math.Floor(x) {
+		return complex(math.Inf(1), 0)
+	}
+
+	// reflection: log Γ(z) = log π - log sin(π z) - log Γ(1-z) + 2πi⋅m
+	if x < 0 {
+		m := math.Floor(0.5*x + 0.25)
+		if math.Signbit(y) {
+			m = -m
+		}
+		return complex(math.Log(math.Pi), 2*math.Pi*m) - logSinPi(z) - LogGammaC(1-z)
+	}
+
+	// shift
+	var sum complex128
+	for real(z) < 10 {
+		sum += cmplx.Log(z)
+		z++
+	}
+	return stirlingLogGamma(z) - sum
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// stirlingLogGamma computes log Γ(z) with the Stirling series (accurate for Re(z) ≥ 10)
+func stirlingLogGamma(z complex128) complex128 {
+	res := (z-0.5)*cmplx.Log(z) - z + complex(0.5*math.Log(2*math.Pi), 0)
+	zi := 1 / z
+	z2 := zi * zi
+	t := zi
+	for _, b := range stirlingCoef {
+		res += complex(b, 0) * t
+		t *= z2
+	}
+	return res
+}
+
+// stirlingCoef holds B₂ₖ/(2k(2k-1)) for the Stirling series
+var stirlingCoef = []float64{
+	1.0 / 12.0,
+	-1.0 / 360.0,
+	1.0 / 1260.0,
+	-1.0 / 1680.0,
+	1.0 / 1188.0,
+	-691.0 / 360360.0,
+	1.0 / 156.0,
+	-3617.0 / 122400.0,
+}
+
+// logSinPi computes the principal value of log(sin(π z)) avoiding overflow for large |Im(z)|
+func logSinPi(z complex128) (res complex128) {
+	if math.Abs(imag(z)) < 20 {
+		return cmplx.Log(cmplx.Sin(math.Pi * z))
+	}
+	// sin(πz) = ±(i/2) e^{∓iπz} (1 - e^{±2iπz}) for ±Im(z) > 0
+	s := 1.0
+	if imag(z) < 0 {
+		s = -1.0
+	}
+	res = complex(0, -s*math.Pi)*z + cmplx.Log(complex(0, s*0.5)) + cmplx.Log(1-cmplx.Exp(complex(0, 2*s*math.Pi)*z))
+	im := math.Remainder(imag(res), 2*math.Pi) // reduce to [-π, π]
+	return complex(real(res), im)
+}
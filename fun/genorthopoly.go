@@ -6,6 +6,7 @@ package fun
 
 import (
 	"math"
+	"sort"
 
 	"github.com/lei006/gomath/chk"
 )
@@ -37,19 +38,25 @@ type GeneralOrthoPoly struct {
 // NewGeneralOrthoPoly creates a new orthogonal polynomial
 //
 //	kind -- is the type of orthognal polynomial:
-//	  "J" or "jac"    : Jacobi
-//	  "L" or "leg"    : Legendre
-//	  "H" or "her"    : Hermite
-//	  "T" or "cheby1" : Chebyshev first kind
-//	  "U" or "cheby2" : Chebyshev second kind
+//	  "J" or "jac"      : Jacobi P⁽ᵅ'ᵝ⁾(x)
+//	  "L" or "leg"      : Legendre
+//	  "H" or "her"      : Hermite (physicists'; weight exp(-x²))
+//	  "T" or "cheby1"   : Chebyshev first kind
+//	  "U" or "cheby2"   : Chebyshev second kind
+//	  "La" or "lag"     : generalised Laguerre L⁽ᵅ⁾(x)
+//	  "C" or "geg"      : Gegenbauer (ultraspherical) C⁽ᵅ⁾(x); α > -1/2 and α ≠ 0
+//	  "He" or "herp"    : Hermite (probabilists'; weight exp(-x²/2))
+//	  "Ch" or "charlier": Charlier C(x; a) with a = α > 0 (discrete; x = 0, 1, 2, ...)
+//	  "K" or "kraw"     : Krawtchouk K(x; p, Nk) with p = α ∈ (0,1) and Nk = β (discrete;
+//	                      x = 0, 1, ..., Nk); requires N ≤ Nk
 //
 //	N -- is the (max) degree of the polynomial.
 //	     Lower order can later be quickly obtained after this
 //	     polynomial with max(N) is created
 //
-//	alpha -- Jacobi only: α coefficient
+//	alpha -- Jacobi, Laguerre, Gegenbauer, Charlier and Krawtchouk only: α coefficient
 //
-//	beta -- Jacobi only: β coefficient
+//	beta -- Jacobi and Krawtchouk only: β coefficient
 //
 //	NOTE: all coefficients for the 0...N polynomials will be generated
 //
@@ -60,6 +67,9 @@ func NewGeneralOrthoPoly(kind string, N int, alpha, beta float64) (o *GeneralOrt
 	o.Kind = kind
 	o.N = N
 	o.poly = newopoly(kind, alpha, beta)
+	if nmax, ok := o.poly.(interface{ maxDegree() int }); ok && N > nmax.maxDegree() {
+		chk.Panic("the degree N must not be greater than %d for %q. N=%d is invalid\n", nmax.maxDegree(), kind, N)
+	}
 	o.c = make([][]float64, o.N+1)
 	for n := 1; n <= o.N; n++ {
 		o.c[n] = make([]float64, o.poly.M(o.N)+1)
@@ -99,6 +109,52 @@ func (o *GeneralOrthoPoly) P(n int, x float64) (res float64) {
 	return
 }
 
+// Recurrence returns the coefficients of the three-term recurrence relation of the monic
+// polynomials πₙ(x) = Pₙ(x)/kₙ (with kₙ being the leading coefficient of Pₙ)
+//
+//	π₋₁ = 0,   π₀ = 1,   πₙ₊₁(x) = (x - aₙ) πₙ(x) - bₙ πₙ₋₁(x)
+//
+//	NOTE: by convention, b₀ = μ₀ = Σ w(x) or ∫ w(x) dx is the integral of the weight function
+func (o *GeneralOrthoPoly) Recurrence(n int) (a, b float64) {
+	if n < 0 {
+		chk.Panic("n must be non-negative. n=%d is invalid\n", n)
+	}
+	return o.poly.rec(n)
+}
+
+// GaussXW computes the nodes x and weights w of the n-point Gauss quadrature associated with the
+// weight function of this polynomial using the Golub-Welsch algorithm [2]
+//
+//	  ∫ f(x) w(x) dx  ≈  Σ f(xᵢ) wᵢ    (or Σ f(x) w(x) for discrete families)
+//
+//	The nodes are the eigenvalues of the symmetric tridiagonal (Jacobi) matrix
+//
+//	      ┌ a₀   √b₁          ┐
+//	  J = │ √b₁  a₁   √b₂     │      and     wᵢ = μ₀ (v₀)ᵢ²
+//	      │      √b₂  a₂   ⋱  │
+//	      └           ⋱    ⋱  ┘
+//
+//	where (v₀)ᵢ is the first component of the i-th normalised eigenvector. The nodes are returned in
+//	ascending order.
+//
+//	Reference:
+//	[2] Golub GH, Welsch JH (1969) Calculation of Gauss quadrature rules, Mathematics of
+//	    Computation, 23:221-230
+func (o *GeneralOrthoPoly) GaussXW(n int) (x, w []float64) {
+	if n < 1 {
+		chk.Panic("the number of points must be at least 1. n=%d is invalid\n", n)
+	}
+	if nmax, ok := o.poly.(interface{ maxDegree() int }); ok && n > nmax.maxDegree()+1 {
+		chk.Panic("the number of points must not be greater than %d for %q. n=%d is invalid\n", nmax.maxDegree()+1, o.Kind, n)
+	}
+	a := make([]float64, n)
+	b := make([]float64, n)
+	for k := 0; k < n; k++ {
+		a[k], b[k] = o.poly.rec(k)
+	}
+	return golubWelsch(a, b)
+}
+
 // oPoly database //////////////////////////////////////////////////////////////////////////////////
 
 // oPoly defines the functions that GeneralOrthoPolys must have
@@ -111,11 +167,14 @@ func (o *GeneralOrthoPoly) P(n int, x float64) (res float64) {
 //	                       /
 //	                       ————
 //	                       m = 0
+//
+//	and the three-term recurrence of the monic polynomials is given by rec (see Recurrence)
 type oPoly interface {
 	M(n int) int
 	d(n int) float64
 	c(n, m int) float64
 	g(n, m int, x float64) float64
+	rec(n int) (a, b float64)
 }
 
 // oPolyMaker defines a function that makes new oPolys
@@ -159,6 +218,24 @@ func (o *opJacobi) g(n, m int, x float64) float64 {
 	return math.Pow(x-1, float64(n-m)) * math.Pow(x+1, float64(m))
 }
 
+func (o *opJacobi) rec(n int) (a, b float64) {
+	α, β := o.alpha, o.beta
+	if n == 0 {
+		a = (β - α) / (α + β + 2)
+		b = math.Exp((α+β+1)*math.Ln2 + lgam(α+1) + lgam(β+1) - lgam(α+β+2))
+		return
+	}
+	nn := float64(n)
+	s := 2*nn + α + β
+	a = (β*β - α*α) / (s * (s + 2))
+	if n == 1 {
+		b = 4 * (α + 1) * (β + 1) / ((α + β + 2) * (α + β + 2) * (α + β + 3))
+		return
+	}
+	b = 4 * nn * (nn + α) * (nn + β) * (nn + α + β) / (s * s * (s + 1) * (s - 1))
+	return
+}
+
 func newJacobi(alpha, beta float64) oPoly {
 	o := new(opJacobi)
 	o.alpha = alpha
@@ -189,6 +266,14 @@ func (o *opLegendre) g(n, m int, x float64) float64 {
 	return math.Pow(x, float64(n-2*m))
 }
 
+func (o *opLegendre) rec(n int) (a, b float64) {
+	if n == 0 {
+		return 0, 2
+	}
+	nn := float64(n * n)
+	return 0, nn / (4*nn - 1)
+}
+
 func newLegendre(alpha, beta float64) oPoly {
 	return new(opLegendre)
 }
@@ -215,6 +300,13 @@ func (o *opHermite) g(n, m int, x float64) float64 {
 	return math.Pow(2*x, float64(n-2*m))
 }
 
+func (o *opHermite) rec(n int) (a, b float64) {
+	if n == 0 {
+		return 0, math.Sqrt(math.Pi)
+	}
+	return 0, float64(n) / 2.0
+}
+
 func newHermite(alpha, beta float64) oPoly {
 	return new(opHermite)
 }
@@ -242,6 +334,16 @@ func (o *opChebyshev1) g(n, m int, x float64) float64 {
 	return math.Pow(2*x, float64(n-2*m))
 }
 
+func (o *opChebyshev1) rec(n int) (a, b float64) {
+	switch n {
+	case 0:
+		return 0, math.Pi
+	case 1:
+		return 0, 0.5
+	}
+	return 0, 0.25
+}
+
 func newChebyshev1(alpha, beta float64) oPoly {
 	return new(opChebyshev1)
 }
@@ -269,10 +371,235 @@ func (o *opChebyshev2) g(n, m int, x float64) float64 {
 	return math.Pow(2*x, float64(n-2*m))
 }
 
+func (o *opChebyshev2) rec(n int) (a, b float64) {
+	if n == 0 {
+		return 0, math.Pi / 2.0
+	}
+	return 0, 0.25
+}
+
 func newChebyshev2(alpha, beta float64) oPoly {
 	return new(opChebyshev2)
 }
 
+// Laguerre //////////////////////////////////////////////////////////////////////////////////////////
+
+// opLaguerre implements the generalised Laguerre polynomials (22.3.9 of [1]) with weight
+// xᵅ exp(-x) on [0, ∞)
+type opLaguerre struct {
+	alpha float64
+}
+
+func (o *opLaguerre) M(n int) int {
+	return n
+}
+
+func (o *opLaguerre) d(n int) float64 {
+	return 1.0
+}
+
+func (o *opLaguerre) c(n, m int) float64 {
+	return math.Pow(-1, float64(m)) * Rbinomial(float64(n)+o.alpha, float64(n-m)) / Factorial22(m)
+}
+
+func (o *opLaguerre) g(n, m int, x float64) float64 {
+	return math.Pow(x, float64(m))
+}
+
+func (o *opLaguerre) rec(n int) (a, b float64) {
+	nn := float64(n)
+	a = 2*nn + o.alpha + 1
+	if n == 0 {
+		return a, math.Gamma(o.alpha + 1)
+	}
+	return a, nn * (nn + o.alpha)
+}
+
+func newLaguerre(alpha, beta float64) oPoly {
+	if alpha <= -1 {
+		chk.Panic("Laguerre polynomials require α > -1. α=%g is invalid\n", alpha)
+	}
+	return &opLaguerre{alpha}
+}
+
+// Gegenbauer //////////////////////////////////////////////////////////////////////////////////////////
+
+// opGegenbauer implements the Gegenbauer (ultraspherical) polynomials (22.3.4 of [1]) with weight
+// (1 - x²)^(α-1/2) on [-1, 1]
+type opGegenbauer struct {
+	alpha float64
+}
+
+func (o *opGegenbauer) M(n int) int {
+	return int(math.Floor(float64(n) / 2.0))
+}
+
+func (o *opGegenbauer) d(n int) float64 {
+	return 1.0
+}
+
+func (o *opGegenbauer) c(n, m int) float64 {
+	r := pochhammer(o.alpha, n-m)
+	s := Factorial22(m)
+	t := Factorial22(n - 2*m)
+	return math.Pow(-1, float64(m)) * r / (s * t)
+}
+
+func (o *opGegenbauer) g(n, m int, x float64) float64 {
+	return math.Pow(2*x, float64(n-2*m))
+}
+
+func (o *opGegenbauer) rec(n int) (a, b float64) {
+	α := o.alpha
+	if n == 0 {
+		return 0, math.Exp(0.5*math.Log(math.Pi) + lgam(α+0.5) - lgam(α+1))
+	}
+	nn := float64(n)
+	return 0, nn * (nn + 2*α - 1) / (4 * (nn + α) * (nn + α - 1))
+}
+
+func newGegenbauer(alpha, beta float64) oPoly {
+	if alpha <= -0.5 || alpha == 0 {
+		chk.Panic("Gegenbauer polynomials require α > -1/2 and α ≠ 0. α=%g is invalid\n", alpha)
+	}
+	return &opGegenbauer{alpha}
+}
+
+// HermiteProb //////////////////////////////////////////////////////////////////////////////////////////
+
+// opHermiteProb implements the probabilists' Hermite polynomials Heₙ (22.3.11 of [1]) with weight
+// exp(-x²/2) on (-∞, ∞)
+type opHermiteProb struct{}
+
+func (o *opHermiteProb) M(n int) int {
+	return int(math.Floor(float64(n) / 2.0))
+}
+
+func (o *opHermiteProb) d(n int) float64 {
+	return Factorial22(n)
+}
+
+func (o *opHermiteProb) c(n, m int) float64 {
+	r := Factorial22(m)
+	s := Factorial22(n - 2*m)
+	return math.Pow(-1, float64(m)) / (r * s * math.Pow(2, float64(m)))
+}
+
+func (o *opHermiteProb) g(n, m int, x float64) float64 {
+	return math.Pow(x, float64(n-2*m))
+}
+
+func (o *opHermiteProb) rec(n int) (a, b float64) {
+	if n == 0 {
+		return 0, math.Sqrt(2 * math.Pi)
+	}
+	return 0, float64(n)
+}
+
+func newHermiteProb(alpha, beta float64) oPoly {
+	return new(opHermiteProb)
+}
+
+// Charlier //////////////////////////////////////////////////////////////////////////////////////////
+
+// opCharlier implements the Charlier polynomials
+//
+//	            n   ⎛n⎞ ⎛  1 ⎞ᵐ
+//	  Cₙ(x;a) = Σ   ⎜ ⎟ ⎜- —⎟   x(x-1)⋯(x-m+1)
+//	           m=0  ⎝m⎠ ⎝  a ⎠
+//
+//	which are orthogonal w.r.t the Poisson distribution w(x) = exp(-a) aˣ / x! for x = 0, 1, 2, ...
+type opCharlier struct {
+	a float64
+}
+
+func (o *opCharlier) M(n int) int {
+	return n
+}
+
+func (o *opCharlier) d(n int) float64 {
+	return 1.0
+}
+
+func (o *opCharlier) c(n, m int) float64 {
+	return Binomial(n, m) * math.Pow(-1.0/o.a, float64(m))
+}
+
+func (o *opCharlier) g(n, m int, x float64) float64 {
+	return fallingFactorial(x, m)
+}
+
+func (o *opCharlier) rec(n int) (a, b float64) {
+	nn := float64(n)
+	if n == 0 {
+		return o.a, 1
+	}
+	return nn + o.a, nn * o.a
+}
+
+func newCharlier(alpha, beta float64) oPoly {
+	if alpha <= 0 {
+		chk.Panic("Charlier polynomials require a = α > 0. α=%g is invalid\n", alpha)
+	}
+	return &opCharlier{alpha}
+}
+
+// Krawtchouk //////////////////////////////////////////////////////////////////////////////////////////
+
+// opKrawtchouk implements the Krawtchouk polynomials
+//
+//	              n        ⎛n⎞   x(x-1)⋯(x-m+1)
+//	  Kₙ(x;p,N) = Σ  (-1)ᵐ ⎜ ⎟ ——————————————————
+//	             m=0       ⎝m⎠    ⎛N⎞
+//	                              ⎜ ⎟ m! pᵐ
+//	                              ⎝m⎠
+//
+//	which are orthogonal w.r.t the binomial distribution w(x) = C(N,x) pˣ (1-p)ᴺ⁻ˣ for
+//	x = 0, 1, ..., N
+type opKrawtchouk struct {
+	p  float64
+	nk int
+}
+
+func (o *opKrawtchouk) M(n int) int {
+	return n
+}
+
+func (o *opKrawtchouk) d(n int) float64 {
+	return 1.0
+}
+
+func (o *opKrawtchouk) c(n, m int) float64 {
+	return math.Pow(-1, float64(m)) * Binomial(n, m) / (Binomial(o.nk, m) * Factorial22(m) * math.Pow(o.p, float64(m)))
+}
+
+func (o *opKrawtchouk) g(n, m int, x float64) float64 {
+	return fallingFactorial(x, m)
+}
+
+func (o *opKrawtchouk) rec(n int) (a, b float64) {
+	nn, N, p := float64(n), float64(o.nk), o.p
+	a = p*(N-nn) + nn*(1-p)
+	if n == 0 {
+		return a, 1
+	}
+	return a, nn * p * (1 - p) * (N - nn + 1)
+}
+
+func (o *opKrawtchouk) maxDegree() int {
+	return o.nk
+}
+
+func newKrawtchouk(alpha, beta float64) oPoly {
+	if alpha <= 0 || alpha >= 1 {
+		chk.Panic("Krawtchouk polynomials require 0 < p = α < 1. α=%g is invalid\n", alpha)
+	}
+	if beta < 1 || beta != math.Floor(beta) {
+		chk.Panic("Krawtchouk polynomials require a positive integer N = β. β=%g is invalid\n", beta)
+	}
+	return &opKrawtchouk{alpha, int(beta)}
+}
+
 // add polynomials to database /////////////////////////////////////////////////////////////////////
 
 func init() {
@@ -281,4 +608,119 @@ func init() {
 	oPolyDB["H"] = newHermite
 	oPolyDB["T"] = newChebyshev1
 	oPolyDB["U"] = newChebyshev2
+	oPolyDB["La"] = newLaguerre
+	oPolyDB["C"] = newGegenbauer
+	oPolyDB["He"] = newHermiteProb
+	oPolyDB["Ch"] = newCharlier
+	oPolyDB["K"] = newKrawtchouk
+	oPolyDB["jac"] = newJacobi
+	oPolyDB["leg"] = newLegendre
+	oPolyDB["her"] = newHermite
+	oPolyDB["cheby1"] = newChebyshev1
+	oPolyDB["cheby2"] = newChebyshev2
+	oPolyDB["lag"] = newLaguerre
+	oPolyDB["geg"] = newGegenbauer
+	oPolyDB["herp"] = newHermiteProb
+	oPolyDB["charlier"] = newCharlier
+	oPolyDB["kraw"] = newKrawtchouk
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// lgam returns log(Γ(x)) for x > 0
+func lgam(x float64) float64 {
+	l, _ := math.Lgamma(x)
+	return l
+}
+
+// pochhammer returns the rising factorial (x)ₙ = x(x+1)⋯(x+n-1) = Γ(x+n)/Γ(x)
+func pochhammer(x float64, n int) (res float64) {
+	res = 1
+	for k := 0; k < n; k++ {
+		res *= x + float64(k)
+	}
+	return
+}
+
+// fallingFactorial returns x(x-1)⋯(x-n+1)
+func fallingFactorial(x float64, n int) (res float64) {
+	res = 1
+	for k := 0; k < n; k++ {
+		res *= x - float64(k)
+	}
+	return
+}
+
+// golubWelsch computes the eigenvalues x of the Jacobi matrix (diagonal a and squared
+// off-diagonal b[1:]) and the weights w = b[0] v₀² using the implicit QL algorithm with Wilkinson
+// shifts. Only the first components of the eigenvectors are updated.
+func golubWelsch(a, b []float64) (x, w []float64) {
+	n := len(a)
+	d := make([]float64, n)
+	e := make([]float64, n)
+	z := make([]float64, n)
+	copy(d, a)
+	for i := 0; i < n-1; i++ {
+		e[i] = math.Sqrt(b[i+1])
+	}
+	z[0] = 1
+	for l := 0; l < n; l++ {
+		for it := 0; ; it++ {
+			m := l
+			for ; m < n-1; m++ {
+				dd := math.Abs(d[m]) + math.Abs(d[m+1])
+				if math.Abs(e[m]) <= 2.220446049250313e-16*dd {
+					break
+				}
+			}
+			if m == l {
+				break
+			}
+			if it == 100 {
+				chk.Panic("Golub-Welsch: QL algorithm did not converge\n")
+			}
+			g := (d[l+1] - d[l]) / (2.0 * e[l])
+			r := math.Hypot(g, 1)
+			g = d[m] - d[l] + e[l]/(g+math.Copysign(r, g))
+			s, c, p := 1.0, 1.0, 0.0
+			i := m - 1
+			for ; i >= l; i-- {
+				f, bb := s*e[i], c*e[i]
+				r = math.Hypot(f, g)
+				e[i+1] = r
+				if r == 0 {
+					d[i+1] -= p
+					e[m] = 0
+					break
+				}
+				s, c = f/r, g/r
+				g = d[i+1] - p
+				r = (d[i]-g)*s + 2.0*c*bb
+				p = s * r
+				d[i+1] = g + p
+				g = c*r - bb
+				f = z[i+1]
+				z[i+1] = s*z[i] + c*f
+				z[i] = c*z[i] - s*f
+			}
+			if r == 0 && i >= l {
+				continue
+			}
+			d[l] -= p
+			e[l] = g
+			e[m] = 0
+		}
+	}
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool { return d[idx[i]] < d[idx[j]] })
+	x = make([]float64, n)
+	w = make([]float64, n)
+	for k, i := range idx {
+		x[k] = d[i]
+		w[k] = b[0] * z[i] * z[i]
+	}
+	return
 }
@@ -0,0 +1,386 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"github.com/lei006/gomath/chk"
+)
+
+// ExtrapType specifies how GridInterp behaves outside the range of its axes
+type ExtrapType int
+
+const (
+
+	// ExtrapClamp clamps the coordinates to the range of the axes
+	ExtrapClamp ExtrapType = iota
+
+	// ExtrapLinear extends the interpolant linearly using the slope at the boundary
+	ExtrapLinear
+
+	// ExtrapError panics if the coordinates are outside the range of the axes
+	ExtrapError
+)
+
+// GridInterp implements an N-dimensional interpolant over a rectilinear (tensor) grid
+//
+//	The data is given at the nodes of the grid defined by N axes with arbitrarily spaced (but
+//	strictly monotonic) coordinates. The first axis runs fastest; e.g. in 3D:
+//
+//	  f(i,j,k) is stored at f[i + n0*j + n0*n1*k]
+//
+//	MultiLinearType: tensor-product of linear segments (C0 continuous)
+//
+//	MultiCubicType: tensor-product of cubic Hermite segments with nodal slopes computed from
+//	                the parabola through the node and its neighbours (C1 continuous). Quadratic
+//	                functions are reproduced exactly.
+//
+//	NOTE: the Axis objects keep hunt data; thus GridInterp is not safe for concurrent use
+type GridInterp struct {
+
+	// configuration
+	Extrap ExtrapType // extrapolation policy
+
+	// input
+	typ  InterpType // MultiLinearType or MultiCubicType
+	axes []*Axis    // axes
+	f    []float64  // function values at nodes
+
+	// derived
+	ndim    int         // number of dimensions
+	ns      int         // number of stencil nodes along each axis: 2 (linear) or 4 (cubic)
+	strides []int       // strides of f along each axis
+	j0      []int       // first stencil node along each axis
+	w       [][]float64 // [ndim][ns] weights of stencil nodes
+	dw      [][]float64 // [ndim][ns] derivatives of weights
+	cnt     []int       // counter over stencil nodes
+}
+
+// NewGridInterp returns a new N-dimensional interpolant
+//
+//	interpType -- MultiLinearType or MultiCubicType
+//	f          -- function values at nodes; first axis runs fastest
+//	axes       -- coordinates along each axis (at least 2 per axis)
+func NewGridInterp(interpType InterpType, f []float64, axes ...[]float64) (o *GridInterp) {
+	o = new(GridInterp)
+	switch interpType {
+	case MultiLinearType:
+		o.ns = 2
+	case MultiCubicType:
+		o.ns = 4
+	default:
+		chk.Panic("GridInterp requires MultiLinearType or MultiCubicType. %d is invalid\n", interpType)
+	}
+	if len(axes) < 1 {
+		chk.Panic("GridInterp requires at least one axis\n")
+	}
+	o.typ = interpType
+	o.ndim = len(axes)
+	o.axes = make([]*Axis, o.ndim)
+	o.strides = make([]int, o.ndim)
+	o.j0 = make([]int, o.ndim)
+	o.w = make([][]float64, o.ndim)
+	o.dw = make([][]float64, o.ndim)
+	o.cnt = make([]int, o.ndim)
+	npts := 1
+	for k, data := range axes {
+		o.axes[k] = NewAxis(data, interpType)
+		o.strides[k] = npts
+		o.w[k] = make([]float64, o.ns)
+		o.dw[k] = make([]float64, o.ns)
+		npts *= len(data)
+	}
+	if len(f) != npts {
+		chk.Panic("length of function values %d is not equal to the number of grid points %d\n", len(f), npts)
+	}
+	o.f = f
+	return
+}
+
+// SetDisableHunt disables the hunt function for all axes
+func (o *GridInterp) SetDisableHunt(disable bool) {
+	for _, a := range o.axes {
+		a.DisableHunt = disable
+	}
+}
+
+// Ndim returns the number of dimensions
+func (o *GridInterp) Ndim() int {
+	return o.ndim
+}
+
+// Check returns an error if x is outside the range of the axes
+func (o *GridInterp) Check(x []float64) (err error) {
+	if len(x) != o.ndim {
+		return chk.Err("length of x must be equal to %d. %d is invalid\n", o.ndim, len(x))
+	}
+	for k, a := range o.axes {
+		if a.side(x[k]) != 0 {
+			return chk.Err("x[%d]=%g is outside the range of axis [%g, %g]\n", k, x[k], a.data[0], a.data[a.n-1])
+		}
+	}
+	return
+}
+
+// P computes the interpolated value at x
+func (o *GridInterp) P(x []float64) (res float64) {
+	o.calcWeights(x)
+	o.loop(func(idx int, prod float64, grad []float64) {
+		res += prod * o.f[idx]
+	}, nil)
+	return
+}
+
+// G computes the interpolated value at x and its gradient
+//
+//	Output:
+//	  grad -- gradient ∂f/∂x[k] (must have length Ndim)
+//	  res  -- the interpolated value
+func (o *GridInterp) G(grad, x []float64) (res float64) {
+	o.calcWeights(x)
+	for k := range grad {
+		grad[k] = 0
+	}
+	o.loop(func(idx int, prod float64, g []float64) {
+		res += prod * o.f[idx]
+		for k := 0; k < o.ndim; k++ {
+			grad[k] += g[k] * o.f[idx]
+		}
+	}, grad)
+	return
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// calcWeights computes the weights of the stencil nodes along each axis
+func (o *GridInterp) calcWeights(x []float64) {
+	if len(x) != o.ndim {
+		chk.Panic("length of x must be equal to %d. %d is invalid\n", o.ndim, len(x))
+	}
+	for k, a := range o.axes {
+		side := a.side(x[k])
+		if side == 0 {
+			j := a.locate(x[k])
+			o.axisWeights(k, j, x[k])
+			continue
+		}
+		if o.Extrap == ExtrapError {
+			chk.Panic("x[%d]=%g is outside the range of axis [%g, %g]\n", k, x[k], a.data[0], a.data[a.n-1])
+		}
+		j, xb := 0, a.data[0]
+		if side > 0 {
+			j, xb = a.n-2, a.data[a.n-1]
+		}
+		o.axisWeights(k, j, xb)
+		for m := 0; m < o.ns; m++ {
+			if o.Extrap == ExtrapLinear {
+				o.w[k][m] += (x[k] - xb) * o.dw[k][m]
+			} else {
+				o.dw[k][m] = 0
+			}
+		}
+	}
+}
+
+// axisWeights computes the weights of the stencil nodes along axis k for x in cell j
+func (o *GridInterp) axisWeights(k, j int, x float64) {
+	a := o.axes[k]
+	w, dw := o.w[k], o.dw[k]
+	h := a.data[j+1] - a.data[j]
+	t := (x - a.data[j]) / h
+
+	// linear
+	if o.ns == 2 {
+		o.j0[k] = j
+		w[0], w[1] = 1-t, t
+		dw[0], dw[1] = -1/h, 1/h
+		return
+	}
+
+	// cubic Hermite basis: stencil nodes are j-1, j, j+1, j+2
+	o.j0[k] = j - 1
+	t2, t3 := t*t, t*t*t
+	h00, h10, h01, h11 := 2*t3-3*t2+1, t3-2*t2+t, -2*t3+3*t2, t3-t2
+	d00, d10, d01, d11 := (6*t2-6*t)/h, 3*t2-4*t+1, (-6*t2+6*t)/h, 3*t2-2*t
+	for m := 0; m < 4; m++ {
+		w[m], dw[m] = 0, 0
+	}
+	w[1], w[2] = h00, h01
+	dw[1], dw[2] = d00, d01
+	for m, coef := range [2][2]float64{{h * h10, d10}, {h * h11, d11}} {
+		s, c := a.slope(j + m)
+		for l := 0; l < 3; l++ {
+			w[s+l-j+1] += coef[0] * c[l]
+			dw[s+l-j+1] += coef[1] * c[l]
+		}
+	}
+}
+
+// loop runs over all stencil nodes and calls fcn with the flat index of the node and the product
+// of weights. If grad != nil, the products with one weight replaced by its derivative are computed
+func (o *GridInterp) loop(fcn func(idx int, prod float64, g []float64), grad []float64) {
+	var g []float64
+	if grad != nil {
+		g = make([]float64, o.ndim)
+	}
+	for k := range o.cnt {
+		o.cnt[k] = 0
+	}
+	for {
+		idx, prod, ok := 0, 1.0, true
+		for k := 0; k < o.ndim; k++ {
+			i := o.j0[k] + o.cnt[k]
+			if i < 0 || i >= o.axes[k].n {
+				ok = false
+				break
+			}
+			idx += i * o.strides[k]
+			prod *= o.w[k][o.cnt[k]]
+		}
+		if ok {
+			if g != nil {
+				for k := 0; k < o.ndim; k++ {
+					g[k] = o.dw[k][o.cnt[k]]
+					for l := 0; l < o.ndim; l++ {
+						if l != k {
+							g[k] *= o.w[l][o.cnt[l]]
+						}
+					}
+				}
+			}
+			fcn(idx, prod, g)
+		}
+		k := 0
+		for ; k < o.ndim; k++ {
+			o.cnt[k]++
+			if o.cnt[k] < o.ns {
+				break
+			}
+			o.cnt[k] = 0
+		}
+		if k == o.ndim {
+			break
+		}
+	}
+}
+
+// side returns -1 if x is before the first point, +1 if x is after the last point and 0 otherwise
+func (o *Axis) side(x float64) int {
+	dir := o.data[o.n-1] - o.data[0]
+	if (x-o.data[0])*dir < 0 {
+		return -1
+	}
+	if (x-o.data[o.n-1])*dir > 0 {
+		return +1
+	}
+	return 0
+}
+
+// slope returns the coefficients to compute the slope at node i from the parabola through three
+// nodes; i.e. slope = c[0]⋅f[s] + c[1]⋅f[s+1] + c[2]⋅f[s+2]. With only two nodes, c[2] = 0
+func (o *Axis) slope(i int) (s int, c [3]float64) {
+	x := o.data
+	if o.n == 2 {
+		h := x[1] - x[0]
+		return 0, [3]float64{-1 / h, 1 / h, 0}
+	}
+	switch {
+	case i == 0:
+		s = 0
+	case i == o.n-1:
+		s = o.n - 3
+	default:
+		s = i - 1
+	}
+	x0, x1, x2, xi := x[s], x[s+1], x[s+2], x[i]
+	c[0] = (2*xi - x1 - x2) / ((x0 - x1) * (x0 - x2))
+	c[1] = (2*xi - x0 - x2) / ((x1 - x0) * (x1 - x2))
+	c[2] = (2*xi - x0 - x1) / ((x2 - x0) * (x2 - x1))
+	return
+}
+
+// BiCubic implements a two dimensional bi-cubic interpolant (see GridInterp)
+type BiCubic struct {
+	g    *GridInterp // N-D interpolant
+	x    []float64   // coordinates
+	grad []float64   // gradient
+}
+
+// NewBiCubic builds a two dimensional bi-cubic interpolant
+//
+//	xx -- function sample points abscissas
+//	yy -- function sample points ordinates
+//	f  -- function values; f(i,j) is stored at f[len(xx)*j + i]
+func NewBiCubic(f, xx, yy []float64) (o *BiCubic) {
+	o = new(BiCubic)
+	o.g = NewGridInterp(MultiCubicType, f, xx, yy)
+	o.x = make([]float64, 2)
+	o.grad = make([]float64, 2)
+	return
+}
+
+// SetDisableHunt disables the hunt function for both axis
+func (o *BiCubic) SetDisableHunt(disable bool) {
+	o.g.SetDisableHunt(disable)
+}
+
+// SetExtrap sets the extrapolation policy
+func (o *BiCubic) SetExtrap(extrap ExtrapType) {
+	o.g.Extrap = extrap
+}
+
+// P is the interpolation polynomial
+func (o *BiCubic) P(x, y float64) float64 {
+	o.x[0], o.x[1] = x, y
+	return o.g.P(o.x)
+}
+
+// G computes the interpolated value and its gradient
+func (o *BiCubic) G(x, y float64) (res, dfdx, dfdy float64) {
+	o.x[0], o.x[1] = x, y
+	res = o.g.G(o.grad, o.x)
+	return res, o.grad[0], o.grad[1]
+}
+
+// TriCubic implements a three dimensional tri-cubic interpolant (see GridInterp)
+type TriCubic struct {
+	g    *GridInterp // N-D interpolant
+	x    []float64   // coordinates
+	grad []float64   // gradient
+}
+
+// NewTriCubic builds a three dimensional tri-cubic interpolant
+//
+//	xx, yy, zz -- coordinates of sample points
+//	f          -- function values; f(i,j,k) is stored at f[i + len(xx)*j + len(xx)*len(yy)*k]
+func NewTriCubic(f, xx, yy, zz []float64) (o *TriCubic) {
+	o = new(TriCubic)
+	o.g = NewGridInterp(MultiCubicType, f, xx, yy, zz)
+	o.x = make([]float64, 3)
+	o.grad = make([]float64, 3)
+	return
+}
+
+// SetDisableHunt disables the hunt function for all axes
+func (o *TriCubic) SetDisableHunt(disable bool) {
+	o.g.SetDisableHunt(disable)
+}
+
+// SetExtrap sets the extrapolation policy
+func (o *TriCubic) SetExtrap(extrap ExtrapType) {
+	o.g.Extrap = extrap
+}
+
+// P is the interpolation polynomial
+func (o *TriCubic) P(x, y, z float64) float64 {
+	o.x[0], o.x[1], o.x[2] = x, y, z
+	return o.g.P(o.x)
+}
+
+// G computes the interpolated value and its gradient
+func (o *TriCubic) G(x, y, z float64) (res, dfdx, dfdy, dfdz float64) {
+	o.x[0], o.x[1], o.x[2] = x, y, z
+	res = o.g.G(o.grad, o.x)
+	return res, o.grad[0], o.grad[1], o.grad[2]
+}
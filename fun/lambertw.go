@@ -0,0 +1,109 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import "math"
+
+// LambertW returns the principal branch W₀(x) of the Lambert W function, i.e. the solution w ≥ -1
+// of w eʷ = x, for x ≥ -1/e
+//
+//	Special cases:
+//	  W₀(-1/e) = -1
+//	  W₀(0) = 0
+//	  W₀(+Inf) = +Inf
+//	  W₀(x < -1/e) = NaN
+func LambertW(x float64) float64 {
+	if math.IsNaN(x) || x < -lambertEm1 {
+		return math.NaN()
+	}
+	if x == 0 || math.IsInf(x, +1) {
+		return x
+	}
+	if x == -lambertEm1 {
+		return -1
+	}
+	var w float64
+	switch {
+	case x < -0.32: // series about the branch point
+		p := lambertP(x)
+		if p < 1e-3 {
+			return lambertBranch(p)
+		}
+		w = -1.0 + p*(1.0+p*(-1.0/3.0+p*11.0/72.0))
+	case x < 3:
+		w = math.Log1p(x)
+	default:
+		l := math.Log(x)
+		w = l - math.Log(l)
+	}
+	return lambertHalley(x, w)
+}
+
+// LambertWm1 returns the lower branch W₋₁(x) of the Lambert W function, i.e. the solution w ≤ -1
+// of w eʷ = x, for -1/e ≤ x < 0
+//
+//	Special cases:
+//	  W₋₁(-1/e) = -1
+//	  W₋₁(0) = -Inf
+//	  W₋₁(x < -1/e or x > 0) = NaN
+func LambertWm1(x float64) float64 {
+	if math.IsNaN(x) || x < -lambertEm1 || x > 0 {
+		return math.NaN()
+	}
+	if x == 0 {
+		return math.Inf(-1)
+	}
+	if x == -lambertEm1 {
+		return -1
+	}
+	var w float64
+	if x < -0.25 { // series about the branch point
+		p := -lambertP(x)
+		if p > -1e-3 {
+			return lambertBranch(p)
+		}
+		w = -1.0 + p*(1.0+p*(-1.0/3.0+p*11.0/72.0))
+	} else {
+		l1 := math.Log(-x)
+		l2 := math.Log(-l1)
+		w = l1 - l2 + l2/l1
+	}
+	return lambertHalley(x, w)
+}
+
+// 1/e split into the nearest float64 and the remainder
+const (
+	lambertEm1   = 0.36787944117144233
+	lambertEm1lo = -1.2428753672788363e-17
+)
+
+// lambertP computes p = √(2(e x + 1)) accurately near the branch point x = -1/e
+func lambertP(x float64) float64 {
+	return math.Sqrt(2.0 * math.E * ((x + lambertEm1) + lambertEm1lo))
+}
+
+// lambertBranch evaluates the series of W about the branch point in terms of p = ±√(2(e x + 1))
+func lambertBranch(p float64) float64 {
+	return -1.0 + p*(1.0+p*(-1.0/3.0+p*(11.0/72.0+p*(-43.0/540.0+p*(769.0/17280.0+p*(-221.0/8505.0))))))
+}
+
+// lambertHalley refines w such that w eʷ = x using Halley's method
+func lambertHalley(x, w float64) float64 {
+	for it := 0; it < 100; it++ {
+		ew := math.Exp(w)
+		f := w*ew - x
+		wp1 := w + 1.0
+		if wp1 == 0 {
+			break
+		}
+		den := ew*wp1 - 0.5*(w+2.0)*f/wp1
+		dw := f / den
+		w -= dw
+		if math.Abs(dw) <= 4.0*2.220446049250313e-16*(1.0+math.Abs(w)) {
+			break
+		}
+	}
+	return w
+}
@@ -22,6 +22,12 @@ const (
 
 	// BiCubicType defines the bi-cubic type
 	BiCubicType InterpType = 3
+
+	// MultiLinearType defines the N-dimensional multi-linear type (see GridInterp)
+	MultiLinearType InterpType = 4
+
+	// MultiCubicType defines the N-dimensional multi-cubic type (see GridInterp)
+	MultiCubicType InterpType = 5
 )
 
 // Axis implements a type to hold an arbitrarily spaced discrete data
@@ -54,10 +60,12 @@ func NewAxis(data []float64, interpType InterpType) (o *Axis) {
 		o.m = 2
 	case BiCubicType:
 		o.m = 3
+	case MultiLinearType, MultiCubicType:
+		o.m = 2 // locate the cell only; GridInterp picks the neighbours
 	}
 
 	// check that axis is strictly monotonic
-	if o.n > 2 {
+	if o.n >= 2 {
 		inc, dec := true, true
 		for i := 1; i < o.n; i++ {
 			if o.data[i] > o.data[i-1] {
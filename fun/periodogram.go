@@ -0,0 +1,151 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/utl"
+)
+
+// LombScargle computes the (normalised) Lomb-Scargle periodogram of unevenly sampled data
+//
+//	P(f) = 1/(2σ²) { [Σ(yᵢ-ȳ) cos ω(tᵢ-τ)]² / Σcos² ω(tᵢ-τ) + [Σ(yᵢ-ȳ) sin ω(tᵢ-τ)]² / Σsin² ω(tᵢ-τ) }
+//
+//	where ω = 2⋅π⋅f, σ² is the sample variance of y and τ is given by
+//
+//	tan(2ωτ) = Σ sin 2ωtᵢ / Σ cos 2ωtᵢ
+//
+//	Input:
+//	  t     -- sampling times
+//	  y     -- data values
+//	  freqs -- frequencies f > 0 (cycles per unit of t) where P is computed
+//
+//	Output:
+//	  P -- periodogram; if y is pure Gaussian noise, P(f) is exponentially distributed with unit mean
+//
+//	References:
+//	  [1] Lomb NR (1976) Least-squares frequency analysis of unequally spaced data, Astrophysics
+//	      and Space Science, 39:447-462
+//	  [2] Scargle JD (1982) Studies in astronomical time series analysis. II. Statistical aspects
+//	      of spectral analysis of unevenly spaced data, The Astrophysical Journal, 263:835-853
+//	  [3] Press WH, Teukolsky SA, Vetterling WT, Flannery BP (2007) Numerical Recipes: The Art of
+//	      Scientific Computing. Third Edition. Cambridge University Press. 1235p
+func LombScargle(t, y, freqs []float64) (P []float64) {
+
+	// check
+	n := len(t)
+	if n != len(y) {
+		chk.Panic("len(t) must be equal to len(y). %d != %d\n", n, len(y))
+	}
+	if n < 3 {
+		chk.Panic("at least 3 data points are required. n = %d is invalid\n", n)
+	}
+
+	// mean and variance
+	var ȳ, σ2 float64
+	for _, v := range y {
+		ȳ += v
+	}
+	ȳ /= float64(n)
+	for _, v := range y {
+		σ2 += (v - ȳ) * (v - ȳ)
+	}
+	σ2 /= float64(n - 1)
+	P = make([]float64, len(freqs))
+	if σ2 == 0 {
+		return
+	}
+
+	// periodogram
+	for k, f := range freqs {
+		ω := 2.0 * math.Pi * f
+		var s2, c2 float64
+		for _, ti := range t {
+			s, c := math.Sincos(2.0 * ω * ti)
+			s2 += s
+			c2 += c
+		}
+		ωτ := 0.5 * math.Atan2(s2, c2)
+		var yc, ys, cc, ss float64
+		for i, ti := range t {
+			s, c := math.Sincos(ω*ti - ωτ)
+			yc += (y[i] - ȳ) * c
+			ys += (y[i] - ȳ) * s
+			cc += c * c
+			ss += s * s
+		}
+		if cc > 0 {
+			P[k] += yc * yc / cc
+		}
+		if ss > 0 {
+			P[k] += ys * ys / ss
+		}
+		P[k] /= 2.0 * σ2
+	}
+	return
+}
+
+// LombScargleFreqs returns a grid of frequencies for the Lomb-Scargle periodogram
+//
+//	The spacing is Δf = 1/(ofac⋅span) and the frequencies go up to hifac times the average Nyquist
+//	frequency n/(2⋅span), where span = max(t) - min(t)
+//
+//	Input:
+//	  t     -- sampling times
+//	  ofac  -- oversampling factor; e.g. 4
+//	  hifac -- highest frequency divided by the average Nyquist frequency; e.g. 1
+func LombScargleFreqs(t []float64, ofac, hifac float64) (freqs []float64) {
+	tmin, tmax := utl.MinMax(t)
+	span := tmax - tmin
+	if span <= 0 {
+		chk.Panic("sampling times must span a positive interval\n")
+	}
+	df := 1.0 / (ofac * span)
+	nf := int(0.5 * ofac * hifac * float64(len(t)))
+	freqs = make([]float64, nf)
+	for i := 0; i < nf; i++ {
+		freqs[i] = float64(i+1) * df
+	}
+	return
+}
+
+// LombScarglePeak finds the frequency with the highest Lomb-Scargle power and its false alarm
+// probability (the probability that pure noise would produce a peak as high as Ppeak)
+//
+//	The frequency grid is given by LombScargleFreqs and the peak is refined by golden-section
+//	search between the neighbouring grid points. The false alarm probability is estimated with
+//
+//	  FAP = 1 - (1 - exp(-Ppeak))ᴹ
+//
+//	where M is the number of independent frequencies, taken as the number of frequencies of the
+//	grid divided by ofac.
+func LombScarglePeak(t, y []float64, ofac, hifac float64) (fpeak, Ppeak, fap float64) {
+	freqs := LombScargleFreqs(t, ofac, hifac)
+	nf := len(freqs)
+	if nf < 1 {
+		chk.Panic("frequency grid is empty. increase ofac or hifac\n")
+	}
+	P := LombScargle(t, y, freqs)
+	k := 0
+	for i := range P {
+		if P[i] > P[k] {
+			k = i
+		}
+	}
+	df := freqs[0]
+	power := func(f float64) float64 {
+		return -LombScargle(t, y, []float64{f})[0]
+	}
+	fpeak = goldenSection(power, math.Max(freqs[k]-df, 0.5*df), freqs[k]+df, 1e-10*freqs[k], 200)
+	Ppeak = -power(fpeak)
+	if Ppeak < P[k] {
+		fpeak, Ppeak = freqs[k], P[k]
+	}
+	M := math.Max(float64(nf)/ofac, 1)
+	fap = -math.Expm1(M * math.Log1p(-math.Exp(-Ppeak)))
+	return
+}
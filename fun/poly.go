@@ -0,0 +1,517 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/utl"
+)
+
+// PolyBasis defines the basis in which the coefficients of a polynomial are given
+type PolyBasis int
+
+const (
+	// PolyMonomial indicates p(x) = Σ cᵢ xⁱ
+	PolyMonomial PolyBasis = iota
+
+	// PolyChebyshev indicates p(x) = Σ cᵢ Tᵢ(x) where Tᵢ are Chebyshev polynomials of first kind
+	PolyChebyshev
+)
+
+// Poly implements a polynomial with real coefficients in the monomial or Chebyshev basis
+//
+//	         n                             n
+//	p(x) =   Σ  cᵢ xⁱ      or      p(x) =  Σ  cᵢ Tᵢ(x)
+//	        i=0                           i=0
+//
+//	The binary operations return a polynomial in the basis of the receiver; the argument is
+//	converted to the receiver's basis if necessary.
+//
+//	The roots are computed as the eigenvalues of the companion (monomial) or colleague
+//	(Chebyshev) matrix [1,2] and then polished with Newton's method.
+//
+//	References:
+//	  [1] Edelman A, Murakami H (1995) Polynomial roots from companion matrix eigenvalues,
+//	      Mathematics of Computation, 64(210):763-776
+//	  [2] Trefethen LN (2013) Approximation Theory and Approximation Practice. SIAM. 305p
+type Poly struct {
+	Basis PolyBasis // basis
+	C     []float64 // coefficients in ascending order; C[i] multiplies xⁱ or Tᵢ(x)
+}
+
+// NewPoly returns a new polynomial in the monomial basis
+//
+//	coef -- coefficients in ascending order: p(x) = coef[0] + coef[1]⋅x + coef[2]⋅x² + ...
+func NewPoly(coef ...float64) (o *Poly) {
+	o = &Poly{Basis: PolyMonomial, C: make([]float64, len(coef))}
+	copy(o.C, coef)
+	if len(o.C) == 0 {
+		o.C = []float64{0}
+	}
+	return
+}
+
+// NewPolyCheby returns a new polynomial in the Chebyshev basis
+//
+//	coef -- coefficients: p(x) = coef[0]⋅T₀(x) + coef[1]⋅T₁(x) + coef[2]⋅T₂(x) + ...
+func NewPolyCheby(coef ...float64) (o *Poly) {
+	o = NewPoly(coef...)
+	o.Basis = PolyChebyshev
+	return
+}
+
+// NewPolyFromRoots returns the monic polynomial (monomial basis) p(x) = Π (x - rᵢ)
+func NewPolyFromRoots(roots ...float64) (o *Poly) {
+	o = NewPoly(1)
+	for _, r := range roots {
+		o = o.Mul(NewPoly(-r, 1))
+	}
+	return
+}
+
+// Degree returns the degree of the polynomial disregarding trailing zero coefficients
+//
+//	NOTE: the degree of the zero polynomial is -1
+func (o *Poly) Degree() int {
+	for i := len(o.C) - 1; i >= 0; i-- {
+		if o.C[i] != 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetCopy returns a copy of this polynomial
+func (o *Poly) GetCopy() (p *Poly) {
+	p = &Poly{Basis: o.Basis, C: make([]float64, len(o.C))}
+	copy(p.C, o.C)
+	return
+}
+
+// F computes p(x) using Horner's method (monomial) or Clenshaw's algorithm (Chebyshev)
+func (o *Poly) F(x float64) float64 {
+	n := len(o.C) - 1
+	if o.Basis == PolyMonomial {
+		res := o.C[n]
+		for i := n - 1; i >= 0; i-- {
+			res = res*x + o.C[i]
+		}
+		return res
+	}
+	var b1, b2 float64
+	for i := n; i > 0; i-- {
+		b1, b2 = o.C[i]+2.0*x*b1-b2, b1
+	}
+	return o.C[0] + x*b1 - b2
+}
+
+// FC computes p(z) for complex z
+func (o *Poly) FC(z complex128) complex128 {
+	n := len(o.C) - 1
+	if o.Basis == PolyMonomial {
+		res := complex(o.C[n], 0)
+		for i := n - 1; i >= 0; i-- {
+			res = res*z + complex(o.C[i], 0)
+		}
+		return res
+	}
+	var b1, b2 complex128
+	for i := n; i > 0; i-- {
+		b1, b2 = complex(o.C[i], 0)+2.0*z*b1-b2, b1
+	}
+	return complex(o.C[0], 0) + z*b1 - b2
+}
+
+// ToMonomial returns a copy of this polynomial converted to the monomial basis
+func (o *Poly) ToMonomial() (p *Poly) {
+	if o.Basis == PolyMonomial {
+		return o.GetCopy()
+	}
+	n := len(o.C) - 1
+	x := NewPoly(0, 1)
+	b1, b2 := NewPoly(0), NewPoly(0)
+	for i := n; i > 0; i-- {
+		b1, b2 = x.Mul(b1).Scale(2).Sub(b2).addConst(o.C[i]), b1
+	}
+	p = x.Mul(b1).Sub(b2).addConst(o.C[0])
+	p.C = p.C[:n+1]
+	return
+}
+
+// ToCheby returns a copy of this polynomial converted to the Chebyshev basis
+func (o *Poly) ToCheby() (p *Poly) {
+	if o.Basis == PolyChebyshev {
+		return o.GetCopy()
+	}
+	n := len(o.C) - 1
+	x := NewPolyCheby(0, 1)
+	p = NewPolyCheby(o.C[n])
+	for i := n - 1; i >= 0; i-- {
+		p = p.Mul(x).addConst(o.C[i])
+	}
+	p.C = p.C[:n+1]
+	return
+}
+
+// Add returns o + p
+func (o *Poly) Add(p *Poly) (r *Poly) {
+	p = o.inBasis(p)
+	r = &Poly{Basis: o.Basis, C: make([]float64, utl.Imax(len(o.C), len(p.C)))}
+	copy(r.C, o.C)
+	for i, c := range p.C {
+		r.C[i] += c
+	}
+	return
+}
+
+// Sub returns o - p
+func (o *Poly) Sub(p *Poly) (r *Poly) {
+	return o.Add(p.Scale(-1))
+}
+
+// Scale returns α⋅o
+func (o *Poly) Scale(α float64) (r *Poly) {
+	r = o.GetCopy()
+	for i := range r.C {
+		r.C[i] *= α
+	}
+	return
+}
+
+// Mul returns o⋅p
+//
+//	The Chebyshev product uses Tᵢ⋅Tⱼ = (Tᵢ₊ⱼ + T|ᵢ₋ⱼ|) / 2
+func (o *Poly) Mul(p *Poly) (r *Poly) {
+	p = o.inBasis(p)
+	r = &Poly{Basis: o.Basis, C: make([]float64, len(o.C)+len(p.C)-1)}
+	for i, a := range o.C {
+		if a == 0 {
+			continue
+		}
+		for j, b := range p.C {
+			if o.Basis == PolyMonomial {
+				r.C[i+j] += a * b
+			} else {
+				r.C[i+j] += 0.5 * a * b
+				r.C[utl.Iabs(i-j)] += 0.5 * a * b
+			}
+		}
+	}
+	return
+}
+
+// DivMod returns the quotient q and remainder r such that o = q⋅p + r with deg(r) < deg(p)
+//
+//	The long division is carried out directly in the basis of the receiver. For the Chebyshev
+//	basis, the leading term is eliminated with Tₖ = 2⋅Tₖ₋ₘ⋅Tₘ - T|ₖ₋₂ₘ|
+func (o *Poly) DivMod(p *Poly) (q, r *Poly) {
+	p = o.inBasis(p)
+	m := p.Degree()
+	if m < 0 {
+		chk.Panic("cannot divide by the zero polynomial\n")
+	}
+	r = o.GetCopy()
+	n := r.Degree()
+	q = &Poly{Basis: o.Basis, C: make([]float64, utl.Imax(n-m+1, 1))}
+	lead := p.C[m]
+	for k := n; k >= m; k-- {
+		if r.C[k] == 0 {
+			continue
+		}
+		d := k - m
+		t := r.C[k] / lead
+		if o.Basis == PolyChebyshev && d > 0 && m > 0 { // Tₖ = Tₖ⋅T₀ if m = 0
+			t *= 2.0
+		}
+		q.C[d] += t
+		for j := 0; j <= m; j++ {
+			if o.Basis == PolyMonomial {
+				r.C[d+j] -= t * p.C[j]
+			} else {
+				r.C[d+j] -= 0.5 * t * p.C[j]
+				r.C[utl.Iabs(d-j)] -= 0.5 * t * p.C[j]
+			}
+		}
+		r.C[k] = 0 // exact elimination of the leading term
+	}
+	nr := utl.Imax(m, 1)
+	if len(r.C) < nr {
+		r.C = append(r.C, make([]float64, nr-len(r.C))...)
+	}
+	r.C = r.C[:nr]
+	return
+}
+
+// Compose returns the composition o(p(x))
+func (o *Poly) Compose(p *Poly) (r *Poly) {
+	p = o.inBasis(p)
+	n := len(o.C) - 1
+	if o.Basis == PolyMonomial {
+		r = NewPoly(o.C[n])
+		for i := n - 1; i >= 0; i-- {
+			r = r.Mul(p).addConst(o.C[i])
+		}
+		return
+	}
+	b1, b2 := NewPolyCheby(0), NewPolyCheby(0)
+	for i := n; i > 0; i-- {
+		b1, b2 = p.Mul(b1).Scale(2).Sub(b2).addConst(o.C[i]), b1
+	}
+	return p.Mul(b1).Sub(b2).addConst(o.C[0])
+}
+
+// Deriv returns the derivative dp/dx
+//
+//	The Chebyshev coefficients are computed with the recurrence c'ₖ₋₁ = c'ₖ₊₁ + 2k⋅cₖ
+func (o *Poly) Deriv() (r *Poly) {
+	n := len(o.C) - 1
+	r = &Poly{Basis: o.Basis, C: make([]float64, utl.Imax(n, 1))}
+	if n == 0 {
+		return
+	}
+	if o.Basis == PolyMonomial {
+		for i := 1; i <= n; i++ {
+			r.C[i-1] = float64(i) * o.C[i]
+		}
+		return
+	}
+	d := make([]float64, n+2)
+	for k := n; k >= 1; k-- {
+		d[k-1] = d[k+1] + 2.0*float64(k)*o.C[k]
+	}
+	d[0] /= 2.0
+	copy(r.C, d[:n])
+	return
+}
+
+// Integ returns the indefinite integral P(x) = ∫ p(x) dx such that P(x0) = 0
+//
+//	The Chebyshev coefficients are computed with bₖ = (cₖ₋₁ - cₖ₊₁) / (2k) and b₁ = c₀ - c₂/2
+func (o *Poly) Integ(x0 float64) (r *Poly) {
+	n := len(o.C) - 1
+	r = &Poly{Basis: o.Basis, C: make([]float64, n+2)}
+	if o.Basis == PolyMonomial {
+		for i := 0; i <= n; i++ {
+			r.C[i+1] = o.C[i] / float64(i+1)
+		}
+	} else {
+		c := make([]float64, n+3)
+		copy(c, o.C)
+		r.C[1] = c[0] - c[2]/2.0
+		for k := 2; k <= n+1; k++ {
+			r.C[k] = (c[k-1] - c[k+1]) / (2.0 * float64(k))
+		}
+	}
+	r.C[0] = -r.F(x0)
+	return
+}
+
+// Integral returns the definite integral ∫ₐᵇ p(x) dx
+func (o *Poly) Integral(a, b float64) float64 {
+	P := o.Integ(a)
+	return P.F(b)
+}
+
+// GCD returns the greatest common divisor of o and p using the Euclidean algorithm
+//
+//	tol -- relative tolerance to consider a remainder as zero; e.g. 1e-10. Coefficients with
+//	       absolute value smaller than tol⋅max|cᵢ| are dropped from the remainders.
+//
+//	NOTE: the result is normalised such that the leading coefficient is equal to one
+func (o *Poly) GCD(p *Poly, tol float64) (g *Poly) {
+	a, b := o.GetCopy(), o.inBasis(p).GetCopy()
+	scale := math.Max(maxAbsSlice(a.C), maxAbsSlice(b.C))
+	a.trimTol(tol * scale)
+	b.trimTol(tol * scale)
+	if a.Degree() < b.Degree() {
+		a, b = b, a
+	}
+	for b.Degree() >= 0 {
+		_, r := a.DivMod(b)
+		r.trimTol(tol * scale)
+		a, b = b, r
+		if a.Degree() == 0 {
+			break
+		}
+	}
+	n := a.Degree()
+	if n < 0 {
+		return NewPoly(0).inBasisOf(o.Basis)
+	}
+	g = a.Scale(1.0 / a.C[n])
+	g.C = g.C[:n+1]
+	return
+}
+
+// Roots computes all (complex) roots of the polynomial
+//
+//	The roots are the eigenvalues of the companion (monomial) or colleague (Chebyshev) matrix.
+//	Each root is then polished by a few Newton iterations, which must decrease |p| and keep the
+//	root within a third of the distance to the nearest other eigenvalue. Hence, two eigenvalues
+//	cannot be polished onto the same root, which would duplicate it and lose another; this
+//	happens otherwise for polynomials with a large dynamic range such as Wilkinson's, since |p|
+//	is smallest near the small roots. The roots are sorted by real part and then by imaginary
+//	part.
+//
+//	NOTE: multiple roots are only computed with accuracy of about ε^(1/m), where m is the
+//	      multiplicity and ε the machine epsilon
+func (o *Poly) Roots() (roots []complex128) {
+	n := o.Degree()
+	if n < 1 {
+		return
+	}
+	c := o.C[:n+1]
+
+	// zero roots of monomials
+	nz := 0
+	if o.Basis == PolyMonomial {
+		for nz < n && c[nz] == 0 {
+			nz++
+		}
+		c = c[nz:]
+		n -= nz
+	}
+	roots = make([]complex128, nz, nz+n)
+
+	// eigenvalues
+	if n > 0 {
+		A := la.NewMatrix(n, n)
+		lead := c[n]
+		if o.Basis == PolyMonomial {
+			for i := 1; i < n; i++ {
+				A.Set(i, i-1, 1)
+			}
+			for i := 0; i < n; i++ {
+				A.Set(i, n-1, -c[i]/lead)
+			}
+		} else {
+			if n > 1 {
+				A.Set(0, 1, 1)
+			}
+			for i := 1; i < n; i++ {
+				A.Set(i, i-1, 0.5)
+				if i+1 < n {
+					A.Set(i, i+1, 0.5)
+				}
+			}
+			for j := 0; j < n; j++ {
+				A.Add(n-1, j, -c[j]/(2.0*lead))
+			}
+			if n == 1 {
+				A.Set(0, 0, -c[0]/lead)
+			}
+		}
+		w := la.NewVectorC(n)
+		la.EigenVal(w, A, false)
+		roots = append(roots, w...)
+	}
+
+	// polish
+	dp := o.Deriv()
+	polished := make([]complex128, len(roots))
+	copy(polished, roots)
+	for i := nz; i < len(roots); i++ {
+		rad := math.Inf(+1)
+		for j, z := range roots {
+			if j != i {
+				rad = math.Min(rad, cmplx.Abs(z-roots[i])/3.0)
+			}
+		}
+		polished[i] = o.polish(dp, roots[i], rad)
+	}
+	roots = polished
+	sort.Slice(roots, func(i, j int) bool {
+		if real(roots[i]) != real(roots[j]) {
+			return real(roots[i]) < real(roots[j])
+		}
+		return imag(roots[i]) < imag(roots[j])
+	})
+	return
+}
+
+// RealRoots returns the real roots of the polynomial in ascending order
+//
+//	tol -- tolerance to consider a root as real: |Im(z)| ≤ tol⋅max(1,|z|)
+func (o *Poly) RealRoots(tol float64) (roots []float64) {
+	for _, z := range o.Roots() {
+		if math.Abs(imag(z)) <= tol*math.Max(1, cmplx.Abs(z)) {
+			roots = append(roots, real(z))
+		}
+	}
+	return
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// inBasis returns p in the basis of o (p itself if no conversion is needed)
+func (o *Poly) inBasis(p *Poly) *Poly {
+	return p.inBasisOf(o.Basis)
+}
+
+// inBasisOf returns o converted to the given basis (o itself if no conversion is needed)
+func (o *Poly) inBasisOf(basis PolyBasis) *Poly {
+	if o.Basis == basis {
+		return o
+	}
+	if basis == PolyMonomial {
+		return o.ToMonomial()
+	}
+	return o.ToCheby()
+}
+
+// addConst adds a constant to o (in place) and returns o. Note that T₀ = 1
+func (o *Poly) addConst(c float64) *Poly {
+	o.C[0] += c
+	return o
+}
+
+// trimTol sets to zero the trailing coefficients with absolute values smaller than or equal to tol
+func (o *Poly) trimTol(tol float64) {
+	for i := len(o.C) - 1; i >= 0; i-- {
+		if math.Abs(o.C[i]) > tol {
+			break
+		}
+		o.C[i] = 0
+	}
+}
+
+// polish improves the root z using Newton's method in complex arithmetic. A step is rejected if
+// |p| does not decrease or if z moves farther than rad from its initial value
+func (o *Poly) polish(dp *Poly, z complex128, rad float64) complex128 {
+	z0 := z
+	fz := cmplx.Abs(o.FC(z))
+	for it := 0; it < 10; it++ {
+		d := dp.FC(z)
+		if d == 0 {
+			break
+		}
+		znew := z - o.FC(z)/d
+		if cmplx.Abs(znew-z0) > rad {
+			break
+		}
+		fnew := cmplx.Abs(o.FC(znew))
+		if fnew >= fz {
+			break
+		}
+		z, fz = znew, fnew
+		if fz == 0 {
+			break
+		}
+	}
+	return z
+}
+
+// maxAbsSlice returns max|vᵢ|
+func maxAbsSlice(v []float64) (res float64) {
+	for _, x := range v {
+		res = math.Max(res, math.Abs(x))
+	}
+	return
+}
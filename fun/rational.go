@@ -0,0 +1,702 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/utl"
+)
+
+// BaryRational implements a rational function in barycentric form
+//
+//	        m   wⱼ fⱼ          m    wⱼ
+//	r(x) =  Σ  ———————   /     Σ  ———————
+//	       j=0  x - zⱼ        j=0  x - zⱼ
+//
+//	where zⱼ are the support points, fⱼ = r(zⱼ) the values and wⱼ the weights. The degree of r
+//	is (m, m).
+//
+//	References:
+//	  [1] Nakatsukasa Y, Sète O, Trefethen LN (2018) The AAA algorithm for rational approximation,
+//	      SIAM J. Sci. Comput., 40(3):A1494-A1522
+//	  [2] Gonnet P, Güttel S, Trefethen LN (2013) Robust Padé approximation via SVD, SIAM Review,
+//	      55(1):101-117
+type BaryRational struct {
+	Z   []float64 // support points zⱼ
+	Val []float64 // values at support points fⱼ
+	W   []float64 // barycentric weights wⱼ
+}
+
+// NewAAA computes a rational approximation of the data (x, f) using the AAA (adaptive
+// Antoulas-Anderson) algorithm [1]
+//
+//	x    -- sample points (all distinct)
+//	f    -- values at sample points
+//	tol  -- relative tolerance on max|f - r|; use 0 for default value [=1e-13]
+//	mmax -- maximum number of support points; use 0 for default value [=100]
+//
+//	NOTE: the maximum error at the sample points, max|f - r|, is also returned
+func NewAAA(x, f []float64, tol float64, mmax int) (o *BaryRational, maxErr float64) {
+
+	// check
+	M := len(x)
+	if M < 1 || len(f) != M {
+		chk.Panic("the number of sample points (%d) must be equal to the number of values (%d) and greater than zero\n", M, len(f))
+	}
+	if tol <= 0 {
+		tol = 1e-13
+	}
+	if mmax <= 0 {
+		mmax = 100
+	}
+	mmax = utl.Imin(mmax, M)
+
+	// initial approximation: mean value
+	o = new(BaryRational)
+	fmax := maxAbsSlice(f)
+	r := make([]float64, M)
+	mean := 0.0
+	for _, v := range f {
+		mean += v
+	}
+	mean /= float64(M)
+	for i := range r {
+		r[i] = mean
+	}
+	free := make([]bool, M) // sample points that are not support points
+	for i := range free {
+		free[i] = true
+	}
+
+	// greedy iterations
+	for m := 0; m < mmax; m++ {
+
+		// next support point: largest residual
+		j, emax := 0, -1.0
+		for i := 0; i < M; i++ {
+			if e := math.Abs(f[i] - r[i]); free[i] && e > emax {
+				j, emax = i, e
+			}
+		}
+		o.Z = append(o.Z, x[j])
+		o.Val = append(o.Val, f[j])
+		free[j] = false
+		ns := len(o.Z)
+
+		// Loewner matrix
+		rows := make([]int, 0, M)
+		for i := 0; i < M; i++ {
+			if free[i] {
+				rows = append(rows, i)
+			}
+		}
+		A := la.NewMatrix(utl.Imax(len(rows), ns), ns)
+		for k, i := range rows {
+			for l := 0; l < ns; l++ {
+				A.Set(k, l, (f[i]-o.Val[l])/(x[i]-o.Z[l]))
+			}
+		}
+
+		// weights: right singular vector of the smallest singular value
+		o.W = smallestRightSingularVector(A)
+
+		// residuals
+		maxErr = 0
+		for i := 0; i < M; i++ {
+			if free[i] {
+				r[i] = o.F(x[i])
+			} else {
+				r[i] = f[i]
+			}
+			maxErr = math.Max(maxErr, math.Abs(f[i]-r[i]))
+		}
+		if maxErr <= tol*fmax {
+			break
+		}
+	}
+	return
+}
+
+// F evaluates r(x); this method can be used as a fun.Ss function
+func (o *BaryRational) F(x float64) float64 {
+	var num, den float64
+	for j, z := range o.Z {
+		if x == z {
+			return o.Val[j]
+		}
+		c := o.W[j] / (x - z)
+		num += c * o.Val[j]
+		den += c
+	}
+	return num / den
+}
+
+// FC evaluates r(z) for complex z
+func (o *BaryRational) FC(z complex128) complex128 {
+	var num, den complex128
+	for j, zj := range o.Z {
+		if z == complex(zj, 0) {
+			return complex(o.Val[j], 0)
+		}
+		c := complex(o.W[j], 0) / (z - complex(zj, 0))
+		num += c * complex(o.Val[j], 0)
+		den += c
+	}
+	return num / den
+}
+
+// Poles returns the poles of r, i.e. the zeros of the denominator
+//
+//	The poles are the finite eigenvalues of the generalised problem E v = λ B v [1] with
+//
+//	      ┌  0   w₀  w₁ ⋯ ┐        ┌ 0        ┐
+//	  E = │  1   z₀       │    B = │    1     │
+//	      │  1       z₁   │        │      1   │
+//	      └  ⋮          ⋱ ┘        └        ⋱ ┘
+//
+//	The two spurious infinite eigenvalues are deflated with Householder reflectors.
+func (o *BaryRational) Poles() []complex128 {
+	return baryRoots(o.Z, o.W)
+}
+
+// Zeros returns the zeros of r, i.e. the zeros of the numerator
+func (o *BaryRational) Zeros() []complex128 {
+	wf := make([]float64, len(o.W))
+	for j := range wf {
+		wf[j] = o.W[j] * o.Val[j]
+	}
+	return baryRoots(o.Z, wf)
+}
+
+// Residues returns the residues of r at the given poles
+//
+//	                 Σ wⱼ fⱼ/(λ - zⱼ)
+//	res(λ) = - ——————————————————————
+//	              Σ wⱼ/(λ - zⱼ)²
+func (o *BaryRational) Residues(poles []complex128) (res []complex128) {
+	res = make([]complex128, len(poles))
+	for k, λ := range poles {
+		var num, dden complex128
+		for j, zj := range o.Z {
+			c := complex(o.W[j], 0) / (λ - complex(zj, 0))
+			num += c * complex(o.Val[j], 0)
+			dden -= c / (λ - complex(zj, 0))
+		}
+		res[k] = num / dden
+	}
+	return
+}
+
+// RatPoly implements a rational function as the quotient of two polynomials
+//
+//	        P(t)             x - X0
+//	r(x) = ——————    with   t = ——————
+//	        Q(t)               H
+type RatPoly struct {
+	P  *Poly   // numerator
+	Q  *Poly   // denominator
+	X0 float64 // shift of the variable
+	H  float64 // scale of the variable
+}
+
+// F evaluates r(x); this method can be used as a fun.Ss function
+func (o *RatPoly) F(x float64) float64 {
+	t := (x - o.X0) / o.H
+	return o.P.F(t) / o.Q.F(t)
+}
+
+// Poles returns the poles of r, i.e. the roots of Q mapped to the x variable
+func (o *RatPoly) Poles() (poles []complex128) {
+	poles = o.Q.Roots()
+	for i, t := range poles {
+		poles[i] = complex(o.X0, 0) + complex(o.H, 0)*t
+	}
+	return
+}
+
+// NewPade computes the [m/n] Padé approximant about x0 from the Taylor coefficients of f
+//
+//	         m+n
+//	f(x) ≈    Σ  cₖ (x - x0)ᵏ    ⇒    r(x) = P(x - x0) / Q(x - x0)   with   Q(0) = 1
+//	         k=0
+//
+//	c -- Taylor coefficients; len(c) must be at least m+n+1
+//
+//	NOTE: the denominator is the null vector of the n×(n+1) Toeplitz matrix of the coefficients
+//	      c_{m+1} … c_{m+n}, computed by the SVD [2]. If this matrix is rank deficient (e.g. cos
+//	      with m = n = 1 since c₁ = 0), the [m/n] approximant does not exist as such and the
+//	      degrees are reduced until the matrix has full rank; common factors (x - x0)ᵏ are then
+//	      removed. Thus, P and Q may have degrees lower than m and n
+func NewPade(c []float64, x0 float64, m, n int) (o *RatPoly) {
+
+	// check
+	if m < 0 || n < 0 {
+		chk.Panic("degrees must be non-negative. m=%d and n=%d are invalid\n", m, n)
+	}
+	if len(c) < m+n+1 {
+		chk.Panic("at least m+n+1 = %d Taylor coefficients are required. %d is invalid\n", m+n+1, len(c))
+	}
+	coef := func(k int) float64 {
+		if k < 0 {
+			return 0
+		}
+		return c[k]
+	}
+
+	// tolerance on the singular values and coefficients
+	var cnrm, pnrm float64
+	for k := 0; k <= m+n; k++ {
+		cnrm += c[k] * c[k]
+		if k <= m {
+			pnrm += c[k] * c[k]
+		}
+	}
+	tol := 1e-14 * math.Sqrt(cnrm)
+	if math.Sqrt(pnrm) <= tol {
+		return &RatPoly{P: NewPoly(0), Q: NewPoly(1), X0: x0, H: 1}
+	}
+
+	// denominator: C q = 0 with C_{ij} = c_{m+1+i-j}, i = 0…n-1, j = 0…n. The degrees are
+	// reduced while C is rank deficient
+	q := []float64{1}
+	for n > 0 {
+		C := la.NewMatrix(n+1, n+1) // the last row is zero
+		for i := 0; i < n; i++ {
+			for j := 0; j <= n; j++ {
+				C.Set(i, j, coef(m+1+i-j))
+			}
+		}
+		s := make([]float64, n+1)
+		U := la.NewMatrix(n+1, n+1)
+		Vt := la.NewMatrix(n+1, n+1)
+		la.MatSvd(s, U, Vt, C, false)
+		rank := 0
+		for _, σ := range s[:n] {
+			if σ > tol {
+				rank++
+			}
+		}
+		if rank == n {
+			q = make([]float64, n+1)
+			for j := 0; j <= n; j++ {
+				q[j] = Vt.Get(n, j)
+			}
+			break
+		}
+		m, n = utl.Imax(m-(n-rank), 0), rank
+	}
+
+	// numerator: p_k = Σ_{j=0}^{min(k,n)} q_j c_{k-j}
+	p := make([]float64, m+1)
+	for k := 0; k <= m; k++ {
+		for j := 0; j <= utl.Imin(k, n); j++ {
+			p[k] += q[j] * c[k-j]
+		}
+	}
+
+	// remove common factors (x - x0)ᵏ and negligible trailing coefficients; normalise Q(0) = 1
+	var qnrm float64
+	for _, v := range q {
+		qnrm += v * v
+	}
+	qtol := 1e-14 * math.Sqrt(qnrm)
+	lam := 0
+	for math.Abs(q[lam]) <= qtol {
+		lam++
+	}
+	q = q[lam:]
+	p = p[utl.Imin(lam, len(p)-1):]
+	for len(q) > 1 && math.Abs(q[len(q)-1]) <= qtol {
+		q = q[:len(q)-1]
+	}
+	for len(p) > 1 && math.Abs(p[len(p)-1]) <= tol {
+		p = p[:len(p)-1]
+	}
+	q0 := q[0]
+	for k := range q {
+		q[k] /= q0
+	}
+	for k := range p {
+		p[k] /= q0
+	}
+	return &RatPoly{P: NewPoly(p...), Q: NewPoly(q...), X0: x0, H: 1}
+}
+
+// Remez computes the best uniform (minimax) rational approximation of degree (m, n) of f over
+// [xmin, xmax] using the Remez exchange algorithm
+//
+//	The polynomials are written in the Chebyshev basis of t ∈ [-1, 1]. For n = 0, the best
+//	polynomial approximation of degree m is computed. For n > 0, the (nonlinear) reference
+//	equations are solved by a linearised iteration on the levelled error E:
+//
+//	  P(tᵢ) - (fᵢ + (-1)ⁱ E_old) (Q(tᵢ) - 1) - (-1)ⁱ E = fᵢ     with   Q = 1 + Σ qₖ Tₖ
+//
+//	tol   -- relative tolerance on the equioscillation: (max|e| - |E|) / max|e| ≤ tol
+//	         use 0 for default value [=1e-8]
+//	maxIt -- maximum number of iterations; use 0 for default value [=100]
+//
+//	NOTE: (1) the rational case may fail for functions whose best approximation is degenerate
+//	      (2) maxErr is the maximum error estimated on a fine grid
+//	      (3) the iterations also stop when maxErr is at the rounding level, i.e. a few ulps of
+//	          max|f|, since the error of an exact fit (e.g. x² with m = 2) does not equioscillate
+func Remez(f Ss, xmin, xmax float64, m, n int, tol float64, maxIt int) (r *RatPoly, maxErr float64, err error) {
+
+	// check
+	if xmax <= xmin {
+		return nil, 0, chk.Err("xmax must be greater than xmin. [%g, %g] is invalid\n", xmin, xmax)
+	}
+	if m < 0 || n < 0 {
+		return nil, 0, chk.Err("degrees must be non-negative. m=%d and n=%d are invalid\n", m, n)
+	}
+	if tol <= 0 {
+		tol = 1e-8
+	}
+	if maxIt <= 0 {
+		maxIt = 100
+	}
+
+	// constants
+	npts := m + n + 2
+	x0, h := (xmax+xmin)/2.0, (xmax-xmin)/2.0
+	g := func(t float64) float64 { return f(x0 + h*t) }
+	r = &RatPoly{X0: x0, H: h}
+
+	// scale of f for the absolute stopping criterion
+	const ε = 2.220446049250313e-16 // machine epsilon
+	var fmax float64
+	for _, t := range utl.LinSpace(-1, 1, 101) {
+		fmax = math.Max(fmax, math.Abs(g(t)))
+	}
+
+	// initial reference: Chebyshev extreme points
+	ref := make([]float64, npts)
+	for i := 0; i < npts; i++ {
+		ref[i] = -math.Cos(float64(i) * math.Pi / float64(npts-1))
+	}
+
+	// iterations
+	E := 0.0
+	for it := 0; it < maxIt; it++ {
+
+		// solve reference equations
+		fr := make([]float64, npts)
+		for i, t := range ref {
+			fr[i] = g(t)
+		}
+		for inner := 0; inner < 100; inner++ {
+			A := la.NewMatrix(npts, npts)
+			b := la.NewVector(npts)
+			for i, t := range ref {
+				sgn := NegOnePowN(i)
+				for k := 0; k <= m; k++ {
+					A.Set(i, k, ChebyshevT(k, t))
+				}
+				for k := 1; k <= n; k++ {
+					A.Set(i, m+k, -(fr[i]+sgn*E)*ChebyshevT(k, t))
+				}
+				A.Set(i, npts-1, -sgn)
+				b[i] = fr[i]
+			}
+			sol := la.NewVector(npts)
+			la.DenSolve(sol, A, b, false)
+			r.P = NewPolyCheby(sol[:m+1]...)
+			r.Q = NewPolyCheby(append([]float64{1}, sol[m+1:m+1+n]...)...)
+			Enew := sol[npts-1]
+			done := n == 0 || math.Abs(Enew-E) <= 1e-14*math.Max(1, math.Abs(Enew))
+			E = Enew
+			if done {
+				break
+			}
+		}
+
+		// new reference from the extrema of the error
+		e := func(t float64) float64 { return g(t) - r.P.F(t)/r.Q.F(t) }
+		var newRef []float64
+		newRef, maxErr = remezExtrema(e, npts)
+		if maxErr <= 16*ε*fmax {
+			return
+		}
+		if len(newRef) < npts {
+			return r, maxErr, chk.Err("Remez: the error does not equioscillate at %d points\n", npts)
+		}
+		ref = newRef
+		if (maxErr-math.Abs(E))/maxErr <= tol {
+			return
+		}
+	}
+	return r, maxErr, chk.Err("Remez did not converge after %d iterations\n", maxIt)
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// smallestRightSingularVector returns the right singular vector of A (M ≥ N) corresponding to
+// the smallest singular value. A is first reduced to the N×N triangular factor R of its QR
+// factorisation (Householder); then the SVD of R is computed.
+func smallestRightSingularVector(A *la.Matrix) (v []float64) {
+	N := A.N
+	householderQR(A, nil)
+	R := la.NewMatrix(N, N)
+	for i := 0; i < N; i++ {
+		for j := i; j < N; j++ {
+			R.Set(i, j, A.Get(i, j))
+		}
+	}
+	s := make([]float64, N)
+	U := la.NewMatrix(N, N)
+	Vt := la.NewMatrix(N, N)
+	la.MatSvd(s, U, Vt, R, false)
+	v = make([]float64, N)
+	for j := 0; j < N; j++ {
+		v[j] = Vt.Get(N-1, j)
+	}
+	return
+}
+
+// householderQR reduces A (M ≥ N) to the upper triangular factor R of its QR factorisation by
+// means of Householder reflections, in place. The reflections are also applied to b (if not nil),
+// which then holds Qᵀb
+func householderQR(A *la.Matrix, b []float64) {
+	M, N := A.M, A.N
+	for k := 0; k < N; k++ {
+		var nrm float64
+		for i := k; i < M; i++ {
+			nrm += A.Get(i, k) * A.Get(i, k)
+		}
+		nrm = math.Sqrt(nrm)
+		if nrm == 0 {
+			continue
+		}
+		α := -math.Copysign(nrm, A.Get(k, k))
+		u := make([]float64, M-k)
+		for i := k; i < M; i++ {
+			u[i-k] = A.Get(i, k)
+		}
+		u[0] -= α
+		var unrm2 float64
+		for _, ui := range u {
+			unrm2 += ui * ui
+		}
+		if unrm2 == 0 {
+			continue
+		}
+		for j := k; j < N; j++ {
+			var d float64
+			for i := k; i < M; i++ {
+				d += u[i-k] * A.Get(i, j)
+			}
+			d *= 2.0 / unrm2
+			for i := k; i < M; i++ {
+				A.Add(i, j, -d*u[i-k])
+			}
+		}
+		if b != nil {
+			var d float64
+			for i := k; i < M; i++ {
+				d += u[i-k] * b[i]
+			}
+			d *= 2.0 / unrm2
+			for i := k; i < M; i++ {
+				b[i] -= d * u[i-k]
+			}
+		}
+	}
+}
+
+// baryRoots computes the (finite) roots of Σ wⱼ/(λ - zⱼ) = 0 (see BaryRational.Poles)
+//
+//	With the reflectors P and Q such that P 1 = √m e₀ and Q w = |w| e₀, the pencil (E, B) is
+//	reduced to (X, Y) = (P Z Q, P Q) and the finite eigenvalues are those of the trailing
+//	(m-1)×(m-1) blocks (X₂₂, Y₂₂). These are computed from ν = 1/(λ - σ), the eigenvalues of
+//	(X₂₂ - σY₂₂)⁻¹ Y₂₂, where σ is a real shift outside the support.
+func baryRoots(z, w []float64) (roots []complex128) {
+
+	// check
+	m := len(z)
+	if m < 2 {
+		return
+	}
+
+	// deflated pencil
+	one := make([]float64, m)
+	for j := range one {
+		one[j] = 1
+	}
+	P, Q := householderMat(one), householderMat(w)
+	Z := la.NewMatrix(m, m)
+	zmin, zmax := z[0], z[0]
+	for j, zj := range z {
+		Z.Set(j, j, zj)
+		zmin = math.Min(zmin, zj)
+		zmax = math.Max(zmax, zj)
+	}
+	σ := zmax + 0.7*(zmax-zmin) + 1.0
+	PZ := la.NewMatrix(m, m)
+	X := la.NewMatrix(m, m)
+	Y := la.NewMatrix(m, m)
+	la.MatMatMul(PZ, 1, P, Z)
+	la.MatMatMul(X, 1, PZ, Q)
+	la.MatMatMul(Y, 1, P, Q)
+	n := m - 1
+	K := la.NewMatrix(n, n)
+	Y22 := la.NewMatrix(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			K.Set(i, j, X.Get(i+1, j+1)-σ*Y.Get(i+1, j+1))
+			Y22.Set(i, j, Y.Get(i+1, j+1))
+		}
+	}
+	Ki := la.NewMatrix(n, n)
+	la.MatInv(Ki, K, false)
+	M := la.NewMatrix(n, n)
+	la.MatMatMul(M, 1, Ki, Y22)
+
+	// eigenvalues ν = 1/(λ - σ); the zero ones correspond to infinite roots
+	ν := la.NewVectorC(n)
+	la.EigenVal(ν, M, false)
+	var νmax float64
+	for _, v := range ν {
+		νmax = math.Max(νmax, cmplx.Abs(v))
+	}
+	for _, v := range ν {
+		if cmplx.Abs(v) > 1e-12*νmax {
+			roots = append(roots, complex(σ, 0)+1/v)
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		if real(roots[i]) != real(roots[j]) {
+			return real(roots[i]) < real(roots[j])
+		}
+		return imag(roots[i]) < imag(roots[j])
+	})
+	return
+}
+
+// householderMat returns the reflector H = I - 2 v vᵀ / vᵀv such that H x is parallel to e₀
+func householderMat(x []float64) (H *la.Matrix) {
+	n := len(x)
+	v := make([]float64, n)
+	copy(v, x)
+	var nrm float64
+	for _, xi := range x {
+		nrm += xi * xi
+	}
+	v[0] += math.Copysign(math.Sqrt(nrm), x[0])
+	var vv float64
+	for _, vi := range v {
+		vv += vi * vi
+	}
+	H = la.NewMatrix(n, n)
+	for i := 0; i < n; i++ {
+		H.Set(i, i, 1)
+		if vv == 0 {
+			continue
+		}
+		for j := 0; j < n; j++ {
+			H.Add(i, j, -2.0*v[i]*v[j]/vv)
+		}
+	}
+	return
+}
+
+// remezExtrema locates the alternating extrema of e(t) over [-1, 1] and selects npts of them
+func remezExtrema(e Ss, npts int) (ref []float64, maxErr float64) {
+
+	// sample
+	ns := utl.Imax(2000, 100*npts)
+	ts := utl.LinSpace(-1, 1, ns)
+	es := make([]float64, ns)
+	for i, t := range ts {
+		es[i] = e(t)
+	}
+
+	// extremum of |e| in each run of constant sign
+	type extremum struct{ t, e float64 }
+	var ext []extremum
+	refine := func(i int) extremum {
+		a := ts[utl.Imax(i-1, 0)]
+		b := ts[utl.Imin(i+1, ns-1)]
+		sgn := Sign(es[i])
+		t := goldenSection(func(t float64) float64 { return -sgn * e(t) }, a, b, 1e-15, 60)
+		if et := e(t); math.Abs(et) > math.Abs(es[i]) {
+			return extremum{t, et}
+		}
+		return extremum{ts[i], es[i]}
+	}
+	start := 0
+	for i := 1; i <= ns; i++ {
+		if i == ns || Sign(es[i]) != Sign(es[start]) {
+			best := start
+			for k := start; k < i; k++ {
+				if math.Abs(es[k]) > math.Abs(es[best]) {
+					best = k
+				}
+			}
+			ext = append(ext, refine(best))
+			start = i
+		}
+	}
+	for _, x := range ext {
+		maxErr = math.Max(maxErr, math.Abs(x.e))
+	}
+
+	// remove excess extrema keeping the alternation
+	for len(ext) > npts {
+		if (len(ext)-npts)%2 == 1 {
+			if math.Abs(ext[0].e) < math.Abs(ext[len(ext)-1].e) {
+				ext = ext[1:]
+			} else {
+				ext = ext[:len(ext)-1]
+			}
+			continue
+		}
+		k := 0
+		for i := range ext {
+			if math.Abs(ext[i].e) < math.Abs(ext[k].e) {
+				k = i
+			}
+		}
+		switch {
+		case k == 0:
+			ext = ext[2:]
+		case k == len(ext)-1:
+			ext = ext[:k-1]
+		default:
+			j := k - 1
+			if math.Abs(ext[k+1].e) < math.Abs(ext[k-1].e) {
+				j = k
+			}
+			ext = append(ext[:j], ext[j+2:]...)
+		}
+	}
+	ref = make([]float64, len(ext))
+	for i, x := range ext {
+		ref[i] = x.t
+	}
+	return
+}
+
+// goldenSection returns the minimiser of φ(t) within [a, b] by golden-section search
+func goldenSection(φ Ss, a, b, tol float64, maxIt int) float64 {
+	const gr = 0.6180339887498949
+	c, d := b-gr*(b-a), a+gr*(b-a)
+	fc, fd := φ(c), φ(d)
+	for k := 0; k < maxIt && b-a > tol; k++ {
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b - gr*(b-a)
+			fc = φ(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + gr*(b-a)
+			fd = φ(d)
+		}
+	}
+	return (a + b) / 2.0
+}
@@ -7,7 +7,10 @@ package fun
 import (
 	"math"
 
+	"github.com/lei006/gomath/chk"
 	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/utl"
 )
 
 // Sinusoid implements the sinusoid equation:
@@ -146,3 +149,205 @@ func (o *Sinusoid) TestPeriodicity(tmin, tmax float64, npts int) bool {
 	}
 	return true
 }
+
+// Harmonics returns the amplitudes Cₖ and phase shifts θₖ of all harmonics k = 0, 1, 2, ...
+//
+//	y(t) = A0 + Σ Cₖ⋅cos(k⋅ω0⋅t + θₖ)
+//
+//	Cₖ = sqrt(Aₖ² + Bₖ²)   θₖ = atan2(-Bₖ, Aₖ)   C0 = A0   θ0 = 0
+func (o *Sinusoid) Harmonics() (C, θ []float64) {
+	C = make([]float64, len(o.A))
+	θ = make([]float64, len(o.A))
+	C[0] = o.A[0]
+	for k := 1; k < len(o.A); k++ {
+		C[k] = math.Sqrt(o.A[k]*o.A[k] + o.B[k]*o.B[k])
+		θ[k] = math.Atan2(-o.B[k], o.A[k])
+	}
+	return
+}
+
+// fitting /////////////////////////////////////////////////////////////////////////////////////////
+
+// SinusoidFit holds the goodness of fit of a truncated Fourier series fitted to data
+type SinusoidFit struct {
+	Npts  int     // number of data points
+	Nharm int     // number of harmonics N
+	Rss   float64 // residual sum of squares Σ(yᵢ - y(tᵢ))²
+	Rmse  float64 // root-mean-square error sqrt(Rss / Npts)
+	Sigma float64 // standard deviation of the residuals sqrt(Rss / (Npts - 2⋅N - 1))
+	R2    float64 // coefficient of determination 1 - Rss / Σ(yᵢ - ȳ)²
+}
+
+// FitSinusoid fits a truncated Fourier series with N harmonics and known period T to (possibly
+// irregularly sampled) data by linear least squares
+//
+//	y(t) = A0 + Σ [Aₖ⋅cos(k⋅ω0⋅t) + Bₖ⋅sin(k⋅ω0⋅t)]   k = 1...N   ω0 = 2⋅π/T
+//
+//	Input:
+//	  t -- sampling times; len(t) ≥ 2⋅N + 1
+//	  y -- data values
+//	  T -- period
+//	  N -- number of harmonics ≥ 1
+//
+//	Output:
+//	  o   -- sinusoid with all coefficients A and B; MeanValue, Amplitude and PhaseShift
+//	         correspond to the first harmonic. See also Harmonics
+//	  fit -- goodness of fit
+func FitSinusoid(t, y []float64, T float64, N int) (o *Sinusoid, fit *SinusoidFit) {
+	checkSinusoidData(t, y, N)
+	if T <= 0 {
+		chk.Panic("period must be positive. T = %g is invalid\n", T)
+	}
+	A, B, rss := sinusoidLsq(t, y, 2.0*math.Pi/T, N)
+	o = NewSinusoidBasis(T, A[0], A[1], B[1])
+	o.A, o.B = A, B
+	fit = newSinusoidFit(y, N, rss)
+	return
+}
+
+// FitSinusoidAuto fits a truncated Fourier series with N harmonics and unknown period to
+// (possibly irregularly sampled) data. See FitSinusoid
+//
+//	The period is first estimated from the peak of the Lomb-Scargle periodogram within
+//	[Tmin, Tmax]. Since the dominant peak may correspond to a higher harmonic, the sub-multiple
+//	frequencies fpeak/k (k = 1...N) are also tried. Each candidate is refined by minimising the
+//	residual sum of squares of the N-harmonic fit with respect to the frequency. A sub-multiple
+//	frequency is only selected if it at least halves the residual sum of squares; otherwise the
+//	extra (fractional) harmonics would simply fit the noise.
+//
+//	Input:
+//	  t, y       -- data
+//	  N          -- number of harmonics ≥ 1
+//	  Tmin, Tmax -- range of periods to search; use zero values to select the range from the
+//	                sampling: Tmax = span of t and Tmin = 2⋅span/len(t) (average Nyquist)
+func FitSinusoidAuto(t, y []float64, N int, Tmin, Tmax float64) (o *Sinusoid, fit *SinusoidFit) {
+
+	// check
+	checkSinusoidData(t, y, N)
+	tmin, tmax := utl.MinMax(t)
+	span := tmax - tmin
+	if Tmax <= 0 {
+		Tmax = span
+	}
+	if Tmin <= 0 {
+		Tmin = 2.0 * span / float64(len(t))
+	}
+	if Tmin >= Tmax {
+		chk.Panic("Tmin must be smaller than Tmax. Tmin = %g, Tmax = %g is invalid\n", Tmin, Tmax)
+	}
+
+	// periodogram
+	ofac := 4.0
+	df := 1.0 / (ofac * span)
+	fmin, fmax := 1.0/Tmax, 1.0/Tmin
+	nf := int((fmax-fmin)/df) + 1
+	freqs := utl.LinSpace(fmin, fmax, utl.Imax(nf, 2))
+	P := LombScargle(t, y, freqs)
+	ipeak := 0
+	for i := range P {
+		if P[i] > P[ipeak] {
+			ipeak = i
+		}
+	}
+
+	// refine candidates
+	rssOf := func(f float64) float64 {
+		_, _, rss := sinusoidLsq(t, y, 2.0*math.Pi*f, N)
+		return rss
+	}
+	fbest, rbest := 0.0, math.Inf(1)
+	for k := 1; k <= N; k++ {
+		fc := freqs[ipeak] / float64(k)
+		if fc < fmin*(1.0-1e-12) {
+			break
+		}
+		a, b := math.Max(fc-df, 1e-3*fc), fc+df
+		f := goldenSection(rssOf, a, b, 1e-12*fc, 200)
+		r := rssOf(f)
+		if r < 0.5*rbest {
+			fbest, rbest = f, r
+		}
+	}
+
+	// results
+	o, fit = FitSinusoid(t, y, 1.0/fbest, N)
+	return
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// checkSinusoidData checks the input data of the fitting functions
+func checkSinusoidData(t, y []float64, N int) {
+	if N < 1 {
+		chk.Panic("number of harmonics must be at least 1. N = %d is invalid\n", N)
+	}
+	if len(t) != len(y) {
+		chk.Panic("len(t) must be equal to len(y). %d != %d\n", len(t), len(y))
+	}
+	if len(t) < 2*N+1 {
+		chk.Panic("at least 2⋅N+1 = %d data points are required. len(t) = %d is invalid\n", 2*N+1, len(t))
+	}
+}
+
+// sinusoidLsq computes the least-squares coefficients of the Fourier series with N harmonics and
+// angular frequency ω0. The residual sum of squares is also returned
+func sinusoidLsq(t, y []float64, ω0 float64, N int) (A, B []float64, rss float64) {
+	m, n := len(t), 2*N+1
+	M := la.NewMatrix(m, n)
+	b := make([]float64, m)
+	for i, ti := range t {
+		M.Set(i, 0, 1)
+		for k := 1; k <= N; k++ {
+			s, c := math.Sincos(float64(k) * ω0 * ti)
+			M.Set(i, 2*k-1, c)
+			M.Set(i, 2*k, s)
+		}
+		b[i] = y[i]
+	}
+	householderQR(M, b)
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		x[i] = b[i]
+		for j := i + 1; j < n; j++ {
+			x[i] -= M.Get(i, j) * x[j]
+		}
+		if M.Get(i, i) == 0 {
+			x[i] = 0 // rank deficient: drop this basis function
+			continue
+		}
+		x[i] /= M.Get(i, i)
+	}
+	for i := n; i < m; i++ {
+		rss += b[i] * b[i]
+	}
+	A = make([]float64, N+1)
+	B = make([]float64, N+1)
+	A[0] = x[0]
+	for k := 1; k <= N; k++ {
+		A[k], B[k] = x[2*k-1], x[2*k]
+	}
+	return
+}
+
+// newSinusoidFit computes the goodness of fit from the residual sum of squares
+func newSinusoidFit(y []float64, N int, rss float64) (fit *SinusoidFit) {
+	n := len(y)
+	var ȳ, sst float64
+	for _, v := range y {
+		ȳ += v
+	}
+	ȳ /= float64(n)
+	for _, v := range y {
+		sst += (v - ȳ) * (v - ȳ)
+	}
+	fit = &SinusoidFit{Npts: n, Nharm: N, Rss: rss}
+	fit.Rmse = math.Sqrt(rss / float64(n))
+	if dof := n - 2*N - 1; dof > 0 {
+		fit.Sigma = math.Sqrt(rss / float64(dof))
+	}
+	fit.R2 = 1
+	if sst > 0 {
+		fit.R2 = 1.0 - rss/sst
+	}
+	return
+}
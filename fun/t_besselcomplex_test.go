@@ -0,0 +1,115 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+func TestBesselC01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BesselC01. Bessel functions of complex argument: real axis")
+
+	for _, ν := range []float64{0, 0.3, 1, 2.5, 7.2, 20} {
+		for _, x := range []float64{0.01, 0.5, 1.9, 2.1, 5, 30, 200, 1500} {
+			i, k, ip, kp := ModBesselIKC(ν, complex(x, 0))
+			ir, kr, ipr, kpr := ModBesselIK(ν, x)
+			if x < 700 { // Iν(x) overflows otherwise
+				checkSpecfunC(tst, io.Sf("I(%g,%g) ", ν, x), 1e-13, i, complex(ir, 0))
+				checkSpecfunC(tst, io.Sf("I'(%g,%g)", ν, x), 1e-13, ip, complex(ipr, 0))
+			}
+			checkSpecfunC(tst, io.Sf("K(%g,%g) ", ν, x), 1e-13, k, complex(kr, 0))
+			checkSpecfunC(tst, io.Sf("K'(%g,%g)", ν, x), 1e-13, kp, complex(kpr, 0))
+			j, y, jp, yp := BesselJYC(ν, complex(x, 0))
+			jr, yr, jpr, ypr := BesselJY(ν, x)
+			checkSpecfunC(tst, io.Sf("J(%g,%g) ", ν, x), 1e-13, j, complex(jr, 0))
+			checkSpecfunC(tst, io.Sf("Y(%g,%g) ", ν, x), 1e-13, y, complex(yr, 0))
+			checkSpecfunC(tst, io.Sf("J'(%g,%g)", ν, x), 1e-13, jp, complex(jpr, 0))
+			checkSpecfunC(tst, io.Sf("Y'(%g,%g)", ν, x), 1e-13, yp, complex(ypr, 0))
+		}
+	}
+
+	// negative real axis: Jn(-x) = (-1)ⁿ Jn(x)
+	io.Pl()
+	for n := 0; n < 3; n++ {
+		checkSpecfunC(tst, io.Sf("J(%d,-2)", n), 1e-15, BesselJC(float64(n), -2), complex(NegOnePowN(n)*math.Jn(n, 2), 0))
+	}
+
+	// negative orders
+	io.Pl()
+	for _, ν := range []float64{-0.5, -1, -2.3} {
+		jr, yr, _, _ := BesselJY(ν, 3.3)
+		checkSpecfunC(tst, io.Sf("J(%g,3.3)", ν), 1e-14, BesselJC(ν, 3.3), complex(jr, 0))
+		checkSpecfunC(tst, io.Sf("Y(%g,3.3)", ν), 1e-14, BesselYC(ν, 3.3), complex(yr, 0))
+	}
+
+	// special cases
+	j, y, _, _ := BesselJYC(0, 0)
+	i, k, _, _ := ModBesselIKC(0, 0)
+	chk.Complex128(tst, "J(0,0)", 1e-17, j, 1)
+	chk.Complex128(tst, "I(0,0)", 1e-17, i, 1)
+	if !cmplx.IsInf(y) || !cmplx.IsInf(k) || !cmplx.IsNaN(ModBesselIC(-1, 1i)) {
+		tst.Errorf("special cases failed\n")
+	}
+}
+
+func TestBesselC02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BesselC02. Bessel functions of complex argument: identities")
+
+	// known values
+	checkSpecfunC(tst, "J(0,1+i)  ", 1e-15, BesselJC(0, 1+1i), 0.9376084768060293-0.4965299476091221i)
+	z := complex(2, 3)
+	checkSpecfunC(tst, "Y(½,2+3i) ", 1e-14, BesselYC(0.5, z), -cmplx.Sqrt(2/(math.Pi*z))*cmplx.Cos(z))
+	checkSpecfunC(tst, "J(½,2+3i) ", 1e-14, BesselJC(0.5, z), cmplx.Sqrt(2/(math.Pi*z))*cmplx.Sin(z))
+	checkSpecfunC(tst, "I(½,2+3i) ", 1e-14, ModBesselIC(0.5, z), cmplx.Sqrt(2/(math.Pi*z))*cmplx.Sinh(z))
+	checkSpecfunC(tst, "K(½,2+3i) ", 1e-14, ModBesselKC(0.5, z), cmplx.Sqrt(math.Pi/(2*z))*cmplx.Exp(-z))
+	checkSpecfunC(tst, "H1(½,2+3i)", 1e-14, HankelH1(0.5, z), -1i*cmplx.Sqrt(2/(math.Pi*z))*cmplx.Exp(1i*z))
+	checkSpecfunC(tst, "H2(½,2+3i)", 1e-14, HankelH2(0.5, z), 1i*cmplx.Sqrt(2/(math.Pi*z))*cmplx.Exp(-1i*z))
+
+	// Wronskians (relative to the size of the terms) and recurrences
+	zz := []complex128{0.1 + 0.2i, 1 + 1i, -1 + 1.5i, -3 - 2i, 0.01 - 5i, 10i, -20 + 0.1i, 50 - 30i, 2i, 1200i, 300 - 1100i}
+	for _, ν := range []float64{0.3, 1, 2.5, 7.2} {
+		io.Pl()
+		for _, z := range zz {
+			i, k, ip, kp := ModBesselIKC(ν, z)
+			j, y, jp, yp := BesselJYC(ν, z)
+			scl := math.Max(1, cmplx.Abs(z*i*kp))
+			chk.Complex128(tst, io.Sf("W{K,I}(%g,%v)", ν, z), 1e-13*scl, z*(i*kp-ip*k), -1)
+			if cmplx.Abs(z) < 700 {
+				scl = math.Max(1, cmplx.Abs(z*j*yp))
+				chk.Complex128(tst, io.Sf("W{J,Y}(%g,%v)", ν, z), 1e-13*scl, z*(j*yp-jp*y), complex(2/math.Pi, 0))
+				c := complex(2*ν, 0) / z
+				checkSpecfunC(tst, io.Sf("J recurrence(%g,%v)", ν, z), 1e-12, BesselJC(ν-1, z)+BesselJC(ν+1, z), c*j)
+				checkSpecfunC(tst, io.Sf("Y recurrence(%g,%v)", ν, z), 1e-12, BesselYC(ν-1, z)+BesselYC(ν+1, z), c*y)
+				if ν >= 1 {
+					checkSpecfunC(tst, io.Sf("I recurrence(%g,%v)", ν, z), 1e-12, ModBesselIC(ν-1, z)-ModBesselIC(ν+1, z), c*i)
+				}
+				checkSpecfunC(tst, io.Sf("H1=J+iY     (%g,%v)", ν, z), 1e-12, HankelH1(ν, z), j+1i*y)
+				checkSpecfunC(tst, io.Sf("H2=J-iY     (%g,%v)", ν, z), 1e-12, HankelH2(ν, z), j-1i*y)
+			}
+		}
+	}
+
+	// derivatives
+	io.Pl()
+	h := 1e-5
+	for _, z := range []complex128{0.5 + 0.5i, -2 + 3i, 4 - 1i} {
+		zp, zm := z+complex(h, 0), z-complex(h, 0)
+		_, _, jp, yp := BesselJYC(1.7, z)
+		_, _, ip, kp := ModBesselIKC(1.7, z)
+		checkSpecfunC(tst, io.Sf("J'(1.7,%v)", z), 1e-8, jp, (BesselJC(1.7, zp)-BesselJC(1.7, zm))/complex(2*h, 0))
+		checkSpecfunC(tst, io.Sf("Y'(1.7,%v)", z), 1e-8, yp, (BesselYC(1.7, zp)-BesselYC(1.7, zm))/complex(2*h, 0))
+		checkSpecfunC(tst, io.Sf("I'(1.7,%v)", z), 1e-8, ip, (ModBesselIC(1.7, zp)-ModBesselIC(1.7, zm))/complex(2*h, 0))
+		checkSpecfunC(tst, io.Sf("K'(1.7,%v)", z), 1e-8, kp, (ModBesselKC(1.7, zp)-ModBesselKC(1.7, zm))/complex(2*h, 0))
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+// checkSpecfun compares a against the reference value b with tolerance scaled by max(1,|b|)
+func checkSpecfun(tst *testing.T, msg string, tol, a, b float64) {
+	chk.Float64(tst, msg, tol*math.Max(1, math.Abs(b)), a, b)
+}
+
+func TestBesselReal01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BesselReal01. Bessel functions of real order")
+
+	_, dat := io.ReadTable("data/specfun-bessel.cmp")
+	for i, x := range dat["x"] {
+		ν := dat["nu"][i]
+		checkSpecfun(tst, io.Sf("J(%5.2f,%8.3f)", ν, x), 1e-14, BesselJ(ν, x), dat["J"][i])
+		checkSpecfun(tst, io.Sf("Y(%5.2f,%8.3f)", ν, x), 1e-14, BesselY(ν, x), dat["Y"][i])
+	}
+
+	// integer orders against the standard library
+	io.Pl()
+	for _, x := range []float64{0.3, 1, 4.5, 17} {
+		for n := -3; n <= 3; n++ {
+			chk.Float64(tst, io.Sf("J(%d,%g)", n, x), 1e-14, BesselJ(float64(n), x), math.Jn(n, x))
+			chk.Float64(tst, io.Sf("Y(%d,%g)", n, x), 1e-13, BesselY(float64(n), x), math.Yn(n, x))
+		}
+	}
+
+	// derivatives: Z'ᵥ = Zᵥ₋₁ - (ν/x) Zᵥ
+	io.Pl()
+	for _, ν := range []float64{0, 0.3, 2.5, 7} {
+		for _, x := range []float64{0.7, 3.2, 12, 1500} {
+			j, y, jp, yp := BesselJY(ν, x)
+			checkSpecfun(tst, io.Sf("dJ/dx(%g,%g)", ν, x), 1e-13, jp, BesselJ(ν-1, x)-ν*j/x)
+			checkSpecfun(tst, io.Sf("dY/dx(%g,%g)", ν, x), 1e-13, yp, BesselY(ν-1, x)-ν*y/x)
+		}
+	}
+}
+
+func TestBesselReal02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BesselReal02. Modified Bessel functions of real order")
+
+	_, dat := io.ReadTable("data/specfun-modbessel.cmp")
+	for i, x := range dat["x"] {
+		ν := dat["nu"][i]
+		checkSpecfun(tst, io.Sf("I(%5.2f,%8.3f)", ν, x), 1e-14, ModBesselI(ν, x), dat["I"][i])
+		checkSpecfun(tst, io.Sf("K(%5.2f,%8.3f)", ν, x), 1e-14, ModBesselK(ν, x), dat["K"][i])
+	}
+
+	// derivatives: I'ᵥ = Iᵥ₊₁ + (ν/x) Iᵥ and K'ᵥ = -Kᵥ₊₁ + (ν/x) Kᵥ
+	io.Pl()
+	for _, ν := range []float64{0, 0.3, 2.5} {
+		for _, x := range []float64{0.7, 3.2, 8} {
+			i, k, ip, kp := ModBesselIK(ν, x)
+			checkSpecfun(tst, io.Sf("dI/dx(%g,%g)", ν, x), 1e-13, ip, ModBesselI(ν+1, x)+ν*i/x)
+			checkSpecfun(tst, io.Sf("dK/dx(%g,%g)", ν, x), 1e-13, kp, -ModBesselK(ν+1, x)+ν*k/x)
+		}
+	}
+}
+
+func TestBesselReal03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BesselReal03. Spherical Bessel functions")
+
+	_, dat := io.ReadTable("data/specfun-sphbessel.cmp")
+	for i, x := range dat["x"] {
+		n := int(dat["n"][i])
+		checkSpecfun(tst, io.Sf("j(%d,%8.3f)", n, x), 1e-14, SphBesselJ(n, x), dat["j"][i])
+		checkSpecfun(tst, io.Sf("y(%d,%8.3f)", n, x), 1e-14, SphBesselY(n, x), dat["y"][i])
+	}
+}
+
+func TestAiry01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Airy01. Airy functions")
+
+	_, dat := io.ReadTable("data/specfun-airy.cmp")
+	for i, x := range dat["x"] {
+		ai, bi, aip, bip := Airy(x)
+		checkSpecfun(tst, io.Sf("Ai (%8.3f)", x), 1e-14, ai, dat["Ai"][i])
+		checkSpecfun(tst, io.Sf("Bi (%8.3f)", x), 1e-14, bi, dat["Bi"][i])
+		checkSpecfun(tst, io.Sf("Ai'(%8.3f)", x), 1e-14, aip, dat["Aip"][i])
+		checkSpecfun(tst, io.Sf("Bi'(%8.3f)", x), 1e-14, bip, dat["Bip"][i])
+	}
+	chk.Float64(tst, "Ai(0)", 1e-15, AiryAi(0), 0.355028053887817239)
+	chk.Float64(tst, "Bi(0)", 1e-15, AiryBi(0), 0.614926627446000736)
+}
+
+func TestBesselReal04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BesselReal04. Bessel functions of real order at small arguments")
+
+	// two terms of the power series: Jν(x), Iν(x) = (x/2)ᵛ/Γ(ν+1) ⋅ (1 ∓ x²/(4(ν+1)))
+	for _, ν := range []float64{0, 1, 1.5, 2.5, 7, 12.5} {
+		for _, x := range []float64{1e-5, 1e-8, 1e-20, 1e-50} {
+			lead := math.Pow(x/2, ν) / math.Gamma(ν+1)
+			c := x * x / (4 * (ν + 1))
+			j, _, jp, _ := BesselJY(ν, x)
+			i, _, ip, _ := ModBesselIK(ν, x)
+			chk.Float64(tst, io.Sf("J(%4.1f,%g)", ν, x), 1e-14*lead, j, lead*(1-c))
+			chk.Float64(tst, io.Sf("I(%4.1f,%g)", ν, x), 1e-14*lead, i, lead*(1+c))
+			if j != 0 { // otherwise, J'ν ≈ (ν/x)⋅Jν may still be representable
+				chk.Float64(tst, io.Sf("J'(%4.1f,%g)", ν, x), 1e-14*math.Abs(jp), jp, ν*j/x-BesselJ(ν+1, x))
+				chk.Float64(tst, io.Sf("I'(%4.1f,%g)", ν, x), 1e-14*ip, ip, ν*i/x+ModBesselI(ν+1, x))
+			}
+		}
+	}
+
+	// leading terms of Yν and Kν
+	io.Pl()
+	for _, ν := range []float64{2.5, 7} {
+		for _, x := range []float64{1e-8, 1e-20} {
+			lead := math.Gamma(ν) * math.Pow(2/x, ν)
+			chk.Float64(tst, io.Sf("Y(%4.1f,%g)", ν, x), 1e-14*lead, BesselY(ν, x), -lead/math.Pi)
+			chk.Float64(tst, io.Sf("K(%4.1f,%g)", ν, x), 1e-14*lead, ModBesselK(ν, x), lead/2)
+		}
+	}
+
+	// underflow of Jν, Iν and jn; overflow of Yν and Kν
+	io.Pl()
+	chk.Float64(tst, "J(2.5,1e-200)", 1e-15, BesselJ(2.5, 1e-200), 0)
+	chk.Float64(tst, "I(7,1e-100)  ", 1e-15, ModBesselI(7, 1e-100), 0)
+	chk.Float64(tst, "j(3,1e-150)  ", 1e-15, SphBesselJ(3, 1e-150), 0)
+	chk.Float64(tst, "j(3,1e-50)   ", 1e-14*1e-150/105, SphBesselJ(3, 1e-50), 1e-150/105)
+	j, y, jp, yp := BesselJY(7, 1e-100)
+	i, k, ip, kp := ModBesselIK(7, 1e-100)
+	for _, v := range []float64{j, y, jp, yp, i, k, ip, kp} {
+		if math.IsNaN(v) {
+			tst.Errorf("Bessel functions of order 7 at x = 1e-100 should not be NaN\n")
+			return
+		}
+	}
+	if !math.IsInf(y, -1) || !math.IsInf(yp, +1) || !math.IsInf(k, +1) || !math.IsInf(kp, -1) {
+		tst.Errorf("Y, Y', K and K' of order 7 at x = 1e-100 should overflow\n")
+	}
+}
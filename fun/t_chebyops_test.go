@@ -0,0 +1,153 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/utl"
+)
+
+func TestChebyOps01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ChebyOps01. adaptive construction, integrals and derivative")
+
+	// f(x) = exp(x) sin(5x) on [0, 2]
+	f := func(x float64) float64 { return math.Exp(x) * math.Sin(5*x) }
+	o, err := NewChebyInterpAdapt(f, 0, 2, 0, 0)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("N = %d\n", o.N)
+	if o.N > 40 {
+		tst.Errorf("degree is too high: N = %d\n", o.N)
+	}
+	for _, x := range utl.LinSpace(0, 2, 11) {
+		chk.Float64(tst, io.Sf("f(%g)", x), 1e-13, o.F(x), f(x))
+	}
+
+	// integral: ∫ eˣ sin(5x) dx = eˣ (sin(5x) - 5 cos(5x)) / 26
+	F := func(x float64) float64 { return math.Exp(x) * (math.Sin(5*x) - 5*math.Cos(5*x)) / 26 }
+	chk.Float64(tst, "∫f", 1e-14, o.Integral(), F(2)-F(0))
+	ig := o.Integ()
+	for _, x := range []float64{0, 0.3, 1.1, 2} {
+		chk.Float64(tst, io.Sf("∫f(%g)", x), 1e-14, ig.F(x), F(x)-F(0))
+	}
+
+	// derivative
+	d := o.Deriv()
+	for _, x := range []float64{0, 0.3, 1.1, 2} {
+		chk.Float64(tst, io.Sf("df/dx(%g)", x), 1e-11, d.F(x), math.Exp(x)*(math.Sin(5*x)+5*math.Cos(5*x)))
+	}
+
+	// Runge function requires a higher degree
+	runge := func(x float64) float64 { return 1.0 / (1.0 + 25.0*x*x) }
+	r, err := NewChebyInterpAdapt(runge, -1, 1, 1e-12, 0)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("Runge: N = %d\n", r.N)
+	chk.Float64(tst, "∫runge", 1e-12, r.Integral(), 0.4*math.Atan(5))
+
+	// non-convergence
+	_, err = NewChebyInterpAdapt(math.Abs, -1, 1, 0, 64)
+	if err == nil {
+		tst.Errorf("|x| should not converge with N ≤ 64\n")
+	}
+}
+
+func TestChebyOps02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ChebyOps02. roots, min and max")
+
+	// sin(x) on [-10, 10]: roots at kπ
+	o, _ := NewChebyInterpAdapt(math.Sin, -10, 10, 0, 0)
+	roots := o.Roots()
+	chk.Int(tst, "number of roots", len(roots), 7)
+	for i, x := range roots {
+		chk.Float64(tst, io.Sf("root %d", i), 1e-13, x, float64(i-3)*math.Pi)
+	}
+
+	// extrema
+	fmax, xmax := o.Max()
+	fmin, xmin := o.Min()
+	chk.Float64(tst, "fmax", 1e-13, fmax, 1)
+	chk.Float64(tst, "fmin", 1e-13, fmin, -1)
+	chk.Float64(tst, "sin(xmax)", 1e-13, math.Sin(xmax), 1)
+	chk.Float64(tst, "sin(xmin)", 1e-13, math.Sin(xmin), -1)
+
+	// extremum at the boundary
+	g, _ := NewChebyInterpAdapt(func(x float64) float64 { return x*x*x - x }, 0, 3, 0, 0)
+	fmax, xmax = g.Max()
+	fmin, xmin = g.Min()
+	chk.Float64(tst, "fmax", 1e-13, fmax, 24)
+	chk.Float64(tst, "xmax", 1e-15, xmax, 3)
+	chk.Float64(tst, "fmin", 1e-14, fmin, -2/(3*math.Sqrt(3)))
+	chk.Float64(tst, "xmin", 1e-7, xmin, 1/math.Sqrt(3))
+}
+
+func TestChebyOps03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ChebyOps03. arithmetic")
+
+	a, _ := NewChebyInterpAdapt(math.Cos, -2, 1, 0, 0)
+	b, _ := NewChebyInterpAdapt(math.Exp, -2, 1, 0, 0)
+	sum, dif, mul, sca := a.Add(b), a.Sub(b), a.Mul(b), a.Scale(-3)
+	for _, x := range utl.LinSpace(-2, 1, 7) {
+		c, e := math.Cos(x), math.Exp(x)
+		chk.Float64(tst, io.Sf("a+b (%g)", x), 1e-13, sum.F(x), c+e)
+		chk.Float64(tst, io.Sf("a-b (%g)", x), 1e-13, dif.F(x), c-e)
+		chk.Float64(tst, io.Sf("a⋅b (%g)", x), 1e-13, mul.F(x), c*e)
+		chk.Float64(tst, io.Sf("-3a (%g)", x), 1e-13, sca.F(x), -3*c)
+	}
+
+	// ∫ cos(x) eˣ dx = eˣ (cos(x) + sin(x)) / 2
+	F := func(x float64) float64 { return math.Exp(x) * (math.Cos(x) + math.Sin(x)) / 2 }
+	chk.Float64(tst, "∫a⋅b", 1e-13, mul.Integral(), F(1)-F(-2))
+
+	// reference interval: F and I coincide
+	c := NewChebyInterp(8, false)
+	c.CalcCoefI(math.Exp)
+	chk.Float64(tst, "F == I", 1e-15, c.F(0.3), c.I(0.3))
+}
+
+func TestChebyOps04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ChebyOps04. multiple roots")
+
+	// sin(10x)(x-0.3)² on [-1,2]: simple roots kπ/10 and a double root at 0.3
+	f := func(x float64) float64 { return math.Sin(10*x) * (x - 0.3) * (x - 0.3) }
+	o, _ := NewChebyInterpAdapt(f, -1, 2, 0, 0)
+	xref := []float64{0.3}
+	for k := -3; k <= 6; k++ {
+		xref = append(xref, float64(k)*math.Pi/10)
+	}
+	sort.Float64s(xref)
+	chk.Array(tst, "roots", 1e-10, o.Roots(), xref)
+
+	// double roots split into two real eigenvalues and a triple root
+	io.Pl()
+	o, _ = NewChebyInterpAdapt(func(x float64) float64 { return math.Sin(3*x) * math.Sin(3*x) }, -1, 2, 0, 0)
+	chk.Array(tst, "sin²(3x)", 1e-10, o.Roots(), []float64{0, math.Pi / 3})
+	o, _ = NewChebyInterpAdapt(func(x float64) float64 { return math.Cos(x) * math.Pow(x-0.3, 3) }, -1, 2, 0, 0)
+	chk.Array(tst, "cos(x)(x-0.3)³", 1e-6, o.Roots(), []float64{0.3, math.Pi / 2})
+
+	// close simple roots 0.5 ± 10⁻⁵ are kept; complex roots 0.3 ± 0.01i are discarded
+	io.Pl()
+	o, _ = NewChebyInterpAdapt(func(x float64) float64 { return (x-0.5)*(x-0.5) - 1e-10 }, -1, 2, 0, 0)
+	chk.Array(tst, "close roots", 1e-10, o.Roots(), []float64{0.5 - 1e-5, 0.5 + 1e-5})
+	o, _ = NewChebyInterpAdapt(func(x float64) float64 { return (x-0.3)*(x-0.3) + 1e-4 }, -1, 2, 0, 0)
+	chk.Int(tst, "no real roots", len(o.Roots()), 0)
+}
@@ -0,0 +1,88 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+func TestEllipticC01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("EllipticC01. Carlson integrals of complex arguments")
+
+	// test values of [1]
+	checkSpecfunC(tst, "Rf(1,2,0)       ", 1e-13, CarlsonRfC(1, 2, 0), 1.3110287771461)
+	checkSpecfunC(tst, "Rf(i,-i,0)      ", 1e-13, CarlsonRfC(1i, -1i, 0), 1.8540746773014)
+	checkSpecfunC(tst, "Rf(i-1,i,0)     ", 1e-13, CarlsonRfC(-1+1i, 1i, 0), 0.79612586584234-1.2138566698365i)
+	checkSpecfunC(tst, "Rf(2,3,4)       ", 1e-13, CarlsonRfC(2, 3, 4), 0.58408284167715)
+	checkSpecfunC(tst, "Rf(i,-i,2)      ", 1e-13, CarlsonRfC(1i, -1i, 2), 1.0441445654064)
+	checkSpecfunC(tst, "Rf(i-1,i,1-i)   ", 1e-13, CarlsonRfC(-1+1i, 1i, 1-1i), 0.93912050218619-0.53296252018635i)
+	io.Pl()
+	checkSpecfunC(tst, "Rc(0,¼)         ", 1e-15, CarlsonRcC(0, 0.25), math.Pi)
+	checkSpecfunC(tst, "Rc(9/4,2)       ", 1e-15, CarlsonRcC(2.25, 2), math.Ln2)
+	checkSpecfunC(tst, "Rc(0,i)         ", 1e-13, CarlsonRcC(0, 1i), (1-1i)*1.1107207345396)
+	checkSpecfunC(tst, "Rc(-i,i)        ", 1e-13, CarlsonRcC(-1i, 1i), 1.2260849569072-0.34471136988768i)
+	checkSpecfunC(tst, "Rc(¼,-2)        ", 1e-15, CarlsonRcC(0.25, -2), math.Ln2/3)
+	checkSpecfunC(tst, "Rc(i,-1)        ", 1e-13, CarlsonRcC(1i, -1), 0.77778596920447+0.19832484993429i)
+	io.Pl()
+	checkSpecfunC(tst, "Rj(0,1,2,3)     ", 1e-13, CarlsonRjC(0, 1, 2, 3), 0.77688623778582)
+	checkSpecfunC(tst, "Rj(2,3,4,5)     ", 1e-13, CarlsonRjC(2, 3, 4, 5), 0.14297579667157)
+	checkSpecfunC(tst, "Rj(2,3,4,-1+i)  ", 1e-13, CarlsonRjC(2, 3, 4, -1+1i), 0.13613945827771-0.38207561624427i)
+	checkSpecfunC(tst, "Rj(i,-i,0,2)    ", 1e-13, CarlsonRjC(1i, -1i, 0, 2), 1.6490011662711)
+	checkSpecfunC(tst, "Rj(-1+i,-1-i,1,2)", 1e-13, CarlsonRjC(-1+1i, -1-1i, 1, 2), 0.94148358841220)
+	checkSpecfunC(tst, "Rj(i,-i,0,1-i)  ", 1e-13, CarlsonRjC(1i, -1i, 0, 1-1i), 1.8260115229009+1.2290661908643i)
+	checkSpecfunC(tst, "Rj(..,-3+i)     ", 1e-13, CarlsonRjC(-1+1i, -1-1i, 1, -3+1i), -0.61127970812028-1.0684038390007i)
+	checkSpecfunC(tst, "Rj(2,3,4,-½)    ", 1e-15, CarlsonRjC(2, 3, 4, -0.5), complex(CarlsonRj(2, 3, 4, -0.5), 0))
+	io.Pl()
+	checkSpecfunC(tst, "Rd(0,2,1)       ", 1e-13, CarlsonRdC(0, 2, 1), 1.7972103521034)
+	checkSpecfunC(tst, "Rd(2,3,4)       ", 1e-13, CarlsonRdC(2, 3, 4), 0.16510527294261)
+	checkSpecfunC(tst, "Rd(i,-i,2)      ", 1e-13, CarlsonRdC(1i, -1i, 2), 0.65933854154220)
+	checkSpecfunC(tst, "Rd(0,i,-i)      ", 1e-13, CarlsonRdC(0, 1i, -1i), 1.2708196271910+2.7811120159521i)
+	checkSpecfunC(tst, "Rd(0,i-1,i)     ", 1e-13, CarlsonRdC(0, 1i-1, 1i), -1.8577235439239-0.96193450888839i)
+	checkSpecfunC(tst, "Rd(-2-i,-i,-1+i)", 1e-13, CarlsonRdC(-2-1i, -1i, -1+1i), 1.8249027393704-1.2218475784827i)
+	checkSpecfunC(tst, "Rd=Rj(x,y,z,z)  ", 1e-14, CarlsonRdC(-2-1i, -1i, -1+1i), CarlsonRjC(-1+1i, -2-1i, -1i, -1+1i))
+}
+
+func TestEllipticC02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("EllipticC02. Legendre elliptic integrals of complex arguments")
+
+	// real values
+	for _, k := range []float64{0, 0.3, 0.99} {
+		for _, φ := range []float64{0.1, 1, math.Pi / 2} {
+			kc, φc := complex(k, 0), complex(φ, 0)
+			checkSpecfunC(tst, io.Sf("F(%g,%g)     ", φ, k), 1e-14, Elliptic1C(φc, kc), complex(Elliptic1(φ, k), 0))
+			checkSpecfunC(tst, io.Sf("E(%g,%g)     ", φ, k), 1e-14, Elliptic2C(φc, kc), complex(Elliptic2(φ, k), 0))
+			checkSpecfunC(tst, io.Sf("Π(0.4,%g,%g) ", φ, k), 1e-15, Elliptic3C(0.4, φc, kc), complex(Elliptic3(0.4, φ, k), 0))
+			checkSpecfunC(tst, io.Sf("Π(-2.4,%g,%g)", φ, k), 1e-15, Elliptic3C(-2.4, φc, kc), complex(Elliptic3(-2.4, φ, k), 0))
+		}
+	}
+
+	// quasi-periodicity
+	io.Pl()
+	checkSpecfunC(tst, "F(3π/2,½)   ", 1e-15, Elliptic1C(3*math.Pi/2, 0.5), complex(3*EllipticK(0.5), 0))
+	checkSpecfunC(tst, "E(-5π/2,½)  ", 1e-15, Elliptic2C(-5*math.Pi/2, 0.5), complex(-5*EllipticE(0.5), 0))
+	checkSpecfunC(tst, "Π(0.3,7π/2,½)", 1e-15, Elliptic3C(0.3, 7*math.Pi/2, 0.5), complex(7*EllipticPi(0.3, 0.5), 0))
+
+	// derivatives with respect to φ, including points on the lines Re(φ) = ±π/2
+	io.Pl()
+	h := 1e-5
+	k, n := complex(0.7, 0.2), complex(0.3, -0.4)
+	for _, φ := range []complex128{0.3 + 0.2i, 1.5 + 0.5i, math.Pi/2 + 0.3i, math.Pi/2 - 0.3i, 2.7 - 0.4i, -4 + 1i, 0.1 - 2i} {
+		s := cmplx.Sin(φ)
+		q := cmplx.Sqrt(1 - k*k*s*s)
+		φp, φm, hh := φ+complex(h, 0), φ-complex(h, 0), complex(2*h, 0)
+		checkSpecfunC(tst, io.Sf("dF/dφ(%v)", φ), 1e-9, (Elliptic1C(φp, k)-Elliptic1C(φm, k))/hh, 1/q)
+		checkSpecfunC(tst, io.Sf("dE/dφ(%v)", φ), 1e-9, (Elliptic2C(φp, k)-Elliptic2C(φm, k))/hh, q)
+		checkSpecfunC(tst, io.Sf("dΠ/dφ(%v)", φ), 1e-9, (Elliptic3C(n, φp, k)-Elliptic3C(n, φm, k))/hh, 1/(q*(1-n*s*s)))
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+func TestEllipticComplete01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("EllipticComplete01. complete elliptic integrals")
+
+	// closed-form values at k = 1/√2
+	g := math.Gamma(0.25)
+	k := 1.0 / math.Sqrt2
+	Kref := g * g / (4.0 * math.Sqrt(math.Pi))
+	Eref := math.Pow(math.Pi, 1.5)/(g*g) + g*g/(8.0*math.Sqrt(math.Pi))
+	chk.Float64(tst, "K(1/√2)", 1e-15, EllipticK(k), Kref)
+	chk.Float64(tst, "E(1/√2)", 1e-15, EllipticE(k), Eref)
+	chk.Float64(tst, "K(0)", 1e-15, EllipticK(0), math.Pi/2)
+	chk.Float64(tst, "E(0)", 1e-15, EllipticE(0), math.Pi/2)
+	chk.Float64(tst, "E(1)", 1e-15, EllipticE(1), 1)
+	if !math.IsInf(EllipticK(1), +1) {
+		tst.Errorf("K(1) should be +Inf\n")
+	}
+
+	// compare with incomplete integrals and Legendre's relation: E K' + E' K - K K' = π/2
+	io.Pl()
+	p90 := math.Pi / 2.0
+	for _, k := range []float64{0.1, 0.3, 0.5, 0.8, 0.95, 0.999} {
+		K, E := EllipticKE(k)
+		chk.Float64(tst, io.Sf("K(%g)", k), 1e-14, K, Elliptic1(p90, k))
+		chk.Float64(tst, io.Sf("E(%g)", k), 1e-14, E, Elliptic2(p90, k))
+		chk.Float64(tst, io.Sf("E(%g)", k), 1e-15, E, EllipticE(k))
+		kp := math.Sqrt(1 - k*k)
+		Kp, Ep := EllipticKE(kp)
+		chk.Float64(tst, io.Sf("Legendre(%g)", k), 1e-14, E*Kp+Ep*K-K*Kp, p90)
+	}
+
+	// third kind
+	io.Pl()
+	for _, n := range []float64{-3, -0.5, 0, 0.3, 0.9} {
+		for _, k := range []float64{0.1, 0.5, 0.9} {
+			chk.Float64(tst, io.Sf("Π(%g,%g)", n, k), 1e-14, EllipticPi(n, k), Elliptic3(n, p90, k))
+		}
+	}
+	chk.Float64(tst, "Π(0.3,0.5)", 1e-15, EllipticPi(0.3, 0.5), 2.0277924458111314770)
+}
+
+func TestJacobi01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Jacobi01. Jacobi elliptic functions")
+
+	// degenerate cases
+	u := 0.7
+	sn, cn, dn, am := Jacobi(u, 0)
+	chk.Array(tst, "k=0", 1e-15, []float64{sn, cn, dn, am}, []float64{math.Sin(u), math.Cos(u), 1, u})
+	sn, cn, dn, am = Jacobi(u, 1)
+	sech := 1.0 / math.Cosh(u)
+	chk.Array(tst, "k=1", 1e-15, []float64{sn, cn, dn, am}, []float64{math.Tanh(u), sech, sech, math.Asin(math.Tanh(u))})
+
+	// amplitude inverts F(φ,k); identities
+	io.Pl()
+	for _, k := range []float64{0.2, 0.5, 0.9, 0.99999} {
+		for _, φ := range []float64{0.1, 0.6, 1.2, 1.5} {
+			u := Elliptic1(φ, k)
+			sn, cn, dn, am := Jacobi(u, k)
+			chk.Float64(tst, io.Sf("am(F(%g,%g))", φ, k), 1e-13, am, φ)
+			chk.Float64(tst, io.Sf("sn(F(%g,%g))", φ, k), 1e-13, sn, math.Sin(φ))
+			chk.Float64(tst, "sn²+cn²", 1e-15, sn*sn+cn*cn, 1)
+			chk.Float64(tst, "dn²+k²sn²", 1e-14, dn*dn+k*k*sn*sn, 1)
+		}
+	}
+
+	// periodicity and quarter period
+	io.Pl()
+	for _, k := range []float64{0.3, 0.8} {
+		K := EllipticK(k)
+		sn, cn, dn, am := Jacobi(K, k)
+		kp := math.Sqrt(1 - k*k)
+		chk.Array(tst, io.Sf("K(%g)", k), 1e-14, []float64{sn, cn, dn, am}, []float64{1, 0, kp, math.Pi / 2})
+		u := 0.37
+		chk.Float64(tst, "sn(u+4K)", 1e-14, JacobiSn(u+4*K, k), JacobiSn(u, k))
+		chk.Float64(tst, "cn(u+2K)", 1e-14, JacobiCn(u+2*K, k), -JacobiCn(u, k))
+		chk.Float64(tst, "dn(u+2K)", 1e-14, JacobiDn(u+2*K, k), JacobiDn(u, k))
+		chk.Float64(tst, "am(u+2K)", 1e-14, JacobiAm(u+2*K, k), JacobiAm(u, k)+math.Pi)
+		chk.Float64(tst, "sn(-u)", 1e-15, JacobiSn(-u, k), -JacobiSn(u, k))
+	}
+
+	// derivatives: sn' = cn dn, cn' = -sn dn, dn' = -k² sn cn
+	io.Pl()
+	k := 0.75
+	for _, u := range []float64{0.2, 1.1, 2.9} {
+		sn, cn, dn, _ := Jacobi(u, k)
+		chk.DerivScaSca(tst, "dsn/du", 1e-10, cn*dn, u, 1e-3, chk.Verbose, func(t float64) float64 { return JacobiSn(t, k) })
+		chk.DerivScaSca(tst, "dcn/du", 1e-10, -sn*dn, u, 1e-3, chk.Verbose, func(t float64) float64 { return JacobiCn(t, k) })
+		chk.DerivScaSca(tst, "ddn/du", 1e-10, -k*k*sn*cn, u, 1e-3, chk.Verbose, func(t float64) float64 { return JacobiDn(t, k) })
+	}
+}
+
+func TestJacobiTheta01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("JacobiTheta01. Nome and theta functions")
+
+	chk.Float64(tst, "q(0)", 1e-15, EllipticNome(0), 0)
+	chk.Float64(tst, "q(1/√2)", 1e-15, EllipticNome(1/math.Sqrt2), math.Exp(-math.Pi))
+
+	for _, k := range []float64{0.2, 0.7, 0.95} {
+		q := EllipticNome(k)
+		K := EllipticK(k)
+		t2, t3, t4 := JacobiTheta(2, 0, q), JacobiTheta(3, 0, q), JacobiTheta(4, 0, q)
+
+		// k = θ₂²/θ₃², k' = θ₄²/θ₃² and 2K/π = θ₃²
+		chk.Float64(tst, io.Sf("k(%g)", k), 1e-14, t2*t2/(t3*t3), k)
+		chk.Float64(tst, io.Sf("k'(%g)", k), 1e-14, t4*t4/(t3*t3), math.Sqrt(1-k*k))
+		chk.Float64(tst, io.Sf("2K/π(%g)", k), 1e-14, t3*t3, 2*K/math.Pi)
+
+		// Jacobi functions from theta functions
+		for _, u := range []float64{0.3, 1.4, 3.3} {
+			v := math.Pi * u / (2 * K)
+			sn := t3 * JacobiTheta(1, v, q) / (t2 * JacobiTheta(4, v, q))
+			cn := t4 * JacobiTheta(2, v, q) / (t2 * JacobiTheta(4, v, q))
+			dn := t4 * JacobiTheta(3, v, q) / (t3 * JacobiTheta(4, v, q))
+			chk.Float64(tst, io.Sf("sn(%g,%g)", u, k), 1e-14, sn, JacobiSn(u, k))
+			chk.Float64(tst, io.Sf("cn(%g,%g)", u, k), 1e-14, cn, JacobiCn(u, k))
+			chk.Float64(tst, io.Sf("dn(%g,%g)", u, k), 1e-14, dn, JacobiDn(u, k))
+		}
+	}
+}
+
+func TestJacobiTheta02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("JacobiTheta02. Theta functions with q near 1")
+
+	// reference values: direct series with 8000 terms (exactly rounded sums)
+	type point struct {
+		z, q  float64
+		θ     [4]float64
+		small [4]bool // |θⱼ| is below the rounding level of the direct series
+	}
+	points := []point{
+		{0.001, 0.99999, [4]float64{0, 507.15956371189463, 507.15956371189463, 0}, [4]bool{true, false, false, true}},
+		{1.57, 0.99999, [4]float64{526.0582015983701, 0, 0, 526.0582015983701}, [4]bool{false, true, true, false}},
+		{0.02, 0.9999, [4]float64{0, 3.246930638597866, 3.2469306385978696, 0}, [4]bool{true, false, false, true}},
+		{0.4, 0.5, [4]float64{0.2868030902420638, 1.690062623020845, 1.6901458203617126, 0.302493367990434}, [4]bool{}},
+	}
+	for _, p := range points {
+		for j := 1; j <= 4; j++ {
+			res := JacobiTheta(j, p.z, p.q)
+			if p.small[j-1] {
+				chk.Float64(tst, io.Sf("θ%d(%g,%g)", j, p.z, p.q), 1e-15, res, 0)
+				continue
+			}
+			chk.Float64(tst, io.Sf("θ%d(%g,%g)", j, p.z, p.q), 1e-13*math.Abs(p.θ[j-1]), res, p.θ[j-1])
+		}
+	}
+
+	// θ₃(0.3, q) is smaller than 10⁻³⁰⁰⁰ for q = 0.99999
+	chk.Float64(tst, "θ₃(0.3,0.99999)", 1e-300, JacobiTheta(3, 0.3, 0.99999), 0)
+
+	// k = θ₂²/θ₃² and 2K/π = θ₃² near k = 1
+	for _, k := range []float64{0.999, 0.9999999} {
+		q := EllipticNome(k)
+		t2, t3 := JacobiTheta(2, 0, q), JacobiTheta(3, 0, q)
+		io.Pforan("k = %g: q = %v\n", k, q)
+		chk.Float64(tst, io.Sf("k(%g)", k), 1e-14, t2*t2/(t3*t3), k)
+		chk.Float64(tst, io.Sf("2K/π(%g)", k), 1e-13, t3*t3, 2*EllipticK(k)/math.Pi)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+func TestErfC01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ErfC01. Error function and Faddeeva function of complex argument")
+
+	// known values
+	checkSpecfunC(tst, "w(0)    ", 1e-15, Faddeeva(0), 1)
+	checkSpecfunC(tst, "w(1+i)  ", 1e-14, Faddeeva(1+1i), 0.3047442052569126+0.2082189382028316i)
+	checkSpecfunC(tst, "erf(1+i)", 1e-14, ErfC(1+1i), 1.3161512816979476+0.1904534692378347i)
+
+	// real axis
+	io.Pl()
+	for _, x := range []float64{-3, -0.7, -0.2, 0, 0.1, 0.45, 0.5, 1.3, 4, 6} {
+		checkSpecfunC(tst, io.Sf("erf(%g) ", x), 1e-15, ErfC(complex(x, 0)), complex(math.Erf(x), 0))
+		checkSpecfunC(tst, io.Sf("erfc(%g)", x), 1e-14, ErfcC(complex(x, 0)), complex(math.Erfc(x), 0))
+		checkSpecfunC(tst, io.Sf("w(i%g)  ", math.Abs(x)), 1e-14, Faddeeva(complex(0, math.Abs(x))), complex(math.Exp(x*x)*math.Erfc(math.Abs(x)), 0))
+	}
+
+	// symmetries and definition
+	io.Pl()
+	for _, z := range []complex128{0.1 + 0.1i, 0.3 - 0.2i, 2 + 3i, -4 + 0.3i, 6 - 1i, 0.01 + 7i, 20 + 1i, -3 - 2i} {
+		checkSpecfunC(tst, io.Sf("erf(z̄)=conj(erf(z)) z=%v", z), 1e-15, ErfC(cmplx.Conj(z)), cmplx.Conj(ErfC(z)))
+		checkSpecfunC(tst, io.Sf("erf(-z)=-erf(z)     z=%v", z), 1e-15, ErfC(-z), -ErfC(z))
+		checkSpecfunC(tst, io.Sf("w=exp(-z²)erfc(-iz) z=%v", z), 1e-13, Faddeeva(z), cmplx.Exp(-z*z)*ErfcC(-1i*z))
+		checkSpecfunC(tst, io.Sf("erfcx(z)=w(iz)      z=%v", z), 1e-15, ErfcxC(z), Faddeeva(1i*z))
+	}
+
+	// derivative: erf'(z) = 2/√π exp(-z²)
+	io.Pl()
+	h := 1e-5
+	for _, z := range []complex128{0.2 + 0.3i, 1 - 1i, -2 + 0.5i} {
+		num := (ErfC(z+complex(h, 0)) - ErfC(z-complex(h, 0))) / complex(2*h, 0)
+		checkSpecfunC(tst, io.Sf("erf'(%v)", z), 1e-9, num, complex(2/math.SqrtPi, 0)*cmplx.Exp(-z*z))
+	}
+}
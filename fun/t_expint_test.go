@@ -0,0 +1,50 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+func TestExpInt01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ExpInt01. Exponential integrals")
+
+	_, dat := io.ReadTable("data/specfun-expint.cmp")
+	for i, x := range dat["x"] {
+		checkSpecfun(tst, io.Sf("E1(%g)", x), 1e-14, ExpIntE1(x), dat["E1"][i])
+		checkSpecfun(tst, io.Sf("E2(%g)", x), 1e-14, ExpIntEn(2, x), dat["E2"][i])
+		checkSpecfun(tst, io.Sf("E5(%g)", x), 1e-14, ExpIntEn(5, x), dat["E5"][i])
+		checkSpecfun(tst, io.Sf("Ei(%g)", x), 1e-14, ExpIntEi(x), dat["Ei"][i])
+	}
+
+	// Ei(-x) = -E₁(x)
+	io.Pl()
+	chk.Float64(tst, "Ei(-2)", 1e-15, ExpIntEi(-2), -ExpIntE1(2))
+	chk.Float64(tst, "E0(3)", 1e-15, ExpIntEn(0, 3), math.Exp(-3)/3)
+}
+
+func TestFresnel01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Fresnel01. Fresnel integrals")
+
+	_, dat := io.ReadTable("data/specfun-fresnel.cmp")
+	for i, x := range dat["x"] {
+		checkSpecfun(tst, io.Sf("S(%g)", x), 1e-14, FresnelS(x), dat["S"][i])
+		checkSpecfun(tst, io.Sf("C(%g)", x), 1e-14, FresnelC(x), dat["C"][i])
+	}
+
+	// odd functions
+	io.Pl()
+	s, c := Fresnel(-2.1)
+	chk.Float64(tst, "S(-x)", 1e-15, s, -FresnelS(2.1))
+	chk.Float64(tst, "C(-x)", 1e-15, c, -FresnelC(2.1))
+}
@@ -0,0 +1,166 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+)
+
+func TestFourierInterpNd01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FourierInterpNd01. 2D interpolation and derivatives")
+
+	// f(x,y) = exp(sin(2πx)) cos(πy) on [0,1] × [0,2]
+	L := []float64{1, 2}
+	a, b := 2*math.Pi/L[0], 2*math.Pi/L[1]
+	f := func(x la.Vector) float64 { return math.Exp(math.Sin(a*x[0])) * math.Cos(b*x[1]) }
+	dfdx := func(x []float64) float64 { return a * math.Cos(a*x[0]) * f(x) }
+	dfdy := func(x []float64) float64 { return -b * math.Exp(math.Sin(a*x[0])) * math.Sin(b*x[1]) }
+	d2fdx2 := func(x []float64) float64 {
+		c, s := math.Cos(a*x[0]), math.Sin(a*x[0])
+		return a * a * (c*c - s) * f(x)
+	}
+	lap := func(x []float64) float64 { return d2fdx2(x) - b*b*f(x) }
+
+	// interpolant
+	o := NewFourierInterp2d(32, 8, L, "")
+	defer o.Free()
+	chk.Int(tst, "npts", o.Npoints(), 256)
+	o.CalcU(f)
+	o.CalcA()
+
+	// interpolation and gradient at grid points
+	x := make([]float64, 2)
+	for _, idx := range []int{0, 9, 100, 255} {
+		o.Node(x, idx)
+		chk.Float64(tst, io.Sf("I(node %d)", idx), 1e-14, o.I(x), o.U[idx])
+	}
+
+	// derivatives at grid points
+	o.CalcGrad()
+	o.CalcLap()
+	o.CalcD([]int{2, 0})
+	var e0, e1, e2, e3 float64
+	for idx := 0; idx < o.Npoints(); idx++ {
+		o.Node(x, idx)
+		e0 = math.Max(e0, math.Abs(o.Grad[0][idx]-dfdx(x)))
+		e1 = math.Max(e1, math.Abs(o.Grad[1][idx]-dfdy(x)))
+		e2 = math.Max(e2, math.Abs(o.Lap[idx]-lap(x)))
+		e3 = math.Max(e3, math.Abs(o.Du[idx]-d2fdx2(x)))
+	}
+	io.Pforan("errors: dfdx = %g, dfdy = %g, lap = %g, d2fdx2 = %g\n", e0, e1, e2, e3)
+	chk.Float64(tst, "max err: dfdx", 1e-12, e0, 0)
+	chk.Float64(tst, "max err: dfdy", 1e-12, e1, 0)
+	chk.Float64(tst, "max err: lap", 1e-10, e2, 0)
+	chk.Float64(tst, "max err: d2fdx2", 1e-10, e3, 0)
+
+	// arbitrary points
+	rnd := rand.New(rand.NewSource(1111))
+	g := make([]float64, 2)
+	for i := 0; i < 10; i++ {
+		x[0], x[1] = rnd.Float64()*L[0], rnd.Float64()*L[1]
+		chk.Float64(tst, "I(x)", 1e-13, o.I(x), f(x))
+		chk.Float64(tst, "G: res", 1e-13, o.G(g, x), f(x))
+		chk.Array(tst, "G: grad", 1e-11, g, []float64{dfdx(x), dfdy(x)})
+	}
+}
+
+func TestFourierInterpNd02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FourierInterpNd02. 3D interpolation and derivatives")
+
+	// f(x,y,z) = sin(x) cos(2y) + cos(z)² on [0,2π]³
+	f := func(x la.Vector) float64 { return math.Sin(x[0])*math.Cos(2*x[1]) + math.Pow(math.Cos(x[2]), 2) }
+	grad := func(x []float64) []float64 {
+		return []float64{
+			math.Cos(x[0]) * math.Cos(2*x[1]),
+			-2 * math.Sin(x[0]) * math.Sin(2*x[1]),
+			-math.Sin(2 * x[2]),
+		}
+	}
+	lap := func(x []float64) float64 { return -5*math.Sin(x[0])*math.Cos(2*x[1]) - 2*math.Cos(2*x[2]) }
+
+	// interpolant
+	o := NewFourierInterp3d(6, 8, 6, nil, "")
+	defer o.Free()
+	o.CalcU(f)
+	o.CalcA()
+	o.CalcGrad()
+	o.CalcLap()
+	o.CalcD([]int{1, 1, 0})
+	x := make([]float64, 3)
+	for idx := 0; idx < o.Npoints(); idx++ {
+		o.Node(x, idx)
+		g := grad(x)
+		for i := 0; i < 3; i++ {
+			if math.Abs(o.Grad[i][idx]-g[i]) > 1e-13 {
+				tst.Errorf("gradient[%d] at node %d is incorrect: %g != %g\n", i, idx, o.Grad[i][idx], g[i])
+				return
+			}
+		}
+		chk.Float64(tst, "lap", 1e-13, o.Lap[idx], lap(x))
+		chk.Float64(tst, "d2fdxdy", 1e-13, o.Du[idx], -2*math.Cos(x[0])*math.Sin(2*x[1]))
+	}
+
+	// arbitrary point
+	x = []float64{0.3, 4.1, 2.2}
+	g := make([]float64, 3)
+	chk.Float64(tst, "I(x)", 1e-14, o.I(x), f(x))
+	chk.Float64(tst, "G: res", 1e-14, o.G(g, x), f(x))
+	chk.Array(tst, "G: grad", 1e-14, g, grad(x))
+}
+
+func TestFourierInterpNd03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FourierInterpNd03. de-aliasing with the 2/3-rule")
+
+	// u = cos(4x) + sin(y) ; w = u² computed at grid points
+	N := 12
+	o := NewFourierInterp2d(N, N, nil, "")
+	defer o.Free()
+	u := func(x la.Vector) float64 { return math.Cos(4*x[0]) + math.Sin(x[1]) }
+	o.CalcU(func(x la.Vector) float64 { return u(x) * u(x) })
+	o.CalcA()
+
+	// w = 1 + cos(8x)/2 - cos(2y)/2 + 2 cos(4x) sin(y): k = ±8 is aliased into k = ∓4
+	x := []float64{0.7, 1.9}
+	w := func(x []float64) float64 {
+		return 1 + math.Cos(8*x[0])/2 - math.Cos(2*x[1])/2 + 2*math.Cos(4*x[0])*math.Sin(x[1])
+	}
+	io.Pforan("aliasing error = %g\n", math.Abs(o.I(x)-w(x)))
+	if math.Abs(o.I(x)-w(x)) < 0.1 {
+		tst.Errorf("aliasing error should be noticeable\n")
+	}
+
+	// the remaining coefficients (3|k| < N) are exact
+	o.Dealias()
+	chk.Float64(tst, "w(x)", 1e-14, o.I(x), 1-math.Cos(2*x[1])/2)
+	for idx := 0; idx < o.Npoints(); idx++ {
+		j0, j1 := idx/N, idx%N
+		if 3*int(math.Abs(o.K[0][j0])) >= N || 3*int(math.Abs(o.K[1][j1])) >= N {
+			chk.Complex128(tst, "truncated A", 1e-17, o.A[idx], 0)
+		}
+	}
+
+	// well-resolved functions are not affected
+	v := func(x la.Vector) float64 { return math.Cos(3*x[0])*math.Sin(2*x[1]) + 0.5 }
+	o.CalcU(v)
+	o.CalcA()
+	o.Dealias()
+	o.CalcUfromA()
+	for idx := 0; idx < o.Npoints(); idx++ {
+		o.Node(x, idx)
+		chk.Float64(tst, "v", 1e-14, o.U[idx], v(x))
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+)
+
+// checkSpecfunC compares a against the reference value b with tolerance scaled by max(1,|b|)
+func checkSpecfunC(tst *testing.T, msg string, tol float64, a, b complex128) {
+	chk.Complex128(tst, msg, tol*math.Max(1, cmplx.Abs(b)), a, b)
+}
+
+func TestGammaC01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GammaC01. Gamma function of complex argument")
+
+	// known values
+	checkSpecfunC(tst, "Γ(i)    ", 1e-15, GammaC(1i), -0.1549498283018107-0.4980156681183560i)
+	checkSpecfunC(tst, "Γ(1+i)  ", 1e-15, GammaC(1+1i), 0.4980156681183560-0.1549498283018107i)
+	checkSpecfunC(tst, "Γ(½)    ", 1e-15, GammaC(0.5), complex(math.Sqrt(math.Pi), 0))
+	checkSpecfunC(tst, "|Γ(iy)|²", 1e-14, GammaC(2i)*GammaC(-2i), complex(math.Pi/(2*math.Sinh(2*math.Pi)), 0))
+
+	// real axis
+	io.Pl()
+	for _, x := range []float64{-4.5, -0.3, 0.1, 1, 2.5, 7, 30} {
+		checkSpecfunC(tst, io.Sf("Γ(%g)", x), 1e-14, GammaC(complex(x, 1e-300)), complex(math.Gamma(x), 0))
+		lg, s := math.Lgamma(x)
+		if s > 0 {
+			checkSpecfunC(tst, io.Sf("logΓ(%g)", x), 1e-14, LogGammaC(complex(x, 0)), complex(lg, 0))
+		}
+	}
+
+	// poles
+	if !cmplx.IsNaN(GammaC(-3)) || !cmplx.IsInf(LogGammaC(0)) {
+		tst.Errorf("poles should give NaN and +Inf\n")
+	}
+
+	// recurrence and reflection
+	io.Pl()
+	for _, z := range []complex128{0.3 + 0.2i, -2.5 + 0.5i, 4 - 3i, -7.7 - 2i, 0.1 + 15i} {
+		checkSpecfunC(tst, io.Sf("Γ(z+1)=zΓ(z)        z=%v", z), 1e-13, GammaC(z+1), z*GammaC(z))
+		checkSpecfunC(tst, io.Sf("Γ(z)Γ(1-z)=π/sin(πz) z=%v", z), 1e-13, GammaC(z)*GammaC(1-z), complex(math.Pi, 0)/cmplx.Sin(math.Pi*z))
+	}
+
+	// principal branch: logΓ(z+1) = logΓ(z) + log(z) exactly, including the imaginary parts
+	io.Pl()
+	for _, z := range []complex128{-2.5 + 0.5i, -30.3 - 4i, -0.2 + 0.001i, -7.7 - 100i, 3 + 50i, -100.5 + 1e-3i} {
+		checkSpecfunC(tst, io.Sf("logΓ(z+1)=logΓ(z)+log(z) z=%v", z), 1e-15, LogGammaC(z+1), LogGammaC(z)+cmplx.Log(z))
+	}
+
+	// Stirling: large |z|
+	z := complex(200, 300)
+	checkSpecfunC(tst, "logΓ(200+300i)", 1e-15, LogGammaC(z), stirlingLogGamma(z))
+
+	// vector
+	v := la.VectorC{1i, 1 + 1i}
+	res := la.NewVectorC(2)
+	MapC(res, GammaC, v)
+	chk.ArrayC(tst, "MapC", 1e-15, res, []complex128{GammaC(1i), GammaC(1 + 1i)})
+}
@@ -0,0 +1,98 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+func TestGammaInc01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GammaInc01. Regularized incomplete gamma and beta functions")
+
+	_, dat := io.ReadTable("data/specfun-gammainc.cmp")
+	for i, x := range dat["x"] {
+		a := dat["a"][i]
+		checkSpecfun(tst, io.Sf("P(%g,%g)", a, x), 1e-14, GammaP(a, x), dat["P"][i])
+		checkSpecfun(tst, io.Sf("Q(%g,%g)", a, x), 1e-14, GammaQ(a, x), dat["Q"][i])
+	}
+
+	io.Pl()
+	_, dat = io.ReadTable("data/specfun-betainc.cmp")
+	for i, x := range dat["x"] {
+		a, b := dat["a"][i], dat["b"][i]
+		checkSpecfun(tst, io.Sf("I(%g,%g,%g)", a, b, x), 1e-14, BetaInc(a, b, x), dat["I"][i])
+	}
+
+	// symmetry: Iₓ(a,b) = 1 - I₁₋ₓ(b,a)
+	io.Pl()
+	chk.Float64(tst, "I sym", 1e-15, BetaInc(2.5, 4, 0.3), 1-BetaInc(4, 2.5, 0.7))
+	chk.Float64(tst, "P(1,x)", 1e-15, GammaP(1, 2.3), 1-math.Exp(-2.3))
+}
+
+func TestDigamma01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Digamma01. Digamma and polygamma functions")
+
+	_, dat := io.ReadTable("data/specfun-digamma.cmp")
+	for i, x := range dat["x"] {
+		checkSpecfun(tst, io.Sf("ψ(%g)", x), 1e-14, Digamma(x), dat["psi"][i])
+		checkSpecfun(tst, io.Sf("ψ'(%g)", x), 1e-14, Polygamma(1, x), dat["psi1"][i])
+		checkSpecfun(tst, io.Sf("ψ''(%g)", x), 1e-14, Polygamma(2, x), dat["psi2"][i])
+		checkSpecfun(tst, io.Sf("ψ'''(%g)", x), 1e-14, Polygamma(3, x), dat["psi3"][i])
+	}
+
+	// reflection for negative arguments: ψ(1-x) - ψ(x) = π cot(πx)
+	io.Pl()
+	for _, x := range []float64{-0.5, -1.25, -3.7} {
+		chk.Float64(tst, io.Sf("ψ(%g)", x), 1e-13, Digamma(1-x)-Digamma(x), math.Pi/math.Tan(math.Pi*x))
+	}
+	if !math.IsNaN(Digamma(-2)) {
+		tst.Errorf("ψ(-2) should be NaN\n")
+	}
+}
+
+func TestGammaInc02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GammaInc02. Incomplete beta function with large parameters")
+
+	// symmetry: I½(a,a) = ½. The tolerance accounts for the rounding errors of the continued
+	// fraction near the mean
+	for _, a := range []float64{10, 1e3, 1e5, 1e7} {
+		chk.Float64(tst, io.Sf("I(%g,%g,½)", a, a), 1e-13, BetaInc(a, a, 0.5), 0.5)
+	}
+
+	// integer a and b: Iₓ(a,b) = Σ_{j=a}^{n} C(n,j) xʲ (1-x)ⁿ⁻ʲ with n = a+b-1. The terms are
+	// computed by recurrence and normalised by their sum
+	io.Pl()
+	for _, c := range [][]float64{{50, 60, 0.4}, {500, 500, 0.49}, {5000, 3000, 0.63}} {
+		a, b, x := int(c[0]), int(c[1]), c[2]
+		n := a + b - 1
+		t := make([]float64, n+1)
+		m := int(float64(n) * x)
+		t[m] = 1
+		for j := m; j < n; j++ {
+			t[j+1] = t[j] * float64(n-j) / float64(j+1) * x / (1 - x)
+		}
+		for j := m; j > 0; j-- {
+			t[j-1] = t[j] * float64(j) / float64(n-j+1) * (1 - x) / x
+		}
+		var sum, tail float64
+		for j := n; j >= 0; j-- {
+			sum += t[j]
+			if j >= a {
+				tail += t[j]
+			}
+		}
+		chk.Float64(tst, io.Sf("I(%d,%d,%g)", a, b, x), 1e-13, BetaInc(c[0], c[1], x), tail/sum)
+	}
+}
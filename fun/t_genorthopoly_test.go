@@ -195,3 +195,112 @@ func TestGenOrthoPoly06(tst *testing.T) {
 		chk.Float64(tst, "U5", 1e-13, y, 6*x-32*x*x*x+32*math.Pow(x, 5))
 	}
 }
+
+func TestGenOrthoPoly07(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GenOrthoPoly07 Laguerre, Gegenbauer and probabilists' Hermite polynomials")
+
+	α := 0.7
+	lg := NewGeneralOrthoPoly("La", 3, α, 0)
+	ge := NewGeneralOrthoPoly("C", 3, α, 0)
+	le := NewGeneralOrthoPoly("C", 4, 0.5, 0)
+	he := NewGeneralOrthoPoly("He", 4, 0, 0)
+	for _, x := range utl.LinSpace(-2, 2, 7) {
+		chk.Float64(tst, "L1", 1e-15, lg.P(1, x), 1+α-x)
+		chk.Float64(tst, "L2", 1e-14, lg.P(2, x), (x*x-2*(α+2)*x+(α+1)*(α+2))/2)
+		chk.Float64(tst, "C1", 1e-15, ge.P(1, x), 2*α*x)
+		chk.Float64(tst, "C2", 1e-14, ge.P(2, x), 2*α*(1+α)*x*x-α)
+		chk.Float64(tst, "C3", 1e-14, ge.P(3, x), 4.0/3.0*α*(1+α)*(2+α)*x*x*x-2*α*(1+α)*x)
+		for n := 0; n <= 4; n++ {
+			chk.Float64(tst, "C(1/2)=P", 1e-14, le.P(n, x), calcLegendre(n, x))
+		}
+		chk.Float64(tst, "He3", 1e-14, he.P(3, x), x*x*x-3*x)
+		chk.Float64(tst, "He4", 1e-13, he.P(4, x), x*x*x*x-6*x*x+3)
+	}
+}
+
+func TestGenOrthoPoly08(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GenOrthoPoly08 Charlier and Krawtchouk polynomials")
+
+	a := 1.5
+	ch := NewGeneralOrthoPoly("Ch", 3, a, 0)
+	p, N := 0.3, 6
+	kr := NewGeneralOrthoPoly("K", 3, p, float64(N))
+	for x := 0.0; x <= float64(N); x++ {
+		chk.Float64(tst, "C1", 1e-15, ch.P(1, x), 1-x/a)
+		chk.Float64(tst, "C2", 1e-14, ch.P(2, x), 1-2*x/a+x*(x-1)/(a*a))
+		chk.Float64(tst, "K1", 1e-15, kr.P(1, x), 1-x/(p*float64(N)))
+	}
+
+	// orthogonality w.r.t the binomial distribution
+	w := func(x int) float64 { return Binomial(N, x) * math.Pow(p, float64(x)) * math.Pow(1-p, float64(N-x)) }
+	for m := 0; m <= 3; m++ {
+		for n := 0; n < m; n++ {
+			var sum float64
+			for x := 0; x <= N; x++ {
+				sum += w(x) * kr.P(m, float64(x)) * kr.P(n, float64(x))
+			}
+			chk.Float64(tst, io.Sf("(K%d,K%d)", m, n), 1e-15, sum, 0)
+		}
+	}
+}
+
+func TestGenOrthoPoly09(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GenOrthoPoly09 recurrence and Golub-Welsch quadrature")
+
+	// orthogonality with Gauss quadrature (checks recurrence and P at the same time)
+	for _, c := range []struct {
+		kind string
+		α, β float64
+	}{{"J", 0.5, -0.3}, {"L", 0, 0}, {"H", 0, 0}, {"T", 0, 0}, {"U", 0, 0}, {"La", 1.5, 0},
+		{"C", 0.8, 0}, {"He", 0, 0}, {"Ch", 2, 0}, {"K", 0.4, 10}} {
+		N := 5
+		op := NewGeneralOrthoPoly(c.kind, N, c.α, c.β)
+		x, w := op.GaussXW(N + 1)
+		_, μ0 := op.Recurrence(0)
+		var sumw float64
+		for _, wi := range w {
+			sumw += wi
+		}
+		chk.Float64(tst, c.kind+": Σw", 1e-13*μ0, sumw, μ0)
+		for m := 0; m <= N; m++ {
+			for n := 0; n < m; n++ {
+				var sum, nrm float64
+				for i := range x {
+					sum += w[i] * op.P(m, x[i]) * op.P(n, x[i])
+					nrm += w[i] * op.P(m, x[i]) * op.P(m, x[i])
+				}
+				chk.Float64(tst, io.Sf("%s: (P%d,P%d)", c.kind, m, n), 1e-12, sum/math.Sqrt(nrm*μ0), 0)
+			}
+		}
+		xn, _ := op.GaussXW(N)
+		for i, xi := range xn {
+			chk.Float64(tst, io.Sf("%s: P%d(x%d)", c.kind, N, i), 1e-10, op.P(N, xi), 0)
+		}
+	}
+
+	// 3-point Gauss-Hermite
+	x, w := NewGeneralOrthoPoly("H", 3, 0, 0).GaussXW(3)
+	chk.Array(tst, "Hermite: x", 1e-15, x, []float64{-math.Sqrt(1.5), 0, math.Sqrt(1.5)})
+	chk.Array(tst, "Hermite: w", 1e-14, w, []float64{math.Sqrt(math.Pi) / 6, 2 * math.Sqrt(math.Pi) / 3, math.Sqrt(math.Pi) / 6})
+
+	// Gauss-Laguerre: ∫ xᵏ exp(-x) dx = k!
+	x, w = NewGeneralOrthoPoly("La", 1, 0, 0).GaussXW(8)
+	for k := 0; k < 16; k++ {
+		var sum float64
+		for i := range x {
+			sum += w[i] * math.Pow(x[i], float64(k))
+		}
+		chk.Float64(tst, io.Sf("∫x^%d exp(-x)", k), 1e-13*Factorial22(k), sum, Factorial22(k))
+	}
+
+	// Krawtchouk with N+1 points recovers the binomial distribution
+	x, w = NewGeneralOrthoPoly("K", 1, 0.3, 4).GaussXW(5)
+	chk.Array(tst, "Krawtchouk: x", 1e-13, x, []float64{0, 1, 2, 3, 4})
+	chk.Array(tst, "Krawtchouk: w", 1e-14, w, []float64{0.2401, 0.4116, 0.2646, 0.0756, 0.0081})
+}
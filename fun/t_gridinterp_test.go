@@ -0,0 +1,173 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/utl"
+)
+
+// gridValues computes f at all nodes of a grid (first axis runs fastest)
+func gridValues(fcn func(x []float64) float64, axes ...[]float64) (f []float64) {
+	ndim := len(axes)
+	cnt := make([]int, ndim)
+	x := make([]float64, ndim)
+	for {
+		for k := 0; k < ndim; k++ {
+			x[k] = axes[k][cnt[k]]
+		}
+		f = append(f, fcn(x))
+		k := 0
+		for ; k < ndim; k++ {
+			cnt[k]++
+			if cnt[k] < len(axes[k]) {
+				break
+			}
+			cnt[k] = 0
+		}
+		if k == ndim {
+			return
+		}
+	}
+}
+
+func TestGridInterp01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GridInterp01. multi-linear interpolation")
+
+	// f(x,y,z) = 1 + 2x - y + 3z + xy  (exact for multi-linear)
+	fcn := func(x []float64) float64 { return 1 + 2*x[0] - x[1] + 3*x[2] + x[0]*x[1] }
+	xx := []float64{0, 0.5, 1.5, 2}
+	yy := []float64{3, 2, 0} // descending
+	zz := []float64{-1, 1}
+	o := NewGridInterp(MultiLinearType, gridValues(fcn, xx, yy, zz), xx, yy, zz)
+
+	grad := make([]float64, 3)
+	for _, x := range [][]float64{{0, 3, -1}, {0.1, 2.5, 0.3}, {1.2, 0.7, -0.2}, {2, 0, 1}} {
+		chk.Float64(tst, io.Sf("P(%v)", x), 1e-14, o.P(x), fcn(x))
+		res := o.G(grad, x)
+		chk.Float64(tst, "G: res", 1e-14, res, fcn(x))
+		chk.Array(tst, "grad", 1e-14, grad, []float64{2 + x[1], -1 + x[0], 3})
+	}
+}
+
+func TestGridInterp02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GridInterp02. multi-cubic interpolation")
+
+	// quadratic functions are reproduced exactly
+	fcn := func(x []float64) float64 {
+		return 1 + x[0]*x[0] - 2*x[1]*x[1] + x[0]*x[1] + 3*x[2] + x[2]*x[2]*x[1] + x[3]
+	}
+	xx := []float64{0, 0.3, 1, 1.2, 2}
+	yy := []float64{-1, 0, 1.5, 2}
+	zz := []float64{0, 0.5, 1}
+	ww := []float64{0, 1}
+	o := NewGridInterp(MultiCubicType, gridValues(fcn, xx, yy, zz, ww), xx, yy, zz, ww)
+	chk.Int(tst, "ndim", o.Ndim(), 4)
+
+	grad := make([]float64, 4)
+	for _, x := range [][]float64{{0, -1, 0, 0}, {0.1, -0.5, 0.2, 0.3}, {1.1, 1.7, 0.7, 1}, {1.9, 0.1, 0.9, 0.5}} {
+		chk.Float64(tst, io.Sf("P(%v)", x), 1e-13, o.P(x), fcn(x))
+		o.G(grad, x)
+		chk.Array(tst, "grad", 1e-12, grad, []float64{
+			2*x[0] + x[1],
+			-4*x[1] + x[0] + x[2]*x[2],
+			3 + 2*x[2]*x[1],
+			1,
+		})
+	}
+
+	// smooth function: check convergence and derivatives
+	g := func(x []float64) float64 { return math.Sin(x[0]) * math.Exp(-x[1]) * math.Cos(x[2]) }
+	ax := utl.LinSpace(0, 2, 21)
+	bx := utl.LinSpace(0, 1, 11)
+	cx := utl.LinSpace(-1, 1, 21)
+	p := NewGridInterp(MultiCubicType, gridValues(g, ax, bx, cx), ax, bx, cx)
+	x := []float64{0.77, 0.31, 0.12}
+	chk.Float64(tst, "P(smooth)", 1e-4, p.P(x), g(x))
+	grad = make([]float64, 3)
+	p.G(grad, x)
+	chk.DerivScaVec(tst, "dPdx", 1e-7, grad, x, 1e-3, chk.Verbose, func(xx []float64) float64 {
+		return p.P(xx)
+	})
+}
+
+func TestGridInterp03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GridInterp03. extrapolation")
+
+	fcn := func(x []float64) float64 { return 1 + 2*x[0] + x[1]*x[1] }
+	xx := []float64{0, 1, 2, 3}
+	yy := []float64{0, 1, 2}
+	o := NewGridInterp(MultiCubicType, gridValues(fcn, xx, yy), xx, yy)
+
+	// clamp
+	grad := make([]float64, 2)
+	chk.Float64(tst, "clamp: P", 1e-14, o.P([]float64{-1, 3}), fcn([]float64{0, 2}))
+	o.G(grad, []float64{-1, 1.5})
+	chk.Array(tst, "clamp: grad", 1e-14, grad, []float64{0, 3})
+
+	// linear
+	o.Extrap = ExtrapLinear
+	chk.Float64(tst, "linear: P", 1e-14, o.P([]float64{4, 3}), 1+2*4+4+4*(3-2))
+	o.G(grad, []float64{-1, 2.5})
+	chk.Array(tst, "linear: grad", 1e-14, grad, []float64{2, 4})
+
+	// error
+	o.Extrap = ExtrapError
+	if o.Check([]float64{1, 2.5}) == nil {
+		tst.Errorf("Check should have failed\n")
+		return
+	}
+	if err := o.Check([]float64{1, 1.5}); err != nil {
+		tst.Errorf("Check failed: %v\n", err)
+		return
+	}
+	defer chk.RecoverTstPanicIsOK(tst)
+	o.P([]float64{-0.1, 1})
+}
+
+func TestBiTriCubic01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BiTriCubic01. bi-cubic and tri-cubic interpolants")
+
+	// bi-cubic
+	f2 := func(x, y float64) float64 { return x*x + 2*y*y - x*y }
+	xx := []float64{0.0, 0.5, 1.0, 2.0}
+	yy := []float64{0.0, 1.0, 2.0}
+	b := NewBiCubic(gridValues(func(x []float64) float64 { return f2(x[0], x[1]) }, xx, yy), xx, yy)
+	b.SetDisableHunt(true)
+	for _, x := range []float64{0.1, 0.7, 1.9} {
+		for _, y := range []float64{0.2, 1.4} {
+			chk.Float64(tst, "BiCubic: P", 1e-14, b.P(x, y), f2(x, y))
+			res, dfdx, dfdy := b.G(x, y)
+			chk.Float64(tst, "BiCubic: res", 1e-14, res, f2(x, y))
+			chk.Float64(tst, "BiCubic: dfdx", 1e-13, dfdx, 2*x-y)
+			chk.Float64(tst, "BiCubic: dfdy", 1e-13, dfdy, 4*y-x)
+		}
+	}
+	b.SetExtrap(ExtrapLinear)
+	chk.Float64(tst, "BiCubic: P(extrap)", 1e-14, b.P(-1, 1), f2(0, 1)+(-1)*(-1))
+
+	// tri-cubic
+	f3 := func(x []float64) float64 { return x[0]*x[1] + x[2]*x[2] - x[1] }
+	zz := []float64{-1, 0, 2}
+	c := NewTriCubic(gridValues(f3, xx, yy, zz), xx, yy, zz)
+	c.SetDisableHunt(false)
+	c.SetExtrap(ExtrapClamp)
+	res, dfdx, dfdy, dfdz := c.G(0.3, 1.2, 0.5)
+	chk.Float64(tst, "TriCubic: P", 1e-14, c.P(0.3, 1.2, 0.5), f3([]float64{0.3, 1.2, 0.5}))
+	chk.Float64(tst, "TriCubic: res", 1e-14, res, f3([]float64{0.3, 1.2, 0.5}))
+	chk.Array(tst, "TriCubic: grad", 1e-13, []float64{dfdx, dfdy, dfdz}, []float64{1.2, 0.3 - 1, 1})
+}
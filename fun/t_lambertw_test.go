@@ -0,0 +1,42 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+func TestLambertW01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LambertW01. Lambert W function")
+
+	_, dat := io.ReadTable("data/specfun-lambertw.cmp")
+	for i, x := range dat["x"] {
+		checkSpecfun(tst, io.Sf("W0(%g)", x), 1e-14, LambertW(x), dat["W0"][i])
+	}
+
+	io.Pl()
+	_, dat = io.ReadTable("data/specfun-lambertwm1.cmp")
+	for i, x := range dat["x"] {
+		checkSpecfun(tst, io.Sf("W-1(%g)", x), 1e-14, LambertWm1(x), dat["Wm1"][i])
+	}
+
+	// definition: W eᵂ = x
+	io.Pl()
+	for _, x := range []float64{-0.2, 0.5, 10, 1e5} {
+		w := LambertW(x)
+		chk.Float64(tst, io.Sf("W(%g) exp(W)", x), 1e-14*math.Max(1, x), w*math.Exp(w), x)
+	}
+	chk.Float64(tst, "W0(-1/e)", 1e-15, LambertW(-1/math.E), -1)
+	chk.Float64(tst, "W0(e)", 1e-15, LambertW(math.E), 1)
+	if !math.IsNaN(LambertW(-0.5)) || !math.IsNaN(LambertWm1(0.1)) {
+		tst.Errorf("W outside domain should be NaN\n")
+	}
+}
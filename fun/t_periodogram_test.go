@@ -0,0 +1,63 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+func TestLombScargle01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LombScargle01. frequency estimation")
+
+	// y = 2 sin(2π f t) + noise with f = 0.081 sampled at irregular times
+	rnd := rand.New(rand.NewSource(3579))
+	n := 100
+	t := make([]float64, n)
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		t[i] = float64(i) + 0.5*rnd.Float64()
+		y[i] = 2*math.Sin(2*math.Pi*0.081*t[i]) + 0.5*rnd.NormFloat64()
+	}
+
+	// grid
+	freqs := LombScargleFreqs(t, 4, 1)
+	chk.Int(tst, "nf", len(freqs), 200)
+	chk.Float64(tst, "f0", 1e-15, freqs[0], 1.0/(4*(t[n-1]-t[0])))
+
+	// peak
+	fpeak, Ppeak, fap := LombScarglePeak(t, y, 4, 1)
+	io.Pforan("fpeak = %v, Ppeak = %v, fap = %g\n", fpeak, Ppeak, fap)
+	chk.Float64(tst, "fpeak", 5e-4, fpeak, 0.081)
+	if fap > 1e-10 {
+		tst.Errorf("false alarm probability of a strong signal should be small: %g\n", fap)
+	}
+
+	// pure noise
+	for i := 0; i < n; i++ {
+		y[i] = rnd.NormFloat64()
+	}
+	_, Ppeak, fap = LombScarglePeak(t, y, 4, 1)
+	io.Pforan("noise: Ppeak = %v, fap = %g\n", Ppeak, fap)
+	if fap < 0.01 {
+		tst.Errorf("false alarm probability of noise should be large: %g\n", fap)
+	}
+
+	// invariance with respect to time shifts and affine transformations of y
+	P := LombScargle(t, y, freqs)
+	ts := make([]float64, n)
+	ys := make([]float64, n)
+	for i := 0; i < n; i++ {
+		ts[i] = t[i] + 123.456
+		ys[i] = 3 - 2*y[i]
+	}
+	chk.Array(tst, "P(t+t0, a+b⋅y)", 1e-10, LombScargle(ts, ys, freqs), P)
+}
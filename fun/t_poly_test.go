@@ -0,0 +1,193 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/utl"
+)
+
+func TestPoly01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Poly01. evaluation and basis conversion")
+
+	// p(x) = 1 - 2x + 3x³
+	p := NewPoly(1, -2, 0, 3)
+	chk.Int(tst, "degree", p.Degree(), 3)
+	chk.Float64(tst, "p(2)", 1e-15, p.F(2), 21)
+	chk.Complex128(tst, "p(i)", 1e-15, p.FC(1i), complex(1, -5))
+
+	// Chebyshev: x³ = (3T₁ + T₃)/4  ⇒  p = T₀ + (9/4-2)T₁ + (3/4)T₃
+	c := p.ToCheby()
+	chk.Array(tst, "cheby", 1e-15, c.C, []float64{1, 0.25, 0, 0.75})
+	for _, x := range utl.LinSpace(-1.5, 1.5, 7) {
+		chk.Float64(tst, io.Sf("c(%g)", x), 1e-14, c.F(x), p.F(x))
+		chk.Float64(tst, io.Sf("T5(%g)", x), 1e-14, NewPolyCheby(0, 0, 0, 0, 0, 1).F(x), ChebyshevT(5, x))
+	}
+	chk.Array(tst, "back to monomial", 1e-15, c.ToMonomial().C, p.C)
+	chk.Array(tst, "T4", 1e-15, NewPolyCheby(0, 0, 0, 0, 1).ToMonomial().C, []float64{1, 0, -8, 0, 8})
+	chk.Int(tst, "degree(0)", NewPoly(0, 0).Degree(), -1)
+}
+
+func TestPoly02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Poly02. arithmetic")
+
+	for _, basis := range []PolyBasis{PolyMonomial, PolyChebyshev} {
+		a := NewPoly(2, -1, 0.5, 4).inBasisOf(basis)
+		b := NewPoly(-3, 0, 1).inBasisOf(basis)
+		sum, dif, mul := a.Add(b), a.Sub(b), a.Mul(b)
+		q, r := a.DivMod(b)
+		chk.Int(tst, "deg(q)", q.Degree(), 1)
+		chk.Int(tst, "len(r)", len(r.C), 2)
+		comp := a.Compose(b)
+		for _, x := range []float64{-1.3, -0.2, 0.7, 2.1} {
+			fa, fb := a.F(x), b.F(x)
+			chk.Float64(tst, "a+b", 1e-14, sum.F(x), fa+fb)
+			chk.Float64(tst, "a-b", 1e-14, dif.F(x), fa-fb)
+			chk.Float64(tst, "a⋅b", 1e-13, mul.F(x), fa*fb)
+			chk.Float64(tst, "q⋅b+r", 1e-13, q.F(x)*fb+r.F(x), fa)
+			chk.Float64(tst, "a(b)", 1e-12, comp.F(x), a.F(fb))
+		}
+
+		// mixed bases: the result is in the basis of the receiver
+		c := a.Mul(NewPolyCheby(1, 1))
+		if c.Basis != basis {
+			tst.Errorf("basis of result is incorrect\n")
+		}
+		chk.Float64(tst, "a⋅(1+x)", 1e-13, c.F(0.3), a.F(0.3)*1.3)
+	}
+
+	// exact division
+	p := NewPolyFromRoots(1, 2, 3, 4)
+	q, r := p.DivMod(NewPolyFromRoots(2, 4))
+	chk.Array(tst, "q", 1e-15, q.C, NewPolyFromRoots(1, 3).C)
+	chk.Array(tst, "r", 1e-15, r.C, []float64{0, 0})
+
+	// division by a constant
+	for _, basis := range []PolyBasis{PolyMonomial, PolyChebyshev} {
+		a := NewPoly(1, -2, 0.5, 3).inBasisOf(basis)
+		b := NewPoly(2).inBasisOf(basis)
+		q, r = a.DivMod(b)
+		chk.Array(tst, "a/2", 1e-15, q.C, a.Scale(0.5).C)
+		chk.Array(tst, "r", 1e-15, r.C, []float64{0})
+		for _, x := range []float64{-1.3, -0.2, 0.7, 2.1} {
+			chk.Float64(tst, "q⋅b+r", 1e-14, q.Mul(b).Add(r).F(x), a.F(x))
+		}
+	}
+	q, _ = NewPolyCheby(0, 0, 1).DivMod(NewPolyCheby(2))
+	chk.Array(tst, "T₂/2", 1e-15, q.C, []float64{0, 0, 0.5})
+}
+
+func TestPoly03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Poly03. calculus")
+
+	for _, basis := range []PolyBasis{PolyMonomial, PolyChebyshev} {
+		p := NewPoly(0.5, -1, 2, 0.3, -0.7, 1.1).inBasisOf(basis)
+		dp := p.Deriv()
+		P := p.Integ(-0.5)
+		chk.Float64(tst, "P(x0)", 1e-15, P.F(-0.5), 0)
+		for _, x := range []float64{-0.9, 0.1, 0.8} {
+			chk.DerivScaSca(tst, "dp/dx", 1e-9, dp.F(x), x, 1e-3, chk.Verbose, p.F)
+			chk.DerivScaSca(tst, "dP/dx", 1e-9, p.F(x), x, 1e-3, chk.Verbose, P.F)
+		}
+		chk.Array(tst, "d(∫p)/dx", 1e-14, P.Deriv().inBasisOf(PolyMonomial).C, p.inBasisOf(PolyMonomial).C)
+	}
+
+	// ∫₋₁¹ T₄ dx = -2/15
+	chk.Float64(tst, "∫T4", 1e-15, NewPolyCheby(0, 0, 0, 0, 1).Integral(-1, 1), -2.0/15.0)
+	chk.Float64(tst, "∫x²", 1e-15, NewPoly(0, 0, 1).Integral(0, 3), 9)
+}
+
+func TestPoly04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Poly04. GCD")
+
+	a := NewPolyFromRoots(1, -2, 0.5, 3)
+	b := NewPolyFromRoots(-2, 3, 7)
+	for _, basis := range []PolyBasis{PolyMonomial, PolyChebyshev} {
+		g := a.inBasisOf(basis).GCD(b, 1e-10)
+		chk.Array(tst, "gcd", 1e-12, g.ToMonomial().Scale(1/g.ToMonomial().C[2]).C, NewPolyFromRoots(-2, 3).C)
+	}
+	g := a.GCD(NewPolyFromRoots(5, 6), 1e-10)
+	chk.Int(tst, "coprime", g.Degree(), 0)
+}
+
+func TestPoly05(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Poly05. roots")
+
+	// real roots; monomial and Chebyshev bases
+	roots := []float64{-2.5, -1, 0, 0.3, 1.7, 4}
+	p := NewPolyFromRoots(roots...)
+	chk.Array(tst, "real roots", 1e-13, p.RealRoots(1e-10), roots)
+	chk.Array(tst, "cheby roots", 1e-13, p.ToCheby().RealRoots(1e-10), roots)
+
+	// complex roots: x⁴ + 1 = 0
+	z := NewPoly(1, 0, 0, 0, 1).Roots()
+	s := math.Sqrt2 / 2
+	chk.ArrayC(tst, "roots of x⁴+1", 1e-15, z, []complex128{complex(-s, -s), complex(-s, s), complex(s, -s), complex(s, s)})
+	chk.Int(tst, "no real roots", len(NewPoly(1, 0, 0, 0, 1).RealRoots(1e-10)), 0)
+
+	// cubic against num.EqCubicSolveReal's case: x³ - 6x² + 11x - 6
+	chk.Array(tst, "cubic", 1e-14, NewPoly(-6, 11, -6, 1).RealRoots(1e-10), []float64{1, 2, 3})
+
+	// Chebyshev T₁₀: roots are cos((2k-1)π/20)
+	t10 := NewPolyCheby(0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1)
+	xr := t10.RealRoots(1e-10)
+	chk.Int(tst, "n roots", len(xr), 10)
+	for k := 0; k < 10; k++ {
+		chk.Float64(tst, io.Sf("T10 root %d", k), 1e-15, xr[k], -math.Cos(float64(2*k+1)*math.Pi/20))
+	}
+
+	// Wilkinson-like polynomial of degree 12 in the Chebyshev basis on [-1,1]
+	wr := utl.LinSpace(-0.9, 0.9, 12)
+	w := NewPolyFromRoots(wr...).ToCheby()
+	chk.Array(tst, "Wilkinson", 1e-12, w.RealRoots(1e-8), wr)
+}
+
+func TestPoly06(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Poly06. multiple and closely spaced roots")
+
+	// double root: accuracy of about √ε
+	z := NewPolyFromRoots(1, 1, 2).Roots()
+	chk.Int(tst, "n roots", len(z), 3)
+	chk.Complex128(tst, "root 0", 1e-7, z[0], 1)
+	chk.Complex128(tst, "root 1", 1e-7, z[1], 1)
+	chk.Complex128(tst, "root 2", 1e-14, z[2], 2)
+
+	// closely spaced roots must not be merged
+	io.Pl()
+	x := NewPolyFromRoots(1, 1+1e-6, 2).RealRoots(1e-10)
+	chk.Array(tst, "close roots", 1e-9, x, []float64{1, 1 + 1e-6, 2})
+
+	// Wilkinson's polynomial of degree 15 in the monomial basis
+	io.Pl()
+	wr := utl.LinSpace(1, 15, 15)
+	chk.Array(tst, "Wilkinson", 1e-5, NewPolyFromRoots(wr...).RealRoots(1e-8), wr)
+
+	// Newton's steps must keep z within rad: starting at 1.08, the iterations for
+	// (x-1)(x-1.1) would converge to 1.1
+	io.Pl()
+	p := NewPolyFromRoots(1, 1.1)
+	zp := p.polish(p.Deriv(), 1.08, 0.01)
+	if cmplx.Abs(zp-1.08) > 0.01 {
+		tst.Errorf("polish moved z from 1.08 to %v, beyond rad = 0.01\n", zp)
+	}
+	chk.Complex128(tst, "free polish", 1e-15, p.polish(p.Deriv(), 1.08, math.Inf(+1)), 1.1)
+}
@@ -0,0 +1,200 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/utl"
+)
+
+func TestRational01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Rational01. AAA approximation, poles and residues")
+
+	// rational function: 1/(x - 1.5) + 2/(x + 2)
+	f := func(x float64) float64 { return 1.0/(x-1.5) + 2.0/(x+2.0) }
+	x := utl.LinSpace(-1, 1, 200)
+	y := make([]float64, len(x))
+	for i, xi := range x {
+		y[i] = f(xi)
+	}
+	o, maxErr := NewAAA(x, y, 0, 0)
+	io.Pforan("number of support points = %d, max error = %g\n", len(o.Z), maxErr)
+	chk.Int(tst, "number of support points", len(o.Z), 3)
+	var g Ss = o.F
+	for _, xi := range []float64{-0.97, -0.3, 0.11, 0.8} {
+		chk.Float64(tst, io.Sf("r(%g)", xi), 1e-13, g(xi), f(xi))
+	}
+	poles := o.Poles()
+	chk.Int(tst, "number of poles", len(poles), 2)
+	chk.Complex128(tst, "pole 0", 1e-12, poles[0], -2)
+	chk.Complex128(tst, "pole 1", 1e-12, poles[1], 1.5)
+	res := o.Residues(poles)
+	chk.Complex128(tst, "res 0", 1e-11, res[0], 2)
+	chk.Complex128(tst, "res 1", 1e-11, res[1], 1)
+	zeros := o.Zeros()
+	chk.Int(tst, "number of zeros", len(zeros), 1)
+	chk.Complex128(tst, "zero", 1e-12, zeros[0], 1.0/3.0) // 3x - 1 = 0
+
+	// Runge function: poles at ±i/5 and residues ∓i/10
+	runge := func(x float64) float64 { return 1.0 / (1.0 + 25.0*x*x) }
+	x = utl.LinSpace(-1, 1, 1000)
+	y = make([]float64, len(x))
+	for i, xi := range x {
+		y[i] = runge(xi)
+	}
+	o, maxErr = NewAAA(x, y, 1e-13, 0)
+	io.Pforan("Runge: number of support points = %d, max error = %g\n", len(o.Z), maxErr)
+	if maxErr > 1e-13 {
+		tst.Errorf("max error is too large: %g\n", maxErr)
+	}
+	for _, xi := range []float64{-0.9995, -0.123, 0.5004} {
+		chk.Float64(tst, io.Sf("r(%g)", xi), 1e-13, o.F(xi), runge(xi))
+	}
+	chk.Complex128(tst, "r(0.1+0.1i)", 1e-12, o.FC(complex(0.1, 0.1)), 1/(1+25*complex(0.1, 0.1)*complex(0.1, 0.1)))
+	poles = o.Poles()
+	var found int
+	for _, p := range poles {
+		for _, q := range []complex128{0.2i, -0.2i} {
+			if math.Abs(real(p-q))+math.Abs(imag(p-q)) < 1e-8 {
+				found++
+				r := o.Residues([]complex128{p})[0]
+				chk.Complex128(tst, io.Sf("res(%v)", q), 1e-8, r, -q/2)
+			}
+		}
+	}
+	chk.Int(tst, "number of poles found", found, 2)
+}
+
+func TestRational02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Rational02. Padé approximants")
+
+	// exp(x): [2/2] = (1 + x/2 + x²/12) / (1 - x/2 + x²/12)
+	c := []float64{1, 1, 1.0 / 2.0, 1.0 / 6.0, 1.0 / 24.0}
+	o := NewPade(c, 0, 2, 2)
+	chk.Array(tst, "P", 1e-15, o.P.C, []float64{1, 0.5, 1.0 / 12.0})
+	chk.Array(tst, "Q", 1e-15, o.Q.C, []float64{1, -0.5, 1.0 / 12.0})
+	chk.Float64(tst, "r(0.1)", 1e-7, o.F(0.1), math.Exp(0.1))
+
+	// log(x) about x0 = 1: log(1 + t) = t - t²/2 + t³/3 - ...
+	c = make([]float64, 12)
+	for k := 1; k < len(c); k++ {
+		c[k] = NegOnePowN(k+1) / float64(k)
+	}
+	var g Ss = NewPade(c, 1, 5, 6).F
+	for _, x := range []float64{0.5, 1, 1.7, 3} {
+		chk.Float64(tst, io.Sf("log(%g)", x), 1e-5, g(x), math.Log(x))
+	}
+	chk.Float64(tst, "log(1.2)", 1e-13, g(1.2), math.Log(1.2))
+
+	// poles of [0/1] of 1/(1-x) = Σ xᵏ
+	o = NewPade([]float64{1, 1}, 0, 0, 1)
+	poles := o.Poles()
+	chk.Int(tst, "number of poles", len(poles), 1)
+	chk.Complex128(tst, "pole", 1e-15, poles[0], 1)
+
+	// cos(x) = 1 - x²/2 + x⁴/24 - ...: [1/1] is degenerate (c₁ = 0) and reduces to r = 1
+	c = []float64{1, 0, -1.0 / 2.0, 0, 1.0 / 24.0}
+	o = NewPade(c, 0, 1, 1)
+	chk.Array(tst, "cos [1/1]: P", 1e-15, o.P.C, []float64{1})
+	chk.Array(tst, "cos [1/1]: Q", 1e-15, o.Q.C, []float64{1})
+
+	// cos(x): [2/2] = (1 - 5x²/12) / (1 + x²/12)
+	o = NewPade(c, 0, 2, 2)
+	chk.Array(tst, "cos [2/2]: P", 1e-15, o.P.C, []float64{1, 0, -5.0 / 12.0})
+	chk.Array(tst, "cos [2/2]: Q", 1e-15, o.Q.C, []float64{1, 0, 1.0 / 12.0})
+}
+
+func TestRational03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Rational03. Remez minimax approximation")
+
+	// best linear approximation of exp(x) on [-1, 1]: equioscillation at -1, x*, 1
+	b := math.Sinh(1)
+	xs := math.Log(b)
+	E := (math.Exp(-1) + b*xs) / 2
+	r, maxErr, err := Remez(math.Exp, -1, 1, 1, 0, 0, 0)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("linear: E = %g, max error = %g\n", E, maxErr)
+	chk.Float64(tst, "E", 1e-12, maxErr, E)
+	chk.Float64(tst, "slope", 1e-10, r.P.C[1], b)
+
+	// polynomial and rational approximations of exp(x) on [0, 2]
+	_, e2, err := Remez(math.Exp, 0, 2, 2, 0, 0, 0)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	r, e11, err := Remez(math.Exp, 0, 2, 1, 1, 1e-10, 0)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("polynomial (2,0): max error = %g\n", e2)
+	io.Pforan("rational   (1,1): max error = %g\n", e11)
+	if e11 >= e2 {
+		tst.Errorf("rational approximation should be better than the polynomial one\n")
+	}
+	var g Ss = r.F
+	for _, x := range utl.LinSpace(0, 2, 21) {
+		if math.Abs(g(x)-math.Exp(x)) > e11*(1+1e-8) {
+			tst.Errorf("error at %g is greater than max error\n", x)
+		}
+	}
+
+	// rational approximation of a function with a nearby pole
+	f := func(x float64) float64 { return math.Sqrt(x+1.1) * math.Atan(x) }
+	_, e33, err := Remez(f, -1, 1, 3, 3, 1e-8, 0)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	_, e6, err := Remez(f, -1, 1, 6, 0, 1e-8, 0)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("polynomial (6,0): max error = %g\n", e6)
+	io.Pforan("rational   (3,3): max error = %g\n", e33)
+	if e33 >= e6 {
+		tst.Errorf("rational approximation should be better than the polynomial one\n")
+	}
+
+	// exact fits: the error is at the rounding level and does not equioscillate
+	sq := func(x float64) float64 { return x * x }
+	r, maxErr, err = Remez(sq, -1, 1, 2, 0, 0, 0)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("x² with m = 2: max error = %g\n", maxErr)
+	chk.Float64(tst, "x²: max error", 1e-15, maxErr, 0)
+	chk.Float64(tst, "x²: r(0.3)", 1e-15, r.F(0.3), 0.09)
+	cube := func(x float64) float64 { return x * x * x }
+	r, maxErr, err = Remez(cube, 0, 2, 4, 0, 0, 0)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	chk.Float64(tst, "x³: max error", 1e-14, maxErr, 0)
+	chk.Float64(tst, "x³: r(1.5)", 1e-14, r.F(1.5), 3.375)
+
+	// errors
+	_, _, err = Remez(math.Exp, 1, 0, 1, 0, 0, 0)
+	if err == nil {
+		tst.Errorf("invalid interval should fail\n")
+	}
+}
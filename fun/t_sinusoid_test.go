@@ -6,9 +6,11 @@ package fun
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
 	"github.com/lei006/gomath/utl"
 )
 
@@ -52,3 +54,79 @@ func TestSinusoid01(tst *testing.T) {
 		chk.Panic("failed\n")
 	}
 }
+
+func TestSinusoid02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Sinusoid02. least-squares fit with known period")
+
+	// irregular sampling of a series with 3 harmonics
+	T := 2.5
+	ω0 := 2.0 * math.Pi / T
+	yfcn := func(t float64) float64 {
+		return 1.2 + 0.8*math.Cos(ω0*t+0.3) + 0.25*math.Cos(2*ω0*t-1.1) + 0.1*math.Cos(3*ω0*t+2.0)
+	}
+	rnd := rand.New(rand.NewSource(1357))
+	n := 80
+	t := make([]float64, n)
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		t[i] = 10 * rnd.Float64()
+		y[i] = yfcn(t[i])
+	}
+
+	// exact fit
+	o, fit := FitSinusoid(t, y, T, 3)
+	C, θ := o.Harmonics()
+	chk.Array(tst, "C", 1e-13, C, []float64{1.2, 0.8, 0.25, 0.1})
+	chk.Array(tst, "θ", 1e-12, θ, []float64{0, 0.3, -1.1, 2.0})
+	chk.Float64(tst, "Amplitude", 1e-13, o.Amplitude, 0.8)
+	chk.Float64(tst, "PhaseShift", 1e-12, o.PhaseShift, 0.3)
+	chk.Float64(tst, "MeanValue", 1e-13, o.MeanValue, 1.2)
+	chk.Float64(tst, "Ybasis", 1e-13, o.Ybasis(3.3), yfcn(3.3))
+	chk.Int(tst, "Npts", fit.Npts, n)
+	chk.Int(tst, "Nharm", fit.Nharm, 3)
+	chk.Float64(tst, "Rss", 1e-24, fit.Rss, 0)
+	chk.Float64(tst, "R2", 1e-15, fit.R2, 1)
+
+	// truncated fit: residual equals the energy of the third harmonic
+	o, fit = FitSinusoid(t, y, T, 2)
+	var rss float64
+	for i := 0; i < n; i++ {
+		rss += math.Pow(y[i]-o.Ybasis(t[i]), 2)
+	}
+	io.Pforan("N=2: Rss = %g, Rmse = %g, R2 = %g\n", fit.Rss, fit.Rmse, fit.R2)
+	chk.Float64(tst, "Rss(N=2)", 1e-14, fit.Rss, rss)
+	chk.Float64(tst, "Rmse(N=2)", 1e-15, fit.Rmse, math.Sqrt(rss/float64(n)))
+	chk.Float64(tst, "Sigma(N=2)", 1e-15, fit.Sigma, math.Sqrt(rss/float64(n-5)))
+	if math.Abs(fit.Rmse-0.1/math.Sqrt2) > 0.02 {
+		tst.Errorf("Rmse should be close to the rms of the missing harmonic: %g\n", fit.Rmse)
+	}
+}
+
+func TestSinusoid03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Sinusoid03. least-squares fit with unknown period")
+
+	// noisy samples
+	rnd := rand.New(rand.NewSource(2468))
+	n := 200
+	t := make([]float64, n)
+	y := make([]float64, n)
+	for _, c := range []struct{ T, a1, a2 float64 }{{1.7, 1.0, 0.3}, {3.1, 0.2, 1.0}} {
+		ω0 := 2.0 * math.Pi / c.T
+		for i := 0; i < n; i++ {
+			t[i] = 40 * rnd.Float64()
+			y[i] = 5 + c.a1*math.Sin(ω0*t[i]) + c.a2*math.Cos(2*ω0*t[i]+0.5) + 0.05*rnd.NormFloat64()
+		}
+		o, fit := FitSinusoidAuto(t, y, 3, 0, 0)
+		io.Pforan("T = %v: Tfit = %v, σ = %g, R2 = %g\n", c.T, o.Period, fit.Sigma, fit.R2)
+		chk.Float64(tst, "T", 1e-3, o.Period, c.T)
+		C, _ := o.Harmonics()
+		chk.Array(tst, "C", 0.03, C, []float64{5, c.a1, c.a2, 0})
+		if math.Abs(fit.Sigma-0.05) > 0.01 {
+			tst.Errorf("σ should be close to the noise level: %g\n", fit.Sigma)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+)
+
+func TestTensorInterp01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("TensorInterp01. 3D evaluation and gradient")
+
+	// function and box
+	f := func(x la.Vector) float64 { return math.Exp(x[0]) * math.Sin(x[1]) * math.Cos(x[2]) }
+	dfdx := func(g, x []float64) {
+		g[0] = math.Exp(x[0]) * math.Sin(x[1]) * math.Cos(x[2])
+		g[1] = math.Exp(x[0]) * math.Cos(x[1]) * math.Cos(x[2])
+		g[2] = -math.Exp(x[0]) * math.Sin(x[1]) * math.Sin(x[2])
+	}
+	xmin := []float64{0, -1, 0.5}
+	xmax := []float64{1, 2, 1.5}
+
+	// interpolant
+	o := NewTensorInterpCheby([]int{12, 16, 12}, xmin, xmax)
+	o.CalcU(f)
+	chk.Int(tst, "ndim", o.Ndim(), 3)
+	chk.Int(tst, "npts", o.Npoints(), 13*17*13)
+
+	// nodes
+	x := make([]float64, 3)
+	for _, idx := range []int{0, 17, 1000, o.Npoints() - 1} {
+		o.Node(x, idx)
+		chk.Float64(tst, io.Sf("I(node %d)", idx), 1e-15, o.F(x), o.U[idx])
+	}
+
+	// random points
+	rnd := rand.New(rand.NewSource(1234))
+	g, gana := make([]float64, 3), make([]float64, 3)
+	for i := 0; i < 20; i++ {
+		for k := 0; k < 3; k++ {
+			x[k] = xmin[k] + rnd.Float64()*(xmax[k]-xmin[k])
+		}
+		chk.Float64(tst, io.Sf("I(%.3f,%.3f,%.3f)", x[0], x[1], x[2]), 1e-12, o.F(x), f(x))
+		res := o.G(g, x)
+		dfdx(gana, x)
+		chk.Float64(tst, "G: res", 1e-12, res, f(x))
+		chk.Array(tst, "G: grad", 1e-10, g, gana)
+	}
+
+	// gradient at a node
+	o.Node(x, 1234)
+	o.G(g, x)
+	dfdx(gana, x)
+	chk.Array(tst, "grad(node)", 1e-10, g, gana)
+
+	// other grids
+	lis := NewLagIntSet(2, []int{10, 12}, []string{"cg", "uni"})
+	p := NewTensorInterp(lis, nil, nil)
+	p.CalcU(func(x la.Vector) float64 { return 1 + x[0]*x[0]*x[0] - 2*x[0]*x[1]*x[1] })
+	chk.Float64(tst, "I(-0.3,0.7)", 1e-13, p.F([]float64{-0.3, 0.7}), 1-0.027+0.6*0.49)
+}
+
+func TestTensorInterp02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("TensorInterp02. differentiation matrices and Chebyshev coefficients")
+
+	// f(x,y) = sin(x) y³ on [0, 2] × [-1, 1]
+	o := NewTensorInterpCheby([]int{16, 4}, []float64{0, -1}, []float64{2, 1})
+	o.CalcU(func(x la.Vector) float64 { return math.Sin(x[0]) * x[1] * x[1] * x[1] })
+	npts := o.Npoints()
+	v := la.NewVector(npts)
+	x := make([]float64, 2)
+	ana := []func(x []float64) float64{
+		func(x []float64) float64 { return math.Cos(x[0]) * x[1] * x[1] * x[1] },
+		func(x []float64) float64 { return 3 * math.Sin(x[0]) * x[1] * x[1] },
+		func(x []float64) float64 { return -math.Sin(x[0]) * x[1] * x[1] * x[1] },
+		func(x []float64) float64 { return 6 * math.Sin(x[0]) * x[1] },
+	}
+	for i, c := range []struct{ k, order int }{{0, 1}, {1, 1}, {0, 2}, {1, 2}} {
+		D := o.DiffMat(c.k, c.order).ToMatrix(nil)
+		la.SpMatVecMul(v, 1, D, o.U)
+		var maxErr float64
+		for idx := 0; idx < npts; idx++ {
+			o.Node(x, idx)
+			maxErr = math.Max(maxErr, math.Abs(v[idx]-ana[i](x)))
+		}
+		io.Pforan("k=%d order=%d: max error = %g\n", c.k, c.order, maxErr)
+		if maxErr > 1e-9 {
+			tst.Errorf("differentiation error is too large: %g\n", maxErr)
+		}
+	}
+
+	// f = 0.5 + T₂(x) T₃(y) + 2 T₁(y)
+	p := NewTensorInterpCheby([]int{4, 5}, nil, nil)
+	p.CalcU(func(x la.Vector) float64 {
+		return 0.5 + ChebyshevT(2, x[0])*ChebyshevT(3, x[1]) + 2*x[1]
+	})
+	correct := make([]float64, p.Npoints())
+	correct[0] = 0.5
+	correct[2+5*3] = 1
+	correct[0+5*1] = 2
+	chk.Array(tst, "coef", 1e-14, p.CoefCheby(), correct)
+}
+
+func TestSmolyak01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Smolyak01. sparse grids")
+
+	// number of points of the Clenshaw-Curtis sparse grids; 9²⁰ finest-grid indices overflow int64 for d = 20
+	for _, c := range []struct{ d, μ, n int }{{1, 3, 9}, {2, 0, 1}, {2, 1, 5}, {2, 2, 13}, {2, 3, 29}, {3, 2, 25}, {3, 3, 69}, {20, 3, 11561}} {
+		o := NewSmolyak(c.d, c.μ, nil, nil)
+		chk.Int(tst, io.Sf("npts(d=%d,μ=%d)", c.d, c.μ), o.Npoints(), c.n)
+	}
+
+	// exactness for polynomials of the sparse space
+	f := func(x la.Vector) float64 {
+		return 1 + math.Pow(x[0], 4) + x[0]*x[0]*x[1]*x[1] + x[1]*x[2] - 3*x[2]*x[2]
+	}
+	o := NewSmolyak(3, 2, []float64{-1, 0, 1}, []float64{1, 2, 2})
+	o.CalcU(f)
+	rnd := rand.New(rand.NewSource(4321))
+	x := make([]float64, 3)
+	g := make([]float64, 3)
+	for i := 0; i < 10; i++ {
+		for k := 0; k < 3; k++ {
+			x[k] = o.Xmin[k] + rnd.Float64()*(o.Xmax[k]-o.Xmin[k])
+		}
+		chk.Float64(tst, "A(x)", 1e-13, o.F(x), f(x))
+		o.G(g, x)
+		gana := []float64{
+			4*math.Pow(x[0], 3) + 2*x[0]*x[1]*x[1],
+			2*x[0]*x[0]*x[1] + x[2],
+			x[1] - 6*x[2],
+		}
+		chk.Array(tst, "grad", 1e-12, g, gana)
+	}
+
+	// interpolation at sparse-grid points
+	for i, p := range o.X {
+		if math.Abs(o.F(p)-o.U[i]) > 1e-13 {
+			tst.Errorf("interpolant at point %d (%v) is incorrect\n", i, p)
+		}
+	}
+
+	// convergence in 4D
+	h := func(x la.Vector) float64 { return math.Exp(-(x[0]*x[0] + x[1]*x[1] + x[2]*x[2] + x[3]*x[3]) / 4) }
+	xs := make([][]float64, 50)
+	for i := range xs {
+		xs[i] = make([]float64, 4)
+		for k := range xs[i] {
+			xs[i][k] = 2*rnd.Float64() - 1
+		}
+	}
+	prev := math.Inf(1)
+	for μ := 2; μ <= 6; μ++ {
+		s := NewSmolyak(4, μ, nil, nil)
+		s.CalcU(h)
+		var maxErr float64
+		for _, p := range xs {
+			maxErr = math.Max(maxErr, math.Abs(s.F(p)-h(p)))
+		}
+		io.Pforan("μ=%d: npts = %5d, max error = %g\n", μ, s.Npoints(), maxErr)
+		if maxErr > prev {
+			tst.Errorf("error should decrease with the level\n")
+		}
+		prev = maxErr
+	}
+	if prev > 1e-6 {
+		tst.Errorf("error is too large: %g\n", prev)
+	}
+}
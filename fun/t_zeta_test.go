@@ -0,0 +1,40 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+func TestZeta01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Zeta01. Riemann and Hurwitz zeta functions")
+
+	_, dat := io.ReadTable("data/specfun-zeta.cmp")
+	for i, s := range dat["s"] {
+		checkSpecfun(tst, io.Sf("ζ(%g)", s), 1e-14, Zeta(s), dat["zeta"][i])
+	}
+
+	io.Pl()
+	_, dat = io.ReadTable("data/specfun-hurwitz.cmp")
+	for i, s := range dat["s"] {
+		q := dat["q"][i]
+		checkSpecfun(tst, io.Sf("ζ(%g,%g)", s, q), 1e-14, HurwitzZeta(s, q), dat["zeta"][i])
+	}
+
+	// special values
+	io.Pl()
+	chk.Float64(tst, "ζ(0)", 1e-15, Zeta(0), -0.5)
+	chk.Float64(tst, "ζ(2)", 1e-15, Zeta(2), math.Pi*math.Pi/6)
+	chk.Float64(tst, "ζ(-2)", 1e-15, Zeta(-2), 0)
+	if !math.IsInf(Zeta(1), +1) {
+		tst.Errorf("ζ(1) should be +Inf\n")
+	}
+}
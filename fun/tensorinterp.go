@@ -0,0 +1,603 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/la"
+)
+
+// TensorInterp implements a tensor-product (spectral) Lagrange interpolant over a box
+//
+//	                  N₀      Nd-1
+//	   I{f}(x) =  Σ  ⋯  Σ   U[i₀,…,id-1] ⋅ ℓ⁰_i₀(ξ₀) ⋯ ℓᵈ⁻¹_id-1(ξd-1)
+//	                 i₀=0    id-1=0
+//
+//	where ℓᵏ_i are the cardinal polynomials of the k-th LagrangeInterp in Lis and ξ ∈ [-1, 1]ᵈ
+//	are the reference coordinates of x ∈ [Xmin, Xmax]:
+//
+//	         2 xₖ - (Xminₖ + Xmaxₖ)
+//	   ξₖ = ————————————————————————
+//	            Xmaxₖ - Xminₖ
+//
+//	The values at nodes are stored with the first axis running fastest; i.e. in 3D:
+//
+//	   U(i,j,k) is stored at U[i + n₀⋅j + n₀⋅n₁⋅k]    with nₖ = Nₖ + 1
+//
+//	The interpolant is evaluated by sum-factorisation; thus the cost is O(n₀⋅n₁⋯nd-1).
+//
+//	NOTE: the scratch arrays make TensorInterp not safe for concurrent use
+//
+//	References:
+//	  [1] Canuto C, Hussaini MY, Quarteroni A, Zang TA (2006) Spectral Methods: Fundamentals in
+//	      Single Domains. Springer. 563p
+//	  [2] Berrut JP, Trefethen LN (2004) Barycentric Lagrange Interpolation,
+//	      SIAM Review Vol. 46, No. 3, pp. 501-517
+type TensorInterp struct {
+
+	// input
+	Lis  LagIntSet // 1D interpolators [ndim]
+	Xmin []float64 // lower bounds of the box [ndim]
+	Xmax []float64 // upper bounds of the box [ndim]
+
+	// computed
+	U []float64 // function values at nodes [npts]; first axis runs fastest
+
+	// derived
+	ndim    int         // number of dimensions
+	npts    int         // number of nodes
+	n       []int       // number of nodes along each axis
+	strides []int       // strides of U along each axis
+	w       [][]float64 // [ndim][n[k]] cardinal functions at x
+	dw      [][]float64 // [ndim][n[k]] derivatives of cardinal functions at x
+	buf     []float64   // scratch array for the sum-factorisation
+}
+
+// NewTensorInterp returns a new tensor-product interpolant
+//
+//	lis        -- 1D interpolators; e.g. from NewLagIntSet
+//	xmin, xmax -- bounds of the box; use nil for [-1, 1]ᵈ
+func NewTensorInterp(lis LagIntSet, xmin, xmax []float64) (o *TensorInterp) {
+	if len(lis) < 1 {
+		chk.Panic("TensorInterp requires at least one LagrangeInterp\n")
+	}
+	o = new(TensorInterp)
+	o.Lis = lis
+	o.ndim = len(lis)
+	o.Xmin, o.Xmax = boxBounds(o.ndim, xmin, xmax)
+	o.n = make([]int, o.ndim)
+	o.strides = make([]int, o.ndim)
+	o.w = make([][]float64, o.ndim)
+	o.dw = make([][]float64, o.ndim)
+	o.npts = 1
+	for k, l := range lis {
+		o.n[k] = l.N + 1
+		o.strides[k] = o.npts
+		o.w[k] = make([]float64, o.n[k])
+		o.dw[k] = make([]float64, o.n[k])
+		o.npts *= o.n[k]
+	}
+	o.U = make([]float64, o.npts)
+	o.buf = make([]float64, o.npts)
+	return
+}
+
+// NewTensorInterpCheby returns a new tensor-product interpolant over Chebyshev-Gauss-Lobatto
+// grids with given degrees along each axis
+func NewTensorInterpCheby(degrees []int, xmin, xmax []float64) (o *TensorInterp) {
+	types := make([]string, len(degrees))
+	for k := range types {
+		types[k] = "cgl"
+	}
+	return NewTensorInterp(NewLagIntSet(len(degrees), degrees, types), xmin, xmax)
+}
+
+// Ndim returns the number of dimensions
+func (o *TensorInterp) Ndim() int {
+	return o.ndim
+}
+
+// Npoints returns the number of nodes
+func (o *TensorInterp) Npoints() int {
+	return o.npts
+}
+
+// Node computes the (physical) coordinates x of the node with index idx
+func (o *TensorInterp) Node(x []float64, idx int) {
+	for k := 0; k < o.ndim; k++ {
+		i := (idx / o.strides[k]) % o.n[k]
+		x[k] = toPhysBox(o.Lis[k].X[i], o.Xmin[k], o.Xmax[k])
+	}
+}
+
+// CalcU computes the function values U at all nodes
+func (o *TensorInterp) CalcU(f Sv) {
+	x := la.NewVector(o.ndim)
+	for idx := 0; idx < o.npts; idx++ {
+		o.Node(x, idx)
+		o.U[idx] = f(x)
+	}
+}
+
+// F computes the interpolated value at x
+//
+//	NOTE: U must be computed first with CalcU or set directly
+func (o *TensorInterp) F(x []float64) float64 {
+	o.calcWeights(x, false)
+	return tensorContract(o.buf, o.U, o.n, o.w, -1, nil)
+}
+
+// G computes the interpolated value at x and its gradient
+//
+//	Output:
+//	  grad -- gradient ∂I{f}/∂x[k] (must have length Ndim)
+//	  res  -- the interpolated value
+func (o *TensorInterp) G(grad, x []float64) (res float64) {
+	o.calcWeights(x, true)
+	for k := 0; k < o.ndim; k++ {
+		grad[k] = tensorContract(o.buf, o.U, o.n, o.w, k, o.dw[k]) * 2.0 / (o.Xmax[k] - o.Xmin[k])
+	}
+	return tensorContract(o.buf, o.U, o.n, o.w, -1, nil)
+}
+
+// DiffMat computes the (sparse) differentiation matrix along axis k
+//
+//	 ∂ᵖ I{f}  |            npts-1
+//	———————— |         =   Σ    Dₖ[I,J] ⋅ U[J]        p = order = 1 or 2
+//	  ∂xₖᵖ    |x=node I     J=0
+//
+//	i.e. Dₖ = I ⊗ ⋯ ⊗ D⁽ᵖ⁾ ⊗ ⋯ ⊗ I (scaled to physical coordinates), where D⁽ᵖ⁾ is the D1 or D2
+//	matrix of the k-th LagrangeInterp (computed here if not available yet)
+func (o *TensorInterp) DiffMat(k, order int) (D *la.Triplet) {
+	if k < 0 || k >= o.ndim {
+		chk.Panic("axis index must be in [0, %d]. k=%d is invalid\n", o.ndim-1, k)
+	}
+	l := o.Lis[k]
+	var d *la.Matrix
+	switch order {
+	case 1:
+		if l.D1 == nil {
+			l.CalcD1()
+		}
+		d = l.D1
+	case 2:
+		if l.D2 == nil {
+			l.CalcD2()
+		}
+		d = l.D2
+	default:
+		chk.Panic("order of derivative must be 1 or 2. %d is invalid\n", order)
+	}
+	scale := math.Pow(2.0/(o.Xmax[k]-o.Xmin[k]), float64(order))
+	nk, sk := o.n[k], o.strides[k]
+	D = la.NewTriplet(o.npts, o.npts, o.npts*nk)
+	for idx := 0; idx < o.npts; idx++ {
+		i := (idx / sk) % nk
+		base := idx - i*sk
+		for j := 0; j < nk; j++ {
+			D.Put(idx, base+j*sk, scale*d.Get(i, j))
+		}
+	}
+	return
+}
+
+// CoefCheby computes the coefficients of the tensor-product Chebyshev expansion of the
+// interpolant; i.e. the multi-dimensional version of ChebyInterp.CoefI
+//
+//	   I{f}(x) =  Σ  ⋯  Σ   C[k₀,…,kd-1] ⋅ T_k₀(ξ₀) ⋯ T_kd-1(ξd-1)
+//
+//	The coefficients are stored with the first axis running fastest (as U). The decay of these
+//	coefficients can be used to assess the resolution along each axis.
+//
+//	NOTE: all 1D interpolators must use Chebyshev-Gauss-Lobatto ("cgl") grids
+func (o *TensorInterp) CoefCheby() (coef []float64) {
+	coef = make([]float64, o.npts)
+	copy(coef, o.U)
+	tmp := make([]float64, o.npts)
+	for k, l := range o.Lis {
+		X := ChebyshevXlob(l.N)
+		for i, x := range X {
+			if math.Abs(x-l.X[i]) > 1e-14 {
+				chk.Panic("CoefCheby requires Chebyshev-Gauss-Lobatto grids. axis %d is invalid\n", k)
+			}
+		}
+
+		// ChebyInterp.X runs from +1 to -1; i.e. in the reverse order of LagrangeInterp.X
+		c := NewChebyInterp(l.N, false)
+		c.CalcConvMats()
+		nk, sk := o.n[k], o.strides[k]
+		for idx := 0; idx < o.npts; idx++ {
+			i := (idx / sk) % nk
+			base := idx - i*sk
+			var sum float64
+			for j := 0; j < nk; j++ {
+				sum += c.C.Get(i, nk-1-j) * coef[base+j*sk]
+			}
+			tmp[idx] = sum
+		}
+		coef, tmp = tmp, coef
+	}
+	return
+}
+
+// Smolyak implements a sparse-grid interpolant using Smolyak's combination of tensor-product
+// interpolants over nested Chebyshev-Gauss-Lobatto (Clenshaw-Curtis) grids
+//
+//	               ⎛  d-1  ⎞
+//	   A(μ,d) =  Σ  (-1)ᵠ ⎜       ⎟ U^l₀ ⊗ ⋯ ⊗ U^ld-1     with   q = d + μ - |l|
+//	        μ+1 ≤ |l| ≤ d+μ   ⎝   q   ⎠
+//
+//	where lₖ ≥ 1 are the levels along each axis, μ is the Level of the sparse grid and U^l is the
+//	1D interpolant with m(l) nodes:
+//
+//	   m(1) = 1 (at the centre)    and    m(l) = 2ˡ⁻¹ + 1 for l > 1
+//
+//	Because the grids are nested, A(μ,d) interpolates the data at the sparse-grid points. The
+//	number of points grows as O(2^μ μ^(d-1)) instead of O(2^(μd)) for the full tensor grid.
+//
+//	NOTE: the scratch arrays make Smolyak not safe for concurrent use
+//
+//	References:
+//	  [1] Barthelmann V, Novak E, Ritter K (2000) High dimensional polynomial interpolation on
+//	      sparse grids, Advances in Computational Mathematics, 12:273-288
+//	  [2] Gerstner T, Griebel M (1998) Numerical integration using sparse grids, Numerical
+//	      Algorithms, 18:209-232
+type Smolyak struct {
+
+	// input
+	Ndim  int       // number of dimensions
+	Level int       // level μ of the sparse grid
+	Xmin  []float64 // lower bounds of the box [ndim]
+	Xmax  []float64 // upper bounds of the box [ndim]
+
+	// computed
+	X [][]float64 // sparse-grid points (physical coordinates) [npts][ndim]
+	U []float64   // function values at sparse-grid points [npts]
+
+	// derived
+	lag   []*LagrangeInterp // 1D interpolators for each level [Level+2]; nil for levels 0 and 1
+	terms []*smolyakTerm    // tensor-product terms
+	w     [][][]float64     // [ndim][level][m(level)] cardinal functions at x
+	dw    [][][]float64     // [ndim][level][m(level)] derivatives of cardinal functions at x
+	wt    [][]float64       // [ndim] cardinal functions of one term
+	buf   []float64         // scratch array for the sum-factorisation
+	ut    []float64         // scratch array with the values of one term
+}
+
+// smolyakTerm holds one tensor-product term of the Smolyak formula
+type smolyakTerm struct {
+	coef   float64 // combination coefficient
+	levels []int   // levels along each axis
+	n      []int   // number of nodes along each axis
+	idx    []int   // maps tensor-product nodes to sparse-grid points
+}
+
+// NewSmolyak returns a new Smolyak sparse-grid interpolant
+//
+//	ndim       -- number of dimensions
+//	level      -- level μ ≥ 0 of the sparse grid
+//	xmin, xmax -- bounds of the box; use nil for [-1, 1]ᵈ
+func NewSmolyak(ndim, level int, xmin, xmax []float64) (o *Smolyak) {
+
+	// check
+	if ndim < 1 {
+		chk.Panic("Smolyak requires at least one dimension. ndim=%d is invalid\n", ndim)
+	}
+	if level < 0 || level > 10 {
+		chk.Panic("level must be in [0, 10]. level=%d is invalid\n", level)
+	}
+
+	// allocate
+	o = new(Smolyak)
+	o.Ndim = ndim
+	o.Level = level
+	o.Xmin, o.Xmax = boxBounds(ndim, xmin, xmax)
+	lmax := level + 1
+	o.lag = make([]*LagrangeInterp, lmax+1)
+	for l := 2; l <= lmax; l++ {
+		o.lag[l] = NewLagrangeInterp(smolyakM(l)-1, "cgl")
+	}
+	o.w = make([][][]float64, ndim)
+	o.dw = make([][][]float64, ndim)
+	for k := 0; k < ndim; k++ {
+		o.w[k] = make([][]float64, lmax+1)
+		o.dw[k] = make([][]float64, lmax+1)
+		for l := 1; l <= lmax; l++ {
+			o.w[k][l] = make([]float64, smolyakM(l))
+			o.dw[k][l] = make([]float64, smolyakM(l))
+		}
+	}
+
+	// sparse-grid points are identified by their indices on the finest 1D grid
+	nfine := smolyakM(lmax) - 1
+	fineIndex := func(l, i int) int {
+		if l == 1 {
+			return nfine / 2
+		}
+		return i * nfine / (smolyakM(l) - 1)
+	}
+	points := make(map[string]int)
+	key := make([]int, ndim)
+	code := make([]byte, 0, 4*ndim) // composite key of the points map
+
+	// multi-indices with μ+1 ≤ |l| ≤ d+μ
+	levels := make([]int, ndim)
+	for k := range levels {
+		levels[k] = 1
+	}
+	maxTerm := 0
+	for {
+		sum := 0
+		for _, l := range levels {
+			sum += l
+		}
+		q := ndim + level - sum
+		if q >= 0 && q <= ndim-1 {
+			t := &smolyakTerm{
+				coef:   NegOnePowN(q) * Binomial(ndim-1, q),
+				levels: append([]int{}, levels...),
+				n:      make([]int, ndim),
+			}
+			ntot := 1
+			for k, l := range levels {
+				t.n[k] = smolyakM(l)
+				ntot *= t.n[k]
+			}
+			t.idx = make([]int, ntot)
+			for j := 0; j < ntot; j++ {
+				r := j
+				code = code[:0]
+				for k := 0; k < ndim; k++ {
+					key[k] = fineIndex(t.levels[k], r%t.n[k])
+					r /= t.n[k]
+					code = strconv.AppendInt(code, int64(key[k]), 36)
+					code = append(code, ',')
+				}
+				p, ok := points[string(code)]
+				if !ok {
+					p = len(o.X)
+					points[string(code)] = p
+					x := make([]float64, ndim)
+					for k := 0; k < ndim; k++ {
+						ξ := 0.0
+						if lmax > 1 {
+							ξ = o.lag[lmax].X[key[k]]
+						}
+						x[k] = toPhysBox(ξ, o.Xmin[k], o.Xmax[k])
+					}
+					o.X = append(o.X, x)
+				}
+				t.idx[j] = p
+			}
+			o.terms = append(o.terms, t)
+			if ntot > maxTerm {
+				maxTerm = ntot
+			}
+		}
+
+		// next multi-index with |l| ≤ d+μ
+		k := 0
+		for ; k < ndim; k++ {
+			levels[k]++
+			if sum++; sum <= ndim+level {
+				break
+			}
+			sum -= levels[k] - 1
+			levels[k] = 1
+		}
+		if k == ndim {
+			break
+		}
+	}
+	o.U = make([]float64, len(o.X))
+	o.wt = make([][]float64, ndim)
+	o.buf = make([]float64, maxTerm)
+	o.ut = make([]float64, maxTerm)
+	return
+}
+
+// Npoints returns the number of sparse-grid points
+func (o *Smolyak) Npoints() int {
+	return len(o.X)
+}
+
+// CalcU computes the function values U at all sparse-grid points
+func (o *Smolyak) CalcU(f Sv) {
+	for i, x := range o.X {
+		o.U[i] = f(x)
+	}
+}
+
+// F computes the interpolated value at x
+//
+//	NOTE: U must be computed first with CalcU or set directly
+func (o *Smolyak) F(x []float64) (res float64) {
+	o.calcWeights(x, false)
+	w := o.wt
+	for _, t := range o.terms {
+		for k, l := range t.levels {
+			w[k] = o.w[k][l]
+		}
+		res += t.coef * tensorContract(o.buf, o.termValues(t), t.n, w, -1, nil)
+	}
+	return
+}
+
+// G computes the interpolated value at x and its gradient
+//
+//	Output:
+//	  grad -- gradient ∂A{f}/∂x[k] (must have length Ndim)
+//	  res  -- the interpolated value
+func (o *Smolyak) G(grad, x []float64) (res float64) {
+	o.calcWeights(x, true)
+	for k := range grad {
+		grad[k] = 0
+	}
+	w := o.wt
+	for _, t := range o.terms {
+		for k, l := range t.levels {
+			w[k] = o.w[k][l]
+		}
+		u := o.termValues(t)
+		res += t.coef * tensorContract(o.buf, u, t.n, w, -1, nil)
+		for k, l := range t.levels {
+			if l > 1 {
+				grad[k] += t.coef * tensorContract(o.buf, u, t.n, w, k, o.dw[k][l]) * 2.0 / (o.Xmax[k] - o.Xmin[k])
+			}
+		}
+	}
+	return
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// calcWeights computes the cardinal functions (and derivatives) of each axis at x
+func (o *TensorInterp) calcWeights(x []float64, withDeriv bool) {
+	if len(x) != o.ndim {
+		chk.Panic("length of x must be equal to %d. %d is invalid\n", o.ndim, len(x))
+	}
+	for k, l := range o.Lis {
+		ξ := toRefBox(x[k], o.Xmin[k], o.Xmax[k])
+		if withDeriv {
+			lagCardinals(o.w[k], o.dw[k], l, ξ)
+		} else {
+			lagCardinals(o.w[k], nil, l, ξ)
+		}
+	}
+}
+
+// calcWeights computes the cardinal functions (and derivatives) of each axis and level at x
+func (o *Smolyak) calcWeights(x []float64, withDeriv bool) {
+	if len(x) != o.Ndim {
+		chk.Panic("length of x must be equal to %d. %d is invalid\n", o.Ndim, len(x))
+	}
+	for k := 0; k < o.Ndim; k++ {
+		ξ := toRefBox(x[k], o.Xmin[k], o.Xmax[k])
+		o.w[k][1][0], o.dw[k][1][0] = 1, 0
+		for l := 2; l < len(o.lag); l++ {
+			if withDeriv {
+				lagCardinals(o.w[k][l], o.dw[k][l], o.lag[l], ξ)
+			} else {
+				lagCardinals(o.w[k][l], nil, o.lag[l], ξ)
+			}
+		}
+	}
+}
+
+// termValues collects the values of U at the nodes of a tensor-product term
+func (o *Smolyak) termValues(t *smolyakTerm) []float64 {
+	u := o.ut[:len(t.idx)]
+	for j, p := range t.idx {
+		u[j] = o.U[p]
+	}
+	return u
+}
+
+// smolyakM returns the number of nodes of the 1D Clenshaw-Curtis rule with level l ≥ 1
+func smolyakM(l int) int {
+	if l == 1 {
+		return 1
+	}
+	return 1<<uint(l-1) + 1
+}
+
+// tensorContract computes Σ u[i₀,…] w₀[i₀] ⋯ wd-1[id-1] by sum-factorisation. If kd ≥ 0, the
+// weights of axis kd are replaced by wd.
+//
+//	NOTE: buf must have length ≥ len(u)
+func tensorContract(buf, u []float64, n []int, w [][]float64, kd int, wd []float64) float64 {
+	size := len(u)
+	copy(buf, u)
+	for k, nk := range n {
+		wk := w[k]
+		if k == kd {
+			wk = wd
+		}
+		m := size / nk
+		for r := 0; r < m; r++ {
+			var sum float64
+			for i := 0; i < nk; i++ {
+				sum += wk[i] * buf[i+nk*r]
+			}
+			buf[r] = sum
+		}
+		size = m
+	}
+	return buf[0]
+}
+
+// lagCardinals computes the cardinal functions ℓᵢ(ξ) of a LagrangeInterp (barycentric form) and,
+// if dw != nil, their derivatives
+//
+//	              tᵢ                  λᵢ              dℓᵢ     -tᵢ/(ξ - xᵢ) - ℓᵢ S'
+//	   ℓᵢ(ξ) =  —————    with   tᵢ = ————————   and  ———— =  ——————————————————————
+//	              S                  ξ - xᵢ           dξ               S
+//
+//	where S = Σ tᵢ and S' = -Σ tᵢ/(ξ - xᵢ). At the nodes, the rows of D1 are used.
+func lagCardinals(w, dw []float64, l *LagrangeInterp, ξ float64) {
+	for i, xi := range l.X {
+		if math.Abs(ξ-xi) < 1e-15 {
+			for j := range w {
+				w[j] = 0
+			}
+			w[i] = 1
+			if dw != nil {
+				if l.D1 == nil {
+					l.CalcD1()
+				}
+				for j := range dw {
+					dw[j] = l.D1.Get(i, j)
+				}
+			}
+			return
+		}
+	}
+	var S, dS float64
+	for i, xi := range l.X {
+		w[i] = l.Lam[i] / (ξ - xi)
+		S += w[i]
+		dS -= w[i] / (ξ - xi)
+	}
+	for i, xi := range l.X {
+		if dw != nil {
+			dw[i] = (-w[i]/(ξ-xi) - w[i]/S*dS) / S
+		}
+		w[i] /= S
+	}
+}
+
+// boxBounds returns the bounds of a box; [-1, 1]ᵈ if xmin and xmax are nil
+func boxBounds(ndim int, xmin, xmax []float64) (lo, hi []float64) {
+	lo, hi = make([]float64, ndim), make([]float64, ndim)
+	for k := 0; k < ndim; k++ {
+		lo[k], hi[k] = -1, 1
+	}
+	if xmin != nil || xmax != nil {
+		if len(xmin) != ndim || len(xmax) != ndim {
+			chk.Panic("lengths of xmin (%d) and xmax (%d) must be equal to ndim = %d\n", len(xmin), len(xmax), ndim)
+		}
+		copy(lo, xmin)
+		copy(hi, xmax)
+	}
+	for k := 0; k < ndim; k++ {
+		if hi[k] <= lo[k] {
+			chk.Panic("xmax must be greater than xmin. [%g, %g] is invalid\n", lo[k], hi[k])
+		}
+	}
+	return
+}
+
+// toRefBox maps x ∈ [xmin, xmax] onto ξ ∈ [-1, 1]
+func toRefBox(x, xmin, xmax float64) float64 {
+	return (2.0*x - xmin - xmax) / (xmax - xmin)
+}
+
+// toPhysBox maps ξ ∈ [-1, 1] onto x ∈ [xmin, xmax]
+func toPhysBox(ξ, xmin, xmax float64) float64 {
+	return 0.5 * ((xmax-xmin)*ξ + xmax + xmin)
+}
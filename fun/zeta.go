@@ -0,0 +1,105 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import "math"
+
+// Zeta returns the Riemann zeta function ζ(s)
+//
+//	        ∞    1
+//	ζ(s) =  Σ   ——
+//	       k=1   s
+//	            k
+//
+//	Negative arguments are computed with the reflection formula:
+//
+//	          s  s-1     πs
+//	ζ(s) = 2 ⋅ π    sin(——) Γ(1-s) ζ(1-s)
+//	                     2
+//
+//	Special cases:
+//	  ζ(1) = +Inf
+//	  ζ(0) = -½
+func Zeta(s float64) float64 {
+	if math.IsNaN(s) {
+		return math.NaN()
+	}
+	if s == 1 {
+		return math.Inf(+1)
+	}
+	if s < 0 {
+		if s/2 == math.Floor(s/2) {
+			return 0 // trivial zeros
+		}
+		g, sg := math.Lgamma(1.0 - s)
+		return float64(sg) * math.Exp(s*math.Ln2+(s-1.0)*math.Log(math.Pi)+g) * math.Sin(0.5*math.Pi*s) * Zeta(1.0-s)
+	}
+	if s > 60 {
+		return 1.0 + math.Pow(2, -s)
+	}
+	return HurwitzZeta(s, 1)
+}
+
+// HurwitzZeta returns the Hurwitz zeta function ζ(s,q) with s ≠ 1 and q > 0
+//
+//	          ∞      1
+//	ζ(s,q) =  Σ   ————————
+//	         k=0         s
+//	              (k + q)
+//
+//	The Euler-Maclaurin summation formula is employed; thus, the real part of s does not need
+//	to be greater than 1. Nonetheless, this function is more accurate for s > 1.
+//
+//	Reference:
+//	[1] Abramowitz M, Stegun IA (1972) Handbook of Mathematical Functions with Formulas, Graphs,
+//	     and Mathematical Tables. U.S. Department of Commerce, NIST
+func HurwitzZeta(s, q float64) float64 {
+	if math.IsNaN(s) || math.IsNaN(q) || q <= 0 {
+		return math.NaN()
+	}
+	if s == 1 {
+		return math.Inf(+1)
+	}
+
+	// direct summation
+	const nsum = 10
+	sum := 0.0
+	for k := 0; k < nsum; k++ {
+		sum += math.Pow(q+float64(k), -s)
+	}
+
+	// integral and boundary terms
+	a := q + nsum
+	sum += math.Pow(a, 1.0-s)/(s-1.0) + 0.5*math.Pow(a, -s)
+
+	// Bernoulli corrections: B₂ⱼ/(2j)! ⋅ s(s+1)⋯(s+2j-2) ⋅ a^(-s-2j+1)
+	fac := s * math.Pow(a, -s-1.0) // s a^(-s-1)
+	for j := 0; j < len(zetaB2j); j++ {
+		del := zetaB2j[j] * fac
+		sum += del
+		if math.Abs(del) < 1e-17*math.Abs(sum) {
+			break
+		}
+		m := float64(2*j + 1)
+		fac *= (s + m) * (s + m + 1.0) / (a * a)
+	}
+	return sum
+}
+
+// zetaB2j holds B₂ⱼ/(2j)! for j = 1, 2, ...
+var zetaB2j = []float64{
+	1.0 / 6.0 / 2.0,
+	-1.0 / 30.0 / 24.0,
+	1.0 / 42.0 / 720.0,
+	-1.0 / 30.0 / 40320.0,
+	5.0 / 66.0 / 3628800.0,
+	-691.0 / 2730.0 / 479001600.0,
+	7.0 / 6.0 / 87178291200.0,
+	-3617.0 / 510.0 / 20922789888000.0,
+	43867.0 / 798.0 / 6402373705728000.0,
+	-174611.0 / 330.0 / 2432902008176640000.0,
+	854513.0 / 138.0 / 1.1240007277776077e21,
+	-236364091.0 / 2730.0 / 6.204484017332394e23,
+}
@@ -121,6 +121,14 @@ func (o *Triplet) Size() (m, n int) {
 	return o.m, o.n
 }
 
+// Entry returns the indices and value of the k-th item inserted in the triplet (0 ≤ k < Len())
+func (o *Triplet) Entry(k int) (i, j int, x float64) {
+	if k < 0 || k >= o.pos {
+		chk.Panic("cannot get item because index is outside range (k=%d, len=%d)", k, o.pos)
+	}
+	return o.i[k], o.j[k], o.x[k]
+}
+
 // ToDense returns the dense matrix corresponding to this Triplet
 func (o *Triplet) ToDense() (a *Matrix) {
 	a = NewMatrix(o.m, o.n)
@@ -392,4 +392,9 @@ func TestTripletInfo01(tst *testing.T) {
 	m, n := A.Size()
 	chk.Int(tst, "m", m, 5)
 	chk.Int(tst, "n", n, 5)
+
+	i, j, x := A.Entry(5)
+	chk.Int(tst, "i", i, 4)
+	chk.Int(tst, "j", j, 1)
+	chk.Float64(tst, "x", 1e-17, x, 4)
 }
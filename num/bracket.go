@@ -178,3 +178,85 @@ func (o *Bracket) Min(a0, b0 float64) (a, b, c, fa, fb, fc float64) {
 	chk.Panic("fail to converge after %d iterations", o.NumIter)
 	return
 }
+
+// Root expands the interval [a0, b0] until it brackets a root, i.e. f(a) ⋅ f(b) ≤ 0
+//
+//	The interval is enlarged geometrically (by the golden ratio) towards the end with the
+//	smallest |f| as in the zbrac routine of [1]. An error is returned if no bracket is found
+//	within MaxIt iterations.
+func (o *Bracket) Root(a0, b0 float64) (a, b, fa, fb float64, err error) {
+
+	// check
+	if a0 == b0 {
+		return 0, 0, 0, 0, chk.Err("a0=%g must be different than b0=%g\n", a0, b0)
+	}
+
+	// initialization
+	a, b = a0, b0
+	if b < a {
+		a, b = b, a
+	}
+	fa = o.ffcn(a)
+	fb = o.ffcn(b)
+	o.NumFeval = 2
+
+	// expand
+	for o.NumIter = 0; o.NumIter < o.MaxIt; o.NumIter++ {
+		if fa*fb <= 0 {
+			return
+		}
+		if math.Abs(fa) < math.Abs(fb) {
+			a += o.gold * (a - b)
+			fa = o.ffcn(a)
+		} else {
+			b += o.gold * (b - a)
+			fb = o.ffcn(b)
+		}
+		o.NumFeval++
+		if o.Verbose {
+			io.Pf("%4d: a=%g b=%g fa=%g fb=%g\n", o.NumIter, a, b, fa, fb)
+		}
+		if math.IsInf(a, 0) || math.IsInf(b, 0) || math.IsNaN(fa) || math.IsNaN(fb) {
+			break
+		}
+	}
+	err = chk.Err("cannot bracket root after %d iterations: a=%g b=%g fa=%g fb=%g\n", o.NumIter, a, b, fa, fb)
+	return
+}
+
+// RootScan finds all subintervals of [xmin, xmax] where f changes sign
+//
+//	The interval is divided into n subintervals of equal length as in the zbrak routine of [1].
+//	The i-th bracket is [xa[i], xb[i]]; roots that coincide with grid points are bracketed by the
+//	subinterval ending (or, at xmin, starting) at that point.
+//
+//	NOTE: roots without sign change (e.g. double roots) and pairs of roots within the same
+//	      subinterval are not detected
+func (o *Bracket) RootScan(xmin, xmax float64, n int) (xa, xb []float64, err error) {
+
+	// check
+	if n < 1 || !(xmax > xmin) {
+		return nil, nil, chk.Err("RootScan requires n ≥ 1 and xmax > xmin. n=%d, xmin=%g, xmax=%g\n", n, xmin, xmax)
+	}
+
+	// scan
+	dx := (xmax - xmin) / float64(n)
+	a := xmin
+	fa := o.ffcn(a)
+	o.NumFeval = 1
+	for i := 0; i < n; i++ {
+		b := xmin + float64(i+1)*dx
+		if i == n-1 {
+			b = xmax
+		}
+		fb := o.ffcn(b)
+		o.NumFeval++
+		if fa*fb < 0 || fb == 0 || (i == 0 && fa == 0) {
+			xa = append(xa, a)
+			xb = append(xb, b)
+		}
+		a, fa = b, fb
+	}
+	o.NumIter = n
+	return
+}
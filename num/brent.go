@@ -23,11 +23,13 @@ type Brent struct {
 	// statistics
 	NumFeval int // number of calls to Ffcn (function evaluations)
 	NumJeval int // number of calls to Jfcn (Jacobian/derivatives)
+	NumHeval int // number of calls to Hfcn (second derivatives)
 	NumIter  int // number of iterations from last call to Solve
 
 	// internal
 	ffcn  fun.Ss  // y = f(x) function
 	Jfcn  fun.Ss  // Jfcn(x) = dy/dx [optional / may be nil]
+	Hfcn  fun.Ss  // Hfcn(x) = d²y/dx² [optional / may be nil; required by Halley's method]
 	gsr   float64 // gold section ratio
 	sqeps float64 // sqrt(EPS)
 }
@@ -0,0 +1,545 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/utl"
+)
+
+// Continuation traces the solution branch of F(x, λ) = 0 as the parameter λ varies
+//
+//	The augmented vector u = [x; λ] with n+1 components is employed; thus, the function is
+//	F(f, u) with len(f) = n and the Jacobian is the n×(n+1) matrix J = [∂F/∂x  ∂F/∂λ].
+//
+//	Each step consists of a predictor along the tangent t and Newton corrections of the bordered
+//	system [3]
+//
+//	  ⎡ J(u) ⎤       ⎡  F(u)          ⎤
+//	  ⎢      ⎥ δu = -⎢                ⎥
+//	  ⎣  cᵀ  ⎦       ⎣ cᵀ⋅(u - uPred) ⎦
+//
+//	where c = e_λ (natural parameterisation; λ is fixed during the corrections) or c = t
+//	(pseudo-arclength parameterisation [1]; the correction is orthogonal to the tangent). The
+//	pseudo-arclength method passes limit (fold) points where ∂F/∂x is singular and the Newton
+//	iterations with fixed λ diverge. The new tangent solves [J; cᵀ]⋅t = e_λ; hence, its
+//	orientation is kept along the branch.
+//
+//	The step size is adapted with the number of corrector iterations. Special points are detected
+//	by sign changes of test functions [2] between consecutive points:
+//
+//	  fold   : dλ/ds (λ component of the unit tangent)
+//	  branch : det([J; tᵀ])  (det(∂F/∂x) with the natural parameterisation)
+//
+//	and are located by the Illinois (modified regula falsi) method on the step size, where each
+//	trial point is corrected onto the branch. A point where [J; tᵀ] is exactly singular (e.g. the
+//	trial point of a linear test function) is recorded as a branch point.
+//
+//	NOTE: the determinant is computed by the dense LU factorisation; thus, DetectBranch is false
+//	      by default and a dense (n+1)×(n+1) matrix is factorised at each point of systems with a
+//	      sparse Jacobian if it is enabled.
+//
+//	References:
+//	[1] Keller HB (1977) Numerical solution of bifurcation and nonlinear eigenvalue problems, in
+//	    Rabinowitz PH (ed) Applications of Bifurcation Theory, Academic Press, 359-384
+//	[2] Seydel R (2010) Practical Bifurcation and Stability Analysis, 3rd Edition, Springer
+//	[3] Allgower EL, Georg K (2003) Introduction to Numerical Continuation Methods, SIAM
+type Continuation struct {
+
+	// configuration
+	Method       string  // "arclength" (pseudo-arclength) or "natural" [default = "arclength"]
+	Ds           float64 // initial step size; negative means decreasing λ at the start [default = 0.1]
+	DsMin        float64 // minimum step size [default = 1e-8]
+	DsMax        float64 // maximum step size [default = 1]
+	MaxSteps     int     // maximum number of continuation steps [default = 100]
+	LambdaMin    float64 // stop when λ < LambdaMin [default = -∞]
+	LambdaMax    float64 // stop when λ > LambdaMax [default = +∞]
+	MaxIt        int     // maximum number of corrector (Newton) iterations [default = 10]
+	Tol          float64 // tolerance on max|F| [default = 1e-10]
+	NiterOpt     int     // desired number of corrector iterations (step size control) [default = 4]
+	DetectBranch bool    // detect branch points using det([J; tᵀ]) [default = false]
+	Verbose      bool    // show messages
+
+	// function to be called after each converged point
+	OutCallback func(p *ContPoint)
+
+	// results
+	Branch  []*ContPoint // converged points along the branch
+	Special []*ContPoint // folds and branch points (approximate location)
+	Nfeval  int          // number of calls to F
+	Njeval  int          // number of Jacobian evaluations
+
+	// functions
+	neq             int    // number of equations n
+	functionF       fun.Vv // F(f, u) with u = [x; λ]
+	functionJsparse fun.Tv // J(u) = dF/du [n×(n+1)] in triplet format
+	functionJdense  fun.Mv // J(u) = dF/du [n×(n+1)] dense matrix
+
+	// linear solvers
+	useDense bool            // use dense matrices
+	tripletA la.Triplet      // augmented Jacobian [J; cᵀ] (sparse)
+	matrixJ  *la.Matrix      // J (dense)
+	matrixA  *la.Matrix      // augmented Jacobian [J; cᵀ] (dense); overwritten by its LU factors
+	piv      []int           // row permutation of the LU factorisation
+	linsol   la.SparseSolver // sparse solver
+	lsReady  bool            // sparse solver is initialised
+
+	// workspace
+	f    la.Vector // F(u)
+	w    la.Vector // workspace for numerical Jacobian
+	rhs  la.Vector // right-hand side of augmented system
+	du   la.Vector // correction
+	tau  la.Vector // unnormalised tangent
+	upre la.Vector // predicted u
+	eλ   la.Vector // e_λ = [0; 1]
+}
+
+// ContPoint holds a point along the branch computed by Continuation
+type ContPoint struct {
+	X      la.Vector // state x
+	Lambda float64   // parameter λ
+	T      la.Vector // unit tangent [dx/ds; dλ/ds] (or [dx/dλ; 1] with the natural parameterisation)
+	Det    float64   // det([J; tᵀ]) (or det(∂F/∂x) with the natural parameterisation)
+	Kind   string    // "" (point of Branch), "fold" or "branch" (point of Special)
+	Niter  int       // number of corrector iterations
+}
+
+// NewContinuation creates a new Continuation object
+//
+//	neq -- number of equations n
+//	F   -- F(f, u) function with u = [x; λ] (len(u) = n+1) and len(f) = n
+//
+//	NOTE: the numerical Jacobian (with the sparse solver) is used by default
+func NewContinuation(neq int, F fun.Vv) (o *Continuation) {
+	if neq < 1 {
+		chk.Panic("the number of equations must be ≥ 1. neq=%d is invalid\n", neq)
+	}
+	o = new(Continuation)
+	o.Method = "arclength"
+	o.Ds = 0.1
+	o.DsMin = 1e-8
+	o.DsMax = 1
+	o.MaxSteps = 100
+	o.LambdaMin = math.Inf(-1)
+	o.LambdaMax = math.Inf(+1)
+	o.MaxIt = 10
+	o.Tol = 1e-10
+	o.NiterOpt = 4
+	o.neq = neq
+	o.functionF = F
+	n1 := neq + 1
+	o.tripletA.Init(n1, n1, n1*n1)
+	o.linsol = la.NewSparseSolver("umfpack")
+	o.f = la.NewVector(neq)
+	o.w = la.NewVector(neq)
+	o.rhs = la.NewVector(n1)
+	o.du = la.NewVector(n1)
+	o.tau = la.NewVector(n1)
+	o.upre = la.NewVector(n1)
+	o.eλ = la.NewVector(n1)
+	o.eλ[neq] = 1
+	o.piv = make([]int, n1)
+	return
+}
+
+// SetJacobianFunction sets the function to compute the n×(n+1) Jacobian J = dF/du (sparse or dense)
+//
+//	If both are given, the sparse one is used. Jsparse must call Start() before putting the
+//	entries of the first n rows.
+func (o *Continuation) SetJacobianFunction(Jsparse fun.Tv, Jdense fun.Mv) {
+	if Jsparse == nil && Jdense == nil {
+		chk.Panic("one of sparse or dense versions must be given")
+	}
+	o.functionJsparse = Jsparse
+	o.functionJdense = nil
+	o.useDense = false
+	if Jsparse == nil {
+		o.functionJdense = Jdense
+		o.useDense = true
+		n1 := o.neq + 1
+		o.matrixJ = la.NewMatrix(o.neq, n1)
+	}
+}
+
+// Free frees memory
+func (o *Continuation) Free() {
+	if o.lsReady {
+		o.linsol.Free()
+	}
+}
+
+// Run computes the branch starting at x0 "near" the solution at λ0
+//
+//	The initial point is first corrected with λ = λ0 fixed. The computation stops when MaxSteps
+//	is reached or λ leaves [LambdaMin, LambdaMax] (err == nil), or when the step size becomes
+//	smaller than DsMin (err != nil). In all cases, Branch and Special hold the results.
+func (o *Continuation) Run(x0 la.Vector, λ0 float64) (err error) {
+
+	// check
+	n := o.neq
+	if len(x0) != n {
+		chk.Panic("len(x0) must be equal to the number of equations. %d != %d\n", len(x0), n)
+	}
+	natural := o.Method == "natural"
+	if !natural && o.Method != "arclength" && o.Method != "" {
+		chk.Panic("method %q is not available. Options are: \"arclength\" or \"natural\"\n", o.Method)
+	}
+	if o.Ds == 0 {
+		chk.Panic("the step size Ds must not be zero\n")
+	}
+	if o.useDense && o.matrixA == nil {
+		o.matrixA = la.NewMatrix(n+1, n+1)
+	}
+	o.Branch, o.Special = nil, nil
+	o.Nfeval, o.Njeval = 0, 0
+
+	// correct initial point with fixed λ
+	u := la.NewVector(n + 1)
+	copy(u, x0)
+	u[n] = λ0
+	copy(o.upre, u)
+	niter, ok := o.corrector(u, o.eλ)
+	if !ok {
+		return chk.Err("Continuation: cannot compute the initial point at λ=%g\n", λ0)
+	}
+
+	// initial tangent: [J; e_λᵀ]⋅τ = e_λ
+	det, ok := o.tangent(u, o.eλ)
+	if !ok {
+		return chk.Err("Continuation: cannot compute the tangent at the initial point: [J; e_λᵀ] is singular at λ=%g\n", λ0)
+	}
+	t := o.tau.GetCopy()
+	ds := o.Ds
+	if !natural {
+		nrm := o.tau.Norm()
+		sgn := 1.0
+		if o.Ds < 0 {
+			sgn = -1
+		}
+		t.Apply(sgn/nrm, o.tau)
+		det *= sgn * nrm
+		ds = math.Abs(o.Ds)
+	}
+	o.addPoint(&ContPoint{X: u[:n], Lambda: u[n], T: t, Det: det, Niter: niter})
+
+	// steps
+	dsmax := o.DsMax
+	if dsmax <= 0 {
+		dsmax = math.Inf(1)
+	}
+	for step := 0; step < o.MaxSteps; step++ {
+
+		// predictor and corrector
+		a := o.Branch[len(o.Branch)-1]
+		var b *ContPoint
+		for {
+			b, ok = o.pointAt(a, ds, natural)
+			if ok {
+				break
+			}
+			ds *= 0.5
+			if o.Verbose {
+				io.Pfyel("step %d: corrector failed; ds reduced to %g\n", step, ds)
+			}
+			if math.Abs(ds) < o.DsMin {
+				return chk.Err("Continuation: step size became smaller than DsMin=%g at λ=%g (limit point?)\n", o.DsMin, a.Lambda)
+			}
+		}
+
+		// accept point
+		o.addPoint(b)
+		o.detect(a, b, ds, natural)
+
+		// stop?
+		if b.Lambda < o.LambdaMin || b.Lambda > o.LambdaMax {
+			break
+		}
+
+		// step size
+		fac := float64(o.NiterOpt) / float64(utl.Imax(b.Niter, 1))
+		fac = math.Min(math.Max(fac, 0.5), 2)
+		ds *= fac
+		if math.Abs(ds) > dsmax {
+			ds = math.Copysign(dsmax, ds)
+		}
+	}
+	return
+}
+
+// pointAt computes the point at a distance s from a along the branch
+//
+//	predictor: uPred = uₐ + s⋅tₐ
+//	corrector: F(u) = 0 and cᵀ⋅(u - uPred) = 0 with c = tₐ (arclength) or c = e_λ (natural)
+func (o *Continuation) pointAt(a *ContPoint, s float64, natural bool) (p *ContPoint, ok bool) {
+	n := o.neq
+	c := a.T
+	if natural {
+		c = o.eλ
+	}
+	u := la.NewVector(n + 1)
+	for i := 0; i < n; i++ {
+		o.upre[i] = a.X[i] + s*a.T[i]
+	}
+	o.upre[n] = a.Lambda + s*a.T[n]
+	copy(u, o.upre)
+	niter, ok := o.corrector(u, c)
+	if !ok {
+		return
+	}
+	det, ok := o.tangent(u, c)
+	if !ok { // u is a branch point: the tangent is not unique; thus, the previous one is kept
+		return &ContPoint{X: u[:n], Lambda: u[n], T: a.T.GetCopy(), Det: 0, Niter: niter}, true
+	}
+	t := o.tau.GetCopy()
+	if !natural {
+		nrm := o.tau.Norm()
+		t.Apply(1/nrm, o.tau)
+		det *= nrm // det([J; tᵀ]) = det([J; cᵀ])⋅‖τ‖ because τ is parallel to the cofactors of cᵀ
+	}
+	return &ContPoint{X: u[:n], Lambda: u[n], T: t, Det: det, Niter: niter}, true
+}
+
+// corrector performs Newton iterations on [F(u); cᵀ⋅(u - upre)] = 0
+func (o *Continuation) corrector(u, c la.Vector) (niter int, ok bool) {
+	n := o.neq
+	var fmax0 float64
+	for niter = 0; ; niter++ {
+
+		// residual
+		o.functionF(o.f, u)
+		o.Nfeval++
+		fmax := o.f.Largest(1)
+		if math.IsNaN(fmax) || math.IsInf(fmax, 0) {
+			return niter, false
+		}
+		if niter == 0 {
+			fmax0 = fmax
+		} else if fmax > 1e3*math.Max(fmax0, o.Tol) {
+			return niter, false // diverging
+		}
+		if fmax <= o.Tol {
+			return niter, true
+		}
+		if niter == o.MaxIt {
+			return niter, false
+		}
+
+		// solve augmented system
+		for i := 0; i < n; i++ {
+			o.rhs[i] = -o.f[i]
+		}
+		o.rhs[n] = 0
+		for i := 0; i <= n; i++ {
+			o.rhs[n] -= c[i] * (u[i] - o.upre[i])
+		}
+		o.assemble(u, c)
+		if o.useDense {
+			if luFactor(o.matrixA, o.piv) == 0 {
+				return niter, false // singular augmented Jacobian
+			}
+			luSolve(o.du, o.matrixA, o.piv, o.rhs)
+		} else {
+			if !o.lsReady {
+				o.linsol.Init(&o.tripletA, la.NewSparseConfig())
+				o.lsReady = true
+			}
+			o.linsol.Fact()
+			o.linsol.Solve(o.du, o.rhs)
+		}
+		for i := 0; i <= n; i++ {
+			u[i] += o.du[i]
+		}
+	}
+}
+
+// tangent solves [J(u); cᵀ]⋅tau = e_λ and returns det([J(u); cᵀ]) (if dense or DetectBranch;
+// otherwise 1). ok is false if [J(u); cᵀ] is singular (dense or DetectBranch only)
+func (o *Continuation) tangent(u, c la.Vector) (det float64, ok bool) {
+	n := o.neq
+	o.functionF(o.f, u) // f is required by the numerical Jacobian
+	o.Nfeval++
+	o.assemble(u, c)
+	if o.useDense || o.DetectBranch {
+		A := o.matrixA
+		if !o.useDense {
+			A = o.tripletA.ToDense()
+		}
+		det = luFactor(A, o.piv)
+		if det == 0 {
+			return 0, false
+		}
+		luSolve(o.tau, A, o.piv, o.eλ)
+		return det, true
+	}
+	if !o.lsReady {
+		o.linsol.Init(&o.tripletA, la.NewSparseConfig())
+		o.lsReady = true
+	}
+	o.rhs.Fill(0)
+	o.rhs[n] = 1
+	o.linsol.Fact()
+	o.linsol.Solve(o.tau, o.rhs)
+	return 1, true
+}
+
+// assemble computes the augmented Jacobian [J(u); cᵀ]; f = F(u) must be up to date
+func (o *Continuation) assemble(u, c la.Vector) {
+	n := o.neq
+	o.Njeval++
+	if o.useDense {
+		o.functionJdense(o.matrixJ, u)
+		for i := 0; i < n; i++ {
+			for j := 0; j <= n; j++ {
+				o.matrixA.Set(i, j, o.matrixJ.Get(i, j))
+			}
+		}
+		for j := 0; j <= n; j++ {
+			o.matrixA.Set(n, j, c[j])
+		}
+		return
+	}
+	if o.functionJsparse != nil {
+		o.functionJsparse(&o.tripletA, u) // calls Start() and puts the first n rows
+	} else {
+		Jacobian(&o.tripletA, o.functionF, u, o.f, o.w)
+		o.Nfeval += n + 1
+	}
+	for j := 0; j <= n; j++ {
+		o.tripletA.Put(n, j, c[j])
+	}
+}
+
+// addPoint appends a new point to the branch
+func (o *Continuation) addPoint(p *ContPoint) {
+	o.Branch = append(o.Branch, p)
+	if o.Verbose {
+		io.Pf("%4d  λ = %23.15e  dλ/ds = %12.4e  det = %12.4e  niter = %d\n", len(o.Branch)-1, p.Lambda, p.T[o.neq], p.Det, p.Niter)
+	}
+	if o.OutCallback != nil {
+		o.OutCallback(p)
+	}
+}
+
+// detect checks for special points between a and b = pointAt(a, s)
+func (o *Continuation) detect(a, b *ContPoint, s float64, natural bool) {
+	n := o.neq
+	if !natural && a.T[n]*b.T[n] < 0 {
+		o.locate(a, b, s, natural, "fold", func(p *ContPoint) float64 { return p.T[n] })
+	}
+	if o.DetectBranch {
+		if b.Det == 0 && a.Det != 0 { // b is exactly at the branch point
+			sp := *b
+			sp.Kind = "branch"
+			o.Special = append(o.Special, &sp)
+		} else if a.Det*b.Det < 0 {
+			o.locate(a, b, s, natural, "branch", func(p *ContPoint) float64 { return p.Det })
+		}
+	}
+}
+
+// locate finds the zero of the test function ψ between a and b = pointAt(a, s) by the Illinois
+// (modified regula falsi) method and appends the special point
+func (o *Continuation) locate(a, b *ContPoint, s float64, natural bool, kind string, ψ func(p *ContPoint) float64) {
+	var sp *ContPoint
+	slo, shi := 0.0, s
+	ψlo, ψhi := ψ(a), ψ(b)
+	ψtol := 1e-15 * math.Max(math.Abs(ψlo), math.Abs(ψhi)) // rounding level of ψ
+	for it := 0; it < 30; it++ {
+		si := (slo*ψhi - shi*ψlo) / (ψhi - ψlo)
+		p, ok := o.pointAt(a, si, natural)
+		if !ok {
+			break
+		}
+		sp = p
+		ψi := ψ(p)
+		if math.Abs(ψi) <= ψtol || math.Abs(shi-slo) < 1e-12*math.Abs(s) {
+			break
+		}
+		if ψi*ψhi < 0 {
+			slo, ψlo = shi, ψhi
+		} else {
+			ψlo /= 2
+		}
+		shi, ψhi = si, ψi
+		if math.Abs(si-slo) < 1e-12*math.Abs(s) {
+			break
+		}
+	}
+	if sp == nil { // linear interpolation
+		θ := ψ(a) / (ψ(a) - ψ(b))
+		sp = &ContPoint{X: la.NewVector(o.neq), Lambda: a.Lambda + θ*(b.Lambda-a.Lambda)}
+		for i := 0; i < o.neq; i++ {
+			sp.X[i] = a.X[i] + θ*(b.X[i]-a.X[i])
+		}
+	}
+	sp.Kind = kind
+	o.Special = append(o.Special, sp)
+	if o.Verbose {
+		io.Pfgreen("%s point detected at λ = %g\n", kind, sp.Lambda)
+	}
+}
+
+// luFactor computes the LU factorisation with partial pivoting of the square matrix A in place and
+// returns det(A). Columns with a pivot at rounding level are skipped and det = 0 is returned;
+// i.e. singular matrices do not cause a panic
+func luFactor(A *la.Matrix, piv []int) (det float64) {
+	n := A.M
+	amax := A.Largest(1)
+	det = 1
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(A.Get(i, k)) > math.Abs(A.Get(p, k)) {
+				p = i
+			}
+		}
+		piv[k] = p
+		if p != k {
+			for j := 0; j < n; j++ {
+				aij := A.Get(k, j)
+				A.Set(k, j, A.Get(p, j))
+				A.Set(p, j, aij)
+			}
+			det = -det
+		}
+		akk := A.Get(k, k)
+		if math.Abs(akk) <= MACHEPS*amax {
+			det = 0
+			continue
+		}
+		det *= akk
+		for i := k + 1; i < n; i++ {
+			lik := A.Get(i, k) / akk
+			A.Set(i, k, lik)
+			for j := k + 1; j < n; j++ {
+				A.Set(i, j, A.Get(i, j)-lik*A.Get(k, j))
+			}
+		}
+	}
+	return
+}
+
+// luSolve solves A⋅x = b with the LU factors computed by luFactor (A must not be singular)
+func luSolve(x la.Vector, A *la.Matrix, piv []int, b la.Vector) {
+	n := A.M
+	copy(x, b)
+	for k := 0; k < n; k++ {
+		x[k], x[piv[k]] = x[piv[k]], x[k]
+		for i := k + 1; i < n; i++ {
+			x[i] -= A.Get(i, k) * x[k]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			x[i] -= A.Get(i, j) * x[j]
+		}
+		x[i] /= A.Get(i, i)
+	}
+}
@@ -6,6 +6,7 @@ package num
 
 import (
 	"math"
+	"math/cmplx"
 
 	"github.com/lei006/gomath/fun"
 )
@@ -73,3 +74,47 @@ func EqCubicSolveReal(a, b, c float64) (x1, x2, x3 float64, nx int) {
 	nx = 1
 	return
 }
+
+// EqCubicSolveComplex solves a cubic equation, returning all (complex) roots
+//
+//	The equation is specified by:
+//	 x³ + a x² + b x + c = 0
+//	Notes:
+//	 1) Cardano's formula is applied to the depressed cubic t³ + p t + q = 0 with x = t - a/3;
+//	    the cube root is taken of the term with the largest modulus to avoid cancellation
+//	 2) the roots are polished by Newton's method in complex arithmetic
+//	 3) real roots have zero imaginary parts; complex roots appear in conjugate pairs
+//	Output:
+//	 z[i] -- roots sorted by real part and then by imaginary part
+func EqCubicSolveComplex(a, b, c float64) (z1, z2, z3 complex128) {
+
+	// depressed cubic
+	p := b - a*a/3.0
+	q := 2.0*a*a*a/27.0 - a*b/3.0 + c
+
+	// Cardano
+	D := cmplx.Sqrt(complex(q*q/4.0+p*p*p/27.0, 0))
+	w := complex(-q/2.0, 0) + D
+	if alt := complex(-q/2.0, 0) - D; cmplx.Abs(alt) > cmplx.Abs(w) {
+		w = alt
+	}
+	var t [3]complex128
+	if w != 0 {
+		u := cmplx.Pow(w, 1.0/3.0)
+		ω := complex(-0.5, math.Sqrt(3.0)/2.0)
+		for k := 0; k < 3; k++ {
+			t[k] = u - complex(p, 0)/(3.0*u)
+			u *= ω
+		}
+	}
+
+	// polish and clean up
+	coef := []complex128{complex(c, 0), complex(b, 0), complex(a, 0), 1}
+	var z [3]complex128
+	for k := 0; k < 3; k++ {
+		z[k] = polyPolish(coef, t[k]-complex(a/3.0, 0))
+	}
+	polyConjugates(coef, z[:])
+	polySort(z[:])
+	return z[0], z[1], z[2]
+}
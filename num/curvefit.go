@@ -0,0 +1,579 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+)
+
+// CurveFit fits the parameters p of a nonlinear model to data (nonlinear least squares) by
+// minimising
+//
+//	       m-1
+//	χ²  =   Σ  wᵢ (yᵢ - fᵢ(p))²      with  wᵢ = 1/σᵢ²
+//	       i=0
+//
+//	The Levenberg-Marquardt method [1,2] (default) or the Gauss-Newton method with line search is
+//	employed. The damped normal equations
+//
+//	  (JᵀWJ + μ D) δp = JᵀW (y - f)       with  D = diag(JᵀWJ)
+//
+//	are solved at each iteration, where J = df/dp is computed by the given function or by
+//	finite differences (see Jacobian). Bounds are enforced by projecting the trial points onto the
+//	feasible box; parameters at an active bound (and fixed parameters) are excluded from the step.
+//
+//	After convergence, the covariance of the free parameters is estimated by
+//
+//	  Cov = s² (JᵀWJ)⁻¹
+//
+//	where s² = χ²/ν with ν = m - nfree degrees of freedom, unless the absolute standard deviations
+//	of the data were given with SetSigma.
+//
+//	References:
+//	[1] Marquardt DW (1963) An algorithm for least-squares estimation of nonlinear parameters,
+//	    Journal of the Society for Industrial and Applied Mathematics, 11(2):431-441
+//	[2] Madsen K, Nielsen HB, Tingleff O (2004) Methods for non-linear least squares problems,
+//	    2nd Edition, Informatics and Mathematical Modelling, Technical University of Denmark
+type CurveFit struct {
+
+	// configuration
+	Method  string  // "lm" (Levenberg-Marquardt) or "gn" (Gauss-Newton with line search) [default = "lm"]
+	MaxIt   int     // maximum number of iterations [default = 200]
+	Ftol    float64 // tolerance on the relative reduction of χ² [default = 1e-14]
+	Xtol    float64 // tolerance on the relative change of parameters [default = 1e-10]
+	Gtol    float64 // tolerance on the (scaled) gradient [default = 1e-12]
+	Verbose bool    // show messages
+
+	// results
+	P       la.Vector  // best-fit parameters [npar]
+	Sigma   la.Vector  // standard errors of the parameters [npar] (zero for fixed parameters)
+	Cov     *la.Matrix // covariance matrix [npar][npar] (zero rows/columns for fixed parameters)
+	Chi2    float64    // χ² at the best-fit parameters
+	Dof     int        // degrees of freedom ν = ndata - nfree
+	RedChi2 float64    // reduced χ² = χ²/ν
+	Niter   int        // number of iterations
+	Nfeval  int        // number of calls to the model function
+	Njeval  int        // number of calls to the Jacobian function (or numerical Jacobians)
+
+	// input
+	ndata, npar int        // number of data points and parameters
+	y           la.Vector  // observed data
+	w           la.Vector  // weights wᵢ = 1/σᵢ²
+	absSigma    bool       // weights correspond to absolute standard deviations
+	model       fun.Vv     // f(f, p) model values at all data points
+	jacobian    fun.Mv     // J(J, p) = df/dp [optional]
+	lower       la.Vector  // lower bounds [optional]
+	upper       la.Vector  // upper bounds [optional]
+	fixed       []bool     // fixed parameters [optional]
+	tripletJ    la.Triplet // numerical Jacobian
+	workspace   la.Vector  // workspace for numerical Jacobian
+}
+
+// NewCurveFit creates a new CurveFit
+//
+//	y     -- observed data [ndata]
+//	npar  -- number of parameters
+//	model -- computes the model values f(p) at all data points: model(f, p) with len(f) = ndata
+func NewCurveFit(y []float64, npar int, model fun.Vv) (o *CurveFit) {
+	if len(y) < 1 || npar < 1 {
+		chk.Panic("the number of data points and parameters must be ≥ 1. ndata=%d, npar=%d is invalid\n", len(y), npar)
+	}
+	o = new(CurveFit)
+	o.ndata, o.npar = len(y), npar
+	o.y = la.NewVectorSlice(y).GetCopy()
+	o.w = la.NewVector(o.ndata)
+	o.w.Fill(1)
+	o.model = model
+	o.fixed = make([]bool, npar)
+	return
+}
+
+// NewCurveFitXY creates a new CurveFit for a model y = f(x; p) of a scalar variable x
+//
+//	x, y  -- data points [ndata]
+//	npar  -- number of parameters
+//	model -- f(p, x) function
+func NewCurveFitXY(x, y []float64, npar int, model fun.Svs) (o *CurveFit) {
+	if len(x) != len(y) {
+		chk.Panic("x and y must have the same length. %d != %d\n", len(x), len(y))
+	}
+	xx := la.NewVectorSlice(x).GetCopy()
+	return NewCurveFit(y, npar, func(f, p la.Vector) {
+		for i, xi := range xx {
+			f[i] = model(p, xi)
+		}
+	})
+}
+
+// SetJacobian sets the function to compute J = df/dp (dense ndata×npar matrix)
+//
+//	NOTE: if not set, forward differences are employed (see Jacobian); an analytical Jacobian is
+//	      more accurate, in particular for parameters near zero
+func (o *CurveFit) SetJacobian(jacobian fun.Mv) {
+	o.jacobian = jacobian
+}
+
+// SetSigma sets the standard deviations of the data; i.e. the weights wᵢ = 1/σᵢ²
+//
+//	absolute -- σ are absolute; thus, the covariance is not scaled by the reduced χ². Otherwise,
+//	            σ are only relative weights
+func (o *CurveFit) SetSigma(σ []float64, absolute bool) {
+	if len(σ) != o.ndata {
+		chk.Panic("len(σ) must be equal to the number of data points. %d != %d\n", len(σ), o.ndata)
+	}
+	for i, s := range σ {
+		if s <= 0 {
+			chk.Panic("standard deviations must be positive. σ[%d]=%g is invalid\n", i, s)
+		}
+		o.w[i] = 1.0 / (s * s)
+	}
+	o.absSigma = absolute
+}
+
+// SetBounds sets lower and upper bounds on the parameters
+//
+//	NOTE: use math.Inf(-1) or math.Inf(+1) for unbounded parameters; nil means no bounds
+func (o *CurveFit) SetBounds(lower, upper []float64) {
+	if lower != nil {
+		if len(lower) != o.npar {
+			chk.Panic("len(lower) must be equal to the number of parameters. %d != %d\n", len(lower), o.npar)
+		}
+		o.lower = la.NewVectorSlice(lower).GetCopy()
+	}
+	if upper != nil {
+		if len(upper) != o.npar {
+			chk.Panic("len(upper) must be equal to the number of parameters. %d != %d\n", len(upper), o.npar)
+		}
+		o.upper = la.NewVectorSlice(upper).GetCopy()
+	}
+	for k := 0; k < o.npar; k++ {
+		if o.lo(k) > o.hi(k) {
+			chk.Panic("lower bound must not be greater than upper bound. lower[%d]=%g > upper[%d]=%g\n", k, o.lo(k), k, o.hi(k))
+		}
+	}
+}
+
+// SetFixed sets which parameters are kept fixed at their initial values
+func (o *CurveFit) SetFixed(fixed []bool) {
+	if len(fixed) != o.npar {
+		chk.Panic("len(fixed) must be equal to the number of parameters. %d != %d\n", len(fixed), o.npar)
+	}
+	copy(o.fixed, fixed)
+}
+
+// Solve computes the best-fit parameters
+//
+//	p -- [input/output] initial guess (projected onto the bounds); best-fit parameters on output
+//	err -- non-nil if the maximum number of iterations is reached (the results correspond to the
+//	       best parameters found) or if JᵀWJ is singular
+func (o *CurveFit) Solve(p []float64) (err error) {
+
+	// check
+	if len(p) != o.npar {
+		chk.Panic("len(p) must be equal to the number of parameters. %d != %d\n", len(p), o.npar)
+	}
+	method := o.Method
+	if method == "" {
+		method = "lm"
+	}
+	if method != "lm" && method != "gn" {
+		chk.Panic("method %q is not available. Options are: \"lm\" or \"gn\"\n", method)
+	}
+	maxit := o.MaxIt
+	if maxit < 1 {
+		maxit = 200
+	}
+	ftol, xtol, gtol := o.Ftol, o.Xtol, o.Gtol
+	if ftol <= 0 {
+		ftol = 1e-14
+	}
+	if xtol <= 0 {
+		xtol = 1e-10
+	}
+	if gtol <= 0 {
+		gtol = 1e-12
+	}
+
+	// initialise
+	n := o.npar
+	o.Niter, o.Nfeval, o.Njeval = 0, 0, 0
+	for k := 0; k < n; k++ {
+		p[k] = math.Min(math.Max(p[k], o.lo(k)), o.hi(k))
+	}
+	f := la.NewVector(o.ndata)
+	J := la.NewMatrix(o.ndata, n)
+	A := la.NewMatrix(n, n) // JᵀWJ
+	g := la.NewVector(n)    // JᵀW(y-f)
+	M := la.NewMatrix(n, n) // damped matrix
+	δ := la.NewVector(n)
+	ptrial := la.NewVector(n)
+	ftrial := la.NewVector(o.ndata)
+	active := make([]bool, n)
+	chi2 := o.chi2(f, p)
+	μ := 1e-3 // damping relative to D = diag(JᵀWJ)
+	ν := 2.0
+
+	// iterations
+	converged := false
+	for o.Niter = 0; o.Niter < maxit; o.Niter++ {
+
+		// normal equations
+		o.calcJ(J, p, f)
+		o.normalEquations(A, g, J, f)
+
+		// active set: free parameters not held at a bound by the gradient
+		nact := 0
+		gmax := 0.0
+		for k := 0; k < n; k++ {
+			active[k] = !o.fixed[k] && !(p[k] <= o.lo(k) && g[k] < 0) && !(p[k] >= o.hi(k) && g[k] > 0)
+			if active[k] {
+				nact++
+				gmax = math.Max(gmax, math.Abs(g[k])/math.Sqrt(math.Max(A.Get(k, k), MACHEPS)))
+			}
+		}
+		if o.Verbose {
+			io.Pf("%4d  χ² = %23.15e  μ = %10.3e  |g| = %10.3e\n", o.Niter, chi2, μ, gmax)
+		}
+		if nact == 0 || gmax <= gtol*math.Sqrt(math.Max(chi2, MACHEPS)) {
+			converged = true
+			break
+		}
+
+		// step
+		accepted := false
+		var chi2trial float64
+		if method == "lm" {
+			for !accepted {
+				for i := 0; i < n; i++ {
+					for j := 0; j < n; j++ {
+						M.Set(i, j, A.Get(i, j))
+					}
+					M.Add(i, i, μ*math.Max(A.Get(i, i), MACHEPS))
+				}
+				if !curveSolve(δ, M, g, active) {
+					return chk.Err("CurveFit: JᵀWJ is singular\n")
+				}
+				o.project(ptrial, p, δ, 1)
+				chi2trial = o.chi2(ftrial, ptrial)
+				pred := 0.0 // predicted reduction δᵀ(μDδ + g)
+				for k := 0; k < n; k++ {
+					if active[k] {
+						dk := ptrial[k] - p[k]
+						pred += dk * (μ*math.Max(A.Get(k, k), MACHEPS)*dk + g[k])
+					}
+				}
+				ρ := (chi2 - chi2trial) / math.Max(pred, MACHEPS*chi2)
+				if ρ > 0 && chi2trial <= chi2 {
+					μ *= math.Max(1.0/3.0, 1.0-math.Pow(2*ρ-1, 3))
+					ν = 2
+					accepted = true
+				} else {
+					μ *= ν
+					ν *= 2
+					if μ > 1e16 || o.stepSmall(p, ptrial, xtol) {
+						break
+					}
+				}
+			}
+		} else {
+			if !curveSolve(δ, A, g, active) {
+				return chk.Err("CurveFit: JᵀWJ is singular\n")
+			}
+			for α := 1.0; α > 1e-10; α *= 0.5 {
+				o.project(ptrial, p, δ, α)
+				chi2trial = o.chi2(ftrial, ptrial)
+				if chi2trial <= chi2 {
+					accepted = true
+					break
+				}
+			}
+		}
+
+		// update
+		if !accepted {
+			converged = true // no further reduction is possible
+			break
+		}
+		small := o.stepSmall(p, ptrial, xtol)
+		reduction := chi2 - chi2trial
+		copy(p, ptrial)
+		copy(f, ftrial)
+		chi2old := chi2
+		chi2 = chi2trial
+		if small || reduction <= ftol*chi2old {
+			converged = true
+			o.Niter++
+			break
+		}
+	}
+
+	// statistics
+	o.calcJ(J, p, f)
+	o.normalEquations(A, g, J, f)
+	o.P = la.NewVectorSlice(p).GetCopy()
+	o.Chi2 = chi2
+	nfree := 0
+	for k := 0; k < n; k++ {
+		active[k] = !o.fixed[k]
+		if active[k] {
+			nfree++
+		}
+	}
+	o.Dof = o.ndata - nfree
+	o.RedChi2 = math.NaN()
+	if o.Dof > 0 {
+		o.RedChi2 = chi2 / float64(o.Dof)
+	}
+	o.Cov = la.NewMatrix(n, n)
+	o.Sigma = la.NewVector(n)
+	if !curveInverse(o.Cov, A, active) {
+		return chk.Err("CurveFit: JᵀWJ is singular; the covariance cannot be computed\n")
+	}
+	scale := 1.0
+	if !o.absSigma && o.Dof > 0 {
+		scale = o.RedChi2
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			o.Cov.Set(i, j, scale*o.Cov.Get(i, j))
+		}
+		o.Sigma[i] = math.Sqrt(math.Max(o.Cov.Get(i, i), 0))
+	}
+	if !converged {
+		return chk.Err("CurveFit: maximum number of iterations (%d) reached\n", maxit)
+	}
+	return
+}
+
+// ConfInterval computes the confidence intervals of the best-fit parameters
+//
+//	P[k] ± t⋅Sigma[k]      where t is the (1+level)/2 quantile of Student's t distribution
+//	                       with Dof degrees of freedom (or of the normal distribution if the
+//	                       absolute σ of the data were given)
+//
+//	level -- confidence level; e.g. 0.95
+func (o *CurveFit) ConfInterval(level float64) (lower, upper la.Vector) {
+	if o.P == nil {
+		chk.Panic("Solve must be called first\n")
+	}
+	if level <= 0 || level >= 1 {
+		chk.Panic("level must be in (0, 1). level=%g is invalid\n", level)
+	}
+	var t float64
+	if o.absSigma || o.Dof < 1 {
+		t = math.Sqrt2 * math.Erfinv(level)
+	} else {
+		t = studentTinv(0.5*(1+level), float64(o.Dof))
+	}
+	lower = la.NewVector(o.npar)
+	upper = la.NewVector(o.npar)
+	for k := 0; k < o.npar; k++ {
+		lower[k] = o.P[k] - t*o.Sigma[k]
+		upper[k] = o.P[k] + t*o.Sigma[k]
+	}
+	return
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// lo returns the lower bound of parameter k
+func (o *CurveFit) lo(k int) float64 {
+	if o.lower == nil {
+		return math.Inf(-1)
+	}
+	return o.lower[k]
+}
+
+// hi returns the upper bound of parameter k
+func (o *CurveFit) hi(k int) float64 {
+	if o.upper == nil {
+		return math.Inf(+1)
+	}
+	return o.upper[k]
+}
+
+// chi2 computes f(p) and χ²
+func (o *CurveFit) chi2(f, p la.Vector) (res float64) {
+	o.model(f, p)
+	o.Nfeval++
+	for i := 0; i < o.ndata; i++ {
+		r := o.y[i] - f[i]
+		res += o.w[i] * r * r
+	}
+	return
+}
+
+// calcJ computes J = df/dp (f = f(p) must be up to date)
+func (o *CurveFit) calcJ(J *la.Matrix, p, f la.Vector) {
+	o.Njeval++
+	if o.jacobian != nil {
+		o.jacobian(J, p)
+		return
+	}
+	if len(o.workspace) != o.ndata {
+		o.workspace = la.NewVector(o.ndata)
+		o.tripletJ.Init(o.ndata, o.npar, o.ndata*o.npar)
+	}
+	Jacobian(&o.tripletJ, o.model, p, f, o.workspace)
+	o.Nfeval += o.npar
+	o.tripletJ.ToDense().CopyInto(J, 1)
+}
+
+// normalEquations computes A = JᵀWJ and g = JᵀW(y-f)
+func (o *CurveFit) normalEquations(A *la.Matrix, g la.Vector, J *la.Matrix, f la.Vector) {
+	n := o.npar
+	A.Fill(0)
+	g.Fill(0)
+	for i := 0; i < o.ndata; i++ {
+		r := o.w[i] * (o.y[i] - f[i])
+		for k := 0; k < n; k++ {
+			wJik := o.w[i] * J.Get(i, k)
+			g[k] += J.Get(i, k) * r
+			for l := 0; l <= k; l++ {
+				A.Add(k, l, wJik*J.Get(i, l))
+			}
+		}
+	}
+	for k := 0; k < n; k++ {
+		for l := 0; l < k; l++ {
+			A.Set(l, k, A.Get(k, l))
+		}
+	}
+}
+
+// project computes ptrial = clamp(p + α⋅δ) onto the bounds
+func (o *CurveFit) project(ptrial, p, δ la.Vector, α float64) {
+	for k := range p {
+		ptrial[k] = math.Min(math.Max(p[k]+α*δ[k], o.lo(k)), o.hi(k))
+	}
+}
+
+// stepSmall checks whether |ptrial - p| ≤ xtol⋅(|p| + xtol)
+func (o *CurveFit) stepSmall(p, ptrial la.Vector, xtol float64) bool {
+	var dp, pn float64
+	for k := range p {
+		dp += (ptrial[k] - p[k]) * (ptrial[k] - p[k])
+		pn += p[k] * p[k]
+	}
+	return math.Sqrt(dp) <= xtol*(math.Sqrt(pn)+xtol)
+}
+
+// curveSolve solves the SPD system A⋅x = b restricted to the active variables by Cholesky
+// factorisation; x is zero for inactive variables. Returns false if A is not positive-definite
+func curveSolve(x la.Vector, A *la.Matrix, b la.Vector, active []bool) bool {
+	idx := curveActive(active)
+	L, ok := curveCholesky(A, idx)
+	if !ok {
+		return false
+	}
+	x.Fill(0)
+	m := len(idx)
+	z := make([]float64, m)
+	for i := 0; i < m; i++ { // forward substitution: L⋅z = b
+		s := b[idx[i]]
+		for k := 0; k < i; k++ {
+			s -= L[i][k] * z[k]
+		}
+		z[i] = s / L[i][i]
+	}
+	for i := m - 1; i >= 0; i-- { // back substitution: Lᵀ⋅x = z
+		s := z[i]
+		for k := i + 1; k < m; k++ {
+			s -= L[k][i] * x[idx[k]]
+		}
+		x[idx[i]] = s / L[i][i]
+	}
+	return true
+}
+
+// curveInverse computes the inverse of the SPD matrix A restricted to the active variables; the
+// other rows and columns of Ai are zero. Returns false if A is not positive-definite
+func curveInverse(Ai, A *la.Matrix, active []bool) bool {
+	Ai.Fill(0)
+	n := len(active)
+	e := la.NewVector(n)
+	x := la.NewVector(n)
+	for j := 0; j < n; j++ {
+		if !active[j] {
+			continue
+		}
+		e.Fill(0)
+		e[j] = 1
+		if !curveSolve(x, A, e, active) {
+			return false
+		}
+		for i := 0; i < n; i++ {
+			Ai.Set(i, j, x[i])
+		}
+	}
+	return true
+}
+
+// curveActive returns the indices of the active variables
+func curveActive(active []bool) (idx []int) {
+	for k, a := range active {
+		if a {
+			idx = append(idx, k)
+		}
+	}
+	return
+}
+
+// curveCholesky computes the Cholesky factor of A[idx,idx]
+func curveCholesky(A *la.Matrix, idx []int) (L [][]float64, ok bool) {
+	m := len(idx)
+	L = make([][]float64, m)
+	for i := 0; i < m; i++ {
+		L[i] = make([]float64, i+1)
+		for j := 0; j <= i; j++ {
+			s := A.Get(idx[i], idx[j])
+			for k := 0; k < j; k++ {
+				s -= L[i][k] * L[j][k]
+			}
+			if i == j {
+				if s <= 0 {
+					return nil, false
+				}
+				L[i][i] = math.Sqrt(s)
+			} else {
+				L[i][j] = s / L[j][j]
+			}
+		}
+	}
+	return L, true
+}
+
+// studentTinv computes the inverse of the cumulative distribution function of Student's t
+// distribution with ν degrees of freedom; i.e. t such that P(T ≤ t) = prob
+//
+//	P(T ≤ t) = 1 - ½ I(ν/(ν+t²); ν/2, ½)      for t ≥ 0
+//
+//	where I is the regularised incomplete Beta function
+func studentTinv(prob, ν float64) float64 {
+	if prob < 0.5 {
+		return -studentTinv(1-prob, ν)
+	}
+	cdf := func(t float64) float64 {
+		return 1 - 0.5*fun.BetaInc(0.5*ν, 0.5, ν/(ν+t*t))
+	}
+	a, b := 0.0, 1.0
+	for cdf(b) < prob {
+		a, b = b, 2*b
+	}
+	for i := 0; i < 200 && b-a > 1e-15*b; i++ { // bisection
+		c := 0.5 * (a + b)
+		if cdf(c) < prob {
+			a = c
+		} else {
+			b = c
+		}
+	}
+	return 0.5 * (a + b)
+}
@@ -0,0 +1,242 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/lei006/gomath/fun"
+	"github.com/lei006/gomath/la"
+)
+
+// DerivRidders approximates the derivative df/dx using Ridders' method [1,2]
+//
+//	Central differences D(h) = (f(x+h) - f(x-h)) / (2h) are computed with decreasing steps
+//	hᵢ = h₀ / 1.4ⁱ and extrapolated to h → 0 (Richardson extrapolation with Neville's tableau).
+//	The estimate with the smallest error is returned; the process stops when the error grows
+//	(i.e. when rounding errors dominate).
+//
+//	The initial step is reduced (h₀, h₀/10, h₀/100, ...) until all values of f are finite and the
+//	estimated error is small compared with the derivative; e.g. if h₀ is too large compared with
+//	the scale of f (sin(x) at large |x|) or x - h₀ is outside the domain of f (log(x) near 0).
+//	Thus, the step does not need to be chosen carefully.
+//
+//	INPUT:
+//	  x  -- point where the derivative is evaluated
+//	  h0 -- initial (large) step; use 0 for the default h0 = 0.1⋅max(1, |x|)
+//	  f  -- function f(x)
+//	OUTPUT:
+//	  res    -- derivative; NaN if f is not finite for all steps
+//	  errEst -- estimate of the error; +Inf if res is NaN. NOTE: errEst may be comparable to |res|
+//	            if the derivative could not be computed accurately
+//
+//	References:
+//	  [1] Ridders CJF (1982) Accurate computation of F'(x) and F'(x) F''(x), Advances in
+//	      Engineering Software, 4(2):75-76
+//	  [2] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes:
+//	      The Art of Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+func DerivRidders(x, h0 float64, f fun.Ss) (res, errEst float64) {
+	return derivAdaptive(derivStep(x, h0), func(h float64) float64 {
+		return (f(x+h) - f(x-h)) / (2.0 * h)
+	})
+}
+
+// SecondDerivRidders approximates the second derivative d²f/dx² using Ridders' method
+//
+//	The central differences (f(x+h) - 2f(x) + f(x-h)) / h² are extrapolated to h → 0.
+//	See DerivRidders for the input and output.
+func SecondDerivRidders(x, h0 float64, f fun.Ss) (res, errEst float64) {
+	fx := f(x)
+	return derivAdaptive(derivStep(x, h0), func(h float64) float64 {
+		return (f(x+h) - 2.0*fx + f(x-h)) / (h * h)
+	})
+}
+
+// DerivComplexStep approximates the derivative df/dx using the complex-step method [3]
+//
+//	  df/dx ≈ Im(f(x + i h)) / h    with h = 1e-20
+//
+//	There is no subtractive cancellation; thus, the result is exact to machine precision.
+//	NOTE: f must be analytic and implemented with complex arithmetic (e.g. math/cmplx)
+//	      without using abs, conjugates or comparisons of the complex argument
+//
+//	References:
+//	  [3] Martins JRRA, Sturdza P, Alonso JJ (2003) The complex-step derivative approximation,
+//	      ACM Transactions on Mathematical Software, 29(3):245-262
+func DerivComplexStep(x float64, f fun.Cc) float64 {
+	h := 1e-20
+	return imag(f(complex(x, h))) / h
+}
+
+// Gradient approximates the gradient g = df/dx of a scalar function of a vector using Ridders'
+// method for each component (see DerivRidders)
+//
+//	INPUT:
+//	  x -- point where the gradient is evaluated [unchanged on exit]
+//	  f -- function f(x)
+//	OUTPUT:
+//	  g      -- gradient [must be pre-allocated with len(g) == len(x)]
+//	  errEst -- maximum of the estimated errors of all components; +Inf if some component is NaN
+func Gradient(g, x la.Vector, f fun.Sv) (errEst float64) {
+	for i := 0; i < len(x); i++ {
+		xi := x[i]
+		var e float64
+		g[i], e = derivAdaptive(derivStep(xi, 0), func(h float64) float64 {
+			x[i] = xi + h
+			fp := f(x)
+			x[i] = xi - h
+			fm := f(x)
+			x[i] = xi
+			return (fp - fm) / (2.0 * h)
+		})
+		errEst = math.Max(errEst, e)
+	}
+	return
+}
+
+// Hessian approximates the Hessian H = d²f/dxdx of a scalar function of a vector using Ridders'
+// method for each component
+//
+//	The diagonal is computed with the second derivative central differences and the
+//	off-diagonal components with the 4-point formula for the mixed derivatives:
+//
+//	  Hᵢⱼ ≈ (f(x+hᵢ+hⱼ) - f(x+hᵢ-hⱼ) - f(x-hᵢ+hⱼ) + f(x-hᵢ-hⱼ)) / (4 h²)
+//
+//	INPUT:
+//	  x -- point where the Hessian is evaluated [unchanged on exit]
+//	  f -- function f(x)
+//	OUTPUT:
+//	  H      -- Hessian (symmetric) [must be pre-allocated with size len(x)×len(x)]
+//	  errEst -- maximum of the estimated errors of all components; +Inf if some component is NaN
+func Hessian(H *la.Matrix, x la.Vector, f fun.Sv) (errEst float64) {
+	n := len(x)
+	fx := f(x)
+	for i := 0; i < n; i++ {
+		xi := x[i]
+		hii, e := derivAdaptive(derivStep(xi, 0), func(h float64) float64 {
+			x[i] = xi + h
+			fp := f(x)
+			x[i] = xi - h
+			fm := f(x)
+			x[i] = xi
+			return (fp - 2.0*fx + fm) / (h * h)
+		})
+		H.Set(i, i, hii)
+		errEst = math.Max(errEst, e)
+		for j := i + 1; j < n; j++ {
+			xj := x[j]
+			hij, e := derivAdaptive(derivStep(math.Max(math.Abs(xi), math.Abs(xj)), 0), func(h float64) float64 {
+				x[i], x[j] = xi+h, xj+h
+				fpp := f(x)
+				x[i], x[j] = xi+h, xj-h
+				fpm := f(x)
+				x[i], x[j] = xi-h, xj+h
+				fmp := f(x)
+				x[i], x[j] = xi-h, xj-h
+				fmm := f(x)
+				x[i], x[j] = xi, xj
+				return (fpp - fpm - fmp + fmm) / (4.0 * h * h)
+			})
+			H.Set(i, j, hij)
+			H.Set(j, i, hij)
+			errEst = math.Max(errEst, e)
+		}
+	}
+	return
+}
+
+// GradientFunc returns a gradient function computed by Gradient (e.g. for optimisation problems
+// without an analytical gradient)
+func GradientFunc(f fun.Sv) fun.Vv {
+	return func(g, x la.Vector) {
+		Gradient(g, x, f)
+	}
+}
+
+// HessianFunc returns a Hessian function computed by Hessian (e.g. for optimisation problems
+// without an analytical Hessian)
+func HessianFunc(f fun.Sv) fun.Mv {
+	return func(H *la.Matrix, x la.Vector) {
+		Hessian(H, x, f)
+	}
+}
+
+// lower level functions //////////////////////////////////////////////////////////////////////////
+
+// derivStep returns the initial step of Ridders' method
+func derivStep(x, h0 float64) float64 {
+	if h0 > 0 {
+		return h0
+	}
+	return 0.1 * math.Max(1, math.Abs(x))
+}
+
+// derivAdaptive calls ridders with the initial steps h₀, h₀/10, h₀/100, ... until two consecutive
+// results agree and their error estimates are small compared with the result. The error estimate
+// is max(eₖ, |rₖ - rₖ₋₁|) because a too large h₀ may yield a wrong result with a small eₖ.
+// Otherwise, the estimate with the smallest error is returned (res = NaN and errEst = +Inf if D is
+// never finite)
+func derivAdaptive(h0 float64, D func(h float64) float64) (res, errEst float64) {
+	const (
+		ntrials = 12   // maximum number of initial steps
+		rtol    = 1e-6 // relative tolerance to accept the result
+	)
+	res, errEst = math.NaN(), math.Inf(1)
+	prev := math.NaN()
+	for k := 0; k < ntrials; k++ {
+		r, e := ridders(h0, D)
+		e = math.Max(e, math.Abs(r-prev)) // NaN if k == 0 or the previous result is NaN
+		if e <= rtol*math.Abs(r) {
+			return r, e
+		}
+		if e < errEst {
+			res, errEst = r, e
+		}
+		prev = r
+		h0 /= 10
+	}
+	return
+}
+
+// ridders extrapolates the approximations D(h) with error expansion in even powers of h to h → 0
+// using Neville's tableau with steps h₀, h₀/c, h₀/c², ... (c = 1.4)
+//
+//	NOTE: res = NaN and errEst = +Inf are returned if any D(h) is not finite
+func ridders(h float64, D func(h float64) float64) (res, errEst float64) {
+	const (
+		con  = 1.4 // step size is decreased by con at each iteration
+		con2 = con * con
+		ntab = 10  // maximum size of tableau
+		safe = 2.0 // return when error is safe worse than the best so far
+	)
+	var a [ntab][ntab]float64
+	a[0][0] = D(h)
+	if math.IsNaN(a[0][0]) || math.IsInf(a[0][0], 0) {
+		return math.NaN(), math.Inf(1)
+	}
+	res = a[0][0]
+	errEst = math.MaxFloat64
+	for i := 1; i < ntab; i++ {
+		h /= con
+		a[0][i] = D(h)
+		if math.IsNaN(a[0][i]) || math.IsInf(a[0][i], 0) {
+			return math.NaN(), math.Inf(1)
+		}
+		fac := con2
+		for j := 1; j <= i; j++ {
+			a[j][i] = (a[j-1][i]*fac - a[j-1][i-1]) / (fac - 1.0)
+			fac *= con2
+			errt := math.Max(math.Abs(a[j][i]-a[j-1][i]), math.Abs(a[j][i]-a[j-1][i-1]))
+			if errt <= errEst {
+				errEst = errt
+				res = a[j][i]
+			}
+		}
+		if math.Abs(a[i][i]-a[i-1][i-1]) >= safe*errEst {
+			break
+		}
+	}
+	return
+}
@@ -6,6 +6,7 @@ package num
 
 import (
 	"math"
+	"sort"
 	"testing"
 
 	"github.com/lei006/gomath/chk"
@@ -25,14 +26,17 @@ import (
 //	    ffcn : f(x) function
 //	    x    : station where dfdx has to be calculated
 //	    fx   : f @ x
-//	    w    : workspace with size == n == len(x)
+//	    w    : workspace with size == m == len(fx)
 //	RETURNS:
 //	    J : dfdx @ x [must be pre-allocated]
+//
+//	NOTE: the number of functions m = len(fx) may differ from the number of variables n = len(x);
+//	      e.g. to compute the Jacobian of residuals in least-squares problems
 func Jacobian(J *la.Triplet, ffcn fun.Vv, x, fx, w []float64) {
 	ndim := len(x)
-	start, endp1 := 0, ndim
+	start, endp1 := 0, len(fx)
 	if J.Max() == 0 {
-		J.Init(ndim, ndim, ndim*ndim)
+		J.Init(len(fx), ndim, len(fx)*ndim)
 	}
 	J.Start()
 	var df float64
@@ -49,6 +53,154 @@ func Jacobian(J *la.Triplet, ffcn fun.Vv, x, fx, w []float64) {
 	}
 }
 
+// JacSparsity holds the sparsity pattern of an m×n Jacobian matrix and a colouring of its columns
+//
+//	Columns are structurally orthogonal if they do not share any nonzero row. Thus, all columns
+//	with the same colour can be perturbed at once and the Jacobian is estimated with one function
+//	evaluation per colour (Curtis-Powell-Reid method [1]) instead of n evaluations. The colouring
+//	is computed by the greedy algorithm with the largest-first ordering [2], i.e. the columns with
+//	more nonzeros are coloured first.
+//
+//	References:
+//	[1] Curtis AR, Powell MJD, Reid JK (1974) On the estimation of sparse Jacobian matrices,
+//	    IMA Journal of Applied Mathematics, 13(1):117-119
+//	[2] Coleman TF, Moré JJ (1983) Estimation of sparse Jacobian matrices and graph coloring
+//	    problems, SIAM Journal on Numerical Analysis, 20(1):187-209
+type JacSparsity struct {
+	Ncolors int     // number of colours == number of function evaluations
+	Colors  []int   // colour of each column [n]
+	Groups  [][]int // columns of each colour [Ncolors]
+	m, n    int     // dimensions: number of functions and variables
+	nnz     int     // number of nonzeros
+	colRows [][]int // rows with nonzeros in each column [n]
+}
+
+// NewJacSparsity returns a new JacSparsity from the indices of nonzero entries
+//
+//	m, n       -- number of functions (rows) and variables (columns)
+//	rows, cols -- indices of nonzero entries; repeated pairs are allowed
+func NewJacSparsity(m, n int, rows, cols []int) (o *JacSparsity) {
+
+	// check
+	if len(rows) != len(cols) {
+		chk.Panic("lengths of rows and cols must be equal. %d != %d\n", len(rows), len(cols))
+	}
+
+	// pattern
+	o = new(JacSparsity)
+	o.m, o.n = m, n
+	o.colRows = make([][]int, n)
+	rowCols := make([][]int, m)
+	seen := make(map[int]bool)
+	for k := 0; k < len(rows); k++ {
+		i, j := rows[k], cols[k]
+		if i < 0 || i >= m || j < 0 || j >= n {
+			chk.Panic("index (%d,%d) is outside range (%d,%d)\n", i, j, m, n)
+		}
+		if seen[i*n+j] {
+			continue
+		}
+		seen[i*n+j] = true
+		o.colRows[j] = append(o.colRows[j], i)
+		rowCols[i] = append(rowCols[i], j)
+		o.nnz++
+	}
+
+	// largest-first ordering
+	order := utl.IntRange(n)
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(o.colRows[order[a]]) > len(o.colRows[order[b]])
+	})
+
+	// greedy colouring: smallest colour not used by any column sharing a row
+	o.Colors = make([]int, n)
+	for j := 0; j < n; j++ {
+		o.Colors[j] = -1
+	}
+	forbidden := make([]int, n+1) // forbidden[c] == j+1 => colour c is forbidden for column j
+	for _, j := range order {
+		for _, i := range o.colRows[j] {
+			for _, k := range rowCols[i] {
+				if o.Colors[k] >= 0 {
+					forbidden[o.Colors[k]] = j + 1
+				}
+			}
+		}
+		c := 0
+		for forbidden[c] == j+1 {
+			c++
+		}
+		o.Colors[j] = c
+		if c+1 > o.Ncolors {
+			o.Ncolors = c + 1
+		}
+	}
+
+	// groups
+	o.Groups = make([][]int, o.Ncolors)
+	for j := 0; j < n; j++ {
+		o.Groups[o.Colors[j]] = append(o.Groups[o.Colors[j]], j)
+	}
+	return
+}
+
+// NewJacSparsityTriplet returns a new JacSparsity with the pattern of the entries in T
+//
+//	NOTE: the values in T are ignored; e.g. T may be computed by an analytical Jacobian function
+//	      at an arbitrary point or be assembled with ones
+func NewJacSparsityTriplet(T *la.Triplet) (o *JacSparsity) {
+	m, n := T.Size()
+	rows := make([]int, T.Len())
+	cols := make([]int, T.Len())
+	for k := 0; k < T.Len(); k++ {
+		rows[k], cols[k], _ = T.Entry(k)
+	}
+	return NewJacSparsity(m, n, rows, cols)
+}
+
+// Nnz returns the number of nonzeros (without repetitions)
+func (o *JacSparsity) Nnz() int {
+	return o.nnz
+}
+
+// JacobianColored computes Jacobian (sparse) matrix with one function evaluation per colour
+//
+//	INPUT:
+//	    ffcn : f(x) function
+//	    x    : station where dfdx has to be calculated
+//	    fx   : f @ x
+//	    w    : workspace with size == m == len(fx)
+//	    sp   : sparsity pattern and colouring of columns
+//	RETURNS:
+//	    J : dfdx @ x [must be pre-allocated or be empty; then, it is allocated with sp.Nnz() items]
+//
+//	NOTE: the number of function evaluations is sp.Ncolors
+func JacobianColored(J *la.Triplet, ffcn fun.Vv, x, fx, w []float64, sp *JacSparsity) {
+	if len(x) != sp.n || len(fx) != sp.m {
+		chk.Panic("dimensions of x and fx (%d,%d) are incompatible with sparsity pattern (%d,%d)\n", len(x), len(fx), sp.n, sp.m)
+	}
+	if J.Max() < sp.nnz {
+		J.Init(sp.m, sp.n, sp.nnz)
+	}
+	J.Start()
+	xsafe := make([]float64, len(x))
+	delta := make([]float64, len(x))
+	for _, group := range sp.Groups {
+		for _, col := range group {
+			xsafe[col] = x[col]
+			delta[col] = math.Sqrt(MACHEPS * utl.Max(1e-5, math.Abs(x[col])))
+			x[col] = xsafe[col] + delta[col]
+		}
+		ffcn(w, x) // w := f(x+Σδx[col])
+		for _, col := range group {
+			for _, row := range sp.colRows[col] {
+				J.Put(row, col, (w[row]-fx[row])/delta[col])
+			}
+			x[col] = xsafe[col]
+		}
+	}
+}
+
 // CompareJac compares Jacobian matrix (e.g. for testing)
 func CompareJac(tst *testing.T, ffcn fun.Vv, Jfcn fun.Tv, x []float64, tol float64) {
 
@@ -15,9 +15,26 @@ import (
 
 // NlSolver implements a solver to nonlinear systems of equations
 //
+//	The method is selected by NlSolverConfig.Method:
+//	  "newton"      -- full or modified (ConstantJacobian) Newton's method [default]
+//	  "broyden"     -- quasi-Newton method with Broyden's "good" update of the Jacobian [2]
+//	  "broyden-bad" -- quasi-Newton method with Broyden's "bad" update of the inverse Jacobian [2]
+//	  "jfnk"        -- Jacobian-free Newton-Krylov method; the linear systems are solved by GMRES
+//	                   with directional differences J⋅v ≈ (f(x+εv) - f(x))/ε [3]
+//	and globalised by LineSearch or by Powell's dogleg trust-region (TrustRegion) [4]. In the JFNK
+//	method, the dogleg step is computed within the Krylov subspace [5].
+//
 //	References:
 //	 [1] G.Forsythe, M.Malcolm, C.Moler, Computer methods for mathematical
 //	     computations. M., Mir, 1980, p.180 of the Russian edition
+//	 [2] Broyden CG (1965) A class of methods for solving nonlinear simultaneous equations,
+//	     Mathematics of Computation, 19(92):577-593
+//	 [3] Knoll DA, Keyes DE (2004) Jacobian-free Newton-Krylov methods: a survey of approaches and
+//	     applications, Journal of Computational Physics, 193:357-397
+//	 [4] Powell MJD (1970) A hybrid method for nonlinear equations, in Rabinowitz P (ed) Numerical
+//	     Methods for Nonlinear Algebraic Equations, Gordon and Breach, 87-114
+//	 [5] Brown PN, Saad Y (1990) Hybrid Krylov methods for nonlinear systems of equations,
+//	     SIAM Journal on Scientific and Statistical Computing, 11(3):450-481
 type NlSolver struct {
 
 	// configuration
@@ -45,16 +62,29 @@ type NlSolver struct {
 	lsReady  bool            // linear solver is ready
 
 	// workspace for numerical Jacobian (sparse)
-	workspaceNumJac la.Vector // workspace
+	workspaceNumJac la.Vector    // workspace
+	sparsity        *JacSparsity // sparsity pattern and colouring for the numerical Jacobian [may be nil]
 
 	// data for dense solver (matrix inversion)
 	matrixJ    *la.Matrix // dense Jacobian matrix
 	matrixJinv *la.Matrix // inverse of Jacobian matrix
 
+	// data for quasi-Newton, Newton-Krylov and trust-region methods
+	broyden bool       // Broyden's method: matrixJ and matrixJinv hold the approximations B and B⁻¹
+	krylov  *nlsKrylov // GMRES data (JFNK)
+	fx0     la.Vector  // f(x0)
+	dx      la.Vector  // step
+	dxN     la.Vector  // Newton (or quasi-Newton) step
+	grad    la.Vector  // steepest descent direction -Jᵀ⋅f
+	Jv      la.Vector  // J⋅v
+	ftrial  la.Vector  // f(x + dx)
+	xtrial  la.Vector  // x + dx
+
 	// stats
-	Niter  int // number of iterations from the last call to Solve
-	Nfeval int // number of calls to Ffcn (function evaluations)
-	Njeval int // number of calls to Jfcn (Jacobian evaluations)
+	Niter   int // number of iterations from the last call to Solve
+	Nfeval  int // number of calls to Ffcn (function evaluations)
+	Njeval  int // number of calls to Jfcn (Jacobian evaluations)
+	Nkrylov int // number of Krylov (GMRES) iterations
 }
 
 // NewNlSolver creates a new NlSolver
@@ -104,6 +134,28 @@ func (o *NlSolver) SetJacobianFunction(Jsparse fun.Tv, Jdense fun.Mv) {
 	o.config.hasJacobianFunction = true
 }
 
+// SetJacobianSparsity sets the sparsity pattern of the Jacobian matrix
+//
+//	The numerical Jacobian is then computed by JacobianColored with one function evaluation per
+//	colour of the Curtis-Powell-Reid grouping instead of one evaluation per variable.
+//	NOTE: this has no effect if the Jacobian function has been set with SetJacobianFunction
+func (o *NlSolver) SetJacobianSparsity(sp *JacSparsity) {
+	if sp.m != o.neq || sp.n != o.neq {
+		chk.Panic("sparsity pattern (%d,%d) is incompatible with the number of equations %d\n", sp.m, sp.n, o.neq)
+	}
+	o.sparsity = sp
+}
+
+// SetConfig sets the configuration parameters (a copy of config is stored)
+//
+//	NOTE: the choice of Jacobian function made with SetJacobianFunction is kept
+func (o *NlSolver) SetConfig(config *NlSolverConfig) {
+	useDenseSolver, hasJacobianFunction := o.config.useDenseSolver, o.config.hasJacobianFunction
+	c := *config
+	o.config = &c
+	o.config.useDenseSolver, o.config.hasJacobianFunction = useDenseSolver, hasJacobianFunction
+}
+
 // Free frees memory
 func (o *NlSolver) Free() {
 	if !o.config.useDenseSolver {
@@ -127,7 +179,21 @@ func (o *NlSolver) Solve(x []float64) {
 
 	// evaluate function @ x
 	o.functionF(o.fx, x) // fx := f(x)
-	o.Nfeval, o.Njeval = 1, 0
+	o.Nfeval, o.Njeval, o.Nkrylov = 1, 0, 0
+
+	// quasi-Newton, Newton-Krylov or trust-region methods
+	switch o.config.Method {
+	case "", "newton":
+		if o.config.TrustRegion {
+			o.solveGeneral(x)
+			return
+		}
+	case "broyden", "broyden-bad", "jfnk":
+		o.solveGeneral(x)
+		return
+	default:
+		chk.Panic("method %q is not available. Options are: \"newton\", \"broyden\", \"broyden-bad\" or \"jfnk\"\n", o.config.Method)
+	}
 
 	// show message
 	if o.config.Verbose {
@@ -161,17 +227,7 @@ func (o *NlSolver) Solve(x []float64) {
 
 		// evaluate Jacobian @ x
 		if o.Niter == 0 || !o.config.ConstantJacobian {
-			if o.config.useDenseSolver {
-				o.functionJdense(o.matrixJ, x)
-			} else {
-				if o.config.hasJacobianFunction {
-					o.functionJsparse(&o.tripletJ, x)
-				} else {
-					Jacobian(&o.tripletJ, o.functionF, x, o.fx, o.workspaceNumJac)
-					o.Nfeval += o.neq
-				}
-			}
-			o.Njeval++
+			o.calcJacobian(x)
 		}
 
 		// dense solution
@@ -283,6 +339,24 @@ func (o *NlSolver) Solve(x []float64) {
 	}
 }
 
+// calcJacobian computes the Jacobian matrix @ x (dense or sparse); fx must be up to date
+func (o *NlSolver) calcJacobian(x la.Vector) {
+	if o.config.useDenseSolver {
+		o.functionJdense(o.matrixJ, x)
+	} else {
+		if o.config.hasJacobianFunction {
+			o.functionJsparse(&o.tripletJ, x)
+		} else if o.sparsity != nil {
+			JacobianColored(&o.tripletJ, o.functionF, x, o.fx, o.workspaceNumJac, o.sparsity)
+			o.Nfeval += o.sparsity.Ncolors
+		} else {
+			Jacobian(&o.tripletJ, o.functionF, x, o.fx, o.workspaceNumJac)
+			o.Nfeval += o.neq
+		}
+	}
+	o.Njeval++
+}
+
 // CheckJ check Jacobian matrix
 //
 //	Ouptut: cnd -- condition number (with Frobenius norm)
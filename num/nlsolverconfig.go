@@ -21,6 +21,13 @@ type NlSolverConfig struct {
 	MaxIterations    int  // Newton's method maximum iterations
 	EnforceConvRate  bool // check and enforce convergence rate
 
+	// method and globalisation
+	Method       string  // "newton", "broyden" (good), "broyden-bad" or "jfnk" (Jacobian-free Newton-Krylov)
+	TrustRegion  bool    // use Powell's dogleg trust-region instead of line search
+	TrustRadius0 float64 // initial trust-region radius Δ₀ = TrustRadius0 ⋅ max(‖x₀‖, 1)
+	KrylovMaxIt  int     // maximum dimension of the Krylov subspace (GMRES) in the JFNK method
+	KrylovTol    float64 // relative tolerance of GMRES (forcing term) in the JFNK method
+
 	// function to be called during each output
 	OutCallback func(x []float64) // output callback function
 
@@ -41,14 +48,19 @@ type NlSolverConfig struct {
 // NewNlSolverConfig creates a new NlSolverConfig
 // Default values:
 //
-//	CteJac      = false
-//	LinSearch   = false
-//	LinSchMaxIt = 20
-//	MaxIt       = 20
-//	ChkConv     = false
-//	Atol        = 1e-8
-//	Rtol        = 1e-8
-//	Ftol        = 1e-9
+//	CteJac       = false
+//	LinSearch    = false
+//	LinSchMaxIt  = 20
+//	MaxIt        = 20
+//	ChkConv      = false
+//	Method       = "newton"
+//	TrustRegion  = false
+//	TrustRadius0 = 1
+//	KrylovMaxIt  = 30
+//	KrylovTol    = 1e-6
+//	Atol         = 1e-8
+//	Rtol         = 1e-8
+//	Ftol         = 1e-9
 func NewNlSolverConfig() (o *NlSolverConfig) {
 
 	// input
@@ -60,6 +72,13 @@ func NewNlSolverConfig() (o *NlSolverConfig) {
 	o.MaxIterations = 20
 	o.EnforceConvRate = false
 
+	// method and globalisation
+	o.Method = "newton"
+	o.TrustRegion = false
+	o.TrustRadius0 = 1
+	o.KrylovMaxIt = 30
+	o.KrylovTol = 1e-6
+
 	// configurations for linear solver
 	o.LinSolConfig = la.NewSparseConfig()
 
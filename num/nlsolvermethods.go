@@ -0,0 +1,630 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/la"
+)
+
+// solveGeneral solves f(x) == 0 with the quasi-Newton (Broyden), Jacobian-free Newton-Krylov or
+// trust-region (dogleg) methods. fx = f(x) must be up to date
+func (o *NlSolver) solveGeneral(x la.Vector) {
+
+	// method
+	method := o.config.Method
+	if method == "" {
+		method = "newton"
+	}
+	broyden := method == "broyden" || method == "broyden-bad"
+	o.broyden = broyden
+	jfnk := method == "jfnk"
+
+	// allocate workspace
+	n := o.neq
+	if len(o.dx) != n {
+		o.dx = la.NewVector(n)
+		o.dxN = la.NewVector(n)
+		o.grad = la.NewVector(n)
+		o.Jv = la.NewVector(n)
+		o.fx0 = la.NewVector(n)
+		o.ftrial = la.NewVector(n)
+		o.xtrial = la.NewVector(n)
+	}
+	if broyden && o.matrixJ == nil {
+		o.matrixJ = la.NewMatrix(n, n)
+		o.matrixJinv = la.NewMatrix(n, n)
+	}
+	if jfnk {
+		m := o.config.KrylovMaxIt
+		if m < 1 {
+			m = 30
+		}
+		if m > n {
+			m = n
+		}
+		if o.krylov == nil || o.krylov.mmax != m {
+			o.krylov = newNlsKrylov(n, m)
+		}
+	}
+
+	// trust-region radius
+	Δ0 := o.config.TrustRadius0
+	if Δ0 <= 0 {
+		Δ0 = 1
+	}
+	Δ := Δ0 * math.Max(x.Norm(), 1)
+
+	// show message
+	if o.config.Verbose {
+		o.msg("", 0, 0, 0, true, false)
+	}
+
+	// iterations
+	var Ldx, LdxPrev, Θ float64 // RMS norm of delta x, convergence rate
+	var fxMax float64
+	refresh := true // compute the Jacobian of the quasi-Newton method
+	for o.Niter = 0; o.Niter < o.config.MaxIterations; o.Niter++ {
+
+		// check convergence on f(x)
+		fxMax = o.fx.Largest(1.0) // den = 1.0
+		if fxMax < o.config.ftol {
+			if o.config.Verbose {
+				o.msg("fxMax(ini)", o.Niter, Ldx, fxMax, false, true)
+			}
+			break
+		}
+
+		// show message
+		if o.config.Verbose {
+			o.msg("", o.Niter, Ldx, fxMax, false, false)
+		}
+
+		// output
+		if o.config.OutCallback != nil {
+			o.config.OutCallback(x)
+		}
+
+		// Jacobian matrix
+		fresh := false
+		switch {
+		case broyden:
+			if refresh {
+				o.calcJacobian(x)
+				if !o.config.useDenseSolver {
+					o.tripletJ.ToDense().CopyInto(o.matrixJ, 1)
+				}
+				la.MatInv(o.matrixJinv, o.matrixJ, false)
+				refresh, fresh = false, true
+			}
+		case !jfnk:
+			if o.Niter == 0 || !o.config.ConstantJacobian {
+				o.calcJacobian(x)
+				if o.config.useDenseSolver {
+					la.MatInv(o.matrixJinv, o.matrixJ, false)
+				} else {
+					if !o.lsReady {
+						o.linsol.Init(&o.tripletJ, o.config.LinSolConfig)
+						o.lsReady = true
+					}
+					o.linsol.Fact()
+				}
+				fresh = true
+			}
+		}
+
+		// (quasi-)Newton step: dxN = -J⁻¹⋅f
+		if jfnk {
+			o.krylov.solve(o, x)
+		} else {
+			o.newtonStep()
+		}
+
+		// update x and fx
+		copy(o.x0, x)
+		copy(o.fx0, o.fx)
+		switch {
+		case o.config.TrustRegion:
+			maxReject := 0
+			if broyden && !fresh {
+				maxReject = 2 // it is better to recompute the Jacobian
+			}
+			Δnew, ok := o.trustRegionStep(x, Δ, jfnk, maxReject)
+			if !ok {
+				if broyden && !fresh {
+					refresh = true
+					continue
+				}
+				chk.Panic("trust-region radius became too small (Δ=%g); x may be near a local minimum of |f(x)|", Δnew)
+			}
+			Δ = Δnew
+		case o.config.LineSearch:
+			o.phi = 0.5 * la.VecDot(o.fx, o.fx)
+			if broyden && !fresh {
+				// the quasi-Newton step is accepted if the decrease is sufficient (Armijo with
+				// slope = -2φ); otherwise, the Jacobian is recomputed before searching
+				la.VecAdd(o.xtrial, 1, o.x0, 1, o.dxN)
+				o.functionF(o.ftrial, o.xtrial)
+				o.Nfeval++
+				if 0.5*la.VecDot(o.ftrial, o.ftrial) > (1-2e-4)*o.phi {
+					refresh = true
+					continue
+				}
+				copy(x, o.xtrial)
+				copy(o.fx, o.ftrial)
+				break
+			}
+			if jfnk {
+				slope := o.krylov.slope() // fᵀ⋅J⋅dxN
+				la.VecAdd(o.dphidx, slope/la.VecDot(o.dxN, o.dxN), o.dxN, 0, o.dxN)
+			} else {
+				o.jacTrMul(o.dphidx, o.fx) // dφdx := transpose(J) * fx
+			}
+			la.VecAdd(o.mdx, -1, o.dxN, 0, o.dxN)
+			o.Nfeval += LineSearch(x, o.fx, o.functionF, o.mdx, o.x0, o.dphidx, o.phi, o.config.LineSearchMaxIt, true)
+		default:
+			for i := 0; i < n; i++ {
+				x[i] += o.dxN[i]
+			}
+			o.functionF(o.fx, x)
+			o.Nfeval++
+		}
+
+		// quasi-Newton update
+		if broyden {
+			o.broydenUpdate(x, method == "broyden-bad")
+		}
+
+		// check convergence on f(x)
+		Ldx = 0.0
+		for i := 0; i < n; i++ {
+			Ldx += ((x[i] - o.x0[i]) / o.scal[i]) * ((x[i] - o.x0[i]) / o.scal[i])
+		}
+		Ldx = math.Sqrt(Ldx / float64(n))
+		fxMax = o.fx.Largest(1.0) // den = 1.0
+		if fxMax < o.config.ftol {
+			if o.config.Verbose {
+				o.msg("fxMax", o.Niter, Ldx, fxMax, false, true)
+			}
+			break
+		}
+
+		// check convergence on Ldx
+		if Ldx < o.config.fnewt {
+			if broyden && !fresh {
+				refresh = true // the quasi-Newton step may have stagnated
+				continue
+			}
+			if o.config.Verbose {
+				o.msg("Ldx", o.Niter, Ldx, fxMax, false, true)
+			}
+			break
+		}
+
+		// check convergence rate
+		if o.Niter > 0 && o.config.EnforceConvRate {
+			Θ = Ldx / LdxPrev
+			if Θ > 0.99 {
+				chk.Panic("solver is diverging with Θ = %g (Ldx=%g, LdxPrev=%g)", Θ, Ldx, LdxPrev)
+			}
+		}
+		LdxPrev = Ldx
+	}
+
+	// output
+	if o.config.OutCallback != nil {
+		o.config.OutCallback(x)
+	}
+
+	// check convergence
+	if o.Niter == o.config.MaxIterations {
+		chk.Panic("cannot converge after %d iterations", o.Niter)
+	}
+}
+
+// newtonStep computes the (quasi-)Newton step dxN = -J⁻¹⋅f with the dense inverse (or its
+// Broyden approximation) or with the factorised sparse Jacobian
+func (o *NlSolver) newtonStep() {
+	if o.config.useDenseSolver || o.broyden {
+		la.MatVecMul(o.dxN, -1, o.matrixJinv, o.fx)
+		return
+	}
+	o.linsol.Solve(o.mdx, o.fx) // mdx = inv(J) * fx
+	la.VecAdd(o.dxN, -1, o.mdx, 0, o.mdx)
+}
+
+// jacMul computes y = J⋅v
+func (o *NlSolver) jacMul(y, v la.Vector) {
+	if o.config.useDenseSolver || o.broyden {
+		la.MatVecMul(y, 1, o.matrixJ, v)
+		return
+	}
+	la.SpTriMatVecMul(y, &o.tripletJ, v)
+}
+
+// jacTrMul computes y = Jᵀ⋅v
+func (o *NlSolver) jacTrMul(y, v la.Vector) {
+	if o.config.useDenseSolver || o.broyden {
+		la.MatTrVecMul(y, 1, o.matrixJ, v)
+		return
+	}
+	la.SpTriMatTrVecMul(y, &o.tripletJ, v)
+}
+
+// trustRegionStep updates x and fx with Powell's dogleg method. The (quasi-)Newton step dxN (or the
+// Krylov data) must be available. x0 and fx0 hold the current x and f(x)
+//
+//	The step p minimises the linear model ½‖f + J⋅p‖² within ‖p‖ ≤ Δ and is accepted if the ratio
+//	ρ = actual/predicted reduction of φ = ½‖f‖² is positive enough. The radius is reduced if ρ < ¼
+//	and increased if ρ > ¾. With the JFNK method, the model is restricted to the Krylov subspace
+//	where J⋅V = V⋅H̄ [5].
+//
+//	maxReject -- maximum number of rejected steps (0 => unlimited)
+//	ok        -- false if the radius became too small or maxReject was reached. x and fx are not
+//	             modified in this case
+func (o *NlSolver) trustRegionStep(x la.Vector, Δ float64, jfnk bool, maxReject int) (Δnew float64, ok bool) {
+
+	// model in full space or Krylov subspace
+	var pN, g, p la.Vector // Newton step, steepest descent direction and step
+	var Jg2 float64        // ‖J⋅g‖²
+	φ0 := 0.5 * la.VecDot(o.fx0, o.fx0)
+	if jfnk {
+		pN, g, p, Jg2 = o.krylov.model()
+	} else {
+		pN, g, p = o.dxN, o.grad, o.dx
+		o.jacTrMul(g, o.fx0)
+		g.Apply(-1, g)
+		o.jacMul(o.Jv, g)
+		Jg2 = la.VecDot(o.Jv, o.Jv)
+	}
+	xnorm := math.Max(o.x0.Norm(), 1)
+
+	// trial steps
+	for nreject := 0; ; nreject++ {
+
+		// dogleg step and predicted reduction
+		boundary := doglegStep(p, pN, g, Jg2, Δ)
+		var pred float64
+		if jfnk {
+			pred = o.krylov.pred(p)
+			o.krylov.toFull(o.dx, p)
+		} else {
+			o.jacMul(o.Jv, p)
+			pred = φ0
+			for i := 0; i < o.neq; i++ {
+				pred -= 0.5 * (o.fx0[i] + o.Jv[i]) * (o.fx0[i] + o.Jv[i])
+			}
+		}
+
+		// actual reduction
+		for i := 0; i < o.neq; i++ {
+			o.xtrial[i] = o.x0[i] + o.dx[i]
+		}
+		o.functionF(o.ftrial, o.xtrial)
+		o.Nfeval++
+		φ := 0.5 * la.VecDot(o.ftrial, o.ftrial)
+		ρ := -1.0
+		if pred > 0 {
+			ρ = (φ0 - φ) / pred
+		}
+
+		// update radius
+		pnorm := p.Norm()
+		if ρ < 0.25 {
+			Δ = 0.25 * pnorm
+		} else if ρ > 0.75 && boundary {
+			Δ *= 2
+		}
+
+		// accept step
+		if ρ > 1e-4 {
+			copy(x, o.xtrial)
+			copy(o.fx, o.ftrial)
+			return Δ, true
+		}
+
+		// failure
+		if Δ <= MACHEPS*xnorm || (maxReject > 0 && nreject+1 >= maxReject) {
+			return Δ, false
+		}
+	}
+}
+
+// doglegStep computes Powell's dogleg step p within the trust region ‖p‖ ≤ Δ
+//
+//	pN  -- (quasi-)Newton step
+//	g   -- steepest descent direction -Jᵀ⋅f
+//	Jg2 -- ‖J⋅g‖²
+//
+//	boundary -- the step lies on the boundary of the trust region
+func doglegStep(p, pN, g la.Vector, Jg2, Δ float64) (boundary bool) {
+
+	// Newton step within the trust region
+	nN := pN.Norm()
+	if nN <= Δ {
+		copy(p, pN)
+		return false
+	}
+
+	// Cauchy point outside the trust region => steepest descent
+	g2 := la.VecDot(g, g)
+	gnorm := math.Sqrt(g2)
+	if Jg2 <= 0 || g2/Jg2*gnorm >= Δ {
+		if gnorm > 0 {
+			la.VecAdd(p, Δ/gnorm, g, 0, g)
+		} else {
+			la.VecAdd(p, Δ/nN, pN, 0, pN)
+		}
+		return true
+	}
+
+	// dogleg: p = pC + τ⋅(pN - pC) with ‖p‖ = Δ
+	α := g2 / Jg2
+	var a, b, c float64
+	for i := range p {
+		pc := α * g[i]
+		d := pN[i] - pc
+		a += d * d
+		b += 2 * pc * d
+		c += pc * pc
+	}
+	c -= Δ * Δ
+	τ := (-b + math.Sqrt(b*b-4*a*c)) / (2 * a)
+	if b > 0 { // avoid cancellation
+		τ = -2 * c / (b + math.Sqrt(b*b-4*a*c))
+	}
+	for i := range p {
+		pc := α * g[i]
+		p[i] = pc + τ*(pN[i]-pc)
+	}
+	return true
+}
+
+// broydenUpdate performs the rank-one update of the Jacobian approximation B = matrixJ and its
+// inverse H = matrixJinv after the step s = x - x0 with y = f(x) - f(x0)
+//
+//	good: B ← B + (y - B⋅s)⋅sᵀ/(sᵀ⋅s)     H ← H + (s - H⋅y)⋅(sᵀ⋅H)/(sᵀ⋅H⋅y)
+//	bad:  H ← H + (s - H⋅y)⋅yᵀ/(yᵀ⋅y)     B ← B - (B⋅u)⋅(yᵀ⋅B)/(1 + yᵀ⋅B⋅u)   with u = (s - H⋅y)/(yᵀ⋅y)
+func (o *NlSolver) broydenUpdate(x la.Vector, bad bool) {
+
+	// s and y
+	s, y := o.dx, o.ftrial
+	la.VecAdd(s, 1, x, -1, o.x0)
+	la.VecAdd(y, 1, o.fx, -1, o.fx0)
+	ss, yy := la.VecDot(s, s), la.VecDot(y, y)
+	if ss == 0 || yy == 0 {
+		return
+	}
+	B, H := o.matrixJ, o.matrixJinv
+	u, v := o.Jv, o.grad // workspace
+	n := o.neq
+
+	// good Broyden
+	if !bad {
+		la.MatVecMul(u, 1, B, s) // u = B⋅s
+		for i := 0; i < n; i++ {
+			u[i] = (y[i] - u[i]) / ss
+			for j := 0; j < n; j++ {
+				B.Add(i, j, u[i]*s[j])
+			}
+		}
+		la.MatVecMul(u, 1, H, y)   // u = H⋅y
+		la.MatTrVecMul(v, 1, H, s) // v = Hᵀ⋅s
+		den := la.VecDot(s, u)
+		if math.Abs(den) < MACHEPS*ss {
+			la.MatInv(H, B, false)
+			return
+		}
+		for i := 0; i < n; i++ {
+			c := (s[i] - u[i]) / den
+			for j := 0; j < n; j++ {
+				H.Add(i, j, c*v[j])
+			}
+		}
+		return
+	}
+
+	// bad Broyden
+	la.MatVecMul(u, 1, H, y) // u = H⋅y
+	for i := 0; i < n; i++ {
+		u[i] = (s[i] - u[i]) / yy
+		for j := 0; j < n; j++ {
+			H.Add(i, j, u[i]*y[j])
+		}
+	}
+	la.MatVecMul(s, 1, B, u)   // s := B⋅u
+	la.MatTrVecMul(v, 1, B, y) // v = Bᵀ⋅y
+	den := 1 + la.VecDot(y, s)
+	if math.Abs(den) < MACHEPS {
+		la.MatInv(B, H, false)
+		return
+	}
+	for i := 0; i < n; i++ {
+		c := s[i] / den
+		for j := 0; j < n; j++ {
+			B.Add(i, j, -c*v[j])
+		}
+	}
+}
+
+// nlsKrylov holds the data for the GMRES solution of J⋅dx = -f in the JFNK method
+type nlsKrylov struct {
+	mmax int         // maximum dimension of the Krylov subspace
+	m    int         // dimension of the Krylov subspace
+	β    float64     // ‖f‖
+	V    []la.Vector // orthonormal basis [mmax+1][neq]; v₀ = -f/β
+	H    [][]float64 // Hessenberg matrix H̄ [mmax+1][mmax] such that J⋅V = V⋅H̄
+	R    [][]float64 // H̄ after Givens rotations (upper triangular) [mmax+1][mmax]
+	cs   []float64   // Givens rotations (cosines) [mmax]
+	sn   []float64   // Givens rotations (sines) [mmax]
+	gr   []float64   // rotated right-hand side β⋅e₀ [mmax+1]
+	y    la.Vector   // coordinates of the Newton step [mmax]
+	gy   la.Vector   // coordinates of the steepest descent direction [mmax]
+	p    la.Vector   // coordinates of the trust-region step [mmax]
+	w    la.Vector   // workspace [neq]
+	xe   la.Vector   // perturbed x [neq]
+}
+
+// newNlsKrylov allocates a new nlsKrylov structure
+func newNlsKrylov(neq, mmax int) (o *nlsKrylov) {
+	o = new(nlsKrylov)
+	o.mmax = mmax
+	o.V = make([]la.Vector, mmax+1)
+	o.H = make([][]float64, mmax+1)
+	o.R = make([][]float64, mmax+1)
+	for i := 0; i <= mmax; i++ {
+		o.V[i] = la.NewVector(neq)
+		o.H[i] = make([]float64, mmax)
+		o.R[i] = make([]float64, mmax)
+	}
+	o.cs = make([]float64, mmax)
+	o.sn = make([]float64, mmax)
+	o.gr = make([]float64, mmax+1)
+	o.y = la.NewVector(mmax)
+	o.gy = la.NewVector(mmax)
+	o.p = la.NewVector(mmax)
+	o.w = la.NewVector(neq)
+	o.xe = la.NewVector(neq)
+	return
+}
+
+// solve solves J⋅dxN = -f by GMRES (without restarts) with directional differences
+//
+//	J⋅v ≈ (f(x + ε⋅v) - f(x)) / ε      with ε = √(ϵ⋅(1 + ‖x‖)) and ‖v‖ = 1
+//
+//	The iterations stop when ‖f + J⋅dxN‖ ≤ KrylovTol⋅‖f‖ or when the dimension of the subspace
+//	reaches KrylovMaxIt. The result is stored in sol.dxN
+func (o *nlsKrylov) solve(sol *NlSolver, x la.Vector) {
+
+	// initial residual
+	n := sol.neq
+	o.β = sol.fx.Norm()
+	la.VecAdd(o.V[0], -1.0/o.β, sol.fx, 0, sol.fx)
+	for i := range o.gr {
+		o.gr[i] = 0
+	}
+	o.gr[0] = o.β
+	ε := math.Sqrt(MACHEPS * (1 + x.Norm()))
+	tol := sol.config.KrylovTol * o.β
+
+	// Arnoldi iterations
+	o.m = 0
+	for j := 0; j < o.mmax; j++ {
+
+		// w = J⋅vⱼ
+		for i := 0; i < n; i++ {
+			o.xe[i] = x[i] + ε*o.V[j][i]
+		}
+		sol.functionF(o.w, o.xe)
+		sol.Nfeval++
+		sol.Nkrylov++
+		for i := 0; i < n; i++ {
+			o.w[i] = (o.w[i] - sol.fx[i]) / ε
+		}
+
+		// modified Gram-Schmidt
+		for i := 0; i <= j; i++ {
+			o.H[i][j] = la.VecDot(o.w, o.V[i])
+			la.VecAdd(o.w, 1, o.w, -o.H[i][j], o.V[i])
+		}
+		o.H[j+1][j] = o.w.Norm()
+		if o.H[j+1][j] > 0 {
+			la.VecAdd(o.V[j+1], 1.0/o.H[j+1][j], o.w, 0, o.w)
+		}
+
+		// Givens rotations
+		for i := 0; i <= j+1; i++ {
+			o.R[i][j] = o.H[i][j]
+		}
+		for i := 0; i < j; i++ {
+			a, b := o.R[i][j], o.R[i+1][j]
+			o.R[i][j] = o.cs[i]*a + o.sn[i]*b
+			o.R[i+1][j] = -o.sn[i]*a + o.cs[i]*b
+		}
+		r := math.Hypot(o.R[j][j], o.R[j+1][j])
+		if r == 0 {
+			o.cs[j], o.sn[j] = 1, 0
+		} else {
+			o.cs[j], o.sn[j] = o.R[j][j]/r, o.R[j+1][j]/r
+		}
+		o.R[j][j], o.R[j+1][j] = r, 0
+		o.gr[j+1] = -o.sn[j] * o.gr[j]
+		o.gr[j] = o.cs[j] * o.gr[j]
+		o.m = j + 1
+
+		// converged or lucky breakdown
+		if math.Abs(o.gr[j+1]) <= tol || o.H[j+1][j] <= MACHEPS*o.β {
+			break
+		}
+	}
+
+	// solve R⋅y = gr
+	for i := o.m - 1; i >= 0; i-- {
+		if o.R[i][i] == 0 {
+			chk.Panic("GMRES failed: the Hessenberg matrix is singular")
+		}
+		s := o.gr[i]
+		for k := i + 1; k < o.m; k++ {
+			s -= o.R[i][k] * o.y[k]
+		}
+		o.y[i] = s / o.R[i][i]
+	}
+	o.toFull(sol.dxN, o.y[:o.m])
+}
+
+// toFull computes dx = V⋅p
+func (o *nlsKrylov) toFull(dx, p la.Vector) {
+	dx.Fill(0)
+	for j := 0; j < len(p); j++ {
+		la.VecAdd(dx, 1, dx, p[j], o.V[j])
+	}
+}
+
+// slope returns fᵀ⋅J⋅dxN = -β⋅(H̄⋅y)₀
+func (o *nlsKrylov) slope() (res float64) {
+	for j := 0; j < o.m; j++ {
+		res -= o.β * o.H[0][j] * o.y[j]
+	}
+	return
+}
+
+// model returns the data of the linear model ½‖H̄⋅p - β⋅e₀‖² in the Krylov subspace
+//
+//	pN  -- Newton step (coordinates)
+//	g   -- steepest descent direction β⋅H̄ᵀ⋅e₀ (coordinates)
+//	p   -- workspace for the step (coordinates)
+//	Jg2 -- ‖H̄⋅g‖²
+func (o *nlsKrylov) model() (pN, g, p la.Vector, Jg2 float64) {
+	pN, g, p = o.y[:o.m], o.gy[:o.m], o.p[:o.m]
+	for j := 0; j < o.m; j++ {
+		g[j] = o.β * o.H[0][j]
+	}
+	for i := 0; i <= o.m; i++ {
+		var s float64
+		for j := 0; j < o.m; j++ {
+			s += o.H[i][j] * g[j]
+		}
+		Jg2 += s * s
+	}
+	return
+}
+
+// pred returns the predicted reduction ½β² - ½‖H̄⋅p - β⋅e₀‖²
+func (o *nlsKrylov) pred(p la.Vector) (res float64) {
+	res = 0.5 * o.β * o.β
+	for i := 0; i <= o.m; i++ {
+		var s float64
+		for j := 0; j < o.m; j++ {
+			s += o.H[i][j] * p[j]
+		}
+		if i == 0 {
+			s -= o.β
+		}
+		res -= 0.5 * s * s
+	}
+	return
+}
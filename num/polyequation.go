@@ -0,0 +1,373 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"github.com/lei006/gomath/chk"
+)
+
+// EqPolySolve solves a polynomial equation with real coefficients, returning the distinct
+// (complex) roots and their multiplicities
+//
+//	The equation is specified by:
+//	 c[0] + c[1] x + c[2] x² + ... + c[n] xⁿ = 0
+//	Notes:
+//	 1) see EqPolySolveComplex
+//	 2) real roots have zero imaginary parts; complex roots appear in conjugate pairs
+//	Output:
+//	 roots -- distinct roots sorted by real part and then by imaginary part
+//	 mult  -- multiplicities; Σ mult[i] = n
+func EqPolySolve(c []float64) (roots []complex128, mult []int, err error) {
+	coef := make([]complex128, len(c))
+	for i, v := range c {
+		coef[i] = complex(v, 0)
+	}
+	return eqPolySolve(coef, true)
+}
+
+// EqPolySolveComplex solves a polynomial equation with complex coefficients, returning the
+// distinct roots and their multiplicities
+//
+//	The equation is specified by:
+//	 c[0] + c[1] z + c[2] z² + ... + c[n] zⁿ = 0
+//	Notes:
+//	 1) all roots are approximated simultaneously by the Aberth-Ehrlich method [1,2]:
+//	                     p(zₖ)/p'(zₖ)
+//	      zₖ ← zₖ - ───────────────────────────────
+//	                1 - p(zₖ)/p'(zₖ) Σⱼ≠ₖ 1/(zₖ - zⱼ)
+//	    starting from points on a circle; the iterations of a root stop when |p(zₖ)| is at the
+//	    level of the rounding errors of Horner's scheme, i.e. |p(zₖ)| ≤ 4nϵ Σ |cᵢ| |zₖ|ⁱ
+//	 2) the disc centred at zₖ with radius n (|p(zₖ)| + 4nϵ Σ |cᵢ| |zₖ|ⁱ) / |p'(zₖ)| contains at
+//	    least one root [2] (a disc with radius (|p(zₖ)|/|cₙ|)^(1/n) as well; e.g. if p'(zₖ) = 0).
+//	    Approximations with overlapping discs are grouped into clusters. A cluster of size m is a
+//	    root of multiplicity m if Newton's method applied to the (m-1)-th derivative of p and
+//	    started at the centroid converges to a point z within the cluster where p, p', ...,
+//	    p⁽ᵐ⁻¹⁾ vanish to the level of rounding errors. Otherwise, e.g. for ill-conditioned simple
+//	    roots such as those of Wilkinson's polynomial, whose discs overlap, the approximations
+//	    of the cluster are returned with multiplicity 1
+//	 3) zero roots (c[0] = c[1] = ... = 0) are removed beforehand
+//	Output:
+//	 roots -- distinct roots sorted by real part and then by imaginary part
+//	 mult  -- multiplicities; Σ mult[i] = n
+//	References:
+//	 [1] Aberth O (1973) Iteration methods for finding all zeros of a polynomial simultaneously,
+//	     Mathematics of Computation, 27(122):339-344
+//	 [2] Bini DA (1996) Numerical computation of polynomial zeros by means of Aberth's method,
+//	     Numerical Algorithms, 13:179-200
+func EqPolySolveComplex(c []complex128) (roots []complex128, mult []int, err error) {
+	return eqPolySolve(c, false)
+}
+
+// eqPolySolve implements EqPolySolve and EqPolySolveComplex
+func eqPolySolve(c []complex128, realCoef bool) (roots []complex128, mult []int, err error) {
+
+	// degree
+	n := len(c) - 1
+	for n >= 0 && c[n] == 0 {
+		n--
+	}
+	if n < 1 {
+		return nil, nil, chk.Err("the degree of the polynomial must be at least 1. coefficients = %v\n", c)
+	}
+	c = c[:n+1]
+
+	// zero roots
+	nz := 0
+	for c[nz] == 0 {
+		nz++
+	}
+	c = c[nz:]
+	n -= nz
+
+	// Aberth-Ehrlich iterations
+	var z []complex128
+	if n > 0 {
+		z, err = polyAberth(c)
+		if err != nil {
+			return
+		}
+		if realCoef {
+			polyConjugates(c, z)
+		}
+		roots, mult = polyCluster(c, z, realCoef)
+	}
+	if nz > 0 {
+		roots = append(roots, 0)
+		mult = append(mult, nz)
+	}
+
+	// sort
+	idx := make([]int, len(roots))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool { return polyLess(roots[idx[i]], roots[idx[j]]) })
+	r, m := make([]complex128, len(roots)), make([]int, len(roots))
+	for i, k := range idx {
+		r[i], m[i] = roots[k], mult[k]
+	}
+	return r, m, nil
+}
+
+// polyAberth computes all roots of p(z) = Σ cᵢ zⁱ (c[0] ≠ 0) by the Aberth-Ehrlich method
+func polyAberth(c []complex128) (z []complex128, err error) {
+
+	// initial values on a circle with the radius of the geometric mean of the roots' moduli
+	n := len(c) - 1
+	r := math.Pow(cmplx.Abs(c[0])/cmplx.Abs(c[n]), 1.0/float64(n))
+	z = make([]complex128, n)
+	for k := 0; k < n; k++ {
+		z[k] = cmplx.Rect(r, 2.0*math.Pi*float64(k)/float64(n)+0.4)
+	}
+
+	// iterations
+	γ := 4.0 * float64(n) * MACHEPS
+	done := make([]bool, n)
+	maxIt := 100 + 20*n
+	for it := 0; it < maxIt; it++ {
+		ndone := 0
+		for k := 0; k < n; k++ {
+			if done[k] {
+				ndone++
+				continue
+			}
+			p, dp, s := polyEval(c, z[k])
+			if cmplx.Abs(p) <= γ*s {
+				done[k] = true
+				ndone++
+				continue
+			}
+			var sum complex128
+			for j := 0; j < n; j++ {
+				if j != k {
+					sum += 1.0 / (z[k] - z[j])
+				}
+			}
+			if dp == 0 { // pure Ehrlich correction is undefined: perturb
+				z[k] += complex(γ, γ) * complex(math.Max(1, cmplx.Abs(z[k])), 0)
+				continue
+			}
+			ratio := p / dp
+			z[k] -= ratio / (1.0 - ratio*sum)
+		}
+		if ndone == n {
+			return
+		}
+	}
+	return z, chk.Err("Aberth-Ehrlich iterations did not converge after %d iterations\n", maxIt)
+}
+
+// polyCluster groups the approximations z of the roots of p into clusters of overlapping
+// inclusion discs and returns the cluster roots and their multiplicities
+func polyCluster(c, z []complex128, realCoef bool) (roots []complex128, mult []int) {
+
+	// inclusion radii
+	n := len(z)
+	rad := make([]float64, n)
+	for k := 0; k < n; k++ {
+		rad[k] = polyRadius(c, z[k])
+	}
+
+	// union-find
+	parent := make([]int, n)
+	for k := range parent {
+		parent[k] = k
+	}
+	var find func(k int) int
+	find = func(k int) int {
+		if parent[k] != k {
+			parent[k] = find(parent[k])
+		}
+		return parent[k]
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if cmplx.Abs(z[i]-z[j]) <= rad[i]+rad[j] {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	// clusters
+	index := make(map[int]int)
+	for k := 0; k < n; k++ {
+		root := find(k)
+		i, ok := index[root]
+		if !ok {
+			i = len(roots)
+			index[root] = i
+			roots = append(roots, 0)
+			mult = append(mult, 0)
+		}
+		roots[i] += z[k]
+		mult[i]++
+	}
+
+	// members of clusters
+	members := make([][]int, len(roots))
+	for k := 0; k < n; k++ {
+		i := index[find(k)]
+		members[i] = append(members[i], k)
+	}
+
+	// refine multiple roots by Newton's method applied to the (m-1)-th derivative; split clusters
+	// that are not multiple roots
+	var sroots []complex128
+	var smult []int
+	for i := range roots {
+		m := mult[i]
+		zc := roots[i] / complex(float64(m), 0)
+		if m == 1 {
+			sroots, smult = append(sroots, zc), append(smult, 1)
+			continue
+		}
+		if zr, ok := polyMultiple(c, zc, m, z, rad, members[i]); ok {
+			sroots, smult = append(sroots, zr), append(smult, m)
+			continue
+		}
+		for _, k := range members[i] {
+			sroots, smult = append(sroots, z[k]), append(smult, 1)
+		}
+	}
+	return sroots, smult
+}
+
+// polyMultiple refines the centroid zc of a cluster of m approximations z[k] (k ∈ members) with
+// radii rad[k] by Newton's method applied to p⁽ᵐ⁻¹⁾. ok is true if the refined root lies within
+// the cluster and p, p', ..., p⁽ᵐ⁻¹⁾ vanish there to the level of rounding errors
+func polyMultiple(c []complex128, zc complex128, m int, z []complex128, rad []float64, members []int) (zr complex128, ok bool) {
+	R := 0.0
+	for _, k := range members {
+		R = math.Max(R, cmplx.Abs(z[k]-zc)+rad[k])
+	}
+	derivs := [][]complex128{c}
+	for k := 1; k < m; k++ {
+		derivs = append(derivs, polyDeriv(derivs[k-1]))
+	}
+	zr = polyPolish(derivs[m-1], zc)
+	if cmplx.Abs(zr-zc) > R {
+		return
+	}
+	γ := 4.0 * float64(len(c)-1) * MACHEPS
+	for _, d := range derivs {
+		p, _, s := polyEval(d, zr)
+		if cmplx.Abs(p) > γ*s {
+			return
+		}
+	}
+	return zr, true
+}
+
+// polyEval evaluates p(z) = Σ cᵢ zⁱ, p'(z) and s = Σ |cᵢ| |z|ⁱ by Horner's scheme
+func polyEval(c []complex128, z complex128) (p, dp complex128, s float64) {
+	n := len(c) - 1
+	p = c[n]
+	s = cmplx.Abs(c[n])
+	az := cmplx.Abs(z)
+	for i := n - 1; i >= 0; i-- {
+		dp = dp*z + p
+		p = p*z + c[i]
+		s = s*az + cmplx.Abs(c[i])
+	}
+	return
+}
+
+// polyRadius returns the radius of the inclusion disc centred at z, i.e. there is at least one
+// root of p within min(n δ / |p'(z)|, (δ / |cₙ|)^(1/n)) of z, where δ = |p(z)| + 4nϵ s(z)
+func polyRadius(c []complex128, z complex128) float64 {
+	n := float64(len(c) - 1)
+	p, dp, s := polyEval(c, z)
+	δ := cmplx.Abs(p) + 4*n*MACHEPS*s
+	r := math.Pow(δ/cmplx.Abs(c[len(c)-1]), 1/n)
+	if rdp := n * δ / cmplx.Abs(dp); dp != 0 && !math.IsNaN(rdp) && rdp < r { // p'(z) = 0 at multiple roots
+		r = rdp
+	}
+	return r
+}
+
+// polyDeriv returns the coefficients of p'(z)
+func polyDeriv(c []complex128) (d []complex128) {
+	d = make([]complex128, len(c)-1)
+	for i := 1; i < len(c); i++ {
+		d[i-1] = complex(float64(i), 0) * c[i]
+	}
+	return
+}
+
+// polyPolish improves the root z of p(z) = Σ cᵢ zⁱ by Newton's method while |p(z)| decreases
+func polyPolish(c []complex128, z complex128) complex128 {
+	if len(c) < 2 {
+		return z
+	}
+	p, dp, _ := polyEval(c, z)
+	for it := 0; it < 20 && p != 0 && dp != 0; it++ {
+		znew := z - p/dp
+		pnew, dpnew, _ := polyEval(c, znew)
+		if cmplx.Abs(pnew) >= cmplx.Abs(p) {
+			break
+		}
+		z, p, dp = znew, pnew, dpnew
+	}
+	return z
+}
+
+// polyConjugates enforces the symmetry of the roots of a polynomial with real coefficients
+//
+//	An approximation zₖ with Im(zₖ) > 0 is paired with the nearest zⱼ with Im(zⱼ) < 0 if the
+//	inclusion discs of zⱼ and conj(zₖ) intersect and if d = |zⱼ - conj(zₖ)| ≤ ½ min(|Im(zₖ)|,
+//	|Im(zⱼ)|); i.e. if zⱼ is closer to conj(zₖ) than both are to the real axis. The pair is then
+//	replaced by an exact conjugate pair. The remaining approximations whose inclusion discs
+//	intersect the real axis become real. Thus, the approximations of ill-conditioned real roots,
+//	whose large discs include complex points, are not mistaken for conjugate pairs; and complex
+//	pairs are not flattened onto the real axis. Note that a double real root approximated by a
+//	(nearly) conjugate pair is recognised later by polyCluster.
+func polyConjugates(c, z []complex128) {
+	n := len(z)
+	rad := make([]float64, n)
+	for k := 0; k < n; k++ {
+		rad[k] = polyRadius(c, z[k])
+	}
+	paired := make([]bool, n)
+	for k := 0; k < n; k++ {
+		if imag(z[k]) <= 0 {
+			continue
+		}
+		best, dmin := -1, math.Inf(1)
+		for j := 0; j < n; j++ {
+			if !paired[j] && imag(z[j]) < 0 {
+				if d := cmplx.Abs(z[j] - cmplx.Conj(z[k])); d < dmin {
+					best, dmin = j, d
+				}
+			}
+		}
+		if best >= 0 && dmin <= rad[k]+rad[best] && dmin <= 0.5*math.Min(imag(z[k]), -imag(z[best])) {
+			zm := (z[k] + cmplx.Conj(z[best])) / 2.0
+			z[k], z[best] = zm, cmplx.Conj(zm)
+			paired[k], paired[best] = true, true
+		}
+	}
+	for k := 0; k < n; k++ {
+		if !paired[k] && math.Abs(imag(z[k])) <= rad[k] {
+			z[k] = complex(real(z[k]), 0)
+		}
+	}
+}
+
+// polySort sorts z by real part and then by imaginary part
+func polySort(z []complex128) {
+	sort.Slice(z, func(i, j int) bool { return polyLess(z[i], z[j]) })
+}
+
+// polyLess compares complex numbers by real part and then by imaginary part
+func polyLess(a, b complex128) bool {
+	if real(a) != real(b) {
+		return real(a) < real(b)
+	}
+	return imag(a) < imag(b)
+}
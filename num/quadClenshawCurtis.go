@@ -0,0 +1,130 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun"
+)
+
+// ClenshawCurtisXW computes positions (xi) and weights (wi) of the Clenshaw-Curtis rule with n
+// points (including the end-points) [1,2]
+//
+//	xⱼ = -cos(θⱼ)      θⱼ = π j / N      j = 0...N      N = n - 1
+//
+//	      cⱼ  ⎡      N/2      bₖ             ⎤
+//	wⱼ = ———— ⎢ 1  -  Σ   ————————— cos(2kθⱼ) ⎥      c₀ = c_N = 1, cⱼ = 2; b_N/2 = 1, bₖ = 2
+//	       N  ⎣      k=1   4k² - 1            ⎦
+//
+//	(scaled to [x1, x2]). The rule is exact for polynomials of degree ≤ n-1, but converges about
+//	as fast as Gauss-Legendre for most analytic functions. The rules with N = 2ᵏ are nested.
+//
+//	Input:
+//	  x1 -- lower limit of integration
+//	  x2 -- upper limit of integration
+//	  n  -- number of points for quadrature formula; n = 1 gives the mid-point rule
+//	Output:
+//	  x, w -- positions (in ascending order) and weights
+//
+//	References:
+//	[1] Clenshaw CW, Curtis AR (1960) A method for numerical integration on an automatic computer,
+//	    Numerische Mathematik, 2:197-205
+//	[2] Waldvogel J (2006) Fast construction of the Fejér and Clenshaw-Curtis quadrature rules,
+//	    BIT Numerical Mathematics, 46:195-202
+func ClenshawCurtisXW(x1, x2 float64, n int) (x, w []float64) {
+	if n < 1 {
+		chk.Panic("n must be ≥ 1. n=%d is invalid\n", n)
+	}
+	xm := 0.5 * (x2 + x1)
+	xl := 0.5 * (x2 - x1)
+	if n == 1 {
+		return []float64{xm}, []float64{2 * xl}
+	}
+	x = make([]float64, n)
+	w = make([]float64, n)
+	N := n - 1
+	for j := 0; j <= N; j++ {
+		θ := math.Pi * float64(j) / float64(N)
+		z := -math.Cos(θ)
+		if 2*j == N {
+			z = 0
+		} else if 2*j > N { // exact symmetry
+			z = math.Cos(math.Pi * float64(N-j) / float64(N))
+		}
+		x[j] = xm + xl*z
+		sum := 1.0
+		for k := 1; k <= N/2; k++ {
+			bk := 2.0
+			if 2*k == N {
+				bk = 1
+			}
+			sum -= bk * math.Cos(2*float64(k)*θ) / float64(4*k*k-1)
+		}
+		cj := 2.0
+		if j == 0 || j == N {
+			cj = 1
+		}
+		w[j] = xl * cj * sum / float64(N)
+	}
+	return
+}
+
+// FejerXW computes positions (xi) and weights (wi) of Fejér's first rule with n points [2]. The
+// points are the roots of the Chebyshev polynomial Tₙ(x); thus, the end-points are not included.
+//
+//	xⱼ = -cos(θⱼ)      θⱼ = (2j+1) π / (2n)      j = 0...n-1
+//
+//	      2  ⎡       n/2     cos(2kθⱼ) ⎤
+//	wⱼ = ——— ⎢ 1 - 2  Σ    ——————————— ⎥
+//	      n  ⎣       k=1     4k² - 1   ⎦
+//
+//	(scaled to [x1, x2]). The rule is exact for polynomials of degree ≤ n-1 and is useful for
+//	integrands that cannot be evaluated at the end-points.
+//
+//	Input:
+//	  x1 -- lower limit of integration
+//	  x2 -- upper limit of integration
+//	  n  -- number of points for quadrature formula
+//	Output:
+//	  x, w -- positions (in ascending order) and weights
+func FejerXW(x1, x2 float64, n int) (x, w []float64) {
+	if n < 1 {
+		chk.Panic("n must be ≥ 1. n=%d is invalid\n", n)
+	}
+	x = make([]float64, n)
+	w = make([]float64, n)
+	xm := 0.5 * (x2 + x1)
+	xl := 0.5 * (x2 - x1)
+	for j := 0; j < n; j++ {
+		θ := math.Pi * float64(2*j+1) / float64(2*n)
+		z := -math.Cos(θ)
+		if 2*j+1 == n {
+			z = 0
+		} else if 2*j+1 > n { // exact symmetry
+			z = math.Cos(math.Pi * float64(2*(n-1-j)+1) / float64(2*n))
+		}
+		x[j] = xm + xl*z
+		sum := 1.0
+		for k := 1; k <= n/2; k++ {
+			sum -= 2 * math.Cos(2*float64(k)*θ) / float64(4*k*k-1)
+		}
+		w[j] = 2 * xl * sum / float64(n)
+	}
+	return
+}
+
+// QuadClenshawCurtis computes ∫ f(x) dx over [a, b] with the n-point Clenshaw-Curtis rule
+func QuadClenshawCurtis(a, b float64, n int, f fun.Ss) (res float64) {
+	x, w := ClenshawCurtisXW(a, b, n)
+	return quadSumXW(x, w, f)
+}
+
+// QuadFejer computes ∫ f(x) dx over [a, b] with the n-point Fejér (first) rule
+func QuadFejer(a, b float64, n int, f fun.Ss) (res float64) {
+	x, w := FejerXW(a, b, n)
+	return quadSumXW(x, w, f)
+}
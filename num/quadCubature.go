@@ -0,0 +1,396 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun"
+	"github.com/lei006/gomath/la"
+)
+
+// Cubature implements the adaptive h-cubature algorithm of Genz and Malik [1] (as improved by
+// Berntsen et al. [2]) to integrate vector-valued functions f(x) over hyper-rectangles [a, b]
+//
+//	The region with the largest error estimate is bisected along the direction with the largest
+//	fourth divided difference until the error estimate of every component of f satisfies
+//
+//	  abserr[k] ≤ max(EpsAbs, EpsRel⋅|res[k]|)
+//
+//	A degree-7 rule with a degree-5 embedded rule (2ⁿ + 2n² + 2n + 1 points) is used for n ≥ 2
+//	dimensions; the G7K15 rule is used for n = 1.
+//
+//	The zero value is ready to use. Cubature is not modified by Integrate; thus, it may be shared
+//	by several goroutines.
+//
+//	References:
+//	[1] Genz AC, Malik AA (1980) An adaptive algorithm for numerical integration over an
+//	    n-dimensional rectangular region, Journal of Computational and Applied Mathematics,
+//	    6(4):295-302
+//	[2] Berntsen J, Espelid TO, Genz A (1991) An adaptive algorithm for the approximate calculation
+//	    of multiple integrals, ACM Transactions on Mathematical Software, 17(4):437-451
+type Cubature struct {
+	EpsAbs  float64 // absolute tolerance [default = 1.49e-8]
+	EpsRel  float64 // relative tolerance [default = 1.49e-8]
+	MaxEval int     // maximum number of function evaluations [default = 1,000,000]
+}
+
+// Integrate computes the integral of f over the hyper-rectangle [a, b]
+//
+//	Input:
+//	  f    -- vector-valued integrand f(res, x) with len(res) = fdim and len(x) = len(a)
+//	  fdim -- number of components of f
+//	  a, b -- lower and upper limits [ndim]
+//	Output:
+//	  res    -- integral of each component [fdim]
+//	  abserr -- estimate of the absolute error of each component [fdim]
+//	  neval  -- number of function evaluations
+//	  err    -- non-nil if MaxEval was reached before convergence (res is still the best estimate)
+func (o *Cubature) Integrate(f fun.Vv, fdim int, a, b []float64) (res, abserr la.Vector, neval int, err error) {
+
+	// check
+	ndim := len(a)
+	if ndim < 1 || len(b) != ndim || fdim < 1 {
+		return nil, nil, 0, chk.Err("limits must have the same length ≥ 1 and fdim must be ≥ 1. len(a)=%d, len(b)=%d, fdim=%d is invalid\n", len(a), len(b), fdim)
+	}
+	for i := 0; i < ndim; i++ {
+		if math.IsInf(a[i], 0) || math.IsInf(b[i], 0) || math.IsNaN(a[i]) || math.IsNaN(b[i]) {
+			return nil, nil, 0, chk.Err("limits must be finite. a[%d]=%g, b[%d]=%g is invalid\n", i, a[i], i, b[i])
+		}
+	}
+
+	// parameters
+	epsabs, epsrel := o.EpsAbs, o.EpsRel
+	if epsabs <= 0 && epsrel <= 0 {
+		epsabs, epsrel = quadDefaultEps, quadDefaultEps
+	}
+	maxeval := o.MaxEval
+	if maxeval < 1 {
+		maxeval = 1000000
+	}
+
+	// rule and first region
+	var rule cubRule
+	if ndim == 1 {
+		rule = newCubRuleGK(fdim)
+	} else {
+		rule = newCubRuleGM(ndim, fdim)
+	}
+	r := newCubRegion(a, b, fdim)
+	rule.eval(r, f)
+	neval = rule.npts()
+	res = la.NewVector(fdim)
+	abserr = la.NewVector(fdim)
+	res.Apply(1, r.val)
+	abserr.Apply(1, r.err)
+	regions := &cubHeap{r}
+
+	// refine
+	for {
+		converged := true
+		for k := 0; k < fdim; k++ {
+			if abserr[k] > math.Max(epsabs, epsrel*math.Abs(res[k])) {
+				converged = false
+				break
+			}
+		}
+		if converged {
+			break
+		}
+		if neval+2*rule.npts() > maxeval {
+			err = chk.Err("Cubature: maximum number of function evaluations (%d) reached\n", maxeval)
+			break
+		}
+		r := heap.Pop(regions).(*cubRegion)
+		for k := 0; k < fdim; k++ {
+			res[k] -= r.val[k]
+			abserr[k] -= r.err[k]
+		}
+		r2 := r.bisect()
+		rule.eval(r, f)
+		rule.eval(r2, f)
+		neval += 2 * rule.npts()
+		heap.Push(regions, r)
+		heap.Push(regions, r2)
+		for k := 0; k < fdim; k++ {
+			res[k] += r.val[k] + r2.val[k]
+			abserr[k] += r.err[k] + r2.err[k]
+		}
+	}
+
+	// final sums without the rounding errors accumulated by the running totals
+	for k := 0; k < fdim; k++ {
+		res[k], abserr[k] = 0, 0
+	}
+	for _, reg := range *regions {
+		for k := 0; k < fdim; k++ {
+			res[k] += reg.val[k]
+			abserr[k] += reg.err[k]
+		}
+	}
+	return
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// cubRegion holds a hyper-rectangle and the results of the cubature rule
+type cubRegion struct {
+	c, h   []float64 // centre and half-widths
+	val    la.Vector // integral of each component
+	err    la.Vector // error estimate of each component
+	maxerr float64   // largest error (priority)
+	split  int       // direction to bisect
+}
+
+// newCubRegion allocates a new region from limits
+func newCubRegion(a, b []float64, fdim int) (o *cubRegion) {
+	o = &cubRegion{c: make([]float64, len(a)), h: make([]float64, len(a))}
+	for i := range a {
+		o.c[i] = 0.5 * (a[i] + b[i])
+		o.h[i] = 0.5 * (b[i] - a[i])
+	}
+	o.val = la.NewVector(fdim)
+	o.err = la.NewVector(fdim)
+	return
+}
+
+// bisect halves the region along the split direction; o becomes the lower half and the upper
+// half is returned
+func (o *cubRegion) bisect() (r *cubRegion) {
+	r = &cubRegion{c: make([]float64, len(o.c)), h: make([]float64, len(o.h))}
+	copy(r.c, o.c)
+	copy(r.h, o.h)
+	r.val = la.NewVector(len(o.val))
+	r.err = la.NewVector(len(o.err))
+	i := o.split
+	o.h[i] *= 0.5
+	r.h[i] = o.h[i]
+	o.c[i] -= o.h[i]
+	r.c[i] += r.h[i]
+	return
+}
+
+// volume computes the volume of the region
+func (o *cubRegion) volume() (vol float64) {
+	vol = 1
+	for _, h := range o.h {
+		vol *= 2 * h
+	}
+	return
+}
+
+// cubHeap implements a max-heap of regions (by error)
+type cubHeap []*cubRegion
+
+func (o cubHeap) Len() int            { return len(o) }
+func (o cubHeap) Less(i, j int) bool  { return o[i].maxerr > o[j].maxerr }
+func (o cubHeap) Swap(i, j int)       { o[i], o[j] = o[j], o[i] }
+func (o *cubHeap) Push(x interface{}) { *o = append(*o, x.(*cubRegion)) }
+func (o *cubHeap) Pop() interface{} {
+	old := *o
+	n := len(old)
+	x := old[n-1]
+	*o = old[:n-1]
+	return x
+}
+
+// cubRule defines cubature rules with error estimates
+type cubRule interface {
+	npts() int                   // number of function evaluations
+	eval(r *cubRegion, f fun.Vv) // computes r.val, r.err, r.maxerr and r.split
+}
+
+// cubRuleGM implements the Genz-Malik degree-7 rule with embedded degree-5 rule [1]
+type cubRuleGM struct {
+	ndim, fdim int
+	w, we      [5]float64 // weights of degree-7 and degree-5 rules
+}
+
+// Genz-Malik abscissae
+var (
+	cubLambda2 = math.Sqrt(9.0 / 70.0)
+	cubLambda4 = math.Sqrt(9.0 / 10.0)
+	cubLambda5 = math.Sqrt(9.0 / 19.0)
+)
+
+// newCubRuleGM returns a new Genz-Malik rule
+func newCubRuleGM(ndim, fdim int) (o *cubRuleGM) {
+	n := float64(ndim)
+	o = &cubRuleGM{ndim: ndim, fdim: fdim}
+	o.w = [5]float64{
+		(12824 - 9120*n + 400*n*n) / 19683,
+		980.0 / 6561.0,
+		(1820 - 400*n) / 19683,
+		200.0 / 19683.0,
+		6859.0 / 19683.0 / math.Pow(2, n),
+	}
+	o.we = [5]float64{
+		(729 - 950*n + 50*n*n) / 729,
+		245.0 / 486.0,
+		(265 - 100*n) / 1458,
+		25.0 / 729.0,
+		0,
+	}
+	return
+}
+
+// npts returns the number of function evaluations
+func (o *cubRuleGM) npts() int {
+	return 1<<uint(o.ndim) + 2*o.ndim*o.ndim + 2*o.ndim + 1
+}
+
+// eval applies the rule to region r
+func (o *cubRuleGM) eval(r *cubRegion, f fun.Vv) {
+	n, m := o.ndim, o.fdim
+	x := la.NewVector(n)
+	fx := la.NewVector(m)
+	var sums [5]la.Vector
+	for k := 0; k < 5; k++ {
+		sums[k] = la.NewVector(m)
+	}
+	calc := func(s la.Vector) {
+		f(fx, x)
+		for k := 0; k < m; k++ {
+			s[k] += fx[k]
+		}
+	}
+
+	// centre
+	copy(x, r.c)
+	calc(sums[0])
+	f0 := sums[0].GetCopy()
+
+	// points on the axes: fourth differences select the split direction
+	ratio := (cubLambda2 * cubLambda2) / (cubLambda4 * cubLambda4)
+	f2 := la.NewVector(m)
+	f3 := la.NewVector(m)
+	maxdiff, widest := -1.0, 0.0
+	for i := 0; i < n; i++ {
+		f2.Fill(0)
+		f3.Fill(0)
+		x[i] = r.c[i] - cubLambda2*r.h[i]
+		calc(f2)
+		x[i] = r.c[i] + cubLambda2*r.h[i]
+		calc(f2)
+		x[i] = r.c[i] - cubLambda4*r.h[i]
+		calc(f3)
+		x[i] = r.c[i] + cubLambda4*r.h[i]
+		calc(f3)
+		x[i] = r.c[i]
+		var diff float64
+		for k := 0; k < m; k++ {
+			sums[1][k] += f2[k]
+			sums[2][k] += f3[k]
+			diff += math.Abs(f2[k] - 2*f0[k] - ratio*(f3[k]-2*f0[k]))
+		}
+		if diff > maxdiff*(1+1e-10) || (diff >= maxdiff*(1-1e-10) && r.h[i] > widest) {
+			maxdiff, widest = diff, r.h[i]
+			r.split = i
+		}
+	}
+
+	// pairs of axes
+	for i := 0; i < n-1; i++ {
+		for j := i + 1; j < n; j++ {
+			for _, si := range []float64{-1, 1} {
+				for _, sj := range []float64{-1, 1} {
+					x[i] = r.c[i] + si*cubLambda4*r.h[i]
+					x[j] = r.c[j] + sj*cubLambda4*r.h[j]
+					calc(sums[3])
+				}
+			}
+			x[j] = r.c[j]
+		}
+		x[i] = r.c[i]
+	}
+
+	// corners
+	for bits := 0; bits < 1<<uint(n); bits++ {
+		for i := 0; i < n; i++ {
+			if bits&(1<<uint(i)) != 0 {
+				x[i] = r.c[i] + cubLambda5*r.h[i]
+			} else {
+				x[i] = r.c[i] - cubLambda5*r.h[i]
+			}
+		}
+		calc(sums[4])
+	}
+
+	// results
+	vol := r.volume()
+	r.maxerr = 0
+	for k := 0; k < m; k++ {
+		var r7, r5 float64
+		for l := 0; l < 5; l++ {
+			r7 += o.w[l] * sums[l][k]
+			r5 += o.we[l] * sums[l][k]
+		}
+		r.val[k] = vol * r7
+		r.err[k] = math.Abs(vol * (r7 - r5))
+		r.maxerr = math.Max(r.maxerr, r.err[k])
+	}
+}
+
+// cubRuleGK implements the one-dimensional G7K15 rule for vector-valued functions
+type cubRuleGK struct {
+	fdim int
+}
+
+// newCubRuleGK returns a new G7K15 rule
+func newCubRuleGK(fdim int) *cubRuleGK {
+	return &cubRuleGK{fdim}
+}
+
+// npts returns the number of function evaluations
+func (o *cubRuleGK) npts() int {
+	return quadGK15.npts()
+}
+
+// eval applies the rule to region r
+func (o *cubRuleGK) eval(r *cubRegion, f fun.Vv) {
+	rule := &quadGK15
+	nk := len(rule.xgk) - 1
+	m := o.fdim
+	x := la.NewVector(1)
+	fv := make([]la.Vector, 2*nk+1) // values at centre - h⋅xgk[j] (j < nk), centre and centre + h⋅xgk[j]
+	for j := 0; j <= nk; j++ {
+		fv[j] = la.NewVector(m)
+		x[0] = r.c[0] - r.h[0]*rule.xgk[j]
+		f(fv[j], x)
+		if j < nk {
+			fv[2*nk-j] = la.NewVector(m)
+			x[0] = r.c[0] + r.h[0]*rule.xgk[j]
+			f(fv[2*nk-j], x)
+		}
+	}
+	r.split = 0
+	r.maxerr = 0
+	for k := 0; k < m; k++ {
+		fc := fv[nk][k]
+		resk := rule.wgk[nk] * fc
+		resg := rule.wg[len(rule.wg)-1] * fc
+		for j := 0; j < nk; j++ {
+			fsum := fv[j][k] + fv[2*nk-j][k]
+			resk += rule.wgk[j] * fsum
+			if j%2 == 1 {
+				resg += rule.wg[j/2] * fsum
+			}
+		}
+		reskh := 0.5 * resk
+		resasc := rule.wgk[nk] * math.Abs(fc-reskh)
+		for j := 0; j < nk; j++ {
+			resasc += rule.wgk[j] * (math.Abs(fv[j][k]-reskh) + math.Abs(fv[2*nk-j][k]-reskh))
+		}
+		resasc *= r.h[0]
+		r.val[k] = resk * r.h[0]
+		r.err[k] = math.Abs((resk - resg) * r.h[0])
+		if resasc != 0 && r.err[k] != 0 {
+			r.err[k] = resasc * math.Min(1, math.Pow(200*r.err[k]/resasc, 1.5))
+		}
+		r.maxerr = math.Max(r.maxerr, r.err[k])
+	}
+}
@@ -158,3 +158,165 @@ func (o *ElementarySimpson) Integrate() (res float64) {
 	chk.Panic("achieved maximum number of iterations (n=%d)", jmax)
 	return
 }
+
+// ElementaryRomberg structure implements Romberg's method: the results of the trapezoidal rule with
+// successive halvings of the step size h are extrapolated to h → 0 by Neville's algorithm using the
+// last K = 5 refinements (see page 166 of [1]).
+type ElementaryRomberg struct {
+	trapz ElementaryTrapz // trapezoidal rule with refinement
+	eps   float64         // precision
+}
+
+// Init initializes Romberg structure
+func (o *ElementaryRomberg) Init(f fun.Ss, a, b, eps float64) {
+	o.trapz.Init(f, a, b, eps)
+	o.eps = eps
+}
+
+// Integrate performs the numerical integration
+func (o *ElementaryRomberg) Integrate() (res float64) {
+	jmax, K := 20, 5
+	h := make([]float64, jmax+1)
+	s := make([]float64, jmax)
+	h[0] = 1.0
+	o.trapz.n = 0
+	for j := 1; j <= jmax; j++ {
+		s[j-1] = o.trapz.Next()
+		if j >= K {
+			ss, dss := nevilleZero(h[j-K:j], s[j-K:j])
+			if math.Abs(dss) <= o.eps*math.Abs(ss) {
+				return ss
+			}
+		}
+		h[j] = 0.25 * h[j-1] // the error of the trapezoidal rule is a series in h²
+	}
+	chk.Panic("achieved maximum number of iterations (n=%d)", jmax)
+	return
+}
+
+// ElementaryTanhSinh structure implements the double-exponential (tanh-sinh) rule of Takahashi and
+// Mori [2]. With x = c + d⋅tanh(π/2⋅sinh(t)), c = (a+b)/2 and d = (b-a)/2:
+//
+//	 b              ∞                                    π     cosh(t)
+//	∫ f(x) dx = d  ∫  f(x(t)) w(t) dt      with  w(t) = ——— ——————————————————
+//	 a             -∞                                    2   cosh²(π/2⋅sinh(t))
+//
+//	The trapezoidal rule with step sizes h = 1, 1/2, 1/4, ... is applied to the transformed
+//	integral. Since w(t) decays double-exponentially, integrable singularities at the end-points
+//	(e.g. 1/√x or log(x)) are handled efficiently; f is never evaluated at a or b. Note that the
+//	points x(t) are rounded to floating-point numbers near a and b; thus, the best accuracy is
+//	obtained when the singular end-points are at x = 0.
+//
+//	Reference:
+//	[2] Takahashi H, Mori M (1974) Double exponential formulas for numerical integration,
+//	    Publications of the Research Institute for Mathematical Sciences, 9(3):721-741
+type ElementaryTanhSinh struct {
+	a, b float64 // limits
+	eps  float64 // precision
+	f    fun.Ss  // the function
+}
+
+// Init initializes TanhSinh structure
+func (o *ElementaryTanhSinh) Init(f fun.Ss, a, b, eps float64) {
+	o.f = f
+	o.a = a
+	o.b = b
+	o.eps = eps
+}
+
+// Integrate performs the numerical integration
+func (o *ElementaryTanhSinh) Integrate() (res float64) {
+	jmax := 12
+	c := 0.5 * (o.a + o.b)
+	d := 0.5 * (o.b - o.a)
+	if d == 0 {
+		return 0
+	}
+
+	// sum adds the contributions of t = t0, t0 + dt, t0 + 2dt, ... until the points reach the limits
+	sum := func(t0, dt float64) (s float64) {
+		nearA, nearB := true, true
+		for t := t0; nearA || nearB; t += dt {
+			u := 0.5 * math.Pi * math.Sinh(t)
+			ch := math.Cosh(u)
+			w := 0.5 * math.Pi * math.Cosh(t) / (ch * ch)
+			δ := d * math.Exp(-u) / ch // distance to the limits: d⋅(1 - tanh(u))
+			if w == 0 || δ == 0 {
+				return
+			}
+			if xa := o.a + δ; nearA && xa != o.a {
+				s += w * o.f(xa)
+			} else {
+				nearA = false
+			}
+			if xb := o.b - δ; nearB && xb != o.b {
+				s += w * o.f(xb)
+			} else {
+				nearB = false
+			}
+		}
+		return
+	}
+
+	// refinement
+	h := 1.0
+	s := 0.5*math.Pi*o.f(c) + sum(h, h)
+	olds := d * h * s
+	for j := 1; j <= jmax; j++ {
+		h *= 0.5
+		s += sum(h, 2*h)
+		res = d * h * s
+		if j > 2 {
+			if math.Abs(res-olds) < o.eps*math.Abs(olds) || (res == 0 && olds == 0) {
+				return
+			}
+		}
+		olds = res
+	}
+	chk.Panic("achieved maximum number of iterations (n=%d)", jmax)
+	return
+}
+
+// nevilleZero extrapolates the points (xa, ya) to x = 0 by Neville's algorithm and returns the
+// value y and an estimate of the error dy (see page 119 of [1])
+func nevilleZero(xa, ya []float64) (y, dy float64) {
+	n := len(xa)
+	c := make([]float64, n)
+	d := make([]float64, n)
+	copy(c, ya)
+	copy(d, ya)
+	ns := 0
+	dif := math.Abs(xa[0])
+	for i := 1; i < n; i++ { // Here we find the index ns of the closest table entry.
+		if dift := math.Abs(xa[i]); dift < dif {
+			ns = i
+			dif = dift
+		}
+	}
+	y = ya[ns] // This is the initial approximation to y.
+	ns--
+	for m := 1; m < n; m++ { // For each column of the tableau, we loop over the current c’s and d’s and update them.
+		for i := 0; i < n-m; i++ {
+			ho := xa[i]
+			hp := xa[i+m]
+			den := ho - hp
+			if den == 0 {
+				chk.Panic("Neville: two input xa's are identical")
+			}
+			w := c[i+1] - d[i]
+			den = w / den
+			d[i] = hp * den // Here the c’s and d’s are updated.
+			c[i] = ho * den
+		}
+		// After each column in the tableau is completed, we decide which correction, c or d, we want
+		// to add to our accumulating value of y, i.e., which path to take through the tableau.
+		if 2*(ns+1) < n-m {
+			dy = c[ns+1]
+		} else {
+			dy = d[ns]
+			ns--
+		}
+		y += dy
+	}
+	return
+}
@@ -178,3 +178,274 @@ func GaussJacobiXW(alf, bet float64, n int) (x, w []float64) {
 	utl.Qsort2(x, w)
 	return
 }
+
+// GaussLaguerreXW computes positions (xi) and weights (wi) to perform Gauss-Laguerre integrations
+//
+//	  ∞
+//	 ∫ xᵅ exp(-x) f(x) dx  ≈  Σ wᵢ f(xᵢ)
+//	  0
+//
+//	Input:
+//	  alf -- coefficient α > -1 of the weight function
+//	  n   -- number of points for quadrature formula
+//	Output:
+//	  x, w -- positions (in ascending order) and weights
+//	Reference:
+//	[1] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes: The Art of
+//	    Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+func GaussLaguerreXW(alf float64, n int) (x, w []float64) {
+	if n < 1 || alf <= -1 {
+		chk.Panic("n must be ≥ 1 and α must be > -1. n=%d, α=%g is invalid\n", n, alf)
+	}
+	x = make([]float64, n)
+	w = make([]float64, n)
+	N := float64(n)
+	var z, z1, p1, p2, p3, pp float64
+	for i := 0; i < n; i++ { // Loop over the desired roots.
+		if i == 0 { // Initial guess for the smallest root.
+			z = (1.0 + alf) * (3.0 + 0.92*alf) / (1.0 + 2.4*N + 1.8*alf)
+		} else if i == 1 { // Initial guess for the second root.
+			z += (15.0 + 6.25*alf) / (1.0 + 0.9*alf + 2.5*N)
+		} else { // Initial guess for the other roots.
+			ai := float64(i - 1)
+			z += ((1.0+2.55*ai)/(1.9*ai) + 1.26*ai*alf/(1.0+3.5*ai)) * (z - x[i-2]) / (1.0 + 0.3*alf)
+		}
+		it, MAXIT := 0, 100
+		for it = 0; it < MAXIT; it++ { // Refinement by Newton's method.
+			p1 = 1.0
+			p2 = 0.0
+			for j := 0; j < n; j++ { // Loop up the recurrence relation to get the Laguerre polynomial evaluated at z.
+				J := float64(j)
+				p3 = p2
+				p2 = p1
+				p1 = ((2*J+1+alf-z)*p2 - (J+alf)*p3) / (J + 1)
+			}
+			// p1 is now the desired Laguerre polynomial. We next compute pp, its derivative, by a standard relation involving also p2, the polynomial of one lower order.
+			pp = (N*p1 - (N+alf)*p2) / z
+			z1 = z
+			z = z1 - p1/pp // Newton's formula.
+			if math.Abs(z-z1) <= 1e-14*math.Max(1, z) {
+				break
+			}
+		}
+		if it == MAXIT {
+			chk.Panic("Newton's method did not converge after %d iterations", it)
+		}
+		l1, _ := math.Lgamma(alf + N)
+		l2, _ := math.Lgamma(N)
+		x[i] = z // Store the root and the weight.
+		w[i] = -math.Exp(l1-l2) / (pp * N * p2)
+	}
+	return
+}
+
+// GaussHermiteXW computes positions (xi) and weights (wi) to perform Gauss-Hermite integrations
+//
+//	  ∞
+//	 ∫ exp(-x²) f(x) dx  ≈  Σ wᵢ f(xᵢ)
+//	 -∞
+//
+//	Input:
+//	  n -- number of points for quadrature formula
+//	Output:
+//	  x, w -- positions (in ascending order) and weights
+//	Reference:
+//	[1] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes: The Art of
+//	    Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+func GaussHermiteXW(n int) (x, w []float64) {
+	if n < 1 {
+		chk.Panic("n must be ≥ 1. n=%d is invalid\n", n)
+	}
+	x = make([]float64, n)
+	w = make([]float64, n)
+	PIM4 := math.Pow(math.Pi, -0.25)
+	N := float64(n)
+	m := (n + 1) / 2 // The roots are symmetric about the origin, so we have to find only half of them.
+	var z, z1, p1, p2, p3, pp float64
+	for i := 0; i < m; i++ { // Loop over the desired roots.
+		if i == 0 { // Initial guess for the largest root.
+			z = math.Sqrt(2*N+1) - 1.85575*math.Pow(2*N+1, -0.16667)
+		} else if i == 1 { // Initial guess for the second largest root.
+			z -= 1.14 * math.Pow(N, 0.426) / z
+		} else if i == 2 { // Initial guess for the third largest root.
+			z = 1.86*z - 0.86*x[n-1]
+		} else if i == 3 { // Initial guess for the fourth largest root.
+			z = 1.91*z - 0.91*x[n-2]
+		} else { // Initial guess for the other roots.
+			z = 2.0*z - x[n-i+1]
+		}
+		it, MAXIT := 0, 100
+		for it = 0; it < MAXIT; it++ { // Refinement by Newton's method.
+			p1 = PIM4
+			p2 = 0.0
+			for j := 0; j < n; j++ { // Loop up the recurrence relation to get the (normalised) Hermite polynomial evaluated at z.
+				J := float64(j)
+				p3 = p2
+				p2 = p1
+				p1 = z*math.Sqrt(2.0/(J+1))*p2 - math.Sqrt(J/(J+1))*p3
+			}
+			// p1 is now the desired Hermite polynomial. We next compute pp, its derivative, by the relation involving p2, the polynomial of one lower order.
+			pp = math.Sqrt(2*N) * p2
+			z1 = z
+			z = z1 - p1/pp // Newton's formula.
+			if math.Abs(z-z1) <= 1e-14*math.Max(1, math.Abs(z)) {
+				break
+			}
+		}
+		if it == MAXIT {
+			chk.Panic("Newton's method did not converge after %d iterations", it)
+		}
+		x[n-1-i] = z // Store the root and its symmetric counterpart.
+		x[i] = -z
+		w[i] = 2.0 / (pp * pp) // Compute the weight and its symmetric counterpart.
+		w[n-1-i] = w[i]
+	}
+	if n%2 == 1 {
+		x[m-1] = 0
+	}
+	return
+}
+
+// GaussLobattoXW computes positions (xi) and weights (wi) to perform Gauss-Lobatto-Legendre
+// integrations. The end-points x1 and x2 are included and the rule is exact for polynomials of
+// degree ≤ 2n-3
+//
+//	The interior points are the roots of P'ₙ₋₁(x) and the weights are
+//
+//	          2 / (n (n-1) [Pₙ₋₁(xᵢ)]²)
+//
+//	(scaled to [x1, x2]). The Chebyshev-Gauss-Lobatto points are used as initial guesses for
+//	Newton's method.
+//
+//	Input:
+//	  x1 -- lower limit of integration
+//	  x2 -- upper limit of integration
+//	  n  -- number of points for quadrature formula (n ≥ 2)
+//	Output:
+//	  x, w -- positions (in ascending order) and weights
+func GaussLobattoXW(x1, x2 float64, n int) (x, w []float64) {
+	if n < 2 {
+		chk.Panic("n must be ≥ 2. n=%d is invalid\n", n)
+	}
+	x = make([]float64, n)
+	w = make([]float64, n)
+	xm := 0.5 * (x2 + x1)
+	xl := 0.5 * (x2 - x1)
+	N := n - 1
+	m := (n + 1) / 2 // The roots are symmetric in the interval, so we only have to find half of them.
+	for i := 0; i < m; i++ {
+		z := -math.Cos(math.Pi * float64(i) / float64(N))
+		var pN, dz float64
+		it, MAXIT := 0, 100
+		for it = 0; it < MAXIT; it++ { // Newton's method applied to (1-z²) P'ₙ₋₁(z)
+			p1, p2 := z, 1.0
+			for k := 2; k <= N; k++ { // Loop up the recurrence relation of Legendre polynomials.
+				p1, p2 = ((2*float64(k)-1)*z*p1-(float64(k)-1)*p2)/float64(k), p1
+			}
+			pN = p1
+			// pN must be evaluated at the converged root
+			if it > 0 && math.Abs(dz) <= 1e-14 {
+				break
+			}
+			dz = (z*p1 - p2) / (float64(n) * p1)
+			z -= dz
+		}
+		if it == MAXIT {
+			chk.Panic("Newton's method did not converge after %d iterations", it)
+		}
+		if 2*i+1 == n {
+			z = 0
+		}
+		x[i] = xm + xl*z // Scale the root to the desired interval, and put in its symmetric counterpart.
+		x[n-1-i] = xm - xl*z
+		w[i] = 2.0 * xl / (float64(N*n) * pN * pN)
+		w[n-1-i] = w[i]
+	}
+	return
+}
+
+// GaussRadauXW computes positions (xi) and weights (wi) to perform Gauss-Radau-Legendre
+// integrations. The lower limit x1 is included and the rule is exact for polynomials of degree
+// ≤ 2n-2
+//
+//	The free points are the roots of (Pₙ₋₁(x) + Pₙ(x))/(1 + x) and the weights are
+//
+//	  w₀ = 2/n²      wᵢ = (1 - xᵢ) / (n Pₙ₋₁(xᵢ))²
+//
+//	(scaled to [x1, x2]). Note: use x1 > x2 to include the upper limit of [x2, x1] instead; the
+//	weights are always positive.
+//
+//	Input:
+//	  x1 -- lower limit of integration (included)
+//	  x2 -- upper limit of integration
+//	  n  -- number of points for quadrature formula
+//	Output:
+//	  x, w -- positions (in ascending order from x1) and weights
+func GaussRadauXW(x1, x2 float64, n int) (x, w []float64) {
+	if n < 1 {
+		chk.Panic("n must be ≥ 1. n=%d is invalid\n", n)
+	}
+	x = make([]float64, n)
+	w = make([]float64, n)
+	xm := 0.5 * (x2 + x1)
+	xl := 0.5 * (x2 - x1)
+	N := float64(n)
+	x[0] = x1
+	w[0] = 2.0 * math.Abs(xl) / (N * N)
+	for i := 1; i < n; i++ {
+		z := -math.Cos(2 * math.Pi * float64(i) / (2*N - 1))
+		var pn1, dz float64
+		it, MAXIT := 0, 100
+		for it = 0; it < MAXIT; it++ { // Newton's method
+			p1, p2 := z, 1.0 // P₁ and P₀
+			for k := 2; k <= n; k++ {
+				p1, p2 = ((2*float64(k)-1)*z*p1-(float64(k)-1)*p2)/float64(k), p1
+			}
+			pn1 = p2 // Pₙ₋₁
+			// pn1 must be evaluated at the converged root
+			if it > 0 && math.Abs(dz) <= 1e-14 {
+				break
+			}
+			dz = ((1 - z) / N) * (p2 + p1) / (p2 - p1)
+			z -= dz
+		}
+		if it == MAXIT {
+			chk.Panic("Newton's method did not converge after %d iterations", it)
+		}
+		x[i] = xm + xl*z
+		w[i] = math.Abs(xl) * (1 - z) / ((N * pn1) * (N * pn1))
+	}
+	return
+}
+
+// QuadGaussLaguerre computes ∫₀^∞ xᵅ exp(-x) f(x) dx with n Gauss-Laguerre points
+func QuadGaussLaguerre(alf float64, n int, f fun.Ss) (res float64) {
+	x, w := GaussLaguerreXW(alf, n)
+	return quadSumXW(x, w, f)
+}
+
+// QuadGaussHermite computes ∫ exp(-x²) f(x) dx over (-∞, ∞) with n Gauss-Hermite points
+func QuadGaussHermite(n int, f fun.Ss) (res float64) {
+	x, w := GaussHermiteXW(n)
+	return quadSumXW(x, w, f)
+}
+
+// QuadGaussLobatto computes ∫ f(x) dx over [a, b] with n Gauss-Lobatto points
+func QuadGaussLobatto(a, b float64, n int, f fun.Ss) (res float64) {
+	x, w := GaussLobattoXW(a, b, n)
+	return quadSumXW(x, w, f)
+}
+
+// QuadGaussRadau computes ∫ f(x) dx over [a, b] with n Gauss-Radau points (including a)
+func QuadGaussRadau(a, b float64, n int, f fun.Ss) (res float64) {
+	x, w := GaussRadauXW(a, b, n)
+	return quadSumXW(x, w, f)
+}
+
+// quadSumXW computes Σ wᵢ f(xᵢ)
+func quadSumXW(x, w []float64, f fun.Ss) (res float64) {
+	for i := range x {
+		res += w[i] * f(x[i])
+	}
+	return
+}
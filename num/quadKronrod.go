@@ -0,0 +1,1009 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"sort"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun"
+)
+
+// QuadGK implements globally adaptive Gauss-Kronrod quadrature with Wynn's epsilon extrapolation
+// in pure Go, following the QUADPACK routines QAGSE, QAGIE, QAGPE, QAWOE and QAWFE [1]. Thus, no
+// cgo, Fortran compiler or function-id (fid) is needed.
+//
+//	The zero value is ready to use (with default tolerances). The methods of QuadGK do not modify
+//	the structure and all workspace is allocated per call; thus, the same QuadGK may be employed
+//	by several goroutines concurrently.
+//
+//	All methods return:
+//	  res    -- approximation to the integral
+//	  abserr -- estimate of the absolute error, which should be larger than |I - res|
+//	  neval  -- number of integrand evaluations
+//	  err    -- non-nil if the requested accuracy could not be achieved (res is still the best
+//	            estimate available) or the input is invalid
+//
+//	References:
+//	[1] Piessens R, de Doncker-Kapenga E, Überhuber CW, Kahaner DK (1983) QUADPACK: A Subroutine
+//	    Package for Automatic Integration. Springer-Verlag. 301p
+//	[2] Wynn P (1956) On a device for computing the eₘ(Sₙ) transformation, Mathematical Tables and
+//	    Other Aids to Computation, 10(54):91-96
+type QuadGK struct {
+	EpsAbs float64 // absolute tolerance [default = 1.49e-8]
+	EpsRel float64 // relative tolerance [default = 1.49e-8]
+	Limit  int     // maximum number of subintervals [default = 200]
+	Rule   int     // number of Kronrod points: 15 => G7K15; 21 => G10K21 [default = 21]
+}
+
+// Integrate computes the integral of f(x) over [a, b], where a and/or b may be infinite; e.g.
+// math.Inf(-1). Integrable singularities at the end-points are handled by extrapolation (QAGSE)
+//
+//	Infinite intervals are mapped onto (0, 1] by (QAGIE):
+//
+//	  ∫[a,∞) f(x) dx = ∫(0,1] f(a + (1-t)/t) / t² dt
+//	  ∫(-∞,b] f(x) dx = ∫(0,1] f(b - (1-t)/t) / t² dt
+//	  ∫(-∞,∞) f(x) dx = ∫(0,1] (f((1-t)/t) + f(-(1-t)/t)) / t² dt
+func (o *QuadGK) Integrate(f fun.Ss, a, b float64) (res, abserr float64, neval int, err error) {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return 0, 0, 0, chk.Err("limits of integration must not be NaN. a=%g, b=%g is invalid\n", a, b)
+	}
+	if a == b {
+		return
+	}
+	if a > b && (math.IsInf(a, 0) || math.IsInf(b, 0)) {
+		res, abserr, neval, err = o.Integrate(f, b, a)
+		return -res, abserr, neval, err
+	}
+	g, a0, b0 := f, a, b
+	switch {
+	case math.IsInf(a, -1) && math.IsInf(b, +1):
+		g = func(t float64) float64 {
+			x := (1.0 - t) / t
+			return (f(x) + f(-x)) / (t * t)
+		}
+	case math.IsInf(b, +1):
+		g = func(t float64) float64 {
+			return f(a0+(1.0-t)/t) / (t * t)
+		}
+	case math.IsInf(a, -1):
+		g = func(t float64) float64 {
+			return f(b0-(1.0-t)/t) / (t * t)
+		}
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		a, b = 0, 1
+	}
+	return o.adapt(g, nil, []float64{a, b}, 0)
+}
+
+// IntegratePts computes the integral of f(x) over the finite interval [a, b] when the locations
+// of interior singularities or discontinuities are known (QAGPE)
+//
+//	pts -- break points; those outside (a, b) are ignored
+func (o *QuadGK) IntegratePts(f fun.Ss, a, b float64, pts []float64) (res, abserr float64, neval int, err error) {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) || math.IsNaN(a) || math.IsNaN(b) {
+		return 0, 0, 0, chk.Err("limits of integration must be finite. a=%g, b=%g is invalid\n", a, b)
+	}
+	if a == b {
+		return
+	}
+	lo, hi := math.Min(a, b), math.Max(a, b)
+	list := []float64{a, b}
+	for _, p := range pts {
+		if p > lo && p < hi {
+			list = append(list, p)
+		}
+	}
+	if a < b {
+		sort.Float64s(list)
+	} else {
+		sort.Sort(sort.Reverse(sort.Float64Slice(list)))
+	}
+	return o.adapt(f, nil, list, 0)
+}
+
+// IntegrateOsc computes the integral of f(x)⋅cos(ω⋅x) or f(x)⋅sin(ω⋅x) over [a, b] where b may be
+// +Inf (QAWOE and QAWFE)
+//
+//	Finite intervals are refined by QAGSE with the modified Clenshaw-Curtis rule of QAWOE: f(x) is
+//	interpolated by Chebyshev polynomials at 25 points and the products with cos(ω⋅x) or sin(ω⋅x)
+//	are integrated exactly by means of modified Chebyshev moments. Thus, the cost does not grow
+//	with ω. For b = +Inf, the integrals over the consecutive half-periods Iₖ are computed and the
+//	limit of the (alternating) partial sums Σ Iₖ is obtained by Wynn's epsilon algorithm.
+//
+//	useSin -- use sin(ω⋅x) instead of cos(ω⋅x)
+func (o *QuadGK) IntegrateOsc(f fun.Ss, a, b, ω float64, useSin bool) (res, abserr float64, neval int, err error) {
+	if math.IsInf(a, 0) || math.IsNaN(a) || math.IsNaN(b) || math.IsInf(b, -1) || math.IsNaN(ω) {
+		return 0, 0, 0, chk.Err("a must be finite and b must be finite or +Inf. a=%g, b=%g (ω=%g) is invalid\n", a, b, ω)
+	}
+	w := math.Cos
+	if useSin {
+		w = math.Sin
+	}
+	g := func(x float64) float64 { return f(x) * w(ω*x) }
+	if ω == 0 || !math.IsInf(b, +1) {
+		if ω == 0 || a == b {
+			return o.Integrate(g, a, b)
+		}
+		return o.adapt(f, &quadOscRule{ω, useSin}, []float64{a, b}, 0)
+	}
+
+	// half-periods
+	epsabs, epsrel, limit, _ := o.params()
+	c := math.Pi / math.Abs(ω)
+	var wynn quadWynn
+	var sum, errsum float64
+	p, fact := 0.9, 1.0
+	for k := 0; k < quadMaxCycles; k++ {
+		fact *= p
+		sub := QuadGK{EpsAbs: epsabs * fact * (1.0 - p), EpsRel: epsrel, Limit: limit, Rule: o.Rule}
+		ik, ek, nk, errk := sub.adapt(g, nil, []float64{a + float64(k)*c, a + float64(k+1)*c}, 0)
+		neval += nk
+		if errk != nil && err == nil {
+			err = errk
+		}
+		sum += ik
+		errsum += ek
+		wynn.add(sum)
+		tol := math.Max(epsabs, epsrel*math.Abs(sum))
+		if k > 1 && math.Abs(ik)+ek <= 0.01*tol { // direct summation has converged
+			return sum, errsum, neval, err
+		}
+		if k > 1 {
+			reseps, abseps := wynn.extrapolate()
+			if abseps < abserr || k == 2 {
+				res, abserr = reseps, abseps+errsum
+			}
+			if abserr <= tol {
+				return res, abserr, neval, err
+			}
+		}
+	}
+	return res, abserr, neval, chk.Err("QuadGK.IntegrateOsc: extrapolation did not converge after %d cycles\n", quadMaxCycles)
+}
+
+// IntegrateAlgLog computes the integral of f(x)⋅w(x) over the finite interval [a, b] with a < b,
+// where w(x) has algebraic-logarithmic singularities at the end-points
+//
+//	w(x) = (x-a)^α ⋅ (b-x)^β ⋅ v(x)      with α, β > -1
+//
+//	integr -- indicates the weight function v(x):
+//	            integr = 1  ⇒  v(x) = 1
+//	            integr = 2  ⇒  v(x) = log(x-a)
+//	            integr = 3  ⇒  v(x) = log(b-x)
+//	            integr = 4  ⇒  v(x) = log(x-a)⋅log(b-x)
+//
+//	The weight is not integrated with the modified Clenshaw-Curtis moments of QUADPACK's QAWSE.
+//	Instead, the interval is split at its mid-point and, for α < 0 (or β < 0), the substitution
+//	x - a = tᵖ (or b - x = tᵖ) with p = 1/(1+α) removes the algebraic singularity; the resulting
+//	integrands, with at most logarithmic singularities, are then handled by QAGSE.
+func (o *QuadGK) IntegrateAlgLog(f fun.Ss, a, b, α, β float64, integr int) (res, abserr float64, neval int, err error) {
+	if !(a < b) || math.IsInf(a, 0) || math.IsInf(b, 0) || !(α > -1) || !(β > -1) || integr < 1 || integr > 4 {
+		return 0, 0, 0, chk.Err("input is invalid: a < b must be finite, α, β > -1 and 1 ≤ integr ≤ 4. a=%g, b=%g, α=%g, β=%g, integr=%d\n", a, b, α, β, integr)
+	}
+	logA := integr == 2 || integr == 4
+	logB := integr == 3 || integr == 4
+
+	// weight: u = x - a and v = b - x with logu = log(u) and logv = log(v)
+	weight := func(u, v, logu, logv float64) (w float64) {
+		w = 1
+		if α != 0 {
+			w = math.Exp(α * logu)
+		}
+		if β != 0 {
+			w *= math.Exp(β * logv)
+		}
+		if logA {
+			w *= logu
+		}
+		if logB {
+			w *= logv
+		}
+		return
+	}
+
+	// left half
+	c := 0.5 * (a + b)
+	var gl, gr fun.Ss
+	var tl, tr float64
+	if α < 0 {
+		p := 1.0 / (1.0 + α)
+		tl = math.Pow(c-a, 1.0+α)
+		gl = func(t float64) float64 {
+			u := math.Pow(t, p)
+			v := b - a - u
+			w := 1.0 // tᵖ⁻¹ uᵅ = 1
+			if β != 0 {
+				w = math.Exp(β * math.Log(v))
+			}
+			if logA {
+				w *= p * math.Log(t)
+			}
+			if logB {
+				w *= math.Log(v)
+			}
+			return p * w * f(a+u)
+		}
+	} else {
+		tl = c - a
+		gl = func(u float64) float64 {
+			v := b - a - u
+			return weight(u, v, math.Log(u), math.Log(v)) * f(a+u)
+		}
+	}
+
+	// right half
+	if β < 0 {
+		q := 1.0 / (1.0 + β)
+		tr = math.Pow(b-c, 1.0+β)
+		gr = func(t float64) float64 {
+			v := math.Pow(t, q)
+			u := b - a - v
+			w := 1.0
+			if α != 0 {
+				w = math.Exp(α * math.Log(u))
+			}
+			if logA {
+				w *= math.Log(u)
+			}
+			if logB {
+				w *= q * math.Log(t)
+			}
+			return q * w * f(b-v)
+		}
+	} else {
+		tr = b - c
+		gr = func(v float64) float64 {
+			u := b - a - v
+			return weight(u, v, math.Log(u), math.Log(v)) * f(b-v)
+		}
+	}
+
+	// integrate halves
+	epsabs, epsrel, limit, _ := o.params()
+	sub := QuadGK{EpsAbs: 0.5 * epsabs, EpsRel: epsrel, Limit: limit, Rule: o.Rule}
+	rl, el, nl, errl := sub.adapt(gl, nil, []float64{0, tl}, 0)
+	rr, er, nr, errr := sub.adapt(gr, nil, []float64{0, tr}, 0)
+	err = errl
+	if err == nil {
+		err = errr
+	}
+	return rl + rr, el + er, nl + nr, err
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// constants for QuadGK
+const (
+	quadDefaultEps   = 1.49e-8 // default tolerances, as in QUADPACK
+	quadDefaultLimit = 200     // default maximum number of subintervals
+	quadMaxCycles    = 50      // maximum number of cycles in IntegrateOsc with b = +Inf
+	quadWynnLimexp   = 50      // maximum number of elements in the epsilon table
+)
+
+// params returns the tolerances, limit and rule with default values
+func (o *QuadGK) params() (epsabs, epsrel float64, limit int, rule *quadRule) {
+	epsabs, epsrel, limit = o.EpsAbs, o.EpsRel, o.Limit
+	if epsabs <= 0 && epsrel <= 0 {
+		epsabs, epsrel = quadDefaultEps, quadDefaultEps
+	}
+	if limit < 1 {
+		limit = quadDefaultLimit
+	}
+	rule = &quadGK21
+	if o.Rule == 15 {
+		rule = &quadGK15
+	}
+	return
+}
+
+// adapt implements the globally adaptive algorithm with extrapolation of QAGSE and QAGPE [1]
+//
+//	rule  -- basic rule applied to each subinterval [may be nil => Gauss-Kronrod rule of QuadGK]
+//	pts   -- initial subdivision: pts[0] = a < pts[1] < ... < pts[n] = b (or in descending order)
+//	extra -- additional number of subintervals allowed on top of Limit
+func (o *QuadGK) adapt(f fun.Ss, rule quadEvaluator, pts []float64, extra int) (result, abserr float64, neval int, err error) {
+
+	// constants
+	epsabs, epsrel, limit, gk := o.params()
+	if rule == nil {
+		rule = gk
+	}
+	limit += extra
+	epmach := MACHEPS
+	uflow := math.SmallestNonzeroFloat64
+	oflow := math.MaxFloat64
+	n := len(pts) - 1
+	if limit < n {
+		limit = n
+	}
+	if epsabs <= 0 && epsrel < math.Max(50*epmach, 0.5e-28) {
+		return 0, 0, 0, quadError(6)
+	}
+
+	// count evaluations
+	f0 := f
+	f = func(x float64) float64 {
+		neval++
+		return f0(x)
+	}
+
+	// workspace
+	alist := make([]float64, n, limit)
+	blist := make([]float64, n, limit)
+	rlist := make([]float64, n, limit)
+	elist := make([]float64, n, limit)
+	level := make([]int, n, limit)
+	iord := make([]int, n, limit) // indices of intervals sorted by decreasing error
+
+	// first approximation to the integral
+	var resabs float64
+	crude := false
+	for i := 0; i < n; i++ {
+		area1, error1, defabs, resasc := rule.eval(f, pts[i], pts[i+1])
+		result += area1
+		abserr += error1
+		resabs += defabs
+		alist[i], blist[i], rlist[i], elist[i], iord[i] = pts[i], pts[i+1], area1, error1, i
+		if error1 == resasc && error1 != 0 {
+			level[i] = -1 // flag (reset below)
+			crude = true
+		}
+	}
+	var errsum float64
+	for i := 0; i < n; i++ {
+		if level[i] < 0 {
+			level[i] = 0
+			elist[i] = abserr // force bisection of the intervals with unreliable error estimates
+		}
+		errsum += elist[i]
+	}
+	sort.SliceStable(iord, func(i, j int) bool { return elist[iord[i]] > elist[iord[j]] })
+
+	// test on accuracy
+	ier := 0
+	dres := math.Abs(result)
+	errbnd := math.Max(epsabs, epsrel*dres)
+	if abserr <= 100*epmach*resabs && abserr > errbnd {
+		ier = 2
+	}
+	if limit == n {
+		ier = 1
+	}
+	if ier != 0 || abserr == 0 || (abserr <= errbnd && !crude) {
+		return result, abserr, neval, quadError(ier)
+	}
+
+	// initialisation
+	var wynn quadWynn
+	wynn.add(result)
+	maxerr := iord[0]
+	errmax := elist[maxerr]
+	area := result
+	abserr = oflow
+	nrmax := 0
+	ktmin, iroff1, iroff2, iroff3, ierro := 0, 0, 0, 0, 0
+	levmax := 1
+	extrap, noext := false, false
+	var erlarg, ertest, correc float64
+	ksgn := -1
+	if dres >= (1.0-50*epmach)*resabs {
+		ksgn = 1
+	}
+
+	// main loop
+	sumAll := false
+	for last := n; last < limit; last++ {
+		count := last + 1
+
+		// bisect the subinterval with the nrmax-th largest error estimate
+		lev := level[maxerr] + 1
+		a1 := alist[maxerr]
+		b1 := 0.5 * (alist[maxerr] + blist[maxerr])
+		a2 := b1
+		b2 := blist[maxerr]
+		erlast := errmax
+		area1, error1, _, defab1 := rule.eval(f, a1, b1)
+		area2, error2, _, defab2 := rule.eval(f, a2, b2)
+
+		// improve previous approximations to integral and error and test for accuracy
+		area12 := area1 + area2
+		erro12 := error1 + error2
+		errsum += erro12 - errmax
+		area += area12 - rlist[maxerr]
+		if defab1 != error1 && defab2 != error2 {
+			if math.Abs(rlist[maxerr]-area12) <= 1e-5*math.Abs(area12) && erro12 >= 0.99*errmax {
+				if extrap {
+					iroff2++
+				} else {
+					iroff1++
+				}
+			}
+			if count > 10 && erro12 > errmax {
+				iroff3++
+			}
+		}
+		errbnd = math.Max(epsabs, epsrel*math.Abs(area))
+
+		// test for roundoff error, number of subintervals and bad integrand behaviour
+		if iroff1+iroff2 >= 10 || iroff3 >= 20 {
+			ier = 2
+		}
+		if iroff2 >= 5 {
+			ierro = 3
+		}
+		if count == limit {
+			ier = 1
+		}
+		if math.Max(math.Abs(a1), math.Abs(b2)) <= (1.0+100*epmach)*(math.Abs(a2)+1000*uflow) {
+			ier = 4
+		}
+
+		// append the newly-created intervals to the list (the larger error stays at maxerr)
+		if error2 > error1 {
+			a1, b1, area1, error1, a2, b2, area2, error2 = a2, b2, area2, error2, a1, b1, area1, error1
+		}
+		alist[maxerr], blist[maxerr], rlist[maxerr], elist[maxerr], level[maxerr] = a1, b1, area1, error1, lev
+		alist = append(alist, a2)
+		blist = append(blist, b2)
+		rlist = append(rlist, area2)
+		elist = append(elist, error2)
+		level = append(level, lev)
+
+		// maintain the descending ordering of error estimates and select the next subinterval
+		iord = append(iord[:nrmax], iord[nrmax+1:]...)
+		pos := quadInsert(&iord, elist, maxerr)
+		quadInsert(&iord, elist, last)
+		if pos < nrmax {
+			nrmax = pos
+		}
+		maxerr = iord[nrmax]
+		errmax = elist[maxerr]
+		if errsum <= errbnd {
+			sumAll = true
+			break
+		}
+		if ier != 0 {
+			break
+		}
+		if last == n { // first bisection
+			erlarg = errsum
+			ertest = errbnd
+			wynn.add(area)
+			continue
+		}
+		if noext {
+			continue
+		}
+		erlarg -= erlast
+		if lev <= levmax {
+			erlarg += erro12
+		}
+		if !extrap {
+
+			// test whether the interval to be bisected next is the smallest interval
+			if level[maxerr] <= levmax {
+				continue
+			}
+			extrap = true
+			nrmax = 1
+		}
+
+		// the smallest interval has the largest error. before bisecting, decrease the sum of the
+		// errors over the larger intervals (erlarg) and perform extrapolation
+		if ierro != 3 && erlarg > ertest {
+			large := false
+			for nrmax < count {
+				maxerr = iord[nrmax]
+				errmax = elist[maxerr]
+				if level[maxerr] <= levmax {
+					large = true
+					break
+				}
+				nrmax++
+			}
+			if large {
+				continue
+			}
+		}
+
+		// perform extrapolation
+		wynn.add(area)
+		reseps, abseps := wynn.extrapolate()
+		ktmin++
+		if ktmin > 5 && abserr < 1e-3*errsum {
+			ier = 5
+		}
+		if abseps < abserr {
+			ktmin = 0
+			abserr = abseps
+			result = reseps
+			correc = erlarg
+			ertest = math.Max(epsabs, epsrel*math.Abs(reseps))
+			if abserr <= ertest {
+				break
+			}
+		}
+
+		// prepare bisection of the smallest interval
+		if wynn.n == 1 {
+			noext = true
+		}
+		if ier == 5 {
+			break
+		}
+		maxerr = iord[0]
+		errmax = elist[maxerr]
+		nrmax = 0
+		extrap = false
+		levmax++
+		erlarg = errsum
+	}
+
+	// set final result and error estimate
+	if !sumAll {
+		sumAll = abserr == oflow
+	}
+	if !sumAll {
+		divergence := true
+		if ier+ierro != 0 {
+			if ierro == 3 {
+				abserr += correc
+			}
+			if ier == 0 {
+				ier = 3
+			}
+			if result != 0 && area != 0 {
+				if abserr/math.Abs(result) > errsum/math.Abs(area) {
+					sumAll, divergence = true, false
+				}
+			} else if abserr > errsum {
+				sumAll, divergence = true, false
+			} else if area == 0 {
+				divergence = false
+			}
+		}
+
+		// test on divergence
+		if divergence && !(ksgn == -1 && math.Max(math.Abs(result), math.Abs(area)) <= resabs*0.01) {
+			if 0.01 > result/area || result/area > 100 || errsum > math.Abs(area) {
+				ier = 6
+			}
+		}
+	}
+	if sumAll {
+		result = 0
+		for _, r := range rlist {
+			result += r
+		}
+		abserr = errsum
+	}
+	if ier > 2 {
+		ier--
+	}
+	return result, abserr, neval, quadError(ier)
+}
+
+// quadInsert inserts interval i into the list of indices sorted by decreasing error and returns
+// its position
+func quadInsert(iord *[]int, elist []float64, i int) (pos int) {
+	pos = sort.Search(len(*iord), func(k int) bool { return elist[(*iord)[k]] < elist[i] })
+	*iord = append(*iord, 0)
+	copy((*iord)[pos+1:], (*iord)[pos:])
+	(*iord)[pos] = i
+	return
+}
+
+// quadError converts QUADPACK's ier code into an error
+func quadError(ier int) error {
+	switch ier {
+	case 0:
+		return nil
+	case 1:
+		return chk.Err("QuadGK: maximum number of subdivisions reached\n")
+	case 2:
+		return chk.Err("QuadGK: the occurrence of roundoff error is detected\n")
+	case 3:
+		return chk.Err("QuadGK: extremely bad integrand behaviour\n")
+	case 4:
+		return chk.Err("QuadGK: the algorithm does not converge\n")
+	case 5:
+		return chk.Err("QuadGK: the integral is probably divergent, or slowly convergent\n")
+	}
+	return chk.Err("QuadGK: the input is invalid\n")
+}
+
+// Gauss-Kronrod rules ////////////////////////////////////////////////////////////////////////////
+
+// quadEvaluator defines the basic rules applied to each subinterval by QuadGK.adapt
+type quadEvaluator interface {
+
+	// eval returns the approximation to the integral over [a, b], the error estimate and the
+	// approximations to the integrals of |f| and |f - mean(f)|
+	eval(f fun.Ss, a, b float64) (result, abserr, resabs, resasc float64)
+}
+
+// quadRule holds a Gauss-Kronrod rule on [-1, 1] with the QUADPACK layout [1]
+//
+//	xgk -- Kronrod abscissae in decreasing order; xgk[1], xgk[3], ... are the Gauss abscissae and
+//	       the last one is the centre
+//	wgk -- Kronrod weights
+//	wg  -- Gauss weights; if 2⋅len(wg) == len(xgk), the centre is a Gauss point with weight wg[last]
+type quadRule struct {
+	xgk, wgk, wg []float64
+}
+
+// npts returns the number of function evaluations of the rule
+func (o *quadRule) npts() int {
+	return 2*len(o.xgk) - 1
+}
+
+// eval applies the rule to f over [a, b] and returns the Kronrod result, the error estimate and
+// the approximations to the integrals of |f| and |f - mean(f)| (as QK15 and QK21 in [1])
+func (o *quadRule) eval(f fun.Ss, a, b float64) (result, abserr, resabs, resasc float64) {
+	epmach := MACHEPS
+	uflow := math.SmallestNonzeroFloat64
+	nk := len(o.xgk) - 1
+	centr := 0.5 * (a + b)
+	hlgth := 0.5 * (b - a)
+	dhlgth := math.Abs(hlgth)
+
+	// compute the Kronrod approximation to the integral and estimate the absolute error
+	fc := f(centr)
+	var resg float64
+	if 2*len(o.wg) == len(o.xgk) {
+		resg = o.wg[len(o.wg)-1] * fc
+	}
+	resk := o.wgk[nk] * fc
+	resabs = math.Abs(resk)
+	fv1 := make([]float64, nk)
+	fv2 := make([]float64, nk)
+	for j := 0; j < nk; j++ {
+		absc := hlgth * o.xgk[j]
+		fval1 := f(centr - absc)
+		fval2 := f(centr + absc)
+		fv1[j], fv2[j] = fval1, fval2
+		fsum := fval1 + fval2
+		if j%2 == 1 {
+			resg += o.wg[j/2] * fsum
+		}
+		resk += o.wgk[j] * fsum
+		resabs += o.wgk[j] * (math.Abs(fval1) + math.Abs(fval2))
+	}
+	reskh := resk * 0.5
+	resasc = o.wgk[nk] * math.Abs(fc-reskh)
+	for j := 0; j < nk; j++ {
+		resasc += o.wgk[j] * (math.Abs(fv1[j]-reskh) + math.Abs(fv2[j]-reskh))
+	}
+	result = resk * hlgth
+	resabs *= dhlgth
+	resasc *= dhlgth
+	abserr = math.Abs((resk - resg) * hlgth)
+	if resasc != 0 && abserr != 0 {
+		abserr = resasc * math.Min(1, math.Pow(200*abserr/resasc, 1.5))
+	}
+	if resabs > uflow/(50*epmach) {
+		abserr = math.Max(epmach*50*resabs, abserr)
+	}
+	return
+}
+
+// quadGK15 holds the 7-point Gauss and 15-point Kronrod rule
+var quadGK15 = quadRule{
+	xgk: []float64{
+		0.991455371120812639206854697526329,
+		0.949107912342758524526189684047851,
+		0.864864423359769072789712788640926,
+		0.741531185599394439863864773280788,
+		0.586087235467691130294144845693013,
+		0.405845151377397166906606412076961,
+		0.207784955007898467600689403773245,
+		0.000000000000000000000000000000000,
+	},
+	wgk: []float64{
+		0.022935322010529224963732008058970,
+		0.063092092629978553290700663189204,
+		0.104790010322250183839876322541518,
+		0.140653259715525918745189590510238,
+		0.169004726639267902826583426598550,
+		0.190350578064785409913256402421014,
+		0.204432940075298892414161999234649,
+		0.209482141084727828012999174891714,
+	},
+	wg: []float64{
+		0.129484966168869693270611432679082,
+		0.279705391489276667901467771423780,
+		0.381830050505118944950369775488975,
+		0.417959183673469387755102040816327,
+	},
+}
+
+// quadGK21 holds the 10-point Gauss and 21-point Kronrod rule
+var quadGK21 = quadRule{
+	xgk: []float64{
+		0.995657163025808080735527280689003,
+		0.973906528517171720077964012084452,
+		0.930157491355708226001207180059508,
+		0.865063366688984510732096688423493,
+		0.780817726586416897063717578345042,
+		0.679409568299024406234327365114874,
+		0.562757134668604683339000099272694,
+		0.433395394129247190799265943165784,
+		0.294392862701460198131126603103866,
+		0.148874338981631210884826001129720,
+		0.000000000000000000000000000000000,
+	},
+	wgk: []float64{
+		0.011694638867371874278064396062192,
+		0.032558162307964727478818972459390,
+		0.054755896574351996031381300244580,
+		0.075039674810919952767043140916190,
+		0.093125454583697605535065465083366,
+		0.109387158802297641899210590325805,
+		0.123491976262065851077208745178849,
+		0.134709217311473325928054001771707,
+		0.142775938577060080797094273138717,
+		0.147739104901338491374841515972068,
+		0.149445554002916905664936468389821,
+	},
+	wg: []float64{
+		0.066671344308688137593568809893332,
+		0.149451349150580593145776339657697,
+		0.219086362515982043995534934228163,
+		0.269266719309996355091226921569469,
+		0.295524224714752870173892994651338,
+	},
+}
+
+// modified Clenshaw-Curtis rule ///////////////////////////////////////////////////////////////////
+
+// quadOscRule implements the modified Clenshaw-Curtis rule for f(x)⋅cos(ω⋅x) or f(x)⋅sin(ω⋅x) of
+// QAWOE (QC25F in [1]). With x = c + h⋅t, t ∈ [-1, 1] and p = ω⋅h, f is interpolated by the
+// Chebyshev series of degrees 12 and 24, whose products with cos(p⋅t) and sin(p⋅t) are integrated
+// exactly by means of the moments computed by quadOscMoments; the difference between both
+// results estimates the error. Short intervals with |p| ≤ 2 are handled by the 15-point
+// Gauss-Kronrod rule applied to f(x)⋅cos(ω⋅x) or f(x)⋅sin(ω⋅x)
+type quadOscRule struct {
+	ω      float64 // frequency
+	useSin bool    // sin(ω⋅x) instead of cos(ω⋅x)
+}
+
+// eval applies the rule to f over [a, b]
+func (o *quadOscRule) eval(f fun.Ss, a, b float64) (result, abserr, resabs, resasc float64) {
+
+	// Gauss-Kronrod rule
+	centr := 0.5 * (a + b)
+	hlgth := 0.5 * (b - a)
+	par := o.ω * hlgth
+	if math.Abs(par) <= 2 {
+		w := math.Cos
+		if o.useSin {
+			w = math.Sin
+		}
+		return quadGK15.eval(func(x float64) float64 { return f(x) * w(o.ω*x) }, a, b)
+	}
+
+	// Chebyshev coefficients of degrees 12 and 24 from the values at tₖ = cos(k⋅π/24)
+	var fval [25]float64
+	for k := 0; k <= 24; k++ {
+		fval[k] = f(centr + hlgth*math.Cos(float64(k)*math.Pi/24))
+	}
+	var cheb12 [13]float64
+	var cheb24 [25]float64
+	quadChebCoef(cheb12[:], fval[:], 2)
+	quadChebCoef(cheb24[:], fval[:], 1)
+
+	// integrals of the Chebyshev series times cos(p⋅t) (even degrees) and sin(p⋅t) (odd degrees)
+	var mom [25]float64
+	quadOscMoments(mom[:], par)
+	var resc12, ress12, resc24, ress24 float64
+	for j := 0; j <= 24; j++ {
+		if j%2 == 0 {
+			resc24 += cheb24[j] * mom[j]
+		} else {
+			ress24 += cheb24[j] * mom[j]
+		}
+		resabs += math.Abs(cheb24[j])
+	}
+	for j := 0; j <= 12; j++ {
+		if j%2 == 0 {
+			resc12 += cheb12[j] * mom[j]
+		} else {
+			ress12 += cheb12[j] * mom[j]
+		}
+	}
+	estc := math.Abs(resc24 - resc12)
+	ests := math.Abs(ress24 - ress12)
+
+	// cos(ω⋅x) = cos(ω⋅c)⋅cos(p⋅t) - sin(ω⋅c)⋅sin(p⋅t) and sin(ω⋅x) = sin(ω⋅c)⋅cos(p⋅t) + cos(ω⋅c)⋅sin(p⋅t)
+	sc, cc := math.Sincos(o.ω * centr)
+	conc, cons := hlgth*cc, hlgth*sc
+	if o.useSin {
+		result = conc*ress24 + cons*resc24
+		abserr = math.Abs(conc*ests) + math.Abs(cons*estc)
+	} else {
+		result = conc*resc24 - cons*ress24
+		abserr = math.Abs(conc*estc) + math.Abs(cons*ests)
+	}
+	resabs *= math.Abs(hlgth)
+	resasc = resabs
+	return
+}
+
+// quadChebCoef computes the coefficients cⱼ of the Chebyshev interpolant of degree n = len(c)-1
+// from the values fval[k⋅stride] = f(cos(k⋅π/n)) with k = 0...n; i.e. f(t) ≈ Σ cⱼ⋅Tⱼ(t)
+func quadChebCoef(c, fval []float64, stride int) {
+	n := len(c) - 1
+	for j := 0; j <= n; j++ {
+		sum := 0.5 * (fval[0] + math.Cos(float64(j)*math.Pi)*fval[n*stride])
+		for k := 1; k < n; k++ {
+			sum += fval[k*stride] * math.Cos(float64(j*k)*math.Pi/float64(n))
+		}
+		c[j] = 2 * sum / float64(n)
+	}
+	c[0] *= 0.5
+	c[n] *= 0.5
+}
+
+// quadOscMoments computes the modified Chebyshev moments over [-1, 1]
+//
+//	mom[j] = ∫ Tⱼ(t)⋅cos(p⋅t) dt   for even j
+//	mom[j] = ∫ Tⱼ(t)⋅sin(p⋅t) dt   for odd j
+//
+//	(the other moments vanish by symmetry). With Eⱼ = ∫ Tⱼ(t)⋅exp(i⋅p⋅t) dt, integration by parts
+//	of 2⋅Tⱼ = T'ⱼ₊₁/(j+1) - T'ⱼ₋₁/(j-1) gives the forward recurrence
+//
+//	Eⱼ₊₁ = (j+1)/(j-1)⋅Eⱼ₋₁ + 2⋅i⋅(j+1)/p⋅Eⱼ - 2⋅Bⱼ/(i⋅p⋅(j-1))
+//
+//	where Bⱼ = exp(i⋅p) + (-1)ʲ⋅exp(-i⋅p). The recurrence is stable for j < |p| only; thus, for
+//	|p| ≤ len(mom)-1, the moments are computed by 64-point Gauss-Legendre quadrature instead
+func quadOscMoments(mom []float64, p float64) {
+	n := len(mom) - 1
+	if math.Abs(p) <= float64(n) {
+		for j := range mom {
+			mom[j] = 0
+		}
+		for k, t := range quadOscX {
+			w := quadOscW[k]
+			sp, cp := math.Sincos(p * t)
+			tjm1, tj := 1.0, t // Tⱼ₋₁ and Tⱼ
+			mom[0] += w * cp
+			for j := 1; j <= n; j++ {
+				if j%2 == 0 {
+					mom[j] += w * tj * cp
+				} else {
+					mom[j] += w * tj * sp
+				}
+				tjm1, tj = tj, 2*t*tj-tjm1
+			}
+		}
+		return
+	}
+	sp, cp := math.Sincos(p)
+	p2 := p * p
+	mom[0] = 2 * sp / p
+	mom[1] = 2 * (sp - p*cp) / p2
+	mom[2] = 4*((p2-2)*sp+2*p*cp)/(p2*p) - mom[0]
+	for j := 2; j < n; j++ {
+		J := float64(j)
+		if j%2 == 0 {
+			mom[j+1] = (J+1)/(J-1)*mom[j-1] + 2*(J+1)*mom[j]/p + 4*cp/((J-1)*p)
+		} else {
+			mom[j+1] = (J+1)/(J-1)*mom[j-1] - 2*(J+1)*mom[j]/p - 4*sp/((J-1)*p)
+		}
+	}
+}
+
+// quadOscX and quadOscW hold the 64-point Gauss-Legendre rule used by quadOscMoments
+var quadOscX, quadOscW = GaussLegendreXW(-1, 1, 64)
+
+// Wynn's epsilon algorithm ///////////////////////////////////////////////////////////////////////
+
+// quadWynn implements the epsilon algorithm [2] as in QUADPACK's QELG [1]
+type quadWynn struct {
+	tab    [quadWynnLimexp + 3]float64 // epsilon table (1-based)
+	n      int                         // number of elements in the table
+	res3la [3]float64                  // last three results
+	nres   int                         // number of calls to extrapolate
+}
+
+// add appends a new element of the sequence to the table
+func (o *quadWynn) add(v float64) {
+	o.n++
+	o.tab[o.n] = v
+}
+
+// extrapolate computes the limit of the sequence and an estimate of the absolute error
+func (o *quadWynn) extrapolate() (result, abserr float64) {
+	epmach := MACHEPS
+	oflow := math.MaxFloat64
+	e := &o.tab
+	o.nres++
+	abserr = oflow
+	result = e[o.n]
+	if o.n < 3 {
+		return result, math.Max(abserr, 5*epmach*math.Abs(result))
+	}
+	e[o.n+2] = e[o.n]
+	newelm := (o.n - 1) / 2
+	e[o.n] = oflow
+	num := o.n
+	k1 := o.n
+	for i := 1; i <= newelm; i++ {
+		k2, k3 := k1-1, k1-2
+		res := e[k1+2]
+		e0, e1, e2 := e[k3], e[k2], res
+		e1abs := math.Abs(e1)
+		delta2 := e2 - e1
+		err2 := math.Abs(delta2)
+		tol2 := math.Max(math.Abs(e2), e1abs) * epmach
+		delta3 := e1 - e0
+		err3 := math.Abs(delta3)
+		tol3 := math.Max(e1abs, math.Abs(e0)) * epmach
+		if err2 <= tol2 && err3 <= tol3 {
+			// e0, e1 and e2 are equal to within machine accuracy: convergence is assumed
+			return res, math.Max(err2+err3, 5*epmach*math.Abs(res))
+		}
+		e3 := e[k1]
+		e[k1] = e1
+		delta1 := e1 - e3
+		err1 := math.Abs(delta1)
+		tol1 := math.Max(e1abs, math.Abs(e3)) * epmach
+
+		// if two elements are very close to each other or the table is irregular, omit a part
+		// of the table by adjusting the value of n
+		if err1 <= tol1 || err2 <= tol2 || err3 <= tol3 {
+			o.n = i + i - 1
+			break
+		}
+		ss := 1.0/delta1 + 1.0/delta2 - 1.0/delta3
+		if math.Abs(ss*e1) <= 1e-4 {
+			o.n = i + i - 1
+			break
+		}
+
+		// compute a new element and eventually adjust the value of result
+		res = e1 + 1.0/ss
+		e[k1] = res
+		k1 -= 2
+		errA := err2 + math.Abs(res-e2) + err3
+		if errA <= abserr {
+			abserr = errA
+			result = res
+		}
+	}
+
+	// shift the table
+	if o.n == quadWynnLimexp {
+		o.n = 2*(quadWynnLimexp/2) - 1
+	}
+	ib := 1
+	if num%2 == 0 {
+		ib = 2
+	}
+	for i := 1; i <= newelm+1; i++ {
+		e[ib] = e[ib+2]
+		ib += 2
+	}
+	if num != o.n {
+		indx := num - o.n + 1
+		for i := 1; i <= o.n; i++ {
+			e[i] = e[indx]
+			indx++
+		}
+	}
+
+	// compute error estimate
+	if o.nres < 4 {
+		o.res3la[o.nres-1] = result
+		abserr = oflow
+	} else {
+		abserr = math.Abs(result-o.res3la[2]) + math.Abs(result-o.res3la[1]) + math.Abs(result-o.res3la[0])
+		o.res3la[0], o.res3la[1], o.res3la[2] = o.res3la[1], o.res3la[2], result
+	}
+	return result, math.Max(abserr, 5*epmach*math.Abs(result))
+}
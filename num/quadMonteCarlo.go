@@ -0,0 +1,129 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun"
+	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/rnd"
+	"github.com/lei006/gomath/utl"
+)
+
+// MonteCarlo implements (quasi-)Monte Carlo integration over hyper-rectangles [a, b]
+//
+//	The integral is estimated by Nrep independent replicates of n points each:
+//
+//	  Iᵣ = V/n Σ f(xᵢ)      res = mean(Iᵣ)      stderr = std(Iᵣ)/√Nrep
+//
+//	where V is the volume of [a, b]. The points of each replicate are:
+//
+//	  "mc"     : pseudo-random uniform points
+//	  "halton" : Halton points (rnd.HaltonPoints) with an independent random shift modulo 1
+//	             (Cranley-Patterson rotation) [1]
+//	  "lhs"    : Latin hypercube samples, i.e. independent random permutations of the n cells in
+//	             each dimension, with random positions within the cells; the cost is O(n⋅ndim)
+//
+//	Quasi-Monte Carlo ("halton") errors decrease almost as 1/n whereas Monte Carlo errors decrease
+//	as 1/√n; the latter, however, do not depend on the number of dimensions. The random numbers
+//	are generated by package rnd; thus, use rnd.Init to fix the seed.
+//
+//	References:
+//	[1] Cranley R, Patterson TNL (1976) Randomization of number theoretic methods for multiple
+//	    integration, SIAM Journal on Numerical Analysis, 13(6):904-914
+type MonteCarlo struct {
+	Method string // "mc", "halton" or "lhs" [default = "halton"]
+	Nrep   int    // number of replicates ≥ 2 for the error estimate [default = 10]
+}
+
+// Integrate computes the integral of f over [a, b] with n points per replicate
+//
+//	res    -- estimate of the integral
+//	stderr -- standard error of res (the actual error is smaller than 3⋅stderr with high probability)
+func (o *MonteCarlo) Integrate(f fun.Sv, a, b []float64, n int) (res, stderr float64) {
+
+	// check
+	ndim := len(a)
+	if ndim < 1 || len(b) != ndim || n < 2 {
+		chk.Panic("limits must have the same length ≥ 1 and n must be ≥ 2. len(a)=%d, len(b)=%d, n=%d is invalid\n", len(a), len(b), n)
+	}
+	method := o.Method
+	if method == "" {
+		method = "halton"
+	}
+	nrep := o.Nrep
+	if nrep < 2 {
+		nrep = 10
+	}
+
+	// points in the unit hypercube
+	u := la.NewVector(ndim)
+	var next func(i int) // sets u for the i-th point of the current replicate
+	var start func()     // starts a new replicate
+	switch method {
+	case "mc":
+		start = func() {}
+		next = func(i int) { rnd.Float64s(u, 0, 1) }
+	case "halton":
+		h := rnd.HaltonPoints(ndim, n)
+		shift := make([]float64, ndim)
+		start = func() { rnd.Float64s(shift, 0, 1) }
+		next = func(i int) {
+			for k := 0; k < ndim; k++ {
+				u[k] = h[k][i] + shift[k]
+				if u[k] >= 1 {
+					u[k]--
+				}
+			}
+		}
+	case "lhs":
+		cells := make([][]int, ndim)
+		for k := 0; k < ndim; k++ {
+			cells[k] = utl.IntRange(n)
+		}
+		start = func() { // Fisher-Yates shuffle of each row
+			for k := 0; k < ndim; k++ {
+				for i := n - 1; i > 0; i-- {
+					j := rnd.Int(0, i)
+					cells[k][i], cells[k][j] = cells[k][j], cells[k][i]
+				}
+			}
+		}
+		next = func(i int) {
+			for k := 0; k < ndim; k++ {
+				u[k] = (float64(cells[k][i]) + rnd.Float64(0, 1)) / float64(n)
+			}
+		}
+	default:
+		chk.Panic("method %q is not available. Options are: \"mc\", \"halton\" or \"lhs\"\n", method)
+	}
+
+	// replicates
+	vol := 1.0
+	for k := 0; k < ndim; k++ {
+		vol *= b[k] - a[k]
+	}
+	x := la.NewVector(ndim)
+	var m2 float64 // sum of squared deviations from the mean (Welford's algorithm)
+	for r := 0; r < nrep; r++ {
+		start()
+		var s float64
+		for i := 0; i < n; i++ {
+			next(i)
+			for k := 0; k < ndim; k++ {
+				x[k] = a[k] + (b[k]-a[k])*u[k]
+			}
+			s += f(x)
+		}
+		ir := vol * s / float64(n)
+		d := ir - res
+		res += d / float64(r+1)
+		m2 += d * (ir - res)
+	}
+	stderr = math.Sqrt(m2 / float64(nrep-1) / float64(nrep))
+	return
+}
@@ -0,0 +1,189 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun"
+	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/utl"
+)
+
+// SparseGrid holds the points and weights of Smolyak's sparse-grid cubature over [-1, 1]ⁿ [1,2]
+//
+//	The rule of level ℓ is given by the combination technique:
+//
+//	                                   ⎛ n-1 ⎞
+//	  A(ℓ,n) =     Σ       (-1)^(ℓ+n-|i|) ⎜     ⎟ Q(i₁) ⊗ ... ⊗ Q(iₙ)
+//	         ℓ+1 ≤ |i| ≤ ℓ+n              ⎝ℓ+n-|i|⎠
+//
+//	where Q(i) are one-dimensional rules, iₖ ≥ 1 and |i| = i₁ + ... + iₙ. Identical points are
+//	merged. A(ℓ,n) integrates exactly all polynomials of total degree ≤ 2ℓ+1.
+//
+//	References:
+//	[1] Smolyak SA (1963) Quadrature and interpolation formulas for tensor products of certain
+//	    classes of functions, Soviet Mathematics Doklady, 4:240-243
+//	[2] Gerstner T, Griebel M (1998) Numerical integration using sparse grids, Numerical
+//	    Algorithms, 18:209-232
+type SparseGrid struct {
+	Ndim  int         // number of dimensions
+	Level int         // level ℓ
+	Rule  string      // one-dimensional rules: "cc" or "gl"
+	X     [][]float64 // points [Ndim][Npts]
+	W     []float64   // weights [Npts]
+}
+
+// NewSparseGrid generates a Smolyak sparse grid
+//
+//	ndim  -- number of dimensions
+//	level -- level ℓ ≥ 0
+//	rule  -- one-dimensional rules:
+//	           "cc" : nested Clenshaw-Curtis with 1, 3, 5, 9, 17, ... (2ⁱ⁻¹+1) points
+//	           "gl" : Gauss-Legendre with 1, 3, 5, 7, ... (2i-1) points
+func NewSparseGrid(ndim, level int, rule string) (o *SparseGrid) {
+	if ndim < 1 || level < 0 {
+		chk.Panic("ndim must be ≥ 1 and level must be ≥ 0. ndim=%d, level=%d is invalid\n", ndim, level)
+	}
+	var gen func(i int) (x, w []float64)
+	switch rule {
+	case "cc":
+		gen = sparseClenshawCurtis
+	case "gl":
+		gen = func(i int) (x, w []float64) { return GaussLegendreXW(-1, 1, 2*i-1) }
+	default:
+		chk.Panic("rule %q is not available. Options are: \"cc\" or \"gl\"\n", rule)
+	}
+	o = &SparseGrid{Ndim: ndim, Level: level, Rule: rule}
+
+	// one-dimensional rules
+	x1d := make([][]float64, level+2)
+	w1d := make([][]float64, level+2)
+	for i := 1; i <= level+1; i++ {
+		x1d[i], w1d[i] = gen(i)
+	}
+
+	// combination technique
+	o.X = make([][]float64, ndim)
+	index := make(map[string]int)
+	midx := make([]int, ndim)
+	pos := make([]int, ndim)
+	key := make([]byte, 0, 16*ndim)
+	var add func(k, sum int)
+	add = func(k, sum int) {
+		if k < ndim-1 {
+			for i := 1; sum+i+(ndim-k-1) <= level+ndim; i++ {
+				midx[k] = i
+				add(k+1, sum+i)
+			}
+			return
+		}
+		for i := utl.Imax(1, level+1-sum); sum+i <= level+ndim; i++ {
+			midx[k] = i
+			q := level + ndim - sum - i
+			coef := fun.NegOnePowN(q) * fun.Binomial(ndim-1, q)
+			for l := range pos {
+				pos[l] = 0
+			}
+			for { // tensor product
+				w := coef
+				key = key[:0]
+				for l := 0; l < ndim; l++ {
+					xl := x1d[midx[l]][pos[l]]
+					w *= w1d[midx[l]][pos[l]]
+					key = strconv.AppendInt(key, int64(math.Round(xl*1e13)), 36)
+					key = append(key, ',')
+				}
+				if j, ok := index[string(key)]; ok {
+					o.W[j] += w
+				} else {
+					index[string(key)] = len(o.W)
+					o.W = append(o.W, w)
+					for l := 0; l < ndim; l++ {
+						o.X[l] = append(o.X[l], x1d[midx[l]][pos[l]])
+					}
+				}
+				l := 0
+				for ; l < ndim; l++ {
+					pos[l]++
+					if pos[l] < len(x1d[midx[l]]) {
+						break
+					}
+					pos[l] = 0
+				}
+				if l == ndim {
+					break
+				}
+			}
+		}
+	}
+	add(0, 0)
+	return
+}
+
+// Npts returns the number of points
+func (o *SparseGrid) Npts() int {
+	return len(o.W)
+}
+
+// Integrate computes the integral of f over the hyper-rectangle [a, b]
+func (o *SparseGrid) Integrate(f fun.Sv, a, b []float64) (res float64) {
+	if len(a) != o.Ndim || len(b) != o.Ndim {
+		chk.Panic("limits must have length equal to %d. len(a)=%d, len(b)=%d is invalid\n", o.Ndim, len(a), len(b))
+	}
+	x := la.NewVector(o.Ndim)
+	vol := 1.0
+	for l := 0; l < o.Ndim; l++ {
+		vol *= 0.5 * (b[l] - a[l])
+	}
+	for j, w := range o.W {
+		for l := 0; l < o.Ndim; l++ {
+			x[l] = 0.5*(a[l]+b[l]) + 0.5*(b[l]-a[l])*o.X[l][j]
+		}
+		res += w * f(x)
+	}
+	return res * vol
+}
+
+// QuadSparseGrid integrates f over [a, b] with Smolyak sparse grids of increasing level until
+// the difference between the results of two consecutive levels is smaller than
+// max(epsabs, epsrel⋅|res|)
+//
+//	rule     -- one-dimensional rules; see NewSparseGrid
+//	maxLevel -- maximum level
+//	res      -- result of the last level
+//	abserr   -- difference between the last two levels
+//	neval    -- total number of function evaluations
+//	err      -- non-nil if maxLevel was reached before convergence
+func QuadSparseGrid(f fun.Sv, a, b []float64, rule string, epsabs, epsrel float64, maxLevel int) (res, abserr float64, neval int, err error) {
+	ndim := len(a)
+	prev := 0.0
+	for level := 0; level <= maxLevel; level++ {
+		grid := NewSparseGrid(ndim, level, rule)
+		res = grid.Integrate(f, a, b)
+		neval += grid.Npts()
+		if level > 0 {
+			abserr = math.Abs(res - prev)
+			if abserr <= math.Max(epsabs, epsrel*math.Abs(res)) {
+				return
+			}
+		}
+		prev = res
+	}
+	return res, abserr, neval, chk.Err("QuadSparseGrid: maximum level (%d) reached\n", maxLevel)
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// sparseClenshawCurtis returns the Clenshaw-Curtis rule of index i ≥ 1 on [-1, 1] with 1 point
+// (i = 1) or 2ⁱ⁻¹+1 points (i > 1)
+func sparseClenshawCurtis(i int) (x, w []float64) {
+	if i == 1 {
+		return ClenshawCurtisXW(-1, 1, 1)
+	}
+	return ClenshawCurtisXW(-1, 1, 1<<uint(i-1)+1)
+}
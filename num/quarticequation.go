@@ -0,0 +1,98 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math/cmplx"
+	"sort"
+)
+
+// EqQuarticSolveComplex solves a quartic equation, returning all (complex) roots
+//
+//	The equation is specified by:
+//	 x⁴ + a x³ + b x² + c x + d = 0
+//	Notes:
+//	 1) Ferrari's method is applied to the depressed quartic y⁴ + p y² + q y + r = 0 with
+//	    x = y - a/4; the auxiliary root m of the resolvent cubic
+//	       8 m³ + 8 p m² + (2 p² - 8 r) m - q² = 0
+//	    with the largest modulus is selected and the quartic is factored into two quadratics
+//	 2) the roots are polished by Newton's method in complex arithmetic
+//	 3) real roots have zero imaginary parts; complex roots appear in conjugate pairs
+//	Output:
+//	 z[i] -- roots sorted by real part and then by imaginary part
+func EqQuarticSolveComplex(a, b, c, d float64) (z1, z2, z3, z4 complex128) {
+
+	// depressed quartic
+	aa := a * a
+	p := b - 3.0*aa/8.0
+	q := c - a*b/2.0 + aa*a/8.0
+	r := d - a*c/4.0 + aa*b/16.0 - 3.0*aa*aa/256.0
+
+	// solve for y
+	var y [4]complex128
+	if q == 0 { // biquadratic: y⁴ + p y² + r = 0
+		D := cmplx.Sqrt(complex(p*p-4.0*r, 0))
+		for k, s := range []complex128{(complex(-p, 0) + D) / 2.0, (complex(-p, 0) - D) / 2.0} {
+			y[2*k] = cmplx.Sqrt(s)
+			y[2*k+1] = -y[2*k]
+		}
+	} else {
+
+		// auxiliary root of the resolvent cubic: m³ + p m² + (p²/4 - r) m - q²/8 = 0
+		m1, m2, m3 := EqCubicSolveComplex(p, p*p/4.0-r, -q*q/8.0)
+		m := m1
+		for _, mm := range []complex128{m2, m3} {
+			if cmplx.Abs(mm) > cmplx.Abs(m) {
+				m = mm
+			}
+		}
+
+		// factorisation: (y² + s y + p/2 + m - q/(2s)) (y² - s y + p/2 + m + q/(2s)) with s = √(2m)
+		s := cmplx.Sqrt(2.0 * m)
+		for k, σ := range []complex128{1, -1} {
+			B := σ * s
+			C := complex(p/2.0, 0) + m - σ*complex(q, 0)/(2.0*s)
+			D := cmplx.Sqrt(B*B - 4.0*C)
+			y[2*k] = (-B + D) / 2.0
+			y[2*k+1] = (-B - D) / 2.0
+		}
+	}
+
+	// polish and clean up
+	coef := []complex128{complex(d, 0), complex(c, 0), complex(b, 0), complex(a, 0), 1}
+	var z [4]complex128
+	for k := 0; k < 4; k++ {
+		z[k] = polyPolish(coef, y[k]-complex(a/4.0, 0))
+	}
+	polyConjugates(coef, z[:])
+	polySort(z[:])
+	return z[0], z[1], z[2], z[3]
+}
+
+// EqQuarticSolveReal solves a quartic equation, ignoring the complex answers.
+//
+//	The equation is specified by:
+//	 x⁴ + a x³ + b x² + c x + d = 0
+//	Notes:
+//	 1) the roots are computed by EqQuarticSolveComplex
+//	 2) as in EqCubicSolveReal, multiple roots are returned only once; roots within the
+//	    inclusion radius of each other are considered multiple roots (see EqPolySolve)
+//	Output:
+//	 x[i] -- distinct real roots in ascending order; unused values are zero
+//	 nx   -- number of distinct real roots: 0, 1, 2, 3 or 4
+func EqQuarticSolveReal(a, b, c, d float64) (x1, x2, x3, x4 float64, nx int) {
+	z1, z2, z3, z4 := EqQuarticSolveComplex(a, b, c, d)
+	coef := []complex128{complex(d, 0), complex(c, 0), complex(b, 0), complex(a, 0), 1}
+	roots, _ := polyCluster(coef, []complex128{z1, z2, z3, z4}, true)
+	var x [4]float64
+	for _, z := range roots {
+		if imag(z) == 0 {
+			x[nx] = real(z)
+			nx++
+		}
+	}
+	sort.Float64s(x[:nx])
+	return x[0], x[1], x[2], x[3], nx
+}
@@ -0,0 +1,495 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+)
+
+// This file implements alternatives to Brent.Root for bracketed scalar roots. All methods require
+// f(xa) ⋅ f(xb) ≤ 0, use the Tol and MaxIt parameters of Brent, update the statistics NumIter,
+// NumFeval, NumJeval and NumHeval, and return an error (instead of panicking) on failure.
+//
+//	The convergence criterion is the same as in Brent.Root, i.e. the root is located within
+//	±tolAct with tolAct = 2⋅ϵ⋅|x| + Tol/2.
+//
+//	REFERENCES:
+//	[1] Ridders CJF (1979) A new algorithm for computing a single root of a real continuous
+//	    function, IEEE Transactions on Circuits and Systems, 26(11):979-980
+//	[2] Dowell M, Jarratt P (1971) A modified regula falsi method for computing the root of an
+//	    equation, BIT Numerical Mathematics, 11:168-174
+//	[3] Alefeld GE, Potra FA, Shi Y (1995) Algorithm 748: enclosing zeros of continuous
+//	    functions, ACM Transactions on Mathematical Software, 21(3):327-344
+//	[4] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes:
+//	    The Art of Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+
+// Ridders solves y(x) = 0 for x in [xa, xb] with f(xa) * f(xb) ≤ 0 using Ridders' method [1]
+//
+//	The function is evaluated at the midpoint and the root of the exponentially scaled secant is
+//	taken; the method converges quadratically and the root remains bracketed.
+func (o *Brent) Ridders(xa, xb float64) (res float64, err error) {
+
+	// initialise
+	fl, fh, done, res, err := o.rootStart(xa, xb)
+	if done || err != nil {
+		return
+	}
+	xl, xh := xa, xb
+	res = 0.5 * (xl + xh)
+
+	// iterations
+	var xm, fm, s, xnew, fnew float64
+	for o.NumIter = 0; o.NumIter < o.MaxIt; o.NumIter++ {
+		xm = 0.5 * (xl + xh)
+		fm = o.ffcn(xm)
+		o.NumFeval++
+		s = math.Sqrt(fm*fm - fl*fh)
+		if s == 0 {
+			return xm, nil
+		}
+		xnew = xm + (xm-xl)*sgn(1, fl-fh)*fm/s
+		if o.Verbose {
+			io.Pf("%4d%23.15e%23.15e%23.15e\n", o.NumIter, xnew, fm, math.Abs(xh-xl))
+		}
+		res = xnew
+		fnew = o.ffcn(res)
+		o.NumFeval++
+		if fnew == 0 {
+			return
+		}
+
+		// keep root bracketed
+		if sgn(fm, fnew) != fm {
+			xl, fl = xm, fm
+			xh, fh = res, fnew
+		} else if sgn(fl, fnew) != fl {
+			xh, fh = res, fnew
+		} else {
+			xl, fl = res, fnew
+		}
+		if math.Abs(xh-xl) <= 2*o.tolAct(res) {
+			return
+		}
+	}
+	return res, chk.Err("Ridders: fail to converge after %d iterations\n", o.NumIter)
+}
+
+// Illinois solves y(x) = 0 for x in [xa, xb] with f(xa) * f(xb) ≤ 0 using the Illinois variant of
+// the regula falsi (false position) method [2]
+//
+//	The function value at the endpoint that is retained twice in a row is halved; this avoids the
+//	one-sided (linear) convergence of the plain regula falsi method. A bisection step is taken
+//	whenever three steps did not halve the bracket, e.g. when |f| is much larger at one end.
+func (o *Brent) Illinois(xa, xb float64) (res float64, err error) {
+
+	// initialise
+	fa, fb, done, res, err := o.rootStart(xa, xb)
+	if done || err != nil {
+		return
+	}
+	a, b := xa, xb
+
+	// iterations
+	side := 0
+	var w [3]float64 // widths of the bracket one, two and three steps ago
+	var fc float64
+	for o.NumIter = 0; o.NumIter < o.MaxIt; o.NumIter++ {
+		if o.NumIter >= 3 && math.Abs(b-a) > 0.5*w[2] {
+			res = a + (b-a)/2
+		} else {
+			res = (fa*b - fb*a) / (fa - fb)
+			if tol := o.tolAct(res); math.Abs(res-a) < tol { // keep off the ends as in Brent.Root
+				res = a + sgn(tol, b-a)
+			} else if math.Abs(b-res) < tol {
+				res = b - sgn(tol, b-a)
+			}
+		}
+		w[0], w[1], w[2] = math.Abs(b-a), w[0], w[1]
+		fc = o.ffcn(res)
+		o.NumFeval++
+		if o.Verbose {
+			io.Pf("%4d%23.15e%23.15e%23.15e\n", o.NumIter, res, fc, math.Abs(b-a))
+		}
+		if fc == 0 {
+			return
+		}
+		if fc*fb > 0 {
+			b, fb = res, fc
+			if side == -1 {
+				fa /= 2
+			}
+			side = -1
+		} else {
+			a, fa = res, fc
+			if side == +1 {
+				fb /= 2
+			}
+			side = +1
+		}
+		if math.Abs(b-a) <= 2*o.tolAct(res) {
+			return
+		}
+	}
+	return res, chk.Err("Illinois: fail to converge after %d iterations\n", o.NumIter)
+}
+
+// Toms748 solves y(x) = 0 for x in [xa, xb] with f(xa) * f(xb) ≤ 0 using the method of Alefeld,
+// Potra and Shi [3] (Algorithm 748)
+//
+//	Each iteration performs two steps of inverse cubic (or Newton-quadratic) interpolation, a
+//	double-length secant step and, if the bracket was not halved, a bisection step. The
+//	asymptotic efficiency index is about 1.65 and the bracket always shrinks to zero width.
+//	NumIter counts these iterations (the initial secant and quadratic steps being the first
+//	one); thus, MaxIt is comparable to that of Brent.Root although each iteration evaluates f
+//	three or four times.
+func (o *Brent) Toms748(xa, xb float64) (res float64, err error) {
+
+	// initialise
+	if xb < xa {
+		xa, xb = xb, xa
+	}
+	fa, fb, done, res, err := o.rootStart(xa, xb)
+	if done || err != nil {
+		return
+	}
+	w := &toms748{o: o, a: xa, b: xb, fa: fa, fb: fb, e: 1e5, fe: 1e5, fd: 1e5}
+	defer func() {
+		res = w.a
+		if math.Abs(w.fb) < math.Abs(w.fa) {
+			res = w.b
+		}
+		if w.fa == 0 {
+			res = w.a
+		}
+	}()
+
+	// first two steps: secant and quadratic
+	o.NumIter = 1
+	w.bracket(w.secant())
+	if w.converged() {
+		return
+	}
+	c := w.quadratic(2)
+	w.e, w.fe = w.d, w.fd
+	w.bracket(c)
+
+	// iterations
+	for !w.converged() {
+		if o.NumIter >= o.MaxIt {
+			err = chk.Err("Toms748: fail to converge after %d iterations\n", o.NumIter)
+			return
+		}
+		o.NumIter++
+		a0, b0 := w.a, w.b
+
+		// two interpolation steps
+		for k := 0; k < 2; k++ {
+			if w.fa == w.fb || w.fa == w.fd || w.fa == w.fe || w.fb == w.fd || w.fb == w.fe || w.fd == w.fe {
+				c = w.quadratic(2 + k)
+			} else {
+				c = w.cubic()
+			}
+			w.e, w.fe = w.d, w.fd
+			w.bracket(c)
+			if w.converged() {
+				return
+			}
+		}
+
+		// double-length secant step
+		u, fu := w.a, w.fa
+		if math.Abs(w.fb) < math.Abs(w.fa) {
+			u, fu = w.b, w.fb
+		}
+		c = u - 2*(fu/(w.fb-w.fa))*(w.b-w.a)
+		if math.Abs(c-u) > (w.b-w.a)/2 {
+			c = w.a + (w.b-w.a)/2
+		}
+		w.e, w.fe = w.d, w.fd
+		w.bracket(c)
+		if w.converged() {
+			return
+		}
+
+		// bisection if the bracket was not reduced enough
+		if w.b-w.a < 0.5*(b0-a0) {
+			continue
+		}
+		w.e, w.fe = w.d, w.fd
+		w.bracket(w.a + (w.b-w.a)/2)
+	}
+	return
+}
+
+// NewtonSafe solves y(x) = 0 for x in [xa, xb] with f(xa) * f(xb) ≤ 0 using Newton's method
+// safeguarded by bisection [4]
+//
+//	The derivative function Jfcn must be given. A bisection step is taken whenever the Newton
+//	update would leave the bracket or would not reduce the bracket fast enough.
+func (o *Brent) NewtonSafe(xa, xb float64) (res float64, err error) {
+	return o.safeguarded(xa, xb, false)
+}
+
+// HalleySafe solves y(x) = 0 for x in [xa, xb] with f(xa) * f(xb) ≤ 0 using Halley's method
+// safeguarded by bisection
+//
+//	Both derivative functions Jfcn and Hfcn must be given. Halley's update converges cubically:
+//
+//	  δx = (f/f') / (1 - f⋅f''/(2⋅f'²))
+//
+//	and falls back to Newton's update when the correction factor is not positive.
+func (o *Brent) HalleySafe(xa, xb float64) (res float64, err error) {
+	return o.safeguarded(xa, xb, true)
+}
+
+// safeguarded implements NewtonSafe and HalleySafe
+func (o *Brent) safeguarded(xa, xb float64, halley bool) (res float64, err error) {
+
+	// check
+	if o.Jfcn == nil {
+		return 0, chk.Err("safeguarded Newton/Halley methods require derivative function Jfcn\n")
+	}
+	if halley && o.Hfcn == nil {
+		return 0, chk.Err("Halley's method requires second derivative function Hfcn\n")
+	}
+
+	// initialise: orient the search such that f(xl) < 0
+	fl, _, done, res, err := o.rootStart(xa, xb)
+	if done || err != nil {
+		return
+	}
+	xl, xh := xa, xb
+	if fl > 0 {
+		xl, xh = xb, xa
+	}
+	res = 0.5 * (xa + xb)
+	dxold := math.Abs(xb - xa)
+	dx := dxold
+	f, df, d2f := o.ffcn(res), o.Jfcn(res), 0.0
+	o.NumFeval++
+	o.NumJeval++
+	if halley {
+		d2f = o.Hfcn(res)
+		o.NumHeval++
+	}
+
+	// iterations
+	var tmp float64
+	for o.NumIter = 0; o.NumIter < o.MaxIt; o.NumIter++ {
+
+		// bisection if Newton is out of range or not decreasing fast enough
+		if ((res-xh)*df-f)*((res-xl)*df-f) > 0 || math.Abs(2*f) > math.Abs(dxold*df) {
+			dxold = dx
+			dx = 0.5 * (xh - xl)
+			res = xl + dx
+			if xl == res {
+				return
+			}
+
+			// Newton or Halley step
+		} else {
+			dxold = dx
+			dx = f / df
+			if halley {
+				den := 1 - f*d2f/(2*df*df)
+				if den > 0 {
+					tmp = res - dx/den
+					if (tmp-xl)*(tmp-xh) < 0 { // stays inside bracket
+						dx /= den
+					}
+				}
+			}
+			tmp = res
+			res -= dx
+			if tmp == res {
+				return
+			}
+		}
+		if o.Verbose {
+			io.Pf("%4d%23.15e%23.15e%23.15e\n", o.NumIter, res, f, math.Abs(dx))
+		}
+		if math.Abs(dx) < o.tolAct(res) {
+			return
+		}
+
+		// evaluate and keep root bracketed
+		f, df = o.ffcn(res), o.Jfcn(res)
+		o.NumFeval++
+		o.NumJeval++
+		if halley {
+			d2f = o.Hfcn(res)
+			o.NumHeval++
+		}
+		if f == 0 {
+			return
+		}
+		if f < 0 {
+			xl = res
+		} else {
+			xh = res
+		}
+	}
+	return res, chk.Err("safeguarded Newton/Halley: fail to converge after %d iterations\n", o.NumIter)
+}
+
+// Roots finds all roots in [xmin, xmax] that can be bracketed by sign changes of f over n
+// subintervals of equal length (see Bracket.RootScan)
+//
+//	method -- "ridders", "illinois", "toms748", "newton" or "halley"
+//
+//	NOTE: roots of even multiplicity (without sign change) and pairs of roots closer than
+//	      (xmax-xmin)/n may be missed
+func (o *Brent) Roots(xmin, xmax float64, n int, method string) (roots []float64, err error) {
+
+	// select method
+	var solve func(xa, xb float64) (float64, error)
+	switch method {
+	case "ridders":
+		solve = o.Ridders
+	case "illinois":
+		solve = o.Illinois
+	case "toms748":
+		solve = o.Toms748
+	case "newton":
+		solve = o.NewtonSafe
+	case "halley":
+		solve = o.HalleySafe
+	default:
+		return nil, chk.Err("method %q is not available. options: ridders, illinois, toms748, newton, halley\n", method)
+	}
+
+	// scan and solve
+	bracket := NewBracket(o.ffcn)
+	xas, xbs, err := bracket.RootScan(xmin, xmax, n)
+	if err != nil {
+		return
+	}
+	roots = make([]float64, len(xas))
+	for i := 0; i < len(xas); i++ {
+		roots[i], err = solve(xas[i], xbs[i])
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// rootStart evaluates f at the endpoints and checks the bracket
+func (o *Brent) rootStart(xa, xb float64) (fa, fb float64, done bool, res float64, err error) {
+	fa = o.ffcn(xa)
+	fb = o.ffcn(xb)
+	o.NumFeval, o.NumJeval, o.NumHeval, o.NumIter = 2, 0, 0, 0
+	if fa == 0 {
+		return fa, fb, true, xa, nil
+	}
+	if fb == 0 {
+		return fa, fb, true, xb, nil
+	}
+	if fa*fb > 0 || math.IsNaN(fa*fb) {
+		err = chk.Err("root must be bracketed: xa=%g, xb=%g, fa=%g, fb=%g => fa * fb > 0\n", xa, xb, fa, fb)
+	}
+	return
+}
+
+// tolAct returns the actual tolerance around x
+func (o *Brent) tolAct(x float64) float64 {
+	return 2.0*MACHEPS*math.Abs(x) + o.Tol/2.0
+}
+
+// toms748 holds the data of the Toms748 method: a < b bracket the root; d and e are the
+// previous bracket ends (or estimates) with function values fd and fe
+type toms748 struct {
+	o              *Brent
+	a, b, d, e     float64
+	fa, fb, fd, fe float64
+}
+
+// converged checks the width of the bracket
+func (w *toms748) converged() bool {
+	return w.fa == 0 || w.b-w.a <= 2*w.o.tolAct(math.Min(math.Abs(w.a), math.Abs(w.b)))
+}
+
+// bracket evaluates f at c ∈ (a, b) and shrinks the bracket; d receives the discarded end
+func (w *toms748) bracket(c float64) {
+	tol := 2 * MACHEPS
+	if w.b-w.a < 2*tol*math.Abs(w.a) {
+		c = w.a + (w.b-w.a)/2
+	} else if c <= w.a+math.Abs(w.a)*tol {
+		c = w.a + math.Abs(w.a)*tol
+	} else if c >= w.b-math.Abs(w.b)*tol {
+		c = w.b - math.Abs(w.b)*tol
+	}
+	fc := w.o.ffcn(c)
+	w.o.NumFeval++
+	if w.o.Verbose {
+		io.Pf("%4d%23.15e%23.15e%23.15e\n", w.o.NumIter, c, fc, w.b-w.a)
+	}
+	if fc == 0 {
+		w.a, w.fa = c, 0
+		w.d, w.fd = 0, 0
+		return
+	}
+	if sgn(1, w.fa)*sgn(1, fc) < 0 {
+		w.d, w.fd = w.b, w.fb
+		w.b, w.fb = c, fc
+	} else {
+		w.d, w.fd = w.a, w.fa
+		w.a, w.fa = c, fc
+	}
+}
+
+// secant returns the secant point in (a, b) or the midpoint
+func (w *toms748) secant() float64 {
+	tol := 5 * MACHEPS
+	c := w.a - (w.fa/(w.fb-w.fa))*(w.b-w.a)
+	if c <= w.a+math.Abs(w.a)*tol || c >= w.b-math.Abs(w.b)*tol {
+		return (w.a + w.b) / 2
+	}
+	return c
+}
+
+// quadratic performs count Newton steps on the quadratic interpolating (a, b, d)
+func (w *toms748) quadratic(count int) float64 {
+	B := (w.fb - w.fa) / (w.b - w.a)
+	A := (w.fd - w.fb) / (w.d - w.b)
+	A = (A - B) / (w.d - w.a)
+	if A == 0 || math.IsNaN(A) || math.IsInf(A, 0) {
+		return w.secant()
+	}
+	c := w.b
+	if sgn(1, A)*sgn(1, w.fa) > 0 {
+		c = w.a
+	}
+	for i := 0; i < count; i++ {
+		c -= (w.fa + (B+A*(c-w.b))*(c-w.a)) / (B + A*(2*c-w.a-w.b))
+	}
+	if c <= w.a || c >= w.b || math.IsNaN(c) {
+		return w.secant()
+	}
+	return c
+}
+
+// cubic returns the inverse cubic interpolation of (a, b, d, e) or a quadratic step
+func (w *toms748) cubic() float64 {
+	a, b, d, e := w.a, w.b, w.d, w.e
+	fa, fb, fd, fe := w.fa, w.fb, w.fd, w.fe
+	q11 := (d - e) * fd / (fe - fd)
+	q21 := (b - d) * fb / (fd - fb)
+	q31 := (a - b) * fa / (fb - fa)
+	d21 := (b - d) * fd / (fd - fb)
+	d31 := (a - b) * fb / (fb - fa)
+	q22 := (d21 - q11) * fb / (fe - fb)
+	q32 := (d31 - q21) * fa / (fd - fa)
+	d32 := (d31 - q21) * fd / (fd - fa)
+	q33 := (d32 - q22) * fa / (fe - fa)
+	c := q31 + q32 + q33 + a
+	if c <= a || c >= b || math.IsNaN(c) {
+		return w.quadratic(3)
+	}
+	return c
+}
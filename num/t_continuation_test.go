@@ -0,0 +1,268 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+)
+
+func TestContinuation01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Continuation01. fold point of x² + λ = 0")
+
+	// u = [x; λ]
+	F := func(f, u la.Vector) {
+		f[0] = u[0]*u[0] + u[1]
+	}
+	Jdense := func(J *la.Matrix, u la.Vector) {
+		J.Set(0, 0, 2*u[0])
+		J.Set(0, 1, 1)
+	}
+
+	// pseudo-arclength passes the fold at (0, 0)
+	o := NewContinuation(1, F)
+	o.SetJacobianFunction(nil, Jdense)
+	o.Verbose = chk.Verbose
+	o.Ds = 0.1
+	o.DsMax = 0.2
+	o.LambdaMin = -1
+	err := o.Run([]float64{1.1}, -1)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("npoints = %d  nfeval = %d  njeval = %d\n", len(o.Branch), o.Nfeval, o.Njeval)
+	for _, p := range o.Branch {
+		chk.Float64(tst, "F", 1e-10, p.X[0]*p.X[0]+p.Lambda, 0)
+	}
+	last := o.Branch[len(o.Branch)-1]
+	chk.Bool(tst, "x < 0 at the end", last.X[0] < 0, true)
+	chk.Int(tst, "number of special points", len(o.Special), 1)
+	if len(o.Special) == 1 {
+		s := o.Special[0]
+		io.Pforan("%s point at x=%v λ=%v\n", s.Kind, s.X, s.Lambda)
+		chk.String(tst, s.Kind, "fold")
+		chk.Float64(tst, "x(fold)", 1e-12, s.X[0], 0)
+		chk.Float64(tst, "λ(fold)", 0.02, s.Lambda, 0)
+	}
+
+	// natural parameterisation stops at the fold
+	o.Method = "natural"
+	o.Ds = 0.1
+	err = o.Run([]float64{1.1}, -1)
+	io.Pforan("natural: %v\n", err)
+	if err == nil {
+		tst.Errorf("natural parameterisation should fail at the fold\n")
+		return
+	}
+	last = o.Branch[len(o.Branch)-1]
+	chk.Float64(tst, "λ(last)", 1e-6, last.Lambda, 0)
+	chk.Float64(tst, "dx/dλ", 1e-12, last.T[0], -0.5/last.X[0])
+}
+
+func TestContinuation02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Continuation02. pitchfork bifurcation")
+
+	// trivial branch x = 0 with a branch point at λ = 1
+	F := func(f, u la.Vector) {
+		x0, x1, λ := u[0], u[1], u[2]
+		f[0] = (λ-1)*x0 - x0*x0*x0
+		f[1] = x1 - x0*x0
+	}
+	Jsparse := func(J *la.Triplet, u la.Vector) {
+		x0, λ := u[0], u[2]
+		J.Start()
+		J.Put(0, 0, λ-1-3*x0*x0)
+		J.Put(0, 1, 0)
+		J.Put(0, 2, x0)
+		J.Put(1, 0, -2*x0)
+		J.Put(1, 1, 1)
+		J.Put(1, 2, 0)
+	}
+
+	for _, numerical := range []bool{false, true} {
+		for _, method := range []string{"arclength", "natural"} {
+			o := NewContinuation(2, F)
+			if !numerical {
+				o.SetJacobianFunction(Jsparse, nil)
+			}
+			o.Verbose = chk.Verbose
+			o.Method = method
+			o.DetectBranch = true
+			o.Ds = 0.13
+			o.LambdaMax = 2
+			err := o.Run([]float64{0.01, 0.01}, 0)
+			if err != nil {
+				tst.Errorf("%v\n", err)
+				return
+			}
+			io.Pforan("%s numerical=%v: npoints = %d  nfeval = %d  njeval = %d\n", method, numerical, len(o.Branch), o.Nfeval, o.Njeval)
+			chk.Int(tst, "number of special points", len(o.Special), 1)
+			if len(o.Special) == 1 {
+				s := o.Special[0]
+				io.Pforan("%s point at x=%v λ=%v\n", s.Kind, s.X, s.Lambda)
+				chk.String(tst, s.Kind, "branch")
+				chk.Float64(tst, "λ(branch)", 1e-10, s.Lambda, 1)
+				chk.Array(tst, "x(branch)", 1e-10, s.X, []float64{0, 0})
+			}
+			o.Free()
+		}
+	}
+}
+
+func TestContinuation03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Continuation03. Bratu problem: u'' + λ exp(u) = 0")
+
+	// finite differences with n interior points
+	n := 20
+	h := 1.0 / float64(n+1)
+	F := func(f, u la.Vector) {
+		λ := u[n]
+		for i := 0; i < n; i++ {
+			f[i] = -2*u[i] + h*h*λ*math.Exp(u[i])
+			if i > 0 {
+				f[i] += u[i-1]
+			}
+			if i < n-1 {
+				f[i] += u[i+1]
+			}
+		}
+	}
+	Jsparse := func(J *la.Triplet, u la.Vector) {
+		λ := u[n]
+		J.Start()
+		for i := 0; i < n; i++ {
+			J.Put(i, i, -2+h*h*λ*math.Exp(u[i]))
+			J.Put(i, n, h*h*math.Exp(u[i]))
+			if i > 0 {
+				J.Put(i, i-1, 1)
+			}
+			if i < n-1 {
+				J.Put(i, i+1, 1)
+			}
+		}
+	}
+	Jdense := func(J *la.Matrix, u la.Vector) {
+		J.Fill(0)
+		λ := u[n]
+		for i := 0; i < n; i++ {
+			J.Set(i, i, -2+h*h*λ*math.Exp(u[i]))
+			J.Set(i, n, h*h*math.Exp(u[i]))
+			if i > 0 {
+				J.Set(i, i-1, 1)
+			}
+			if i < n-1 {
+				J.Set(i, i+1, 1)
+			}
+		}
+	}
+
+	var λfold float64
+	for k, dense := range []bool{false, true} {
+		o := NewContinuation(n, F)
+		if dense {
+			o.SetJacobianFunction(nil, Jdense)
+		} else {
+			o.SetJacobianFunction(Jsparse, nil)
+		}
+		o.Verbose = chk.Verbose
+		o.Ds = 0.5
+		o.LambdaMin = 1
+		err := o.Run(la.NewVector(n), 1)
+		if err != nil {
+			tst.Errorf("%v\n", err)
+			return
+		}
+		λmax := 0.0
+		for _, p := range o.Branch {
+			λmax = math.Max(λmax, p.Lambda)
+		}
+		io.Pforan("dense=%v: npoints = %d  λmax = %v  special = %d\n", dense, len(o.Branch), λmax, len(o.Special))
+		chk.Int(tst, "number of special points", len(o.Special), 1)
+		if len(o.Special) == 1 {
+			chk.String(tst, o.Special[0].Kind, "fold")
+			chk.Float64(tst, "λ(fold)", 0.02, o.Special[0].Lambda, 3.51)
+			if k == 0 {
+				λfold = o.Special[0].Lambda
+			} else {
+				chk.Float64(tst, "λ(fold): dense == sparse", 1e-8, o.Special[0].Lambda, λfold)
+			}
+		}
+		o.Free()
+	}
+}
+
+func TestContinuation04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Continuation04. branch point hit exactly: x(λ - x²) = 0, y - λ = 0")
+
+	// trivial branch x = 0, y = λ with a branch point at λ = 0 where det([J; tᵀ]) is linear in λ
+	F := func(f, u la.Vector) {
+		x, y, λ := u[0], u[1], u[2]
+		f[0] = x * (λ - x*x)
+		f[1] = y - λ
+	}
+	Jsparse := func(J *la.Triplet, u la.Vector) {
+		x, λ := u[0], u[2]
+		J.Start()
+		J.Put(0, 0, λ-3*x*x)
+		J.Put(0, 2, x)
+		J.Put(1, 1, 1)
+		J.Put(1, 2, -1)
+	}
+	Jdense := func(J *la.Matrix, u la.Vector) {
+		x, λ := u[0], u[2]
+		J.Fill(0)
+		J.Set(0, 0, λ-3*x*x)
+		J.Set(0, 2, x)
+		J.Set(1, 1, 1)
+		J.Set(1, 2, -1)
+	}
+
+	for _, jac := range []string{"sparse", "dense", "numerical"} {
+		for _, method := range []string{"arclength", "natural"} {
+			for _, λ0 := range []float64{-1, -0.75, -0.5} {
+				o := NewContinuation(2, F)
+				switch jac {
+				case "sparse":
+					o.SetJacobianFunction(Jsparse, nil)
+				case "dense":
+					o.SetJacobianFunction(nil, Jdense)
+				}
+				o.Verbose = chk.Verbose
+				o.Method = method
+				o.DetectBranch = true
+				o.Ds = 0.1
+				o.LambdaMax = 1
+				err := o.Run([]float64{0, λ0}, λ0)
+				if err != nil {
+					tst.Errorf("%s %s λ0=%g: %v\n", jac, method, λ0, err)
+					return
+				}
+				io.Pforan("%9s %9s λ0=%5.2f: npoints = %d  special = %d\n", jac, method, λ0, len(o.Branch), len(o.Special))
+				chk.Bool(tst, "λ(last) > 1", o.Branch[len(o.Branch)-1].Lambda > 1, true)
+				chk.Int(tst, "number of special points", len(o.Special), 1)
+				if len(o.Special) == 1 {
+					s := o.Special[0]
+					chk.String(tst, s.Kind, "branch")
+					chk.Float64(tst, "λ(branch)", 1e-12, s.Lambda, 0)
+					chk.Array(tst, "x(branch)", 1e-12, s.X, []float64{0, 0})
+				}
+				o.Free()
+			}
+		}
+	}
+}
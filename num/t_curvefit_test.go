@@ -0,0 +1,231 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+)
+
+func Test_curvefit01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("curvefit01. exponential decay: LM and GN; analytical and numerical Jacobian")
+
+	// data: y = 5 exp(-0.3 x) + 1 with deterministic perturbations
+	ndata := 30
+	x := make([]float64, ndata)
+	y := make([]float64, ndata)
+	for i := 0; i < ndata; i++ {
+		x[i] = 0.5 * float64(i)
+		y[i] = 5*math.Exp(-0.3*x[i]) + 1 + 0.02*math.Sin(7*float64(i))
+	}
+	model := func(p la.Vector, xi float64) float64 {
+		return p[0]*math.Exp(-p[1]*xi) + p[2]
+	}
+	jac := func(J *la.Matrix, p la.Vector) {
+		for i, xi := range x {
+			e := math.Exp(-p[1] * xi)
+			J.Set(i, 0, e)
+			J.Set(i, 1, -p[0]*xi*e)
+			J.Set(i, 2, 1)
+		}
+	}
+
+	// reference solution
+	ref := NewCurveFitXY(x, y, 3, model)
+	ref.SetJacobian(jac)
+	pref := []float64{1, 1, 0}
+	err := ref.Solve(pref)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("p = %v  σ = %v  χ² = %v  (niter=%d)\n", ref.P, ref.Sigma, ref.Chi2, ref.Niter)
+	chk.Array(tst, "p", 0.05, ref.P, []float64{5, 0.3, 1})
+	chk.Int(tst, "dof", ref.Dof, ndata-3)
+	chk.Float64(tst, "redχ²", 1e-15, ref.RedChi2, ref.Chi2/float64(ndata-3))
+
+	// gradient of χ² must vanish
+	f := la.NewVector(ndata)
+	J := la.NewMatrix(ndata, 3)
+	ref.model(f, ref.P)
+	jac(J, ref.P)
+	for k := 0; k < 3; k++ {
+		g := 0.0
+		for i := 0; i < ndata; i++ {
+			g += J.Get(i, k) * (y[i] - f[i])
+		}
+		chk.Float64(tst, io.Sf("g%d", k), 1e-9, g, 0)
+	}
+
+	// other methods and numerical Jacobian
+	for _, method := range []string{"lm", "gn"} {
+		for _, numerical := range []bool{false, true} {
+			o := NewCurveFitXY(x, y, 3, model)
+			o.Method = method
+			if !numerical {
+				o.SetJacobian(jac)
+			}
+			p := []float64{1, 1, 0}
+			err = o.Solve(p)
+			if err != nil {
+				tst.Errorf("%s: %v\n", method, err)
+				return
+			}
+			io.Pf("%s numerical=%v: p = %v (niter=%d, nfeval=%d, njeval=%d)\n", method, numerical, p, o.Niter, o.Nfeval, o.Njeval)
+			chk.Array(tst, "p", 1e-7, p, ref.P)
+			chk.Array(tst, "σ", 1e-6, o.Sigma, ref.Sigma)
+		}
+	}
+}
+
+func Test_curvefit02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("curvefit02. linear model against LinFitSigma; confidence intervals")
+
+	x := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	y := []float64{2.1, 3.9, 6.2, 7.8, 10.1, 12.2, 13.8, 16.1, 18.0, 20.2}
+	a, b, σa, σb, χ2 := LinFitSigma(x, y)
+	io.Pforan("LinFitSigma: a=%v b=%v σa=%v σb=%v χ²=%v\n", a, b, σa, σb, χ2)
+
+	o := NewCurveFitXY(x, y, 2, func(p la.Vector, xi float64) float64 { return p[0] + p[1]*xi })
+	o.SetJacobian(func(J *la.Matrix, p la.Vector) {
+		for i, xi := range x {
+			J.Set(i, 0, 1)
+			J.Set(i, 1, xi)
+		}
+	})
+	p := []float64{0, 0}
+	err := o.Solve(p)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("CurveFit: p=%v σ=%v χ²=%v\n", o.P, o.Sigma, o.Chi2)
+	chk.Array(tst, "p", 1e-8, o.P, []float64{a, b})
+	chk.Float64(tst, "χ²", 1e-10, o.Chi2, χ2)
+
+	// the unit weights are relative; thus σ are scaled by √(χ²/ν) as in LinFitSigma
+	chk.Array(tst, "σ", 1e-8, o.Sigma, []float64{σa, σb})
+
+	// absolute σ = 1 gives the unscaled standard errors
+	s := math.Sqrt(χ2 / float64(len(x)-2))
+	o.SetSigma([]float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, true)
+	err = o.Solve(p)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	chk.Array(tst, "σ(abs)", 1e-8, o.Sigma, []float64{σa / s, σb / s})
+
+	// confidence intervals: normal quantile for absolute σ
+	lo, hi := o.ConfInterval(0.95)
+	chk.Float64(tst, "z", 1e-6, (hi[1]-lo[1])/(2*o.Sigma[1]), 1.959963985)
+
+	// Student's t quantile for relative σ (t₀.₉₇₅ with 8 dof)
+	o.SetSigma([]float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, false)
+	err = o.Solve(p)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	lo, hi = o.ConfInterval(0.95)
+	io.Pforan("95%% intervals: lo=%v hi=%v\n", lo, hi)
+	chk.Float64(tst, "t", 1e-6, (hi[0]-lo[0])/(2*o.Sigma[0]), 2.306004135)
+	chk.Float64(tst, "t(1 dof)", 1e-10, studentTinv(0.975, 1), math.Tan(0.475*math.Pi))
+}
+
+func Test_curvefit03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("curvefit03. bounds and fixed parameters")
+
+	// Michaelis-Menten: y = Vmax x / (Km + x)
+	// Puromycin data (treated)
+	x := []float64{0.02, 0.02, 0.06, 0.06, 0.11, 0.11, 0.22, 0.22, 0.56, 0.56, 1.10, 1.10}
+	y := []float64{76, 47, 97, 107, 123, 139, 159, 152, 191, 201, 207, 200}
+	model := func(p la.Vector, xi float64) float64 { return p[0] * xi / (p[1] + xi) }
+
+	// unconstrained
+	o := NewCurveFitXY(x, y, 2, model)
+	p := []float64{200, 0.1}
+	err := o.Solve(p)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("free:    p = %v  σ = %v\n", p, o.Sigma)
+	chk.Array(tst, "p", 1e-2, p, []float64{212.68, 0.06412})
+	chk.Array(tst, "σ", 1e-2, o.Sigma, []float64{6.947, 0.008281})
+
+	// Vmax ≤ 200 is active
+	o.SetBounds([]float64{0, 0}, []float64{200, math.Inf(1)})
+	p = []float64{150, 0.1}
+	err = o.Solve(p)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("bounded: p = %v  σ = %v\n", p, o.Sigma)
+	chk.Float64(tst, "Vmax", 1e-15, p[0], 200)
+
+	// with Vmax fixed at 200, Km must be the same
+	q := NewCurveFitXY(x, y, 2, model)
+	q.SetFixed([]bool{true, false})
+	pq := []float64{200, 0.1}
+	err = q.Solve(pq)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("fixed:   p = %v  σ = %v\n", pq, q.Sigma)
+	chk.Float64(tst, "Vmax", 1e-15, pq[0], 200)
+	chk.Float64(tst, "Km", 1e-8, pq[1], p[1])
+	chk.Float64(tst, "σVmax", 1e-15, q.Sigma[0], 0)
+	chk.Int(tst, "dof", q.Dof, len(x)-1)
+}
+
+func Test_curvefit04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("curvefit04. Rosenbrock residuals and rectangular Jacobian")
+
+	// residuals r = y - f: f₀ = -10(p₁ - p₀²), f₁ = p₀, with y = {0, 1}
+	o := NewCurveFit([]float64{0, 1}, 2, func(f, p la.Vector) {
+		f[0] = -10 * (p[1] - p[0]*p[0])
+		f[1] = p[0]
+	})
+	p := []float64{-1.2, 1}
+	err := o.Solve(p)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("p = %v (niter=%d)\n", p, o.Niter)
+	chk.Array(tst, "p", 1e-8, p, []float64{1, 1})
+
+	// Jacobian of 3 functions of 2 variables
+	ffcn := func(f, x la.Vector) {
+		f[0] = x[0] * x[1]
+		f[1] = math.Sin(x[0])
+		f[2] = x[1] * x[1]
+	}
+	xx := []float64{0.5, 2}
+	fx := la.NewVector(3)
+	ffcn(fx, xx)
+	var J la.Triplet
+	Jacobian(&J, ffcn, xx, fx, la.NewVector(3))
+	chk.Deep2(tst, "J", 1e-7, J.ToDense().GetDeep2(), [][]float64{
+		{2, 0.5},
+		{math.Cos(0.5), 0},
+		{0, 4},
+	})
+}
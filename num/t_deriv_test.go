@@ -6,11 +6,13 @@ package num
 
 import (
 	"math"
+	"math/cmplx"
 	"testing"
 
 	"github.com/lei006/gomath/chk"
 	"github.com/lei006/gomath/fun"
 	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
 )
 
 func TestDeriv01(tst *testing.T) {
@@ -334,3 +336,174 @@ func TestDeriv04(tst *testing.T) {
 		}
 	}
 }
+
+func TestDeriv05(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Deriv05. Ridders' method with error estimates")
+
+	fcns := []fun.Ss{
+		func(x float64) float64 { return math.Exp(x) },
+		func(x float64) float64 { return math.Sin(1 / x) },
+		func(x float64) float64 { return 1e6 * math.Tan(x/1e3) },
+		func(x float64) float64 { return x * math.Sqrt(x) },
+	}
+	dfcns := []fun.Ss{
+		func(x float64) float64 { return math.Exp(x) },
+		func(x float64) float64 { return -math.Cos(1/x) / (x * x) },
+		func(x float64) float64 { return 1e3 / math.Pow(math.Cos(x/1e3), 2) },
+		func(x float64) float64 { return 1.5 * math.Sqrt(x) },
+	}
+	d2fcns := []fun.Ss{
+		func(x float64) float64 { return math.Exp(x) },
+		func(x float64) float64 { return (2*x*math.Cos(1/x) - math.Sin(1/x)) / (x * x * x * x) },
+		func(x float64) float64 { return 2 * math.Tan(x/1e3) / math.Pow(math.Cos(x/1e3), 2) },
+		func(x float64) float64 { return 0.75 / math.Sqrt(x) },
+	}
+	xs := []float64{1, 0.3, 1200, 2}
+	h0s := []float64{0, 0.01, 0, 0}
+	for k, f := range fcns {
+		x := xs[k]
+		d, e := DerivRidders(x, h0s[k], f)
+		ana := dfcns[k](x)
+		io.Pforan("k=%d: df/dx = %23.15e  ana = %23.15e  err = %.2e  errEst = %.2e\n", k, d, ana, math.Abs(d-ana), e)
+		chk.Float64(tst, "df/dx", 1e-9*math.Max(1, math.Abs(ana)), d, ana)
+		chk.Bool(tst, "err ≤ 100 errEst", math.Abs(d-ana) <= 100*e+1e-15*math.Abs(ana), true)
+
+		d2, e2 := SecondDerivRidders(x, h0s[k], f)
+		ana2 := d2fcns[k](x)
+		io.Pforan("     d²f/dx² = %23.15e  ana = %23.15e  err = %.2e  errEst = %.2e\n", d2, ana2, math.Abs(d2-ana2), e2)
+		chk.Float64(tst, "d²f/dx²", 1e-6*math.Max(1, math.Abs(ana2)), d2, ana2)
+	}
+
+	// too large user step for sin(1/x): Ridders recovers while DerivCen5 fails
+	f, df := fcns[1], dfcns[1]
+	d, _ := DerivRidders(0.3, 0.1, f)
+	dcen := DerivCen5(0.3, 0.1, f)
+	io.Pforan("h=0.1: Ridders error = %.2e  Cen5 error = %.2e\n", math.Abs(d-df(0.3)), math.Abs(dcen-df(0.3)))
+	chk.Float64(tst, "df/dx(h0=0.1)", 1e-8, d, df(0.3))
+}
+
+func TestDeriv06(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Deriv06. complex-step derivatives")
+
+	// f(x) = exp(x) / √(sin³x + cos³x) [3]
+	f := func(z complex128) complex128 {
+		s, c := cmplx.Sin(z), cmplx.Cos(z)
+		return cmplx.Exp(z) / cmplx.Sqrt(s*s*s+c*c*c)
+	}
+	x := 1.5
+	s, c := math.Sin(x), math.Cos(x)
+	den := s*s*s + c*c*c
+	ana := math.Exp(x) / math.Sqrt(den) * (1 - 0.5*(3*s*s*c-3*c*c*s)/den)
+	d := DerivComplexStep(x, f)
+	io.Pforan("df/dx = %23.15e  ana = %23.15e\n", d, ana)
+	chk.Float64(tst, "df/dx", 1e-14*math.Abs(ana), d, ana)
+
+	// polynomial
+	d = DerivComplexStep(2, func(z complex128) complex128 { return z*z*z - 2*z })
+	chk.Float64(tst, "d(x³ - 2x)/dx", 1e-14, d, 10)
+}
+
+func TestDeriv07(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Deriv07. gradient and Hessian of scalar functions of vectors")
+
+	// Rosenbrock function
+	f := func(x la.Vector) float64 {
+		return 100*math.Pow(x[1]-x[0]*x[0], 2) + math.Pow(1-x[0], 2) + x[0]*x[1]*x[2]
+	}
+	x := la.Vector{-1.2, 1, 0.5}
+	xcopy := x.GetCopy()
+	gana := []float64{
+		-400*x[0]*(x[1]-x[0]*x[0]) - 2*(1-x[0]) + x[1]*x[2],
+		200*(x[1]-x[0]*x[0]) + x[0]*x[2],
+		x[0] * x[1],
+	}
+	Hana := [][]float64{
+		{1200*x[0]*x[0] - 400*x[1] + 2, -400*x[0] + x[2], x[1]},
+		{-400*x[0] + x[2], 200, x[0]},
+		{x[1], x[0], 0},
+	}
+
+	// gradient
+	g := la.NewVector(3)
+	e := Gradient(g, x, f)
+	io.Pforan("g = %v  errEst = %.2e\n", g, e)
+	chk.Array(tst, "g", 1e-9, g, gana)
+	chk.Array(tst, "x (unchanged)", 1e-17, x, xcopy)
+
+	// Hessian
+	H := la.NewMatrix(3, 3)
+	e = Hessian(H, x, f)
+	io.Pforan("H =\n%v  errEst = %.2e\n", H.Print("%12.6f"), e)
+	chk.Deep2(tst, "H", 1e-7, H.GetDeep2(), Hana)
+	chk.Array(tst, "x (unchanged)", 1e-17, x, xcopy)
+
+	// functions
+	GradientFunc(f)(g, x)
+	chk.Array(tst, "GradientFunc", 1e-9, g, gana)
+	HessianFunc(f)(H, x)
+	chk.Deep2(tst, "HessianFunc", 1e-7, H.GetDeep2(), Hana)
+}
+
+func TestDeriv08(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Deriv08. Ridders' method at large |x| and near the boundary of the domain")
+
+	fcns := []fun.Ss{
+		func(x float64) float64 { return math.Sin(x) },
+		func(x float64) float64 { return math.Log(x) },
+		func(x float64) float64 { return math.Sqrt(x) },
+		func(x float64) float64 { return math.Exp(100 * x) },
+	}
+	dfcns := []fun.Ss{
+		func(x float64) float64 { return math.Cos(x) },
+		func(x float64) float64 { return 1 / x },
+		func(x float64) float64 { return 0.5 / math.Sqrt(x) },
+		func(x float64) float64 { return 100 * math.Exp(100*x) },
+	}
+	d2fcns := []fun.Ss{
+		func(x float64) float64 { return -math.Sin(x) },
+		func(x float64) float64 { return -1 / (x * x) },
+		func(x float64) float64 { return -0.25 / (x * math.Sqrt(x)) },
+		func(x float64) float64 { return 1e4 * math.Exp(100*x) },
+	}
+	xs := []float64{1e6, 1e-3, 1e-3, 0}
+	for k, f := range fcns {
+		x := xs[k]
+		d, e := DerivRidders(x, 0, f)
+		ana := dfcns[k](x)
+		io.Pforan("k=%d: df/dx = %23.15e  ana = %23.15e  err = %.2e  errEst = %.2e\n", k, d, ana, math.Abs(d-ana), e)
+		chk.Float64(tst, "df/dx", 1e-7*math.Abs(ana), d, ana)
+		chk.Bool(tst, "errEst ≤ 1e-6|df/dx|", e <= 1e-6*math.Abs(d), true)
+
+		d2, e2 := SecondDerivRidders(x, 0, f)
+		ana2 := d2fcns[k](x)
+		io.Pforan("     d²f/dx² = %23.15e  ana = %23.15e  err = %.2e  errEst = %.2e\n", d2, ana2, math.Abs(d2-ana2), e2)
+		chk.Float64(tst, "d²f/dx²", 1e-5*math.Abs(ana2), d2, ana2)
+	}
+
+	// function not finite around x: NaN with infinite error estimate
+	d, e := DerivRidders(0, 0, func(x float64) float64 { return math.Log(-x * x) })
+	chk.Bool(tst, "NaN", math.IsNaN(d), true)
+	chk.Bool(tst, "Inf", math.IsInf(e, 1), true)
+
+	// gradient and Hessian
+	f := func(x la.Vector) float64 { return math.Sin(x[0]) + math.Log(x[1]) }
+	x := la.Vector{1e6, 1e-3}
+	g := la.NewVector(2)
+	e = Gradient(g, x, f)
+	io.Pforan("g = %v  errEst = %.2e\n", g, e)
+	chk.Array(tst, "g", 1e-7, g, []float64{math.Cos(x[0]), 1 / x[1]})
+	H := la.NewMatrix(2, 2)
+	e = Hessian(H, x, f)
+	io.Pforan("H =\n%v  errEst = %.2e\n", H.Print("%14.6f"), e)
+	chk.Float64(tst, "H00", 1e-6, H.Get(0, 0), -math.Sin(x[0]))
+	chk.Float64(tst, "H11", 1e-5*1e6, H.Get(1, 1), -1e6)
+	chk.Float64(tst, "H01", 1e-6, H.Get(0, 1), 0)
+}
@@ -6,10 +6,12 @@ package num
 
 import (
 	"math"
+	"math/cmplx"
 	"testing"
 
 	"github.com/lei006/gomath/chk"
 	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/utl"
 )
 
 func Test_cubiceq01(tst *testing.T) {
@@ -92,3 +94,237 @@ func Test_cubiceq04(tst *testing.T) {
 		}
 	}
 }
+
+func Test_cubiceq05(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("cubiceq05. complex roots of cubic equations")
+
+	// x³ - 1 = 0
+	z1, z2, z3 := EqCubicSolveComplex(0, 0, -1)
+	io.Pforan("z = %v %v %v\n", z1, z2, z3)
+	s := math.Sqrt(3) / 2
+	chk.ArrayC(tst, "x³ - 1", 1e-15, []complex128{z1, z2, z3}, []complex128{complex(-0.5, -s), complex(-0.5, s), 1})
+
+	// three real roots: compare with EqCubicSolveReal
+	z1, z2, z3 = EqCubicSolveComplex(-3, -144, 432)
+	io.Pforan("z = %v %v %v\n", z1, z2, z3)
+	chk.ArrayC(tst, "x³ - 3x² - 144x + 432", 1e-14, []complex128{z1, z2, z3}, []complex128{-12, 3, 12})
+
+	// double root: x³ + x²
+	z1, z2, z3 = EqCubicSolveComplex(1, 0, 0)
+	io.Pforan("z = %v %v %v\n", z1, z2, z3)
+	chk.ArrayC(tst, "x³ + x²", 1e-8, []complex128{z1, z2, z3}, []complex128{-1, 0, 0})
+
+	// (x - 2)(x² + 2x + 5)
+	z1, z2, z3 = EqCubicSolveComplex(0, 1, -10)
+	io.Pforan("z = %v %v %v\n", z1, z2, z3)
+	chk.ArrayC(tst, "(x - 2)(x² + 2x + 5)", 1e-15, []complex128{z1, z2, z3}, []complex128{complex(-1, -2), complex(-1, 2), 2})
+	chk.Float64(tst, "conjugates", 1e-17, imag(z1)+imag(z2), 0)
+}
+
+func Test_quarticeq01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("quarticeq01. real and complex roots of quartic equations")
+
+	// distinct real roots: (x - 1)(x - 2)(x - 3)(x - 4)
+	x1, x2, x3, x4, nx := EqQuarticSolveReal(-10, 35, -50, 24)
+	io.Pforan("nx = %d  x = %v %v %v %v\n", nx, x1, x2, x3, x4)
+	chk.Int(tst, "nx", nx, 4)
+	chk.Array(tst, "x", 1e-14, []float64{x1, x2, x3, x4}, []float64{1, 2, 3, 4})
+
+	// two real and two complex roots: (x - 1)(x + 2)(x² + 1)
+	x1, x2, _, _, nx = EqQuarticSolveReal(1, -1, 1, -2)
+	io.Pforan("nx = %d  x = %v %v\n", nx, x1, x2)
+	chk.Int(tst, "nx", nx, 2)
+	chk.Array(tst, "x", 1e-15, []float64{x1, x2}, []float64{-2, 1})
+	z1, z2, z3, z4 := EqQuarticSolveComplex(1, -1, 1, -2)
+	io.Pforan("z = %v %v %v %v\n", z1, z2, z3, z4)
+	chk.ArrayC(tst, "z", 1e-15, []complex128{z1, z2, z3, z4}, []complex128{-2, -1i, 1i, 1})
+
+	// no real roots: x⁴ + 1 (biquadratic)
+	_, _, _, _, nx = EqQuarticSolveReal(0, 0, 0, 1)
+	chk.Int(tst, "nx", nx, 0)
+	z1, z2, z3, z4 = EqQuarticSolveComplex(0, 0, 0, 1)
+	r := math.Sqrt(2) / 2
+	chk.ArrayC(tst, "x⁴ + 1", 1e-15, []complex128{z1, z2, z3, z4}, []complex128{complex(-r, -r), complex(-r, r), complex(r, -r), complex(r, r)})
+
+	// double roots: (x - 1)²(x + 1)²
+	x1, x2, _, _, nx = EqQuarticSolveReal(0, -2, 0, 1)
+	io.Pforan("nx = %d  x = %v %v\n", nx, x1, x2)
+	chk.Int(tst, "nx", nx, 2)
+	chk.Array(tst, "x", 1e-15, []float64{x1, x2}, []float64{-1, 1})
+
+	// quadruple root: (x - 2)⁴
+	x1, _, _, _, nx = EqQuarticSolveReal(-8, 24, -32, 16)
+	io.Pforan("nx = %d  x = %v\n", nx, x1)
+	chk.Int(tst, "nx", nx, 1)
+	chk.Float64(tst, "x", 1e-14, x1, 2)
+
+	// double roots at zero and one: x²(x - 1)² (p'(0) = 0 exactly)
+	x1, x2, _, _, nx = EqQuarticSolveReal(-2, 1, 0, 0)
+	io.Pforan("nx = %d  x = %v %v\n", nx, x1, x2)
+	chk.Int(tst, "nx", nx, 2)
+	chk.Array(tst, "x", 1e-15, []float64{x1, x2}, []float64{0, 1})
+	chk.Float64(tst, "radius at multiple root", 1e-17, polyRadius([]complex128{0, 0, 1, -2, 1}, 0), 0)
+
+	// compare with cubic: x⁴ - 3x³ - 144x² + 432x = x (x³ - 3x² - 144x + 432)
+	x1, x2, x3, x4, nx = EqQuarticSolveReal(-3, -144, 432, 0)
+	chk.Int(tst, "nx", nx, 4)
+	chk.Array(tst, "x", 1e-14, []float64{x1, x2, x3, x4}, []float64{-12, 0, 3, 12})
+
+	// backward error with pseudo-random coefficients
+	for k := 0; k < 50; k++ {
+		a, b, c, d := 10*math.Sin(float64(k)), 10*math.Cos(3*float64(k)), 10*math.Sin(7*float64(k)+1), 10*math.Cos(11*float64(k)+2)
+		z1, z2, z3, z4 = EqQuarticSolveComplex(a, b, c, d)
+		coef := []complex128{complex(d, 0), complex(c, 0), complex(b, 0), complex(a, 0), 1}
+		for _, z := range []complex128{z1, z2, z3, z4} {
+			p, _, s := polyEval(coef, z)
+			if cmplx.Abs(p) > 1e-14*s {
+				tst.Errorf("backward error is too large: k=%d z=%v |p(z)|/s = %g\n", k, z, cmplx.Abs(p)/s)
+				return
+			}
+		}
+	}
+}
+
+func Test_polyeq01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("polyeq01. general polynomial equations with multiple roots")
+
+	// (x - 1)³ (x + 2)² (x² + 1)
+	c := polyFromRoots([]complex128{1, 1, 1, -2, -2, 1i, -1i})
+	cr := make([]float64, len(c))
+	for i := range c {
+		cr[i] = real(c[i])
+	}
+	roots, mult, err := EqPolySolve(cr)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("roots = %v\nmult  = %v\n", roots, mult)
+	chk.ArrayC(tst, "roots", 1e-14, roots, []complex128{-2, -1i, 1i, 1})
+	chk.Ints(tst, "mult", mult, []int{2, 1, 1, 3})
+
+	// zero roots: x³ (x - 1)
+	roots, mult, err = EqPolySolve([]float64{0, 0, 0, -1, 1})
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	chk.ArrayC(tst, "roots", 1e-15, roots, []complex128{0, 1})
+	chk.Ints(tst, "mult", mult, []int{3, 1})
+
+	// Wilkinson-like polynomial with roots 1, 2, ..., 10
+	var ref []complex128
+	for i := 1; i <= 10; i++ {
+		ref = append(ref, complex(float64(i), 0))
+	}
+	c = polyFromRoots(ref)
+	cr = make([]float64, len(c))
+	for i := range c {
+		cr[i] = real(c[i])
+	}
+	roots, mult, err = EqPolySolve(cr)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("roots = %v\n", roots)
+	chk.ArrayC(tst, "roots", 1e-7, roots, ref) // the roots are ill-conditioned
+	chk.Ints(tst, "mult", mult, utl.IntVals(10, 1))
+
+	// complex coefficients: (z - i)² (z - 2) (z + 1 + i)
+	roots, mult, err = EqPolySolveComplex(polyFromRoots([]complex128{1i, 1i, 2, complex(-1, -1)}))
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("roots = %v\nmult  = %v\n", roots, mult)
+	chk.ArrayC(tst, "roots", 1e-14, roots, []complex128{complex(-1, -1), 1i, 2})
+	chk.Ints(tst, "mult", mult, []int{1, 2, 1})
+
+	// errors
+	_, _, err = EqPolySolve([]float64{1, 0, 0})
+	chk.Bool(tst, "degree 0: error", err != nil, true)
+}
+
+func Test_polyeq02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("polyeq02. clusters that are not multiple roots")
+
+	// Wilkinson polynomials: the computed roots cluster and may even form complex
+	// pairs, but none is a multiple root
+	for _, n := range []int{15, 20} {
+		var ref []complex128
+		for i := 1; i <= n; i++ {
+			ref = append(ref, complex(float64(i), 0))
+		}
+		c := polyFromRoots(ref)
+		cr := make([]float64, len(c))
+		for i := range c {
+			cr[i] = real(c[i])
+		}
+		roots, mult, err := EqPolySolve(cr)
+		if err != nil {
+			tst.Errorf("%v\n", err)
+			return
+		}
+		io.Pforan("n = %d\nroots = %v\nmult  = %v\n", n, roots, mult)
+		chk.Ints(tst, "mult", mult, utl.IntVals(n, 1))
+		chk.ArrayC(tst, "roots 1…5", 1e-5, roots[:5], ref[:5])
+		for _, z := range roots {
+			if imag(z) != 0 {
+				paired := false
+				for _, w := range roots {
+					paired = paired || w == cmplx.Conj(z)
+				}
+				if !paired {
+					tst.Errorf("complex root %v is not paired with its conjugate\n", z)
+					return
+				}
+			}
+			r := math.Min(math.Max(math.Round(real(z)), 1), float64(n))
+			if d := cmplx.Abs(z - complex(r, 0)); d > polyRadius(c, z) {
+				tst.Errorf("root %v is not within its inclusion radius of %g\n", z, r)
+				return
+			}
+		}
+	}
+
+	// multiple roots next to simple ones
+	roots, mult, err := EqPolySolveComplex(polyFromRoots([]complex128{0.1, 0.1, 7, 7, 7}))
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	chk.ArrayC(tst, "roots", 1e-12, roots, []complex128{0.1, 7})
+	chk.Ints(tst, "mult", mult, []int{2, 3})
+
+	// close but distinct roots
+	roots, mult, err = EqPolySolveComplex(polyFromRoots([]complex128{1, 1 + 1e-6, 2}))
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	chk.ArrayC(tst, "roots", 1e-8, roots, []complex128{1, 1 + 1e-6, 2}) // conditioned as ε/δ
+	chk.Ints(tst, "mult", mult, []int{1, 1, 1})
+}
+
+// polyFromRoots returns the coefficients (ascending order) of Π (z - rᵢ)
+func polyFromRoots(r []complex128) (c []complex128) {
+	c = []complex128{1}
+	for _, ri := range r {
+		next := make([]complex128, len(c)+1)
+		for i, ci := range c {
+			next[i+1] += ci
+			next[i] -= ri * ci
+		}
+		c = next
+	}
+	return
+}
@@ -9,7 +9,9 @@ import (
 	"testing"
 
 	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
 	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/utl"
 )
 
 func sin(x float64) float64 { return math.Sin(x) }
@@ -103,3 +105,107 @@ func TestJacobian03(tst *testing.T) {
 	x := []float64{0.5, 0.5}
 	CompareJacDense(tst, ffcn, Jfcn, x, 1e-7)
 }
+
+func TestJacobian04(tst *testing.T) {
+
+	// verbose()
+	chk.PrintTitle("TestJacobian 04 (coloured: tridiagonal)")
+
+	// f[i] = x[i-1] - 2 x[i]³ + x[i+1]
+	n := 50
+	ffcn := func(fx, x la.Vector) {
+		for i := 0; i < n; i++ {
+			fx[i] = -2 * x[i] * x[i] * x[i]
+			if i > 0 {
+				fx[i] += x[i-1]
+			}
+			if i < n-1 {
+				fx[i] += x[i+1]
+			}
+		}
+	}
+	var rows, cols []int
+	for i := 0; i < n; i++ {
+		for j := utl.Imax(i-1, 0); j <= utl.Imin(i+1, n-1); j++ {
+			rows = append(rows, i)
+			cols = append(cols, j)
+		}
+	}
+	sp := NewJacSparsity(n, n, rows, cols)
+	io.Pforan("ncolors = %d  nnz = %d\n", sp.Ncolors, sp.Nnz())
+	chk.Int(tst, "ncolors", sp.Ncolors, 3)
+	chk.Int(tst, "nnz", sp.Nnz(), 3*n-2)
+	checkColouring(tst, sp, rows, cols)
+
+	// compare with one-column-at-a-time Jacobian
+	x := la.NewVector(n)
+	for i := 0; i < n; i++ {
+		x[i] = math.Sin(float64(i))
+	}
+	fx := la.NewVector(n)
+	w := la.NewVector(n)
+	ffcn(fx, x)
+	xcopy := x.GetCopy()
+	var Jcol, Jref la.Triplet
+	JacobianColored(&Jcol, ffcn, x, fx, w, sp)
+	Jacobian(&Jref, ffcn, x, fx, w)
+	chk.Array(tst, "x (unchanged)", 1e-17, x, xcopy)
+	chk.Int(tst, "len(J)", Jcol.Len(), 3*n-2)
+	chk.Deep2(tst, "J", 1e-17, Jcol.ToDense().GetDeep2(), Jref.ToDense().GetDeep2())
+	for i := 0; i < n; i++ {
+		chk.AnaNum(tst, io.Sf("J[%d][%d]", i, i), 1e-6, Jcol.ToDense().Get(i, i), -6*x[i]*x[i], chk.Verbose)
+	}
+}
+
+func TestJacobian05(tst *testing.T) {
+
+	// verbose()
+	chk.PrintTitle("TestJacobian 05 (coloured: rectangular; pattern from triplet)")
+
+	// residuals of an "arrow" model: f[i] = p[0] + p[1+i] ⋅ exp(-p[1+i]) for i = 0..m-1
+	m := 6
+	ffcn := func(fx, p la.Vector) {
+		for i := 0; i < m; i++ {
+			fx[i] = p[0] + p[1+i]*math.Exp(-p[1+i])
+		}
+	}
+	Jfcn := func(dfdp *la.Triplet, p la.Vector) {
+		dfdp.Start()
+		for i := 0; i < m; i++ {
+			dfdp.Put(i, 0, 1)
+			dfdp.Put(i, 1+i, (1-p[1+i])*math.Exp(-p[1+i]))
+		}
+	}
+
+	// pattern
+	p := la.NewVector(m + 1)
+	for i := 0; i < m+1; i++ {
+		p[i] = 0.1 * float64(i+1)
+	}
+	Jana := la.NewTriplet(m, m+1, 2*m)
+	Jfcn(Jana, p)
+	sp := NewJacSparsityTriplet(Jana)
+	io.Pforan("colors = %v\n", sp.Colors)
+	chk.Int(tst, "ncolors", sp.Ncolors, 2)
+	chk.Ints(tst, "colors", sp.Colors, []int{0, 1, 1, 1, 1, 1, 1})
+
+	// Jacobian
+	fx := la.NewVector(m)
+	w := la.NewVector(m)
+	ffcn(fx, p)
+	var Jnum la.Triplet
+	JacobianColored(&Jnum, ffcn, p, fx, w, sp)
+	chk.Deep2(tst, "J", 1e-8, Jnum.ToDense().GetDeep2(), Jana.ToDense().GetDeep2())
+}
+
+// checkColouring checks that columns with the same colour do not share any row
+func checkColouring(tst *testing.T, sp *JacSparsity, rows, cols []int) {
+	for k := 0; k < len(rows); k++ {
+		for l := 0; l < len(rows); l++ {
+			if rows[k] == rows[l] && cols[k] != cols[l] && sp.Colors[cols[k]] == sp.Colors[cols[l]] {
+				tst.Errorf("columns %d and %d share row %d but have the same colour\n", cols[k], cols[l], rows[k])
+				return
+			}
+		}
+	}
+}
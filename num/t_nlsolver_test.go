@@ -256,3 +256,54 @@ func TestNlSolver2NumNoLine(tst *testing.T) {
 	solveProblem(tst, 2, []float64{0.7, 4.0}, []float64{0.5000000377836, 3.1415927055406}, false, true, false, 1e-7, 1e-14)
 	solveProblem(tst, 2, []float64{1.0, 4.0}, []float64{1.65458271876435, -15.819188232171314}, false, true, false, 1e-15, 1e-14)
 }
+
+func TestNlSolverSparsity01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("NlSolverSparsity01. numerical Jacobian with column colouring")
+
+	// discrete Bratu problem: x[i-1] - 2 x[i] + x[i+1] + h² exp(x[i]) = 0
+	n := 40
+	h := 1.0 / float64(n+1)
+	F := func(fx, x la.Vector) {
+		for i := 0; i < n; i++ {
+			fx[i] = -2*x[i] + h*h*math.Exp(x[i])
+			if i > 0 {
+				fx[i] += x[i-1]
+			}
+			if i < n-1 {
+				fx[i] += x[i+1]
+			}
+		}
+	}
+	var rows, cols []int
+	for i := 0; i < n; i++ {
+		for j := i - 1; j <= i+1; j++ {
+			if j >= 0 && j < n {
+				rows = append(rows, i)
+				cols = append(cols, j)
+			}
+		}
+	}
+
+	// solve with and without sparsity pattern
+	var xs [2]la.Vector
+	var nfeval, njeval [2]int
+	for k, colour := range []bool{false, true} {
+		o := NewNlSolver(n, F)
+		if colour {
+			o.SetJacobianSparsity(NewJacSparsity(n, n, rows, cols))
+		}
+		xs[k] = la.NewVector(n)
+		o.Solve(xs[k])
+		nfeval[k], njeval[k] = o.Nfeval, o.Njeval
+		io.Pforan("colour=%v: niter = %d  nfeval = %d  njeval = %d\n", colour, o.Niter, o.Nfeval, o.Njeval)
+		o.Free()
+	}
+	chk.Array(tst, "x: colour == reference", 1e-15, xs[1], xs[0])
+	chk.Int(tst, "njeval", njeval[1], njeval[0])
+	chk.Int(tst, "nfeval: n - 3 evaluations saved per Jacobian", nfeval[0]-nfeval[1], njeval[0]*(n-3))
+	fx := la.NewVector(n)
+	F(fx, xs[1])
+	chk.Float64(tst, "max(|f|)", 1e-9, fx.Largest(1), 0)
+}
@@ -35,4 +35,10 @@ func TestNlsConfig01(tst *testing.T) {
 	chk.Float64(tst, "rtol", 1e-15, c.rtol, 1e-8)
 	chk.Float64(tst, "ftol", 1e-15, c.ftol, 1e-9)
 	chk.Float64(tst, "fnewt", 1e-15, c.fnewt, 0.0001)
+
+	// method and globalisation
+	chk.String(tst, c.Method, "newton")
+	chk.Float64(tst, "TrustRadius0", 1e-15, c.TrustRadius0, 1)
+	chk.Int(tst, "KrylovMaxIt", c.KrylovMaxIt, 30)
+	chk.Float64(tst, "KrylovTol", 1e-15, c.KrylovTol, 1e-6)
 }
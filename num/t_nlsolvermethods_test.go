@@ -0,0 +1,173 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+)
+
+func TestNlSolverMethods01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("NlSolverMethods01. Broyden, JFNK and dogleg with problems 0, 1 and 2")
+
+	for index := 0; index < 3; index++ {
+		for _, method := range []string{"newton", "broyden", "broyden-bad", "jfnk"} {
+			for _, trust := range []bool{false, true} {
+				if method == "newton" && !trust {
+					continue // see t_nlsolver_test.go
+				}
+				name, xTrial, xRef, funcF, funcJsparse, _ := problem(index) // other roots may be found
+				io.Pf("\n%s: method=%s trustRegion=%v\n", name, method, trust)
+				sol := NewNlSolver(len(xTrial), funcF)
+				if method != "jfnk" {
+					sol.SetJacobianFunction(funcJsparse, nil)
+				}
+				config := NewNlSolverConfig()
+				config.Verbose = chk.Verbose
+				config.Method = method
+				config.TrustRegion = trust
+				config.LineSearch = !trust
+				config.MaxIterations = 50
+				sol.SetConfig(config)
+				x := xTrial.GetCopy()
+				sol.Solve(x)
+				io.Pf("niter=%d nfeval=%d njeval=%d nkrylov=%d\n", sol.Niter, sol.Nfeval, sol.Njeval, sol.Nkrylov)
+				fx := la.NewVector(len(x))
+				funcF(fx, x)
+				io.Pf("x = %v (reference: %v)\n", x, xRef)
+				chk.Array(tst, "f(x) = 0? ", 1e-9, fx, nil)
+				sol.Free()
+			}
+		}
+	}
+}
+
+func TestNlSolverMethods02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("NlSolverMethods02. poor initial guess (full Newton steps diverge)")
+
+	funcF := func(fx, x la.Vector) {
+		fx[0] = math.Atan(x[0] - 1)
+		fx[1] = math.Atan(x[0] + x[1] - 3)
+	}
+	funcJdense := func(dfdx *la.Matrix, x la.Vector) {
+		a := x[0] + x[1] - 3
+		dfdx.Set(0, 0, 1/(1+(x[0]-1)*(x[0]-1)))
+		dfdx.Set(0, 1, 0)
+		dfdx.Set(1, 0, 1/(1+a*a))
+		dfdx.Set(1, 1, 1/(1+a*a))
+	}
+	xRef := []float64{1, 2}
+
+	for _, method := range []string{"newton", "broyden", "broyden-bad", "jfnk"} {
+		for _, trust := range []bool{false, true} {
+			io.Pf("\nmethod=%s trustRegion=%v\n", method, trust)
+			sol := NewNlSolver(2, funcF)
+			if method != "jfnk" {
+				sol.SetJacobianFunction(nil, funcJdense)
+			}
+			config := NewNlSolverConfig()
+			config.Verbose = chk.Verbose
+			config.Method = method
+			config.TrustRegion = trust
+			config.LineSearch = !trust
+			config.MaxIterations = 100
+			sol.SetConfig(config)
+			x := []float64{10, -10}
+			sol.Solve(x)
+			io.Pf("niter=%d nfeval=%d njeval=%d nkrylov=%d\n", sol.Niter, sol.Nfeval, sol.Njeval, sol.Nkrylov)
+			checkProblem(tst, sol, x, xRef, funcF, 1e-9, 1e-9, false)
+		}
+	}
+}
+
+func TestNlSolverMethods03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("NlSolverMethods03. Broyden tridiagonal function with 100 equations")
+
+	// Moré JJ, Garbow BS, Hillstrom KE (1981) Testing unconstrained optimization software,
+	// ACM Transactions on Mathematical Software, 7(1):17-41
+	n := 100
+	funcF := func(fx, x la.Vector) {
+		for i := 0; i < n; i++ {
+			fx[i] = (3-2*x[i])*x[i] + 1
+			if i > 0 {
+				fx[i] -= x[i-1]
+			}
+			if i < n-1 {
+				fx[i] -= 2 * x[i+1]
+			}
+		}
+	}
+	funcJsparse := func(dfdx *la.Triplet, x la.Vector) {
+		dfdx.Start()
+		for i := 0; i < n; i++ {
+			dfdx.Put(i, i, 3-4*x[i])
+			if i > 0 {
+				dfdx.Put(i, i-1, -1)
+			}
+			if i < n-1 {
+				dfdx.Put(i, i+1, -2)
+			}
+		}
+	}
+
+	for _, method := range []string{"newton", "broyden", "broyden-bad", "jfnk"} {
+		for _, trust := range []bool{false, true} {
+			io.Pf("\nmethod=%s trustRegion=%v\n", method, trust)
+			sol := NewNlSolver(n, funcF)
+			if method != "jfnk" {
+				sol.SetJacobianFunction(funcJsparse, nil)
+			}
+			config := NewNlSolverConfig()
+			config.Verbose = chk.Verbose
+			config.Method = method
+			config.TrustRegion = trust
+			config.LineSearch = !trust
+			config.MaxIterations = 50
+			sol.SetConfig(config)
+			x := la.NewVector(n)
+			x.Fill(-1)
+			sol.Solve(x)
+			io.Pf("niter=%d nfeval=%d njeval=%d nkrylov=%d\n", sol.Niter, sol.Nfeval, sol.Njeval, sol.Nkrylov)
+			fx := la.NewVector(n)
+			funcF(fx, x)
+			chk.Array(tst, "f(x) = 0? ", 1e-9, fx, nil)
+			sol.Free()
+		}
+	}
+}
+
+func TestNlSolverMethods04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("NlSolverMethods04. dogleg step")
+
+	// Newton step inside
+	p := la.NewVector(2)
+	g := la.Vector{1, 0}
+	pN := la.Vector{0.6, 0.8}
+	boundary := doglegStep(p, pN, g, 1, 2)
+	chk.Array(tst, "p", 1e-15, p, pN)
+	chk.Bool(tst, "boundary", boundary, false)
+
+	// Cauchy point outside: steepest descent
+	boundary = doglegStep(p, la.Vector{10, 10}, g, 0.1, 2)
+	chk.Array(tst, "p", 1e-15, p, []float64{2, 0})
+	chk.Bool(tst, "boundary", boundary, true)
+
+	// dogleg: pC = {1, 0}, pN = {1, 4} => p = {1, √3}
+	boundary = doglegStep(p, la.Vector{1, 4}, g, 1, 2)
+	chk.Array(tst, "p", 1e-15, p, []float64{1, math.Sqrt(3)})
+	chk.Bool(tst, "boundary", boundary, true)
+}
@@ -0,0 +1,218 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/rnd"
+	"github.com/lei006/gomath/utl"
+)
+
+// gaussBox computes the probability of a standard normal vector lying in [a, b]ⁿ
+func gaussBox(ndim int, a, b float64) float64 {
+	return math.Pow(0.5*(math.Erf(b/math.Sqrt2)-math.Erf(a/math.Sqrt2)), float64(ndim))
+}
+
+// gaussPdf computes the multivariate standard normal probability density
+func gaussPdf(x la.Vector) float64 {
+	return math.Exp(-0.5*la.VecDot(x, x)) / math.Pow(2*math.Pi, 0.5*float64(len(x)))
+}
+
+func Test_cubature01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("cubature01. h-cubature of vector-valued functions")
+
+	// 1D
+	quad := Cubature{EpsRel: 1e-10}
+	res, abserr, neval, err := quad.Integrate(func(f, x la.Vector) {
+		f[0] = math.Sqrt(1.0 + math.Pow(math.Sin(x[0]), 3.0))
+		f[1] = 1.0 / math.Sqrt(x[0])
+	}, 2, []float64{0}, []float64{1})
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("res = %v  abserr = %v  neval = %d\n", res, abserr, neval)
+	chk.Array(tst, "res", 1e-9, res, []float64{1.08268158558, 2})
+
+	// 2D and 3D: Gaussian probability and second moment
+	for _, ndim := range []int{2, 3} {
+		eps := 1e-9
+		if ndim == 3 {
+			eps = 1e-7 // Genz-Malik requires many evaluations for higher accuracy in 3D
+		}
+		a := utlVals(ndim, -1)
+		b := utlVals(ndim, 2)
+		quad = Cubature{EpsRel: eps}
+		res, abserr, neval, err = quad.Integrate(func(f, x la.Vector) {
+			f[0] = gaussPdf(x)
+			f[1] = x[0] * x[0] * f[0]
+		}, 2, a, b)
+		if err != nil {
+			tst.Errorf("%v\n", err)
+			return
+		}
+		p1 := gaussBox(1, -1, 2)
+		m2 := p1 - (2*math.Exp(-2)+math.Exp(-0.5))/math.Sqrt(2*math.Pi) // ∫₋₁² x² φ(x) dx
+		io.Pforan("ndim = %d  res = %v  abserr = %v  neval = %d\n", ndim, res, abserr, neval)
+		chk.Array(tst, "res", eps, res, []float64{gaussBox(ndim, -1, 2), m2 * math.Pow(p1, float64(ndim-1))})
+		for k := 0; k < 2; k++ {
+			if abserr[k] > eps*math.Abs(res[k]) {
+				tst.Errorf("error estimate is too large\n")
+			}
+		}
+	}
+
+	// polynomials of degree 7 are integrated exactly by a single region
+	rule := newCubRuleGM(4, 1)
+	r := newCubRegion([]float64{0, 0, 0, 0}, []float64{1, 2, 1, 1}, 1)
+	rule.eval(r, func(f, x la.Vector) { f[0] = math.Pow(x[0], 3) * math.Pow(x[1], 4) * (1 + x[3]) })
+	chk.Float64(tst, "degree 7", 1e-14, r.val[0], 0.25*32.0/5.0*1.5)
+	chk.Int(tst, "npts", rule.npts(), 57)
+
+	// maximum number of evaluations
+	quad = Cubature{EpsRel: 1e-12, MaxEval: 1000}
+	_, _, neval, err = quad.Integrate(func(f, x la.Vector) {
+		f[0] = 1.0 / math.Sqrt(x[0]*x[0]+x[1]*x[1])
+	}, 1, []float64{0, 0}, []float64{1, 1})
+	io.Pforan("err = %v\n", err)
+	if err == nil || neval > 1000 {
+		tst.Errorf("MaxEval should stop the refinement\n")
+	}
+}
+
+func Test_sparseGrid01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("sparseGrid01. Smolyak sparse grids")
+
+	// number of points of the Clenshaw-Curtis grids in 2D
+	for level, npts := range []int{1, 5, 13, 29, 65} {
+		g := NewSparseGrid(2, level, "cc")
+		chk.Int(tst, io.Sf("npts(ℓ=%d)", level), g.Npts(), npts)
+	}
+
+	// exactness for polynomials of total degree ≤ 2ℓ+1
+	for _, rule := range []string{"cc", "gl"} {
+		g := NewSparseGrid(4, 3, rule)
+		a, b := utlVals(4, 0), utlVals(4, 1)
+		sumw := 0.0
+		for _, w := range g.W {
+			sumw += w
+		}
+		chk.Float64(tst, rule+": Σw", 1e-13, sumw, 16)
+		res := g.Integrate(func(x la.Vector) float64 {
+			return 8 * x[0] * x[0] * x[0] * x[1] * x[2] * x[3] * x[3] // degree 7
+		}, a, b)
+		chk.Float64(tst, rule+": degree 7", 1e-13, res, 8.0/4.0/2.0/2.0/3.0)
+	}
+
+	// smooth integrand in 6D
+	ndim := 6
+	res, abserr, neval, err := QuadSparseGrid(func(x la.Vector) float64 {
+		return math.Exp(la.VecDot(x, x) * 0.1)
+	}, utlVals(ndim, 0), utlVals(ndim, 1), "cc", 1e-10, 0, 10)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	ref := 0.0 // ∫₀¹ exp(0.1x²) dx = Σ 0.1ᵏ/(k!(2k+1))
+	t := 1.0
+	for k := 0; k < 30; k++ {
+		ref += t / float64(2*k+1)
+		t *= 0.1 / float64(k+1)
+	}
+	io.Pforan("res = %v  abserr = %v  neval = %d\n", res, abserr, neval)
+	chk.Float64(tst, "exp(0.1|x|²)", 1e-9, res, math.Pow(ref, float64(ndim)))
+}
+
+func Test_monteCarlo01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("monteCarlo01. (quasi-)Monte Carlo")
+
+	rnd.Init(1234)
+	ndim := 6
+	a, b := utlVals(ndim, -1), utlVals(ndim, 2)
+	ref := gaussBox(ndim, -1, 2)
+	var errs []float64
+	for _, method := range []string{"mc", "lhs", "halton"} {
+		n := 4096
+		quad := MonteCarlo{Method: method}
+		res, stderr := quad.Integrate(gaussPdf, a, b, n)
+		io.Pforan("%-6s: res = %v  ref = %v  stderr = %v\n", method, res, ref, stderr)
+		if math.Abs(res-ref) > 5*stderr {
+			tst.Errorf("%s: error is larger than 5 standard errors\n", method)
+		}
+		errs = append(errs, stderr)
+	}
+	if errs[2] > 0.2*errs[0] {
+		tst.Errorf("quasi-Monte Carlo should be more accurate than Monte Carlo\n")
+	}
+
+	// each replicate of "lhs" has exactly one point per cell in each dimension
+	n, nrep := 50, 3
+	var cells [][]int
+	quad := MonteCarlo{Method: "lhs", Nrep: nrep}
+	quad.Integrate(func(x la.Vector) float64 {
+		c := make([]int, ndim)
+		for k := 0; k < ndim; k++ {
+			c[k] = int(float64(n) * (x[k] - a[k]) / (b[k] - a[k]))
+		}
+		cells = append(cells, c)
+		return 0
+	}, a, b, n)
+	for r := 0; r < nrep; r++ {
+		for k := 0; k < ndim; k++ {
+			count := make([]int, n)
+			for i := 0; i < n; i++ {
+				count[cells[r*n+i][k]]++
+			}
+			chk.Ints(tst, io.Sf("lhs: replicate %d, dimension %d", r, k), count, utl.IntVals(n, 1))
+		}
+	}
+}
+
+func Test_monteCarlo02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("monteCarlo02. standard error with a large offset")
+
+	// the replicates of f + c differ from those of f by c⋅V only
+	ndim := 3
+	a, b := utlVals(ndim, -1), utlVals(ndim, 2)
+	ref := gaussBox(ndim, -1, 2)
+	offset := 1e6
+	vol := math.Pow(3, float64(ndim))
+	quad := MonteCarlo{Method: "halton"}
+	rnd.Init(1234)
+	res, stderr := quad.Integrate(gaussPdf, a, b, 1024)
+	rnd.Init(1234)
+	resOff, stderrOff := quad.Integrate(func(x la.Vector) float64 {
+		return gaussPdf(x) + offset
+	}, a, b, 1024)
+	io.Pforan("res = %v  stderr = %v\n", res, stderr)
+	io.Pforan("res = %v  stderr = %v (with offset)\n", resOff-offset*vol, stderrOff)
+	chk.Float64(tst, "res", 1e-6, resOff-offset*vol, res)
+	chk.Float64(tst, "stderr", 1e-3*stderr, stderrOff, stderr)
+	if math.Abs(res-ref) > 5*stderr {
+		tst.Errorf("error is larger than 5 standard errors\n")
+	}
+}
+
+// utlVals returns a slice with n copies of v
+func utlVals(n int, v float64) []float64 {
+	res := make([]float64, n)
+	for i := range res {
+		res[i] = v
+	}
+	return res
+}
@@ -38,3 +38,48 @@ func Test_QuadElem01(tst *testing.T) {
 	io.Pforan("A  = %v\n", A)
 	chk.Float64(tst, "A", 1e-11, A, Acor)
 }
+
+func Test_QuadElem02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("QuadElem02. Romberg and tanh-sinh Elementary")
+
+	y := func(x float64) (res float64) {
+		res = math.Sqrt(1.0 + math.Pow(math.Sin(x), 3.0))
+		return
+	}
+	Acor := 1.08268158558
+
+	// Romberg's method
+	var R QuadElementary
+	R = new(ElementaryRomberg)
+	R.Init(y, 0, 1, 1e-11)
+	A := R.Integrate()
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "A", 1e-11, A, Acor)
+
+	// tanh-sinh rule
+	var D QuadElementary
+	D = new(ElementaryTanhSinh)
+	D.Init(y, 0, 1, 1e-11)
+	A = D.Integrate()
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "A", 1e-11, A, Acor)
+
+	// end-point singularities
+	for i, c := range []struct {
+		f    func(x float64) float64
+		a, b float64
+		ref  float64
+	}{
+		{func(x float64) float64 { return 1.0 / math.Sqrt(x) }, 0, 1, 2},
+		{func(x float64) float64 { return math.Log(x) / math.Sqrt(x) }, 0, 1, -4},
+		{func(x float64) float64 { return math.Pow(x, -0.75) }, 1, 0, -4},
+		{func(x float64) float64 { return math.Log(x) * math.Log(1-x) }, 0, 1, 2 - math.Pi*math.Pi/6},
+	} {
+		D.Init(c.f, c.a, c.b, 1e-12)
+		A = D.Integrate()
+		io.Pforan("A%d = %v\n", i, A)
+		chk.Float64(tst, io.Sf("A%d", i), 1e-12, A, c.ref)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun"
 	"github.com/lei006/gomath/io"
 )
 
@@ -45,3 +46,89 @@ func Test_gaussLegXW01(tst *testing.T) {
 	chk.Array(tst, "xJ", 1e-15, xJ, xRef)
 	chk.Array(tst, "wJ", 1e-14, wJ, wRef)
 }
+
+func Test_gaussLagHerXW01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("gaussLagHerXW01. Gauss-Laguerre and Gauss-Hermite")
+
+	// compare with Golub-Welsch
+	for _, alf := range []float64{0, 0.5, -0.5, 2} {
+		x, w := GaussLaguerreXW(alf, 12)
+		xr, wr := fun.NewGeneralOrthoPoly("La", 12, alf, 0).GaussXW(12)
+		chk.Array(tst, io.Sf("Laguerre(α=%g): x", alf), 1e-12, x, xr)
+		chk.Array(tst, io.Sf("Laguerre(α=%g): w", alf), 1e-14, w, wr)
+	}
+	for _, n := range []int{1, 2, 7, 10} {
+		x, w := GaussHermiteXW(n)
+		xr, wr := fun.NewGeneralOrthoPoly("H", n, 0, 0).GaussXW(n)
+		chk.Array(tst, io.Sf("Hermite(n=%d): x", n), 1e-13, x, xr)
+		chk.Array(tst, io.Sf("Hermite(n=%d): w", n), 1e-14, w, wr)
+	}
+
+	// exactness
+	A := QuadGaussLaguerre(0.5, 6, func(x float64) float64 { return x * x * x })
+	chk.Float64(tst, "∫x^3.5 exp(-x)", 1e-13, A, math.Gamma(4.5))
+	A = QuadGaussHermite(5, func(x float64) float64 { return x*x*x*x + x*x*x })
+	chk.Float64(tst, "∫x⁴ exp(-x²)", 1e-14, A, 0.75*math.Sqrt(math.Pi))
+}
+
+func Test_gaussLobRadXW01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("gaussLobRadXW01. Gauss-Lobatto and Gauss-Radau")
+
+	// Lobatto: 5 points
+	s37 := math.Sqrt(3.0 / 7.0)
+	x, w := GaussLobattoXW(-1, 1, 5)
+	chk.Array(tst, "Lobatto: x", 1e-15, x, []float64{-1, -s37, 0, s37, 1})
+	chk.Array(tst, "Lobatto: w", 1e-15, w, []float64{0.1, 49.0 / 90.0, 32.0 / 45.0, 49.0 / 90.0, 0.1})
+	x, w = GaussLobattoXW(0, 2, 2)
+	chk.Array(tst, "Lobatto(n=2): x", 1e-15, x, []float64{0, 2})
+	chk.Array(tst, "Lobatto(n=2): w", 1e-15, w, []float64{1, 1})
+
+	// Radau: 3 points
+	s6 := math.Sqrt(6)
+	x, w = GaussRadauXW(-1, 1, 3)
+	chk.Array(tst, "Radau: x", 1e-15, x, []float64{-1, (1 - s6) / 5, (1 + s6) / 5})
+	chk.Array(tst, "Radau: w", 1e-15, w, []float64{2.0 / 9.0, (16 + s6) / 18, (16 - s6) / 18})
+	x, w = GaussRadauXW(1, -1, 3)
+	chk.Array(tst, "Radau(reversed): x", 1e-15, x, []float64{1, -(1 - s6) / 5, -(1 + s6) / 5})
+	chk.Array(tst, "Radau(reversed): w", 1e-15, w, []float64{2.0 / 9.0, (16 + s6) / 18, (16 - s6) / 18})
+
+	// exactness
+	for n := 2; n <= 12; n++ {
+		pl := float64(2*n - 3)
+		pr := float64(2*n - 2)
+		Al := QuadGaussLobatto(0, 2, n, func(x float64) float64 { return math.Pow(x, pl) })
+		Ar := QuadGaussRadau(0, 2, n, func(x float64) float64 { return math.Pow(x, pr) })
+		chk.Float64(tst, io.Sf("Lobatto(n=%d)", n), 1e-13, Al*(pl+1)/math.Pow(2, pl+1), 1) // relative
+		chk.Float64(tst, io.Sf("Radau(n=%d)", n), 1e-13, Ar*(pr+1)/math.Pow(2, pr+1), 1)   // relative
+	}
+}
+
+func Test_clenshawCurtisXW01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("clenshawCurtisXW01. Clenshaw-Curtis and Fejér")
+
+	// reference values
+	x, w := ClenshawCurtisXW(-1, 1, 3)
+	chk.Array(tst, "CC(n=3): x", 1e-15, x, []float64{-1, 0, 1})
+	chk.Array(tst, "CC(n=3): w", 1e-15, w, []float64{1.0 / 3.0, 4.0 / 3.0, 1.0 / 3.0})
+	x, w = FejerXW(-1, 1, 2)
+	chk.Array(tst, "Fejér(n=2): x", 1e-15, x, []float64{-1 / math.Sqrt2, 1 / math.Sqrt2})
+	chk.Array(tst, "Fejér(n=2): w", 1e-15, w, []float64{1, 1})
+
+	// exactness and convergence
+	for n := 1; n <= 9; n++ {
+		p := float64(n - 1)
+		Acc := QuadClenshawCurtis(0, 1, n, func(x float64) float64 { return math.Pow(x, p) })
+		Afj := QuadFejer(0, 1, n, func(x float64) float64 { return math.Pow(x, p) })
+		chk.Float64(tst, io.Sf("CC(n=%d)", n), 1e-15, Acc, 1/(p+1))
+		chk.Float64(tst, io.Sf("Fejér(n=%d)", n), 1e-15, Afj, 1/(p+1))
+	}
+	y := func(x float64) float64 { return math.Sqrt(1.0 + math.Pow(math.Sin(x), 3.0)) }
+	chk.Float64(tst, "CC: √(1+sin³x)", 1e-11, QuadClenshawCurtis(0, 1, 17, y), 1.08268158558)
+	chk.Float64(tst, "Fejér: √(1+sin³x)", 1e-11, QuadFejer(0, 1, 17, y), 1.08268158558)
+}
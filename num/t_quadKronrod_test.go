@@ -0,0 +1,315 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun"
+	"github.com/lei006/gomath/io"
+)
+
+func Test_quadGK01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("quadGK01. Gauss-Kronrod rules")
+
+	for _, rule := range []*quadRule{&quadGK15, &quadGK21} {
+		io.Pforan("G%dK%d\n", len(rule.xgk)-1, rule.npts())
+
+		// Gauss weights
+		xg, wg := GaussLegendreXW(-1, 1, len(rule.xgk)-1)
+		for i := 0; i < len(rule.wg); i++ {
+			chk.Float64(tst, io.Sf("xg%d", i), 1e-15, -rule.xgk[2*i+1], xg[i])
+			chk.Float64(tst, io.Sf("wg%d", i), 1e-15, rule.wg[i], wg[i])
+		}
+
+		// exactness: Kronrod rule integrates polynomials up to degree 3n+1 exactly
+		deg := 3*(len(rule.xgk)-1) + 1
+		for p := 0; p <= deg; p++ {
+			f := func(x float64) float64 { return float64(p+1) * math.Pow(x, float64(p)) }
+			res, _, _, _ := rule.eval(f, 0, 1)
+			chk.Float64(tst, io.Sf("∫(p+1)x^%d", p), 1e-14, res, 1)
+		}
+	}
+}
+
+func Test_quadGK02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("quadGK02. finite intervals and end-point singularities")
+
+	// smooth function
+	var quad QuadGK
+	res, abserr, neval, err := quad.Integrate(func(x float64) float64 {
+		return math.Sqrt(1.0 + math.Pow(math.Sin(x), 3.0))
+	}, 0, 1)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("res = %v  abserr = %v  neval = %d\n", res, abserr, neval)
+	chk.Float64(tst, "∫√(1+sin³x)", 1e-11, res, 1.08268158558)
+	chk.Int(tst, "neval", neval, 21)
+
+	// reversed interval
+	res, _, _, err = quad.Integrate(math.Exp, 1, 0)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	chk.Float64(tst, "∫₁⁰exp", 1e-15, res, 1-math.E)
+
+	// singularities (QUADPACK examples)
+	quad = QuadGK{EpsAbs: 0, EpsRel: 1e-10}
+	for _, rule := range []int{15, 21} {
+		quad.Rule = rule
+		res, abserr, neval, err = quad.Integrate(func(x float64) float64 {
+			return math.Pow(x, -0.9) * math.Log(1.0/x)
+		}, 0, 1)
+		if err != nil {
+			tst.Errorf("%v\n", err)
+			return
+		}
+		io.Pforan("rule = %d  res = %v  abserr = %v  neval = %d\n", rule, res, abserr, neval)
+		chk.Float64(tst, "∫x^-0.9 log(1/x)", 1e-8, res, 100)
+		if math.Abs(res-100) > abserr {
+			tst.Errorf("error estimate is too optimistic\n")
+		}
+		res, abserr, neval, err = quad.Integrate(func(x float64) float64 {
+			return math.Log(x) / math.Sqrt(x)
+		}, 0, 1)
+		if err != nil {
+			tst.Errorf("%v\n", err)
+			return
+		}
+		io.Pforan("rule = %d  res = %v  abserr = %v  neval = %d\n", rule, res, abserr, neval)
+		chk.Float64(tst, "∫log(x)/√x", 1e-10, res, -4)
+	}
+
+	// divergent integral
+	_, _, _, err = quad.Integrate(func(x float64) float64 { return 1.0 / x }, 0, 1)
+	io.Pforan("err = %v\n", err)
+	if err == nil {
+		tst.Errorf("divergent integral should return an error\n")
+	}
+}
+
+func Test_quadGK03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("quadGK03. infinite intervals")
+
+	quad := QuadGK{EpsAbs: 1e-12, EpsRel: 1e-12}
+	inf := math.Inf(1)
+	for i, c := range []struct {
+		f      func(x float64) float64
+		a, b   float64
+		ref    float64
+		tol    float64
+		letter string
+	}{
+		{func(x float64) float64 { return math.Exp(-x * x) }, 0, inf, math.Sqrt(math.Pi) / 2, 1e-12, "∫₀^∞ exp(-x²)"},
+		{func(x float64) float64 { return 1.0 / (1.0 + x*x) }, -inf, inf, math.Pi, 1e-12, "∫ℝ 1/(1+x²)"},
+		{math.Exp, -inf, 0, 1, 1e-12, "∫₋∞⁰ exp"},
+		{math.Exp, 0, -inf, -1, 1e-12, "∫₀^-∞ exp"},
+		{func(x float64) float64 { return math.Log(x) / (1.0 + 100*x*x) }, 0, inf, -math.Pi * math.Log(10) / 20, 1e-11, "∫₀^∞ log(x)/(1+100x²)"},
+	} {
+		res, abserr, neval, err := quad.Integrate(c.f, c.a, c.b)
+		if err != nil {
+			tst.Errorf("%d: %v\n", i, err)
+			return
+		}
+		io.Pforan("%s = %v  abserr = %v  neval = %d\n", c.letter, res, abserr, neval)
+		chk.Float64(tst, c.letter, c.tol, res, c.ref)
+	}
+}
+
+func Test_quadGK04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("quadGK04. break points")
+
+	// QUADPACK example for QAGP
+	f := func(x float64) float64 {
+		return x * x * x * math.Log(math.Abs((x*x-1.0)*(x*x-2.0)))
+	}
+	ref := 61*math.Log(2) + 77*math.Log(7)/4 - 27
+	quad := QuadGK{EpsRel: 1e-10}
+	res, abserr, neval, err := quad.IntegratePts(f, 0, 3, []float64{1, math.Sqrt2, -1, 5})
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("res = %v  abserr = %v  neval = %d\n", res, abserr, neval)
+	chk.Float64(tst, "∫x³log|(x²-1)(x²-2)|", 1e-10, res, ref)
+
+	// reversed
+	res, _, _, err = quad.IntegratePts(f, 3, 0, []float64{math.Sqrt2, 1})
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	chk.Float64(tst, "reversed", 1e-10, res, -ref)
+}
+
+func Test_quadGK05(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("quadGK05. oscillatory integrands")
+
+	// same as QuadCs01
+	quad := QuadGK{EpsRel: 1e-12}
+	res, abserr, neval, err := quad.IntegrateOsc(func(x float64) float64 {
+		return math.Exp(-x)
+	}, 0, 10*math.Pi, 3, false)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	ref := (1.0 - math.Exp(-10*math.Pi)) / 10.0 // ∫₀^10π exp(-x) cos(3x) dx
+	io.Pforan("res = %v  abserr = %v  neval = %d\n", res, abserr, neval)
+	chk.Float64(tst, "∫exp(-x)cos(3x)", 1e-14, res, ref)
+
+	// Fourier integrals
+	quad = QuadGK{EpsAbs: 1e-10}
+	res, abserr, neval, err = quad.IntegrateOsc(func(x float64) float64 {
+		if x == 0 {
+			return 0
+		}
+		return 1.0 / math.Sqrt(x)
+	}, 0, math.Inf(1), math.Pi/2, false)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("res = %v  abserr = %v  neval = %d\n", res, abserr, neval)
+	chk.Float64(tst, "∫cos(πx/2)/√x", 1e-8, res, 1)
+
+	res, abserr, neval, err = quad.IntegrateOsc(func(x float64) float64 {
+		if x == 0 {
+			return 1
+		}
+		return 1.0 / x
+	}, 0, math.Inf(1), 1, true)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("res = %v  abserr = %v  neval = %d\n", res, abserr, neval)
+	chk.Float64(tst, "∫sin(x)/x", 1e-8, res, math.Pi/2)
+}
+
+func Test_quadGK06(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("quadGK06. algebraic-logarithmic singularities")
+
+	one := func(x float64) float64 { return 1 }
+	quad := QuadGK{EpsRel: 1e-12}
+	for i, c := range []struct {
+		f          func(x float64) float64
+		a, b, α, β float64
+		integr     int
+		ref, tol   float64
+	}{
+		{one, 0, 1, -0.5, -0.5, 1, math.Pi, 1e-12},
+		{func(x float64) float64 { return x }, 0, 1, -0.5, -0.5, 1, math.Pi / 2, 1e-12},
+		{one, -1, 1, -0.5, -0.5, 1, math.Pi, 1e-12},
+		{one, 0, 1, -0.9, 0, 2, -100, 1e-9},
+		{one, 0, 1, 0, 0, 4, 2 - math.Pi*math.Pi/6, 1e-12},
+		{one, 0, 1, 0, -0.5, 3, -4, 1e-12},
+	} {
+		res, abserr, neval, err := quad.IntegrateAlgLog(c.f, c.a, c.b, c.α, c.β, c.integr)
+		if err != nil {
+			tst.Errorf("%d: %v\n", i, err)
+			return
+		}
+		io.Pforan("%d: res = %v  abserr = %v  neval = %d\n", i, res, abserr, neval)
+		chk.Float64(tst, io.Sf("res%d", i), c.tol, res, c.ref)
+	}
+
+	// invalid input
+	_, _, _, err := quad.IntegrateAlgLog(one, 0, 1, -1, 0, 1)
+	if err == nil {
+		tst.Errorf("α = -1 should be rejected\n")
+	}
+}
+
+func Test_quadGK07(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("quadGK07. concurrency")
+
+	quad := QuadGK{EpsRel: 1e-12}
+	nwork := 8
+	res := make([]float64, nwork)
+	var wg sync.WaitGroup
+	for i := 0; i < nwork; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p := float64(i + 1)
+			res[i], _, _, _ = quad.Integrate(func(x float64) float64 {
+				return math.Exp(-p * x)
+			}, 0, math.Inf(1))
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < nwork; i++ {
+		chk.Float64(tst, io.Sf("res%d", i), 1e-12, res[i], 1.0/float64(i+1))
+	}
+}
+
+func Test_quadGK08(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("quadGK08. high frequencies (modified Clenshaw-Curtis)")
+
+	// moments: recurrence versus quadrature
+	quad := QuadGK{EpsAbs: 1e-14, EpsRel: 1e-14, Limit: 1000}
+	mom := make([]float64, 25)
+	for _, p := range []float64{-30, 25, 40, 1e3} {
+		quadOscMoments(mom, p)
+		for j := 0; j < 25; j++ {
+			w := math.Cos
+			if j%2 == 1 {
+				w = math.Sin
+			}
+			ref, _, _, _ := quad.Integrate(func(t float64) float64 {
+				return fun.ChebyshevT(j, t) * w(p*t)
+			}, -1, 1)
+			chk.Float64(tst, io.Sf("p=%g: mom[%d]", p, j), 1e-13, mom[j], ref)
+		}
+	}
+
+	// ∫₀¹ exp(-x)⋅cos(ω⋅x) dx and ∫₀¹ exp(-x)⋅sin(ω⋅x) dx
+	quad = QuadGK{EpsRel: 1e-10}
+	for _, ω := range []float64{5, 30, 1e3, 1e7, -1e7} {
+		sω, cω := math.Sincos(ω)
+		e, d := math.Exp(-1), 1+ω*ω
+		refc := (e*(ω*sω-cω) + 1) / d
+		refs := (ω - e*(sω+ω*cω)) / d
+		resc, errc, nc, err := quad.IntegrateOsc(func(x float64) float64 { return math.Exp(-x) }, 0, 1, ω, false)
+		if err != nil {
+			tst.Errorf("%v\n", err)
+			return
+		}
+		ress, errs, ns, err := quad.IntegrateOsc(func(x float64) float64 { return math.Exp(-x) }, 0, 1, ω, true)
+		if err != nil {
+			tst.Errorf("%v\n", err)
+			return
+		}
+		io.Pforan("ω = %8g: cos: abserr = %.2e neval = %4d   sin: abserr = %.2e neval = %4d\n", ω, errc, nc, errs, ns)
+		chk.Float64(tst, io.Sf("ω=%g: cos", ω), 1e-10*math.Abs(refc)+1e-15, resc, refc)
+		chk.Float64(tst, io.Sf("ω=%g: sin", ω), 1e-10*math.Abs(refs)+1e-15, ress, refs)
+		if nc > 1000 || ns > 1000 {
+			tst.Errorf("too many function evaluations: %d, %d\n", nc, ns)
+		}
+	}
+}
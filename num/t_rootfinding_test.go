@@ -0,0 +1,206 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/fun"
+	"github.com/lei006/gomath/io"
+)
+
+// rootMethods returns the bracketed root finding methods of Brent
+func rootMethods(o *Brent) (names []string, methods []func(xa, xb float64) (float64, error)) {
+	names = []string{"ridders", "illinois", "toms748", "newton", "halley"}
+	methods = []func(xa, xb float64) (float64, error){o.Ridders, o.Illinois, o.Toms748, o.NewtonSafe, o.HalleySafe}
+	return
+}
+
+func TestRootFinding01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("RootFinding01. bracketed methods")
+
+	// test functions: f, f', f'', [xa, xb], root
+	type problem struct {
+		name         string
+		f, df, d2f   fun.Ss
+		xa, xb, root float64
+	}
+	problems := []problem{
+		{"x³ - 2x - 5",
+			func(x float64) float64 { return x*x*x - 2*x - 5 },
+			func(x float64) float64 { return 3*x*x - 2 },
+			func(x float64) float64 { return 6 * x },
+			2, 3, 2.0945514815423265},
+		{"exp(x) - 1/x (Newton overshoots from x=0.2)",
+			func(x float64) float64 { return math.Exp(x) - 1/x },
+			func(x float64) float64 { return math.Exp(x) + 1/(x*x) },
+			func(x float64) float64 { return math.Exp(x) - 2/(x*x*x) },
+			0.2, 3, 0.5671432904097838},
+		{"atan(20(x - 0.3)) (nearly a step)",
+			func(x float64) float64 { return math.Atan(20 * (x - 0.3)) },
+			func(x float64) float64 { return 20 / (1 + 400*(x-0.3)*(x-0.3)) },
+			func(x float64) float64 { return -16000 * (x - 0.3) / math.Pow(1+400*(x-0.3)*(x-0.3), 2) },
+			-1, 4, 0.3},
+		{"(x - 1)³ (triple root)",
+			func(x float64) float64 { return (x - 1) * (x - 1) * (x - 1) },
+			func(x float64) float64 { return 3 * (x - 1) * (x - 1) },
+			func(x float64) float64 { return 6 * (x - 1) },
+			0, 3.5, 1},
+	}
+
+	for _, p := range problems {
+		io.Pf("\n%s\n", p.name)
+		o := NewBrent(p.f, p.df)
+		o.Hfcn = p.d2f
+		o.Tol = 1e-13
+		o.MaxIt = 200
+		names, methods := rootMethods(o)
+		for k, method := range methods {
+			x, err := method(p.xa, p.xb)
+			if err != nil {
+				tst.Errorf("%s: %v\n", names[k], err)
+				return
+			}
+			io.Pf("%10s: x = %23.15e  nit = %3d  nfeval = %3d  nJeval = %3d  nHeval = %3d\n", names[k], x, o.NumIter, o.NumFeval, o.NumJeval, o.NumHeval)
+			chk.Float64(tst, names[k], 1e-12, x, p.root)
+		}
+	}
+}
+
+func TestRootFinding02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("RootFinding02. efficiency and errors")
+
+	// TOMS748 vs Brent
+	ffcn := func(x float64) float64 { return math.Exp(x)*(x*x-1) - 0.5 }
+	o := NewBrent(ffcn, nil)
+	o.Tol = 1e-14
+	xbrent := o.Root(0, 3)
+	nbrent := o.NumFeval
+	x, err := o.Toms748(0, 3)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("Brent   : x = %v  nfeval = %d\n", xbrent, nbrent)
+	io.Pforan("TOMS748 : x = %v  nfeval = %d\n", x, o.NumFeval)
+	chk.Float64(tst, "x", 1e-14, x, xbrent)
+	chk.Float64(tst, "f(x)", 1e-14, ffcn(x), 0)
+	chk.Bool(tst, "nfeval(TOMS748) ≤ nfeval(Brent)", o.NumFeval <= nbrent, true)
+
+	// root at endpoint
+	o2 := NewBrent(func(x float64) float64 { return x - 1 }, nil)
+	x, err = o2.Ridders(1, 2)
+	chk.Bool(tst, "root at endpoint: error", err != nil, false)
+	chk.Float64(tst, "root at endpoint", 1e-17, x, 1)
+	chk.Int(tst, "root at endpoint: nfeval", o2.NumFeval, 2)
+
+	// errors
+	names, methods := rootMethods(o)
+	for k, method := range methods {
+		_, err = method(-0.5, 0.5)
+		io.Pforan("%10s: %v", names[k], err)
+		chk.Bool(tst, names[k]+": not bracketed", err != nil, true)
+	}
+	_, err = o.NewtonSafe(0, 3)
+	io.Pforan("%v", err)
+	chk.Bool(tst, "no Jfcn", err != nil, true)
+	o = NewBrent(ffcn, func(x float64) float64 { return math.Exp(x) * (x*x + 2*x - 1) })
+	_, err = o.HalleySafe(0, 3)
+	io.Pforan("%v", err)
+	chk.Bool(tst, "no Hfcn", err != nil, true)
+	o.MaxIt = 2
+	_, err = o.Illinois(0, 3)
+	io.Pforan("%v", err)
+	chk.Bool(tst, "MaxIt", err != nil, true)
+
+	// the best bracket end is returned when MaxIt is reached
+	x, err = o.Toms748(0, 3)
+	io.Pforan("%v", err)
+	chk.Bool(tst, "TOMS748: MaxIt", err != nil, true)
+	chk.Bool(tst, "TOMS748: best estimate", x > 0 && x < 3 && math.Abs(ffcn(x)) < math.Abs(ffcn(0)), true)
+
+	// the root is within ±tolAct even if the iterates converge faster than the bracket shrinks
+	o = NewBrent(func(x float64) float64 { return math.Pow(x-0.3, 9) }, nil)
+	o.Tol = 1e-12
+	x, err = o.Ridders(-1, 4)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("Ridders: (x-0.3)⁹ = 0 => x = %v  nfeval = %d\n", x, o.NumFeval)
+	chk.Float64(tst, "Ridders: x", o.tolAct(0.3), x, 0.3)
+
+	// MaxIt counts iterations of TOMS748 (not function evaluations) as in Brent.Root
+	o = NewBrent(func(x float64) float64 { return x * x * x }, nil)
+	x, err = o.Toms748(-1, 2)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("TOMS748: x³ = 0 => x = %v  nit = %d  nfeval = %d\n", x, o.NumIter, o.NumFeval)
+	chk.Float64(tst, "TOMS748: x", o.tolAct(0), x, 0)
+
+	// Illinois falls back to bisection when |f| is much larger at one end
+	o = NewBrent(func(x float64) float64 { return math.Exp(20*x) - 1e6 }, nil)
+	x, err = o.Illinois(-5, 5)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("Illinois: exp(20x) = 1e6 => x = %v  nit = %d\n", x, o.NumIter)
+	chk.Float64(tst, "Illinois: x", o.tolAct(0.7), x, math.Log(1e6)/20)
+}
+
+func TestRootFinding03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("RootFinding03. bracket expansion and multiple roots")
+
+	// expansion
+	ffcn := func(x float64) float64 { return x*x - 100 }
+	bracket := NewBracket(ffcn)
+	a, b, fa, fb, err := bracket.Root(0, 1)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("a=%g b=%g fa=%g fb=%g nfeval=%d\n", a, b, fa, fb, bracket.NumFeval)
+	chk.Bool(tst, "bracketed", fa*fb <= 0 && a < 10 && b > 10, true)
+	_, _, _, _, err = NewBracket(func(x float64) float64 { return x*x + 1 }).Root(0, 1)
+	io.Pforan("%v", err)
+	chk.Bool(tst, "no root: error", err != nil, true)
+
+	// scan
+	xa, xb, err := NewBracket(math.Sin).RootScan(-0.5, 10, 21)
+	if err != nil {
+		tst.Errorf("%v\n", err)
+		return
+	}
+	io.Pforan("xa = %v\nxb = %v\n", xa, xb)
+	chk.Int(tst, "number of brackets", len(xa), 4)
+	xa, _, _ = NewBracket(math.Sin).RootScan(0, 1, 3)
+	chk.Array(tst, "root at xmin", 1e-17, xa, []float64{0})
+
+	// all roots
+	o := NewBrent(math.Sin, math.Cos)
+	o.Hfcn = func(x float64) float64 { return -math.Sin(x) }
+	for _, method := range []string{"ridders", "illinois", "toms748", "newton", "halley"} {
+		roots, err := o.Roots(-0.5, 10, 21, method)
+		if err != nil {
+			tst.Errorf("%v\n", err)
+			return
+		}
+		io.Pforan("%10s: roots = %v\n", method, roots)
+		chk.Array(tst, method, 1e-10, roots, []float64{0, math.Pi, 2 * math.Pi, 3 * math.Pi})
+	}
+	_, err = o.Roots(0, 1, 3, "bisection")
+	chk.Bool(tst, "unknown method", err != nil, true)
+}
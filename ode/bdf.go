@@ -0,0 +1,471 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"time"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/num"
+	"github.com/lei006/gomath/utl"
+)
+
+// BDF implements the variable-order (1 to 5), variable-step backward differentiation formulae
+// (BDF) and the numerical differentiation formulae (NDF) of Shampine and Reichelt [1]
+//
+//	The methods available are:
+//	  bdf -- backward differentiation formulae
+//	  ndf -- numerical differentiation formulae (as in ode15s [1])
+//
+//	The solution is represented by the backward differences of y at equally spaced points
+//	(quasi-constant step size); thus, the differences are interpolated when the step size
+//	changes. Only one (real) linear system of size ndim is factorised per step size/order
+//	change and the Jacobian is reused until the simplified Newton iterations converge too
+//	slowly.
+//
+//	References:
+//	  [1] Shampine LF, Reichelt MW (1997) The MATLAB ODE Suite. SIAM Journal on Scientific
+//	      Computing, 18(1):1-22
+//	  [2] Hairer E, Wanner G (1996). Solving Ordinary Differential Equations II: Stiff and
+//	      Differential-Algebraic Problems. Springer Series in Computational Mathematics,
+//	      Vol. 14, Berlin, Germany, 614 p.
+type BDF struct {
+
+	// main
+	ndim   int          // problem dimension
+	conf   *Config      // configurations
+	work   *rkwork      // workspace
+	stat   *Stat        // statistics
+	fcn    Func         // dy/dx := f(x,y)
+	jac    JacF         // Jacobian function: df/dy(x,y)
+	dfdy   *la.Triplet  // df/dy matrix
+	mtri   *la.Triplet  // M matrix in triplet format
+	mmat   *la.CCMatrix // M matrix in compressed-column format
+	hasM   bool         // has M matrix
+	ready  bool         // matrices and solver are ready
+	jacCur bool         // Jacobian has been computed at the beginning of the current step
+
+	// coefficients
+	ndf     bool      // use numerical differentiation formulae
+	maxk    int       // max order
+	G       []float64 // G[k-1] = Σ 1/j, j = 1..k
+	Kappa   []float64 // NDF coefficients κ [zero for BDF]
+	InvGa   []float64 // 1 / (G ⋅ (1 - κ))
+	ErConst []float64 // error constants: κ ⋅ G + 1/(k+1)
+
+	// linear system: (M - c ⋅ dfdy) ⋅ δ = r with c = h / (G ⋅ (1 - κ))
+	mat *la.Triplet     // matrix of the linear system
+	ls  la.SparseSolver // linear solver
+	c   float64         // current factor of dfdy [0 ⇒ not factorised yet]
+
+	// convergence rate of the iterations (valid while the matrix is not changed)
+	rate    float64 // estimated convergence rate
+	havrate bool    // rate is available
+
+	// state
+	k      int         // current order
+	klast  int         // order of the last accepted step
+	hdif   float64     // step size corresponding to the differences
+	nconhk int         // number of steps with constant h and k
+	nfails int         // number of consecutive failures
+	err    float64     // scaled error estimate of the last step
+	dif    []la.Vector // [maxk+2][ndim] backward differences of y (times h^j)
+	difkp1 la.Vector   // [ndim] correction: ynew - predictor
+	ynew   la.Vector   // [ndim] new y
+	ypred  la.Vector   // [ndim] predictor
+	psi    la.Vector   // [ndim] constant terms of the corrector equation
+	scal   la.Vector   // [ndim] scal = atol + rtol ⋅ max(|y|,|ynew|)
+	rhs    la.Vector   // [ndim] right-hand side of the linear system
+	del    la.Vector   // [ndim] increment of the correction
+	w      la.Vector   // [ndim] workspace
+}
+
+// add method to database
+func init() {
+	rkmDB["bdf"] = func() rkmethod { return &BDF{ndf: false} }
+	rkmDB["ndf"] = func() rkmethod { return &BDF{ndf: true} }
+}
+
+// Free releases memory
+func (o *BDF) Free() {
+	if o.ls != nil {
+		o.ls.Free()
+	}
+}
+
+// Info returns information about this method
+func (o *BDF) Info() (fixedOnly, implicit bool, nstages int) {
+	return false, true, 1
+}
+
+// Init initializes structure
+func (o *BDF) Init(ndim int, conf *Config, work *rkwork, stat *Stat, fcn Func, jac JacF, M *la.Triplet) {
+
+	// check
+	if conf.fixed {
+		chk.Panic("BDF/NDF methods cannot be used with fixed steps\n")
+	}
+	if conf.BdfMaxOrd < 1 || conf.BdfMaxOrd > 5 {
+		chk.Panic("max order of BDF/NDF methods must be in [1, 5]. BdfMaxOrd = %d is invalid\n", conf.BdfMaxOrd)
+	}
+
+	// main
+	o.ndim = ndim
+	o.conf = conf
+	o.work = work
+	o.stat = stat
+	o.fcn = fcn
+	o.jac = jac
+	o.dfdy = new(la.Triplet)
+	o.mtri = M
+	if M == nil {
+		o.mtri = new(la.Triplet)
+		la.SpTriSetDiag(o.mtri, ndim, 1)
+	} else {
+		o.hasM = true
+	}
+	o.mmat = o.mtri.ToMatrix(nil)
+
+	// coefficients
+	o.maxk = conf.BdfMaxOrd
+	o.G = []float64{1, 3.0 / 2.0, 11.0 / 6.0, 25.0 / 12.0, 137.0 / 60.0}
+	o.Kappa = make([]float64, 5)
+	if o.ndf {
+		o.Kappa = []float64{-37.0 / 200.0, -1.0 / 9.0, -0.0823, -0.0415, 0}
+	}
+	o.InvGa = make([]float64, 5)
+	o.ErConst = make([]float64, 5)
+	for j := 0; j < 5; j++ {
+		o.InvGa[j] = 1.0 / (o.G[j] * (1.0 - o.Kappa[j]))
+		o.ErConst[j] = o.Kappa[j]*o.G[j] + 1.0/float64(j+2)
+	}
+
+	// linear solver
+	o.mat = new(la.Triplet)
+	o.ls = la.NewSparseSolver(o.conf.lsKind)
+
+	// workspace
+	o.dif = make([]la.Vector, o.maxk+2)
+	for j := 0; j < o.maxk+2; j++ {
+		o.dif[j] = la.NewVector(ndim)
+	}
+	o.difkp1 = la.NewVector(ndim)
+	o.ynew = la.NewVector(ndim)
+	o.ypred = la.NewVector(ndim)
+	o.psi = la.NewVector(ndim)
+	o.scal = la.NewVector(ndim)
+	o.rhs = la.NewVector(ndim)
+	o.del = la.NewVector(ndim)
+	o.w = la.NewVector(ndim)
+}
+
+// Accept accepts update and computes next stepsize
+func (o *BDF) Accept(y0 la.Vector, x0 float64) (dxnew float64) {
+
+	// update differences: difkp1 is the backward difference of order k+1 of ynew
+	k := o.k
+	la.VecAdd(o.dif[k+1], 1, o.difkp1, -1, o.dif[k])
+	o.dif[k].Apply(1, o.difkp1)
+	for j := k - 1; j >= 0; j-- {
+		la.VecAdd(o.dif[j], 1, o.dif[j], 1, o.dif[j+1])
+	}
+
+	// update y
+	y0.Apply(1, o.ynew)
+
+	// flags
+	o.klast = k
+	o.nfails = 0
+	o.jacCur = false
+	o.nconhk = utl.Imin(o.nconhk+1, o.maxk+2)
+
+	// select step size and order after k+1 steps with constant h and k
+	h := o.work.h
+	dxnew = h
+	if o.nconhk >= k+2 {
+		hopt := o.hnew(h, 1.2, o.err, k+1)
+		kopt := k
+		if k > 1 {
+			hkm1 := o.hnew(h, 1.3, o.rmsNorm(o.dif[k-1])*o.ErConst[k-2], k)
+			if hkm1 > hopt {
+				hopt, kopt = hkm1, k-1
+			}
+		}
+		if k < o.maxk {
+			hkp1 := o.hnew(h, 1.4, o.rmsNorm(o.dif[k+1])*o.ErConst[k], k+2)
+			if hkp1 > hopt {
+				hopt, kopt = hkp1, k+1
+			}
+		}
+		if hopt > h {
+			dxnew, o.k = hopt, kopt
+		}
+	}
+	return
+}
+
+// Reject processes step rejection and computes next stepsize
+func (o *BDF) Reject() (dxnew float64) {
+	o.nfails++
+	h := o.work.h
+	if o.nfails > 1 {
+		return 0.5 * h
+	}
+	k := o.k
+	dxnew = o.hnew(h, 1.2, o.err, k+1)
+	if k > 1 {
+		la.VecAdd(o.w, 1, o.dif[k-1], 1, o.difkp1)
+		hkm1 := o.hnew(h, 1.3, o.rmsNorm(o.w)*o.ErConst[k-2], k)
+		if hkm1 > dxnew {
+			dxnew = utl.Min(h, hkm1)
+			o.k--
+		}
+	}
+	return
+}
+
+// DenseOut produces dense output (after Accept)
+//
+//	NOTE: interpolation using the backward differences of the last accepted step
+func (o *BDF) DenseOut(yout la.Vector, h, x float64, y la.Vector, xout float64) {
+	s := (xout - x) / o.hdif
+	yout.Apply(1, y)
+	coef := 1.0
+	for j := 1; j <= o.klast; j++ {
+		coef *= (s + float64(j) - 1.0) / float64(j)
+		la.VecAdd(yout, 1, yout, coef, o.dif[j-1])
+	}
+}
+
+// Step steps update
+func (o *BDF) Step(x0 float64, y0 la.Vector) {
+
+	// auxiliary
+	h := o.work.h
+	x := x0 + h
+
+	// first step: start with order 1 and δy ≈ h ⋅ (M - c ⋅ dfdy)⁻¹ ⋅ f0
+	if o.work.first {
+		o.k, o.nconhk, o.nfails = 1, 0, 0
+		for j := 0; j < o.maxk+2; j++ {
+			o.dif[j].Fill(0)
+		}
+		o.hdif = h
+		o.updateMatrix(h*o.InvGa[0], x0, y0)
+		o.w.Apply(h, o.work.f0)
+		o.linSolve(o.dif[0], o.w)
+	}
+
+	// interpolate differences to the new step size
+	if h != o.hdif {
+		o.rescale(h / o.hdif)
+		o.hdif = h
+		o.nconhk = 0
+	}
+
+	// predictor and constant terms of the corrector equation
+	k := o.k
+	o.ypred.Apply(1, y0)
+	o.psi.Fill(0)
+	for j := 0; j < k; j++ {
+		la.VecAdd(o.ypred, 1, o.ypred, 1, o.dif[j])
+		la.VecAdd(o.psi, 1, o.psi, o.G[j]*o.InvGa[k-1], o.dif[j])
+	}
+	for m := 0; m < o.ndim; m++ {
+		o.scal[m] = o.conf.atol + o.conf.rtol*utl.Max(math.Abs(y0[m]), math.Abs(o.ypred[m]))
+	}
+	minnrm := 100.0 * o.conf.Eps * o.rmsNorm(o.ypred)
+
+	// simplified Newton iterations; retry once with a new Jacobian if converging too slowly
+	o.updateMatrix(h*o.InvGa[k-1], x0, y0)
+	for {
+		converged := o.corrector(x, minnrm)
+		o.work.theta = o.rate
+		if converged {
+			break
+		}
+		if o.jacCur {
+			o.work.diverg = true
+			o.work.dvfac = 0.3
+			return
+		}
+		o.c = 0 // force new Jacobian and factorisation
+		o.updateMatrix(h*o.InvGa[k-1], x0, y0)
+	}
+
+	// error estimate
+	o.err = o.rmsNorm(o.difkp1) * o.ErConst[k-1]
+	o.work.rerr = utl.Max(o.err, o.conf.Eps)
+}
+
+// corrector solves the corrector equation by simplified Newton iterations
+//
+//	c ⋅ f(x, ynew) - M ⋅ (ψ + difkp1) = 0  with  ynew = ypred + difkp1
+func (o *BDF) corrector(x float64, minnrm float64) (converged bool) {
+	maxit := utl.Imin(4, o.conf.NmaxIt)
+	o.difkp1.Fill(0)
+	o.ynew.Apply(1, o.ypred)
+	var newnrm, oldnrm, errit float64
+	for it := 0; it < maxit; it++ {
+
+		// statistics
+		o.work.nit = it + 1
+		if o.work.nit > o.stat.Nitmax {
+			o.stat.Nitmax = o.work.nit
+		}
+
+		// residual
+		o.stat.Nfeval++
+		o.fcn(o.rhs, o.work.h, x, o.ynew)
+		la.VecAdd(o.del, 1, o.psi, 1, o.difkp1)
+		if o.hasM {
+			la.SpMatVecMul(o.ynew, 1, o.mmat, o.del) // using ynew as workspace
+			o.del.Apply(1, o.ynew)
+		}
+		la.VecAdd(o.rhs, o.c, o.rhs, -1, o.del) // rhs := c ⋅ f - M ⋅ (ψ + difkp1)
+
+		// correction
+		o.linSolve(o.del, o.rhs)
+		newnrm = o.rmsNorm(o.del)
+		la.VecAdd(o.difkp1, 1, o.difkp1, 1, o.del)
+		la.VecAdd(o.ynew, 1, o.ypred, 1, o.difkp1)
+
+		// check convergence [1]
+		if newnrm <= minnrm {
+			return true
+		}
+		if it == 0 {
+			if o.havrate {
+				errit = newnrm * o.rate / (1.0 - o.rate)
+				if errit <= 0.05 { // more stringent when using the old rate
+					return true
+				}
+			} else {
+				o.rate = 0
+			}
+		} else if newnrm > 0.9*oldnrm {
+			return false
+		} else {
+			o.rate = utl.Max(0.9*o.rate, newnrm/oldnrm)
+			o.havrate = true
+			errit = newnrm * o.rate / (1.0 - o.rate)
+			if errit <= 0.5 {
+				return true
+			}
+			if it == maxit-1 || errit*math.Pow(o.rate, float64(maxit-1-it)) > 0.5 {
+				return false
+			}
+		}
+		oldnrm = newnrm
+	}
+	return false
+}
+
+// updateMatrix computes (if needed) the Jacobian and factorises M - c ⋅ dfdy if c has changed
+//
+//	NOTE: set o.c = 0 to force the computation of a new Jacobian
+func (o *BDF) updateMatrix(c, x0 float64, y0 la.Vector) {
+
+	// Jacobian
+	if !o.ready || o.c == 0 {
+		o.stat.Njeval++
+		startTimeJacobian := time.Now()
+		if o.jac == nil { // numerical (w works here as workspace variable)
+			ffcn := func(fy, yy la.Vector) {
+				o.fcn(fy, o.work.h, x0, yy)
+			}
+			if o.conf.JacSparsity != nil {
+				num.JacobianColored(o.dfdy, ffcn, y0, o.work.f0, o.w, o.conf.JacSparsity)
+			} else {
+				num.Jacobian(o.dfdy, ffcn, y0, o.work.f0, o.w)
+			}
+		} else {
+			o.jac(o.dfdy, o.work.h, x0, y0)
+		}
+		o.jacCur = true
+		o.stat.updateNanosecondsJeval(startTimeJacobian)
+	} else if c == o.c {
+		return
+	}
+
+	// matrix
+	if !o.ready {
+		o.mat.Init(o.ndim, o.ndim, o.mtri.Len()+o.dfdy.Len())
+	}
+	la.SpTriAdd(o.mat, 1, o.mtri, -c, o.dfdy) // mat := M - c ⋅ dfdy
+	o.c = c
+	o.havrate = false
+
+	// initialize linear solver
+	if !o.ready {
+		startTimeIniSol := time.Now()
+		o.ls.Init(o.mat, o.conf.LinSolConfig)
+		o.ready = true
+		o.stat.updateNanosecondsIniSol(startTimeIniSol)
+	}
+
+	// perform factorisation
+	startTimeFactorization := time.Now()
+	o.ls.Fact()
+	o.stat.Ndecomp++
+	o.stat.updateNanosecondsFact(startTimeFactorization)
+}
+
+// linSolve solves (M - c ⋅ dfdy) ⋅ x = b
+func (o *BDF) linSolve(x, b la.Vector) {
+	o.stat.Nlinsol++
+	startTimeSolver := time.Now()
+	o.ls.Solve(x, b)
+	o.stat.updateNanosecondsLinSol(startTimeSolver)
+}
+
+// rescale interpolates the differences dif[0..k-1] to the step size ρ ⋅ hdif [1]
+//
+//	dif := dif ⋅ R(ρ) ⋅ U  with  R[i][j] = Π_{m=1}^{i} (m - 1 - j ⋅ ρ) / m  and  U = R(1)
+func (o *BDF) rescale(ρ float64) {
+	k := o.k
+	R := la.NewMatrix(k, k)
+	U := la.NewMatrix(k, k)
+	for j := 1; j <= k; j++ {
+		r, u := 1.0, 1.0
+		for i := 1; i <= k; i++ {
+			r *= (float64(i) - 1.0 - float64(j)*ρ) / float64(i)
+			u *= (float64(i) - 1.0 - float64(j)) / float64(i)
+			R.Set(i-1, j-1, r)
+			U.Set(i-1, j-1, u)
+		}
+	}
+	RU := la.NewMatrix(k, k)
+	la.MatMatMul(RU, 1, R, U)
+	d := make([]float64, k)
+	for m := 0; m < o.ndim; m++ {
+		for j := 0; j < k; j++ {
+			d[j] = 0
+			for i := 0; i < k; i++ {
+				d[j] += o.dif[i][m] * RU.Get(i, j)
+			}
+		}
+		for j := 0; j < k; j++ {
+			o.dif[j][m] = d[j]
+		}
+	}
+}
+
+// hnew returns the step size h / (safe ⋅ err^(1/q)) limited by Mmin and Mmax
+func (o *BDF) hnew(h, safe, err float64, q int) float64 {
+	fac := safe * math.Pow(err, 1.0/float64(q))
+	return h / utl.Min(1.0/o.conf.Mmin, utl.Max(1.0/o.conf.Mmax, fac))
+}
+
+// rmsNorm returns the scaled rms norm of v
+func (o *BDF) rmsNorm(v la.Vector) float64 {
+	sum := 0.0
+	for m := 0; m < o.ndim; m++ {
+		sum += math.Pow(v[m]/o.scal[m], 2)
+	}
+	return math.Sqrt(sum / float64(o.ndim))
+}
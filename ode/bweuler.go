@@ -78,8 +78,13 @@ func (o *BwEuler) Reject() (dxnew float64) {
 }
 
 // DenseOut produces dense output (after Accept)
+//
+//	NOTE: linear interpolation between y_old (saved in v[0]) and y
 func (o *BwEuler) DenseOut(yout la.Vector, h, x float64, y la.Vector, xout float64) {
-	chk.Panic("TODO")
+	s := (xout - x) / h
+	for i := 0; i < o.ndim; i++ {
+		yout[i] = y[i] + s*(y[i]-o.work.v[0][i])
+	}
 }
 
 // Step steps update
@@ -144,10 +149,15 @@ func (o *BwEuler) Step(x0 float64, y0 la.Vector) {
 			o.stat.Njeval++
 
 			// numerical Jacobian
-			if o.jac == nil { // numerical
-				num.Jacobian(o.dfdy, func(fy, yy la.Vector) {
+			if o.jac == nil { // numerical (dr works here as workspace variable)
+				ffcn := func(fy, yy la.Vector) {
 					o.fcn(fy, h, x0, yy)
-				}, y0, o.work.f[0], o.dr) // dr works here as workspace variable
+				}
+				if o.conf.JacSparsity != nil {
+					num.JacobianColored(o.dfdy, ffcn, y0, o.work.f[0], o.dr, o.conf.JacSparsity)
+				} else {
+					num.Jacobian(o.dfdy, ffcn, y0, o.work.f[0], o.dr)
+				}
 
 				// analytical Jacobian
 			} else {
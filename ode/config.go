@@ -9,6 +9,7 @@ import (
 
 	"github.com/lei006/gomath/chk"
 	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/num"
 	"github.com/lei006/gomath/utl"
 )
 
@@ -36,6 +37,8 @@ type Config struct {
 	Verbose    bool    // show messages, e.g. during iterations
 	ZeroTrial  bool    // always start iterations with zero trial values (instead of collocation interpolation)
 	StabBeta   float64 // Lund stabilization coefficient β
+	BdfMaxOrd  int     // max order of BDF/NDF methods (1 to 5) [default = 5]
+	Autonomous bool    // f(x,y) does not depend explicitly on x ⇒ Rosenbrock methods skip ∂f/∂x
 
 	// stiffness detection
 	StiffNstp  int     // number of steps to check stiff situation. 0 ⇒ no check. [default = 1]
@@ -46,6 +49,13 @@ type Config struct {
 	// configurations for linear solver
 	LinSolConfig *la.SparseConfig // configurations for sparse linear solver
 
+	// numerical Jacobian
+	JacSparsity *num.JacSparsity // sparsity pattern of df/dy: one function evaluation per colour [may be nil]
+
+	// events
+	EventTol float64  // tolerance for locating events [default = 1e-12]
+	events   []*event // event functions
+
 	// output
 	stepF     StepOutF  // function to process step output (of accepted steps) [may be nil]
 	denseF    DenseOutF // function to process dense output [may be nil]
@@ -77,7 +87,7 @@ type Config struct {
 
 // NewConfig returns a new [default] set of configuration parameters
 //
-//	method -- the ODE method: e.g. fweuler, bweuler, radau5, moeuler, dopri5
+//	method -- the ODE method: e.g. fweuler, bweuler, radau5, bdf, ndf, rodas, moeuler, dopri5
 //	lsKind -- kind of linear solver: "umfpack" or "mumps" [may be empty]
 func NewConfig(method string, lsKind string) (o *Config) {
 
@@ -110,6 +120,7 @@ func NewConfig(method string, lsKind string) (o *Config) {
 	o.CteTg = false
 	o.UseRmsNorm = true
 	o.Verbose = false
+	o.BdfMaxOrd = 5
 
 	// stiffness detection
 	o.StiffNstp = 0
@@ -117,6 +128,9 @@ func NewConfig(method string, lsKind string) (o *Config) {
 	o.StiffNyes = 15
 	o.StiffNnot = 6
 
+	// events
+	o.EventTol = 1e-12
+
 	// configurations for linear solver
 	o.LinSolConfig = la.NewSparseConfig()
 
@@ -206,3 +220,27 @@ func (o *Config) SetDenseOut(save bool, dxOut, xf float64, out DenseOutF) {
 		o.denseDx = dxOut
 	}
 }
+
+// AddEvent adds an event function g(x,y) whose zero-crossings are located during the solution
+//
+//	g         -- event function; an event happens when g changes sign
+//	direction -- 0: all crossings; +1: only when g increases; -1: only when g decreases
+//	terminal  -- stop the simulation at the event
+//
+//	NOTE: the events are located using the dense output of the method and recorded in Output.
+//	      Thus, explicit Runge-Kutta methods other than "dopri5" and "dopri8" cannot be used with
+//	      events (NewSolver panics)
+func (o *Config) AddEvent(g EventF, direction int, terminal bool) {
+	if g == nil {
+		chk.Panic("event function must not be nil\n")
+	}
+	if direction < -1 || direction > 1 {
+		chk.Panic("direction of event must be -1, 0 or +1. direction = %d is invalid\n", direction)
+	}
+	o.events = append(o.events, &event{g, direction, terminal})
+}
+
+// needDense tells whether the method must prepare data for dense output
+func (o *Config) needDense() bool {
+	return o.denseOut || len(o.events) > 0
+}
@@ -63,5 +63,15 @@ type StepOutF func(istep int, h, x float64, y la.Vector) (stop bool)
 //	  stop -- stop simulation (nicely)
 type DenseOutF func(istep int, h, x float64, y la.Vector, xout float64, yout la.Vector) (stop bool)
 
+// EventF defines an event function g(x,y); an event happens when g changes sign
+//
+//	INPUT:
+//	  x -- scalar variable
+//	  y -- vector variable
+//
+//	OUTPUT:
+//	  g -- value of the event function
+type EventF func(x float64, y la.Vector) (g float64)
+
 // YanaF defines a function to be used when computing analytical solutions
 type YanaF func(res []float64, x float64)
@@ -114,8 +114,11 @@ func (o *ExplicitRK) Init(ndim int, conf *Config, work *rkwork, stat *Stat, fcn
 	o.ndf = float64(ndim)
 
 	// dense output
-	if o.conf.denseOut {
+	if o.conf.needDense() {
 		if o.do == nil {
+			if len(o.conf.events) > 0 {
+				chk.Panic("events require dense output, which is not available for %q. Use \"dopri5\" or \"dopri8\" instead\n", o.conf.method)
+			}
 			chk.Panic("dense output is not available for %q\n", o.conf.method)
 		}
 		for i := 0; i < len(o.do); i++ {
@@ -134,7 +137,7 @@ func (o *ExplicitRK) Init(ndim int, conf *Config, work *rkwork, stat *Stat, fcn
 func (o *ExplicitRK) Accept(y0 la.Vector, x0 float64) (dxnew float64) {
 
 	// store data for future dense output
-	if o.conf.denseOut {
+	if o.conf.needDense() {
 		if o.dfunA != nil {
 			o.dfunA(y0, x0)
 		}
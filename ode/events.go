@@ -0,0 +1,133 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"sort"
+
+	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/num"
+	"github.com/lei006/gomath/utl"
+)
+
+// event holds an event function and its settings
+type event struct {
+	g         EventF // event function
+	direction int    // 0: all crossings; +1: only when g increases; -1: only when g decreases
+	terminal  bool   // stop the simulation at the event
+}
+
+// eventsInit evaluates the event functions at the initial state
+func (o *Solver) eventsInit(x float64, y la.Vector) {
+	o.Out.EventStop = false
+	if len(o.conf.events) == 0 {
+		return
+	}
+	if o.gPrev == nil {
+		o.gPrev = la.NewVector(len(o.conf.events))
+		o.yev = la.NewVector(o.ndim)
+	}
+	for k, ev := range o.conf.events {
+		o.gPrev[k] = ev.g(x, y)
+	}
+}
+
+// eventsCheck locates the events within the last accepted step [x-h, x]
+//
+//	The events are recorded in Out in increasing order of x. If a terminal event is found,
+//	the dense output before this event is executed, y is replaced by the state at the (first)
+//	terminal event, xnew is the x at this event and stop is true. Otherwise, xnew = x and y is
+//	not modified. outStop is true if the dense output function requested to stop.
+//
+//	NOTE: must be called after Accept and after updating x and y
+func (o *Solver) eventsCheck(istep int, x float64, y la.Vector) (xnew float64, stop, outStop bool) {
+
+	// find sign changes
+	xnew = x
+	if len(o.conf.events) == 0 {
+		return
+	}
+	xa := x - o.work.h
+	var idx []int
+	var xev []float64
+	for k, ev := range o.conf.events {
+		ga, gb := o.gPrev[k], ev.g(x, y)
+		o.gPrev[k] = gb
+		if !(ga < 0 && gb >= 0) && !(ga > 0 && gb <= 0) {
+			continue
+		}
+		if (ev.direction > 0 && ga > 0) || (ev.direction < 0 && ga < 0) {
+			continue
+		}
+		idx = append(idx, k)
+		xev = append(xev, o.eventLocate(ev, xa, ga, x, gb, y))
+	}
+	if len(idx) == 0 {
+		return
+	}
+
+	// record events in increasing order of x until the first terminal one
+	order := utl.IntRange(len(idx))
+	sort.SliceStable(order, func(i, j int) bool { return xev[order[i]] < xev[order[j]] })
+	for _, i := range order {
+		k, xe := idx[i], xev[i]
+		ye := la.NewVector(o.ndim)
+		if xe < x {
+			o.Out.dout(ye, o.work.h, x, y, xe)
+		} else {
+			ye.Apply(1, y)
+		}
+		o.Out.EventIdx = append(o.Out.EventIdx, k)
+		o.Out.EventX = append(o.Out.EventX, xe)
+		o.Out.EventY = append(o.Out.EventY, ye)
+		if o.conf.events[k].terminal {
+			for j, ev := range o.conf.events {
+				o.gPrev[j] = ev.g(xe, ye)
+			}
+			outStop = o.Out.executeDenseBefore(istep, o.work.h, x, y, xe)
+			y.Apply(1, ye)
+			o.Out.EventStop = true
+			return xe, true, outStop
+		}
+	}
+	return
+}
+
+// eventLocate finds the root of g(x, y(x)) in [xa, xb] using the dense output of the method
+//
+//	ga and gb are the values of g at xa and xb with ga ≠ 0 and ga ⋅ gb ≤ 0. The returned xr is
+//	such that g(xr) is already on the side of gb; thus, restarting the simulation at xr does
+//	not trigger the same event again.
+func (o *Solver) eventLocate(ev *event, xa, ga, xb, gb float64, y la.Vector) (xr float64) {
+
+	// event at the end of the step
+	if gb == 0 {
+		return xb
+	}
+
+	// g as a function of x using the dense output
+	h := o.work.h
+	gfcn := func(xs float64) float64 {
+		o.Out.dout(o.yev, h, xb, y, xs)
+		return ev.g(xs, o.yev)
+	}
+
+	// root finding
+	solver := num.NewBrent(gfcn, nil)
+	solver.Tol = o.conf.EventTol
+	xr, err := solver.Toms748(xa, xb)
+	if err != nil { // the dense output does not reproduce the sign of g at the ends
+		return xb
+	}
+
+	// move xr past the root, if necessary
+	δ := 4.0 * num.MACHEPS * utl.Max(1, math.Abs(xr))
+	for xr < xb && gfcn(xr)*ga > 0 {
+		xr = utl.Min(xr+δ, xb)
+		δ *= 2
+	}
+	return
+}
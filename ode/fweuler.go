@@ -54,8 +54,10 @@ func (o *FwEuler) Reject() (dxnew float64) {
 }
 
 // DenseOut produces dense output (after Accept)
+//
+//	NOTE: linear interpolation using f0 = f(x-h, y_old)
 func (o *FwEuler) DenseOut(yout la.Vector, h, x float64, y la.Vector, xout float64) {
-	chk.Panic("TODO")
+	la.VecAdd(yout, 1, y, xout-x, o.work.f[0]) // yout := y + (xout - x) ⋅ f0
 }
 
 // Step steps update
@@ -43,6 +43,10 @@ type Solver struct {
 	FixedOnly bool     // method can only be used with fixed steps
 	Implicit  bool     // method is implicit
 	work      *rkwork  // Runge-Kutta workspace
+
+	// events
+	gPrev la.Vector // values of event functions at the beginning of the step
+	yev   la.Vector // y during the location of events
 }
 
 // NewSolver returns a new ODE structure with default values and allocated slices
@@ -99,6 +103,9 @@ func (o *Solver) Free() {
 }
 
 // Solve solves dy/dx = f(x,y) from x to xf with initial y given in y
+//
+//	NOTE: the solution stops before xf if a terminal event happens (see Config.AddEvent);
+//	      then y holds the state at the event and Out.EventStop is true
 func (o *Solver) Solve(y la.Vector, x, xf float64) {
 
 	// benchmark
@@ -121,9 +128,10 @@ func (o *Solver) Solve(y la.Vector, x, xf float64) {
 		o.work.h = utl.Min(o.work.h, o.conf.IniH)
 	}
 
-	// stat and output
+	// stat, events and output
 	o.Stat.Reset()
 	o.Stat.Hopt = o.work.h
+	o.eventsInit(x, y)
 	if o.Out != nil {
 		stop := o.Out.execute(0, false, o.work.rs, o.work.h, x, y)
 		if stop {
@@ -139,12 +147,13 @@ func (o *Solver) Solve(y la.Vector, x, xf float64) {
 
 	// make sure that final x is equal to xf in the end
 	defer func() {
-		if math.Abs(x-xf) > 1e-10 {
+		if !o.Out.EventStop && math.Abs(x-xf) > 1e-10 {
 			io.Pf("warning: |x - xf| = %v > 1e-8\n", math.Abs(x-xf))
 		}
 	}()
 
 	// fixed steps //////////////////////////////
+	var evstop, outstop bool
 	if o.conf.fixed {
 		istep := 1
 		if o.conf.Verbose {
@@ -161,12 +170,16 @@ func (o *Solver) Solve(y la.Vector, x, xf float64) {
 			o.work.first = false
 			x = float64(n+1) * o.work.h
 			o.rkm.Accept(y, x)
-			if o.Out != nil {
-				stop := o.Out.execute(istep, false, o.work.rs, o.work.h, x, y)
+			x, evstop, outstop = o.eventsCheck(istep, x, y)
+			if o.Out != nil && !outstop {
+				stop := o.Out.execute(istep, evstop, o.work.rs, o.work.h, x, y)
 				if stop {
 					return
 				}
 			}
+			if evstop {
+				return
+			}
 			if o.conf.Verbose {
 				io.Pfgreen("x = %v\n", x)
 				io.Pf("y = %v\n", y)
@@ -264,14 +277,23 @@ func (o *Solver) Solve(y la.Vector, x, xf float64) {
 				dxnew = o.rkm.Accept(y, x)
 				x += o.work.h
 
+				// events
+				x, evstop, outstop = o.eventsCheck(o.Stat.Naccepted, x, y)
+
 				// output
-				if o.Out != nil {
-					stop := o.Out.execute(o.Stat.Naccepted, last, o.work.rs, o.work.h, x, y)
+				if o.Out != nil && !outstop {
+					stop := o.Out.execute(o.Stat.Naccepted, last || evstop, o.work.rs, o.work.h, x, y)
 					if stop {
 						return
 					}
 				}
 
+				// terminal event
+				if evstop {
+					o.Stat.Hopt = o.work.h
+					return
+				}
+
 				// converged ?
 				if last {
 					o.Stat.Hopt = o.work.h // optimal stepsize
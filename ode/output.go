@@ -33,6 +33,12 @@ type Output struct {
 	xout      float64     // current x of dense output
 	yout      la.Vector   // current y of dense output (used if denseF != nil only)
 
+	// events
+	EventIdx  []int       // index of event function (in the order of Config.AddEvent) [nevents]
+	EventX    []float64   // x values at events [nevents]
+	EventY    []la.Vector // y values at events [nevents][ndim]
+	EventStop bool        // the simulation was stopped by a terminal event
+
 	// from RK method
 	dout func(yout la.Vector, h, x float64, y la.Vector, xout float64) // function to calculate dense values of y
 }
@@ -141,6 +147,35 @@ func (o *Output) execute(istep int, last bool, ρs, h, x float64, y []float64) (
 	return
 }
 
+// executeDenseBefore executes the dense output at xo < xe within the last step [x-h, x] that is
+// truncated at xe by a terminal event (y is the state at x); execute at xe must follow
+func (o *Output) executeDenseBefore(istep int, h, x float64, y []float64, xe float64) (stop bool) {
+
+	// dense output using function
+	var xo float64
+	if o.conf.denseF != nil {
+		for xo = o.xout; xo < xe; xo += o.conf.denseDx {
+			o.dout(o.yout, h, x, y, xo)
+			stop = o.conf.denseF(istep, h, x, y, xo, o.yout)
+			if stop {
+				return
+			}
+		}
+	}
+
+	// save dense output
+	if o.DenseIdx < o.denseNmax {
+		for xo = o.xout; xo < xe; xo += o.conf.denseDx {
+			o.DenseS[o.DenseIdx] = istep
+			o.DenseX[o.DenseIdx] = xo
+			o.DenseY[o.DenseIdx] = la.NewVector(o.ndim)
+			o.dout(o.DenseY[o.DenseIdx], h, x, y, xo)
+			o.DenseIdx++
+		}
+	}
+	return
+}
+
 // step output ////////////////////////////////////////////////////////////////////////////////////
 
 // GetStepRs returns all ρs (stiffness ratio) values
@@ -408,3 +408,102 @@ func ProbSimpleNdim4b() (o *Problem) {
 	o.Xf = 2.8
 	return
 }
+
+// ProbPendulum returns the undamped nonlinear pendulum d²θ/dx² = -sin(θ) released from rest at θ₀
+// (with g/L = 1). The exact solution is given in terms of the Jacobi elliptic functions:
+//
+//	y = [θ, θ']
+//
+//	sin(θ/2) = k sn(K - x, k)    θ' = -2 k cn(K - x, k)    with   k = sin(θ₀/2)
+//
+//	where K = K(k) and the period is 4K. Xf is set to one period.
+//
+//	θ0 -- initial angle; use 0 for default value [=π/2]. Must satisfy 0 < θ0 < π
+func ProbPendulum(θ0 float64) (o *Problem) {
+
+	o = new(Problem)
+	if θ0 <= 0 {
+		θ0 = math.Pi / 2.0
+	}
+	if θ0 >= math.Pi {
+		chk.Panic("initial angle must be smaller than π. θ0 = %g is invalid\n", θ0)
+	}
+	k := math.Sin(θ0 / 2.0)
+	K := fun.EllipticK(k)
+	o.Xf = 4.0 * K
+	o.Dx = o.Xf / 100.0
+	o.Y = la.NewVectorSlice([]float64{θ0, 0.0})
+	o.Ndim = len(o.Y)
+
+	o.Yana = func(res []float64, x float64) {
+		sn, cn, _, _ := fun.Jacobi(K-x, k)
+		res[0] = 2.0 * math.Asin(k*sn)
+		res[1] = -2.0 * k * cn
+	}
+
+	o.Fcn = func(f la.Vector, dx, x float64, y la.Vector) {
+		f[0] = y[1]
+		f[1] = -math.Sin(y[0])
+	}
+
+	o.Jac = func(dfdy *la.Triplet, dx, x float64, y la.Vector) {
+		if dfdy.Max() == 0 {
+			dfdy.Init(2, 2, 4)
+		}
+		dfdy.Start()
+		dfdy.Put(0, 0, 0.0)
+		dfdy.Put(0, 1, 1.0)
+		dfdy.Put(1, 0, -math.Cos(y[0]))
+		dfdy.Put(1, 1, 0.0)
+	}
+	return
+}
+
+// ProbDuffing returns the undamped and unforced (hardening) Duffing oscillator
+// d²y/dx² + y + ε y³ = 0 with y(0) = A and dy/dx(0) = 0. The exact solution is:
+//
+//	y = A cn(ω x, k)    with   ω² = 1 + ε A²   and   k² = ε A² / (2 ω²)
+//
+//	where the period is 4K(k)/ω. Xf is set to one period.
+//
+//	A   -- amplitude; use 0 for default value [=1]
+//	eps -- ε ≥ 0 coefficient
+func ProbDuffing(A, eps float64) (o *Problem) {
+
+	o = new(Problem)
+	if A == 0 {
+		A = 1.0
+	}
+	if eps < 0 {
+		chk.Panic("coefficient ε must be non-negative. ε = %g is invalid\n", eps)
+	}
+	ω := math.Sqrt(1.0 + eps*A*A)
+	k := math.Sqrt(eps*A*A/2.0) / ω
+	o.Xf = 4.0 * fun.EllipticK(k) / ω
+	o.Dx = o.Xf / 100.0
+	o.Y = la.NewVectorSlice([]float64{A, 0.0})
+	o.Ndim = len(o.Y)
+
+	o.Yana = func(res []float64, x float64) {
+		sn, cn, dn, _ := fun.Jacobi(ω*x, k)
+		res[0] = A * cn
+		res[1] = -A * ω * sn * dn
+	}
+
+	o.Fcn = func(f la.Vector, dx, x float64, y la.Vector) {
+		f[0] = y[1]
+		f[1] = -y[0] - eps*y[0]*y[0]*y[0]
+	}
+
+	o.Jac = func(dfdy *la.Triplet, dx, x float64, y la.Vector) {
+		if dfdy.Max() == 0 {
+			dfdy.Init(2, 2, 4)
+		}
+		dfdy.Start()
+		dfdy.Put(0, 0, 0.0)
+		dfdy.Put(0, 1, 1.0)
+		dfdy.Put(1, 0, -1.0-3.0*eps*y[0]*y[0])
+		dfdy.Put(1, 1, 0.0)
+	}
+	return
+}
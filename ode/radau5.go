@@ -232,10 +232,15 @@ func (o *Radau5) Step(x0 float64, y0 la.Vector) {
 			startTimeJacobian := time.Now()
 
 			// numerical Jacobian
-			if o.jac == nil { // numerical
-				num.Jacobian(o.dfdy, func(fy, yy la.Vector) {
+			if o.jac == nil { // numerical (w works here as workspace variable)
+				ffcn := func(fy, yy la.Vector) {
 					o.fcn(fy, h, x0, yy)
-				}, y0, o.work.f0, o.w[0]) // w works here as workspace variable
+				}
+				if o.conf.JacSparsity != nil {
+					num.JacobianColored(o.dfdy, ffcn, y0, o.work.f0, o.w[0], o.conf.JacSparsity)
+				} else {
+					num.Jacobian(o.dfdy, ffcn, y0, o.work.f0, o.w[0])
+				}
 
 				// analytical Jacobian
 			} else {
@@ -0,0 +1,558 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"time"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/num"
+	"github.com/lei006/gomath/utl"
+)
+
+// Rosenbrock implements Rosenbrock-Wanner (linearly implicit) methods with embedded error
+// estimators; see [1, page 102]
+//
+//	 The methods available are:
+//	   ros3p    -- 3 Lang and Verwer [2]; fixed steps only (see below)
+//	   rodas3   -- 3(2) Sandu et al. [3]; stiffly accurate ⇒ q = 2
+//	   ros34pw2 -- 3(2) W-method of Rang and Angermann [4]; stiffly accurate ⇒ q = 2
+//	   rodas    -- 4(3) Hairer and Wanner [1]; stiffly accurate ⇒ q = 3
+//	where p(q) means method of p-order with embedded estimator of q-order
+//
+//	NOTE: the embedded formula of ros3p (b̂ = [1/3, 1/3, 1/3]) is useless for step size control
+//	      because the first two stages coincide on linear autonomous problems (α₂₁ = 1,
+//	      γ₂₁ = -1) and the estimated error is zero. Any other second-order b̂ requires b̂₃ = 1/3
+//	      and b̂₁ + b̂₂ = 2/3 and has the same defect; thus, ros3p can only be used with fixed steps
+//
+//	NOTE: the embedded formula of ros34pw2 has R̂(∞) ≈ -0.478 (whereas R(∞) = 0); thus, its
+//	      estimate does not vanish on the algebraic components of DAEs and is filtered with
+//	      (M/(h⋅γ) - J)⁻¹ ⋅ M/(h⋅γ) at the cost of one extra solve with the existing factorisation
+//
+//	The stages are computed with the transformed variables u = Γ ⋅ k [1, page 111]:
+//
+//	  (M/(h⋅γ) - J) ⋅ uᵢ = f(x + αᵢ⋅h, y + Σ aᵢⱼ⋅uⱼ) + M ⋅ Σ (cᵢⱼ/h)⋅uⱼ + γᵢ⋅h⋅∂f/∂x
+//	  y_new = y + Σ mᵢ⋅uᵢ
+//
+//	Thus, only one linear system is factorised per step and no Newton iterations are needed.
+//	The Jacobian of W-methods may be reused in the next step if it still predicts the change of f
+//	along the previous step (see jacobianIsOld).
+//
+//	References:
+//	  [1] Hairer E, Wanner G (1996). Solving Ordinary Differential Equations II: Stiff and
+//	      Differential-Algebraic Problems. Springer Series in Computational Mathematics,
+//	      Vol. 14, Berlin, Germany, 614 p.
+//	  [2] Lang J, Verwer J (2001) ROS3P—An accurate third-order Rosenbrock solver designed for
+//	      parabolic problems. BIT Numerical Mathematics, 41:731-738
+//	  [3] Sandu A, Verwer JG, Blom JG, Spee EJ, Carmichael GR, Potra FA (1997) Benchmarking
+//	      stiff ODE solvers for atmospheric chemistry problems II: Rosenbrock solvers.
+//	      Atmospheric Environment, 31(20):3459-3472
+//	  [4] Rang J, Angermann L (2005) New Rosenbrock W-methods of order 3 for partial
+//	      differential algebraic equations of index 1. BIT Numerical Mathematics, 45:761-787
+type Rosenbrock struct {
+
+	// constants
+	W        bool        // W-method: the order is retained with approximate Jacobians
+	Embedded bool        // has a reliable embedded error estimator (variable steps)
+	Filter   bool        // filter the error estimate because R̂(∞) ≠ 0; see Step
+	Nstg     int         // number of stages
+	P        int         // order of y1
+	Q        int         // order of error estimator
+	Gam      float64     // diagonal coefficient γ
+	A        [][]float64 // transformed coefficients: a = α ⋅ Γ⁻¹
+	C        [][]float64 // transformed coefficients: c = diag(1/γ) - Γ⁻¹
+	M        []float64   // transformed weights: m = b ⋅ Γ⁻¹
+	E        []float64   // transformed error weights: e = (b - b̂) ⋅ Γ⁻¹
+	Alp      []float64   // nodes: αᵢ = Σ αᵢⱼ
+	Gsum     []float64   // coefficients of ∂f/∂x: γᵢ = Σ γᵢⱼ
+	D        [][]float64 // dense output coefficients [2][nstg]
+
+	// data
+	ndim int         // problem dimension
+	conf *Config     // configuration
+	work *rkwork     // workspace
+	stat *Stat       // statistics
+	fcn  Func        // dy/dx = f(x,y) function
+	jac  JacF        // Jacobian function: df/dy(x,y)
+	dfdy *la.Triplet // df/dy matrix
+
+	// mass matrix
+	mtri *la.Triplet  // M matrix in triplet format
+	mmat *la.CCMatrix // M matrix in compressed-column format
+	hasM bool         // has M matrix
+
+	// linear system: (M/(h⋅γ) - J) ⋅ u = r
+	mat   *la.Triplet     // matrix of the linear system
+	ls    la.SparseSolver // linear solver
+	ready bool            // matrices and solver are ready
+
+	// workspace
+	u    []la.Vector // [nstg][ndim] transformed stage values
+	ynew la.Vector   // [ndim] new y
+	lerr la.Vector   // [ndim] local error estimate
+	fx   la.Vector   // [ndim] ∂f/∂x
+	rhs  la.Vector   // [ndim] right-hand side
+	v    la.Vector   // [ndim] workspace
+	w    la.Vector   // [ndim] workspace
+
+	// W-methods: data at the beginning of the previous step to check the Jacobian
+	xold float64   // previous x
+	yold la.Vector // [ndim] previous y
+	fold la.Vector // [ndim] previous f(x,y)
+
+	// dense output
+	do []la.Vector // [4][ndim] dense output coefficients
+
+	// auxiliary
+	n    float64 // exponent n = 1/(q+1) of rerrⁿ
+	dmin float64 // dmin = 1/Mmin
+	dmax float64 // dmax = 1/Mmax
+}
+
+// add methods to database
+func init() {
+	for _, kind := range []string{"ros3p", "rodas3", "ros34pw2", "rodas"} {
+		k := kind
+		rkmDB[k] = func() rkmethod { return newRosenbrock(k) }
+	}
+}
+
+// Free releases memory
+func (o *Rosenbrock) Free() {
+	if o.ls != nil {
+		o.ls.Free()
+	}
+}
+
+// Info returns information about this method
+func (o *Rosenbrock) Info() (fixedOnly, implicit bool, nstages int) {
+	return !o.Embedded, true, o.Nstg
+}
+
+// Init initializes structure
+func (o *Rosenbrock) Init(ndim int, conf *Config, work *rkwork, stat *Stat, fcn Func, jac JacF, M *la.Triplet) {
+
+	// data
+	o.ndim = ndim
+	o.conf = conf
+	o.work = work
+	o.stat = stat
+	o.fcn = fcn
+	o.jac = jac
+	o.dfdy = new(la.Triplet)
+
+	// mass matrix
+	o.mtri = M
+	if M == nil {
+		o.mtri = new(la.Triplet)
+		la.SpTriSetDiag(o.mtri, ndim, 1)
+	} else {
+		o.hasM = true
+	}
+	o.mmat = o.mtri.ToMatrix(nil)
+
+	// linear solver
+	o.mat = new(la.Triplet)
+	o.ls = la.NewSparseSolver(o.conf.lsKind)
+
+	// workspace
+	o.u = make([]la.Vector, o.Nstg)
+	for i := 0; i < o.Nstg; i++ {
+		o.u[i] = la.NewVector(ndim)
+	}
+	o.ynew = la.NewVector(ndim)
+	o.lerr = la.NewVector(ndim)
+	o.fx = la.NewVector(ndim)
+	o.rhs = la.NewVector(ndim)
+	o.v = la.NewVector(ndim)
+	o.w = la.NewVector(ndim)
+	if o.W {
+		o.yold = la.NewVector(ndim)
+		o.fold = la.NewVector(ndim)
+	}
+
+	// dense output
+	if o.conf.needDense() {
+		o.do = make([]la.Vector, 4)
+		for i := 0; i < 4; i++ {
+			o.do[i] = la.NewVector(ndim)
+		}
+	}
+
+	// auxiliary
+	o.n = 1.0 / float64(o.Q+1)
+	o.dmin = 1.0 / o.conf.Mmin
+	o.dmax = 1.0 / o.conf.Mmax
+}
+
+// Accept accepts update and computes next stepsize
+func (o *Rosenbrock) Accept(y0 la.Vector, x0 float64) (dxnew float64) {
+
+	// store data for future dense output
+	if o.conf.needDense() {
+		for m := 0; m < o.ndim; m++ {
+			o.do[0][m] = y0[m]
+			o.do[1][m] = o.ynew[m] - y0[m]
+			o.do[2][m], o.do[3][m] = 0, 0
+			for j := 0; j < o.Nstg; j++ {
+				o.do[2][m] += o.D[0][j] * o.u[j][m]
+				o.do[3][m] += o.D[1][j] * o.u[j][m]
+			}
+		}
+	}
+
+	// update y
+	y0.Apply(1, o.ynew)
+
+	// estimate new stepsize
+	d := math.Pow(o.work.rerr, o.n)
+	d = utl.Max(o.dmax, utl.Min(o.dmin, d/o.conf.Mfac)) // we require  fac1 <= hnew/h <= fac2
+	dxnew = o.work.h / d
+	return
+}
+
+// Reject processes step rejection and computes next stepsize
+func (o *Rosenbrock) Reject() (dxnew float64) {
+	d := math.Pow(o.work.rerr, o.n) / o.conf.Mfac
+	dxnew = o.work.h / utl.Min(o.dmin, d)
+	return
+}
+
+// DenseOut produces dense output (after Accept)
+func (o *Rosenbrock) DenseOut(yout la.Vector, h, x float64, y la.Vector, xout float64) {
+	s := (xout - x + h) / h
+	for m := 0; m < o.ndim; m++ {
+		yout[m] = o.do[0][m] + s*(o.do[1][m]+(1-s)*(o.do[2][m]+s*o.do[3][m]))
+	}
+}
+
+// Step steps update
+func (o *Rosenbrock) Step(x0 float64, y0 la.Vector) {
+
+	// auxiliary
+	h := o.work.h
+
+	// f0 = f(x0,y0) is only computed by Solver with variable steps or numerical Jacobian
+	if o.conf.fixed && o.jac != nil {
+		o.stat.Nfeval++
+		o.fcn(o.work.f0, h, x0, y0)
+	}
+
+	// W-methods allow Solver to reuse the Jacobian and decomposition
+	if o.W {
+		o.work.theta = 0
+		if (o.work.reuseJacAndDecOnce || o.work.reuseJacOnce) && o.jacobianIsOld(x0, y0) {
+			o.work.reuseJacAndDecOnce = false
+			o.work.reuseJacOnce = false
+		}
+		o.xold = x0
+		o.yold.Apply(1, y0)
+		o.fold.Apply(1, o.work.f0)
+	} else {
+		o.work.theta = 1
+	}
+
+	// Jacobian and decomposition
+	if o.work.reuseJacAndDecOnce {
+		o.work.reuseJacAndDecOnce = false
+	} else {
+		if o.work.reuseJacOnce {
+			o.work.reuseJacOnce = false
+		} else {
+			o.stat.Njeval++
+			startTimeJacobian := time.Now()
+			if o.jac == nil { // numerical (w works here as workspace variable)
+				ffcn := func(fy, yy la.Vector) {
+					o.fcn(fy, h, x0, yy)
+				}
+				if o.conf.JacSparsity != nil {
+					num.JacobianColored(o.dfdy, ffcn, y0, o.work.f0, o.w, o.conf.JacSparsity)
+				} else {
+					num.Jacobian(o.dfdy, ffcn, y0, o.work.f0, o.w)
+				}
+			} else {
+				o.jac(o.dfdy, h, x0, y0)
+			}
+			o.stat.updateNanosecondsJeval(startTimeJacobian)
+		}
+
+		// matrix
+		if !o.ready {
+			o.mat.Init(o.ndim, o.ndim, o.mtri.Len()+o.dfdy.Len())
+		}
+		la.SpTriAdd(o.mat, 1.0/(h*o.Gam), o.mtri, -1, o.dfdy) // mat := M/(h⋅γ) - dfdy
+
+		// initialize linear solver
+		if !o.ready {
+			startTimeIniSol := time.Now()
+			o.ls.Init(o.mat, o.conf.LinSolConfig)
+			o.ready = true
+			o.stat.updateNanosecondsIniSol(startTimeIniSol)
+		}
+
+		// perform factorisation
+		startTimeFactorization := time.Now()
+		o.ls.Fact()
+		o.stat.Ndecomp++
+		o.stat.updateNanosecondsFact(startTimeFactorization)
+	}
+
+	// ∂f/∂x by forward differences
+	if !o.conf.Autonomous {
+		δ := math.Sqrt(o.conf.Eps * utl.Max(1e-5, math.Abs(x0)))
+		o.stat.Nfeval++
+		o.fcn(o.fx, h, x0+δ, y0)
+		la.VecAdd(o.fx, 1/δ, o.fx, -1/δ, o.work.f0)
+	}
+
+	// stages
+	for i := 0; i < o.Nstg; i++ {
+
+		// f(x + αᵢ⋅h, y + Σ aᵢⱼ⋅uⱼ)
+		if i == 0 {
+			o.rhs.Apply(1, o.work.f0)
+		} else {
+			o.v.Apply(1, y0)
+			for j := 0; j < i; j++ {
+				if o.A[i][j] != 0 {
+					la.VecAdd(o.v, 1, o.v, o.A[i][j], o.u[j])
+				}
+			}
+			o.stat.Nfeval++
+			o.fcn(o.rhs, h, x0+o.Alp[i]*h, o.v)
+
+			// M ⋅ Σ (cᵢⱼ/h)⋅uⱼ
+			o.v.Fill(0)
+			for j := 0; j < i; j++ {
+				la.VecAdd(o.v, 1, o.v, o.C[i][j]/h, o.u[j])
+			}
+			if o.hasM {
+				la.SpMatVecMulAdd(o.rhs, 1, o.mmat, o.v)
+			} else {
+				la.VecAdd(o.rhs, 1, o.rhs, 1, o.v)
+			}
+		}
+
+		// γᵢ⋅h⋅∂f/∂x
+		if !o.conf.Autonomous && o.Gsum[i] != 0 {
+			la.VecAdd(o.rhs, 1, o.rhs, o.Gsum[i]*h, o.fx)
+		}
+
+		// solve linear system
+		o.stat.Nlinsol++
+		startTimeSolver := time.Now()
+		o.ls.Solve(o.u[i], o.rhs)
+		o.stat.updateNanosecondsLinSol(startTimeSolver)
+	}
+
+	// update and error estimate
+	for m := 0; m < o.ndim; m++ {
+		o.ynew[m] = y0[m]
+		o.lerr[m] = 0
+		for i := 0; i < o.Nstg; i++ {
+			o.ynew[m] += o.M[i] * o.u[i][m]
+			o.lerr[m] += o.E[i] * o.u[i][m]
+		}
+	}
+
+	// filter the error estimate: lerr := (M/(h⋅γ) - J)⁻¹ ⋅ M ⋅ lerr/(h⋅γ). If R̂(∞) ≠ 0, the
+	// embedded solution keeps part of the inconsistency of y0 in the algebraic components of
+	// DAEs (and in the very stiff components of ODEs); hence, lerr would not vanish as h → 0.
+	// The filter damps these components with the existing factorisation [1, Section IV.8]
+	if o.Filter {
+		if o.hasM {
+			la.SpMatVecMul(o.rhs, 1/(h*o.Gam), o.mmat, o.lerr)
+		} else {
+			o.rhs.Apply(1/(h*o.Gam), o.lerr)
+		}
+		o.stat.Nlinsol++
+		startTimeSolver := time.Now()
+		o.ls.Solve(o.lerr, o.rhs)
+		o.stat.updateNanosecondsLinSol(startTimeSolver)
+	}
+
+	// error norm
+	var sk, ratio, sum float64
+	for m := 0; m < o.ndim; m++ {
+		sk = o.conf.atol + o.conf.rtol*utl.Max(math.Abs(y0[m]), math.Abs(o.ynew[m]))
+		ratio = o.lerr[m] / sk
+		sum += ratio * ratio
+	}
+	o.work.rerr = utl.Max(math.Sqrt(sum/float64(o.ndim)), 1e-10)
+}
+
+// jacobianIsOld checks whether the current Jacobian J fails to predict the change of f along the
+// previous step; i.e. whether ‖Δf - J⋅Δy - (∂f/∂x)⋅Δx‖ > ρ⋅‖Δf‖ with Δf = f(x,y) - f(xold,yold),
+// Δy = y - yold and Δx = x - xold. The step size controller of W-methods does not detect a poor
+// Jacobian before the step is rejected; thus, this test replaces the θ of Newton's iterations
+func (o *Rosenbrock) jacobianIsOld(x0 float64, y0 la.Vector) bool {
+	const ρ = 0.1 // max relative error of the linear prediction of Δf
+	la.VecAdd(o.v, 1, y0, -1, o.yold)
+	la.SpTriMatVecMul(o.w, o.dfdy, o.v)
+	la.VecAdd(o.v, 1, o.work.f0, -1, o.fold)
+	ndf := o.v.Norm()
+	la.VecAdd(o.w, 1, o.v, -1, o.w)
+	if !o.conf.Autonomous {
+		la.VecAdd(o.w, 1, o.w, o.xold-x0, o.fx)
+	}
+	return o.w.Norm() > ρ*ndf
+}
+
+// newRosenbrock returns the coefficients of a Rosenbrock method
+func newRosenbrock(kind string) rkmethod {
+	o := new(Rosenbrock)
+	switch kind {
+
+	case "ros3p": // Lang and Verwer 3(2)
+		γ := 0.5 + math.Sqrt(3)/6
+		o.setCoefficients(γ, [][]float64{
+			{0, 0, 0},
+			{1, 0, 0},
+			{1, 0, 0},
+		}, [][]float64{
+			{γ, 0, 0},
+			{-1, γ, 0},
+			{-γ, 0.5 - 2*γ, γ},
+		}, []float64{2.0 / 3.0, 0, 1.0 / 3.0}, []float64{1.0 / 3.0, 1.0 / 3.0, 1.0 / 3.0})
+		o.P, o.Q = 3, 2 // Q is only used by the (unreliable) estimator
+
+	case "rodas3": // Sandu et al. 3(2)
+		o.setCoefficients(0.5, [][]float64{
+			{0, 0, 0, 0},
+			{0, 0, 0, 0},
+			{1, 0, 0, 0},
+			{3.0 / 4.0, -1.0 / 4.0, 1.0 / 2.0, 0},
+		}, [][]float64{
+			{0.5, 0, 0, 0},
+			{1, 0.5, 0, 0},
+			{-1.0 / 4.0, -1.0 / 4.0, 0.5, 0},
+			{1.0 / 12.0, 1.0 / 12.0, -2.0 / 3.0, 0.5},
+		}, []float64{5.0 / 6.0, -1.0 / 6.0, -1.0 / 6.0, 1.0 / 2.0}, []float64{3.0 / 4.0, -1.0 / 4.0, 1.0 / 2.0, 0})
+		o.P, o.Q = 3, 2
+		o.Embedded = true
+
+	case "ros34pw2": // Rang and Angermann 3(2)
+		γ := 4.3586652150845900e-01
+		o.setCoefficients(γ, [][]float64{
+			{0, 0, 0, 0},
+			{8.7173304301691801e-01, 0, 0, 0},
+			{8.4457060015369423e-01, -1.1299064236484185e-01, 0, 0},
+			{0, 0, 1, 0},
+		}, [][]float64{
+			{γ, 0, 0, 0},
+			{-8.7173304301691801e-01, γ, 0, 0},
+			{-9.0338057013044082e-01, 5.4180672388095326e-02, γ, 0},
+			{2.4212380706095346e-01, -1.2232505839045147e+00, 5.4526025533510214e-01, γ},
+		}, []float64{2.4212380706095346e-01, -1.2232505839045147e+00, 1.5452602553351020e+00, 4.3586652150845900e-01},
+			[]float64{3.7810903145819369e-01, -9.6042292212423178e-02, 0.5, 2.1793326075422950e-01})
+		o.P, o.Q = 3, 2
+		o.W = true
+		o.Embedded = true
+		o.Filter = true // R̂(∞) = -0.478 whereas R(∞) = 0
+
+	case "rodas": // Hairer and Wanner 4(3) [1, page 452]; coefficients from RODAS (transformed)
+		o.Nstg, o.P, o.Q = 6, 4, 3
+		o.Gam = 0.25
+		o.Embedded = true
+		a51, a52, a53, a54 := 0.1221224509226641e+01, 0.6019134481288629e+01, 0.1253708332932087e+02, -0.6878860361058950e+00
+		o.A = [][]float64{
+			{0, 0, 0, 0, 0, 0},
+			{0.1544e+01, 0, 0, 0, 0, 0},
+			{0.9466785280815826e+00, 0.2557011698983284e+00, 0, 0, 0, 0},
+			{0.3314825187068521e+01, 0.2896124015972201e+01, 0.9986419139977817e+00, 0, 0, 0},
+			{a51, a52, a53, a54, 0, 0},
+			{a51, a52, a53, a54, 1, 0},
+		}
+		o.C = [][]float64{
+			{0, 0, 0, 0, 0, 0},
+			{-0.5668800000000000e+01, 0, 0, 0, 0, 0},
+			{-0.2430093356833875e+01, -0.2063599157091915e+00, 0, 0, 0, 0},
+			{-0.1073529058151375e+00, -0.9594562251023355e+01, -0.2047028614809616e+02, 0, 0, 0},
+			{0.7496443313967647e+01, -0.1024680431464352e+02, -0.3399990352819905e+02, 0.1170890893206160e+02, 0, 0},
+			{0.8083246795921522e+01, -0.7981132988064893e+01, -0.3152159432874371e+02, 0.1631930543123136e+02, -0.6058818238834054e+01, 0},
+		}
+		o.M = []float64{a51, a52, a53, a54, 1, 1}
+		o.E = []float64{0, 0, 0, 0, 0, 1}
+		o.Alp = []float64{0, 0.386, 0.21, 0.63, 1, 1}
+		o.Gsum = []float64{0.25, -0.1043, 0.1035, -0.3620000000000023e-01, 0, 0}
+		o.D = [][]float64{
+			{0.1012623508344586e+02, -0.7487995877610167e+01, -0.3480091861555747e+02, -0.7992771707568823e+01, 0.1025137723295662e+01, 0},
+			{-0.6762803392801253e+00, 0.6087714651680015e+01, 0.1643084320892478e+02, 0.2476722511418386e+02, -0.6594389125716872e+01, 0},
+		}
+
+	default:
+		chk.Panic("cannot find Rosenbrock method named %q\n", kind)
+	}
+	return o
+}
+
+// setCoefficients computes the transformed coefficients from the original ones [1, page 111]
+//
+//	γ  -- diagonal coefficient
+//	α  -- [nstg][nstg] strictly lower triangular coefficients
+//	Γ  -- [nstg][nstg] lower triangular coefficients with Γᵢᵢ = γ
+//	b  -- [nstg] weights of y1
+//	bh -- [nstg] weights of the embedded solution
+//
+//	NOTE: the dense output is the continuous extension of order 2 (with the exact Jacobian)
+//	      y(x+θh) = y + θ⋅(y1 - y) + (θ² - θ)/(2β'ₗ) ⋅ (kₗ - k₁)
+//	      where β'ᵢ = Σⱼ (αᵢⱼ + Γᵢⱼ), j < i, and l is the stage with the largest |β'ᵢ|
+func (o *Rosenbrock) setCoefficients(γ float64, α, Γ [][]float64, b, bh []float64) {
+
+	// inverse of Γ (lower triangular)
+	s := len(b)
+	Ginv := utl.Alloc(s, s)
+	for j := 0; j < s; j++ {
+		Ginv[j][j] = 1.0 / Γ[j][j]
+		for i := j + 1; i < s; i++ {
+			sum := 0.0
+			for k := j; k < i; k++ {
+				sum += Γ[i][k] * Ginv[k][j]
+			}
+			Ginv[i][j] = -sum / Γ[i][i]
+		}
+	}
+
+	// transformed coefficients
+	o.Nstg, o.Gam = s, γ
+	o.A = utl.Alloc(s, s)
+	o.C = utl.Alloc(s, s)
+	o.M = make([]float64, s)
+	o.E = make([]float64, s)
+	o.Alp = make([]float64, s)
+	o.Gsum = make([]float64, s)
+	βp := make([]float64, s)
+	for i := 0; i < s; i++ {
+		for j := 0; j < s; j++ {
+			for k := 0; k < s; k++ {
+				o.A[i][j] += α[i][k] * Ginv[k][j]
+			}
+			if j < i {
+				o.C[i][j] = -Ginv[i][j]
+				βp[i] += α[i][j] + Γ[i][j]
+			}
+			o.M[i] += b[j] * Ginv[j][i]
+			o.E[i] += (b[j] - bh[j]) * Ginv[j][i]
+			o.Alp[i] += α[i][j]
+			if j <= i {
+				o.Gsum[i] += Γ[i][j]
+			}
+		}
+	}
+
+	// dense output: s⋅(y1 - y) + s⋅(1-s)⋅D[0]⋅u
+	l := 0
+	for i := 1; i < s; i++ {
+		if math.Abs(βp[i]) > math.Abs(βp[l]) {
+			l = i
+		}
+	}
+	o.D = utl.Alloc(2, s)
+	for j := 0; j < s; j++ {
+		o.D[0][j] = -(Ginv[l][j] - Ginv[0][j]) / (2.0 * βp[l])
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+)
+
+func TestBdf01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Bdf01. Robertson's equation: BDF and NDF versus Radau5")
+
+	// reference solution
+	xf := 40.0
+	p := ProbRobertson()
+	conf := NewConfig("radau5", "")
+	conf.SetTols(1e-12, 1e-10)
+	conf.IniH = 1e-8
+	sol := NewSolver(p.Ndim, conf, p.Fcn, p.Jac, nil)
+	yref := p.Y.GetCopy()
+	sol.Solve(yref, 0, xf)
+	sol.Free()
+	io.Pforan("radau5: y = %v\n", yref)
+
+	// BDF and NDF with analytical and numerical Jacobians
+	for _, method := range []string{"bdf", "ndf"} {
+		for _, numJac := range []bool{false, true} {
+			p = ProbRobertson()
+			conf = NewConfig(method, "")
+			conf.SetTols(1e-10, 1e-7)
+			conf.IniH = 1e-8
+			jac := p.Jac
+			if numJac {
+				jac = nil
+			}
+			sol = NewSolver(p.Ndim, conf, p.Fcn, jac, nil)
+			sol.Solve(p.Y, 0, xf)
+			io.Pforan("%s numJac=%v: nfeval=%d njeval=%d naccepted=%d nrejected=%d ndecomp=%d\n", method, numJac,
+				sol.Stat.Nfeval, sol.Stat.Njeval, sol.Stat.Naccepted, sol.Stat.Nrejected, sol.Stat.Ndecomp)
+			chk.Float64(tst, "y0", 1e-5, p.Y[0], yref[0])
+			chk.Float64(tst, "y1", 1e-10, p.Y[1], yref[1])
+			chk.Float64(tst, "y2", 1e-5, p.Y[2], yref[2])
+			chk.Float64(tst, "conservation", 1e-12, p.Y[0]+p.Y[1]+p.Y[2], 1)
+			chk.Bool(tst, "Jacobian is reused", sol.Stat.Njeval < sol.Stat.Naccepted/4, true)
+			sol.Free()
+		}
+	}
+}
+
+func TestBdf02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Bdf02. heat equation (method of lines)")
+
+	// y' = (y[i-1] - 2 y[i] + y[i+1]) / Δx² with y = 0 at the boundaries
+	n := 99
+	dx := 1.0 / float64(n+1)
+	fcn := func(f la.Vector, h, x float64, y la.Vector) {
+		for i := 0; i < n; i++ {
+			f[i] = -2 * y[i]
+			if i > 0 {
+				f[i] += y[i-1]
+			}
+			if i < n-1 {
+				f[i] += y[i+1]
+			}
+			f[i] /= dx * dx
+		}
+	}
+	jac := func(dfdy *la.Triplet, h, x float64, y la.Vector) {
+		if dfdy.Max() == 0 {
+			dfdy.Init(n, n, 3*n)
+		}
+		dfdy.Start()
+		for i := 0; i < n; i++ {
+			dfdy.Put(i, i, -2/(dx*dx))
+			if i > 0 {
+				dfdy.Put(i, i-1, 1/(dx*dx))
+			}
+			if i < n-1 {
+				dfdy.Put(i, i+1, 1/(dx*dx))
+			}
+		}
+	}
+
+	// exact solution of the semi-discrete system: y[i] = exp(λ x) sin(π z[i])
+	λ := -4 * math.Pow(math.Sin(math.Pi*dx/2), 2) / (dx * dx)
+	yana := func(res la.Vector, x float64) {
+		for i := 0; i < n; i++ {
+			res[i] = math.Exp(λ*x) * math.Sin(math.Pi*float64(i+1)*dx)
+		}
+	}
+
+	// solve
+	xf := 0.1
+	y0 := la.NewVector(n)
+	yana(y0, 0)
+	ye := la.NewVector(n)
+	for _, method := range []string{"bdf", "ndf", "radau5"} {
+		conf := NewConfig(method, "")
+		conf.SetTols(1e-8, 1e-6)
+		conf.SetDenseOut(true, 0.01, xf, nil)
+		sol := NewSolver(n, conf, fcn, jac, nil)
+		y := y0.GetCopy()
+		sol.Solve(y, 0, xf)
+		io.Pforan("%6s: nfeval=%4d naccepted=%3d nrejected=%2d ndecomp=%3d nlinsol=%4d\n", method,
+			sol.Stat.Nfeval, sol.Stat.Naccepted, sol.Stat.Nrejected, sol.Stat.Ndecomp, sol.Stat.Nlinsol)
+		yana(ye, xf)
+		chk.Array(tst, method+": y(xf)", 1e-5, y, ye)
+		for k, x := range sol.Out.GetDenseX() {
+			yana(ye, x)
+			chk.Array(tst, io.Sf("%s: y(%g)", method, x), 1e-5, sol.Out.DenseY[k], ye)
+		}
+		if bdf, ok := sol.rkm.(*BDF); ok {
+			chk.Bool(tst, method+": order > 2 has been used", bdf.klast > 2, true)
+		}
+		sol.Free()
+	}
+}
+
+func TestBdf03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Bdf03. transistor amplifier (mass matrix)")
+
+	// reference solution
+	p := ProbHwAmplifier()
+	conf := NewConfig("radau5", "")
+	conf.IniH = 1e-6
+	conf.SetTols(1e-11, 1e-8)
+	sol := NewSolver(p.Ndim, conf, p.Fcn, p.Jac, p.M)
+	yref := p.Y.GetCopy()
+	sol.Solve(yref, 0, p.Xf)
+	sol.Free()
+
+	// NDF
+	conf = NewConfig("ndf", "")
+	conf.IniH = 1e-6
+	conf.NmaxSS = 100000
+	conf.SetTols(1e-11, 1e-6)
+	sol = NewSolver(p.Ndim, conf, p.Fcn, p.Jac, p.M)
+	defer sol.Free()
+	sol.Solve(p.Y, 0, p.Xf)
+	io.Pforan("ndf: nfeval=%d naccepted=%d nrejected=%d ndecomp=%d\n", sol.Stat.Nfeval, sol.Stat.Naccepted, sol.Stat.Nrejected, sol.Stat.Ndecomp)
+	io.Pforan("radau5: %v\n", yref)
+	io.Pforan("ndf:    %v\n", p.Y)
+	chk.Array(tst, "y(xf)", 1e-5, p.Y, yref)
+}
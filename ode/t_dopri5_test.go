@@ -187,3 +187,33 @@ func TestDoPri503(tst *testing.T) {
 	chk.Array(tst, "dense: y0", 1e-7, yy0, dd["y0"])
 	chk.Array(tst, "dense: y1", 1e-7, yy1, dd["y1"])
 }
+
+func TestDoPri504(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("DoPri504. Dormand-Prince5. Pendulum and Duffing (elliptic functions)")
+
+	for _, p := range []*Problem{ProbPendulum(2.5), ProbDuffing(1.5, 2.0)} {
+
+		// analytical solution at x = 0 and after one period
+		y0 := la.NewVector(p.Ndim)
+		p.Yana(y0, 0)
+		chk.Array(tst, "Yana(0)", 1e-15, y0, p.Y)
+		p.Yana(y0, p.Xf)
+		chk.Array(tst, "Yana(T)", 1e-13, y0, p.Y)
+
+		// numerical solution
+		conf := NewConfig("dopri5", "")
+		conf.SetTols(1e-10, 1e-10)
+		sol := NewSolver(p.Ndim, conf, p.Fcn, p.Jac, nil)
+		y := p.Y.GetCopy()
+		xm := 0.3 * p.Xf
+		sol.Solve(y, 0.0, xm)
+		chk.Float64(tst, "y0(0.3T)", 1e-8, y[0], p.CalcYana(0, xm))
+		chk.Float64(tst, "y1(0.3T)", 1e-8, y[1], p.CalcYana(1, xm))
+		sol.Solve(y, xm, p.Xf)
+		chk.Float64(tst, "y0(T)", 1e-8, y[0], p.CalcYana(0, p.Xf))
+		chk.Float64(tst, "y1(T)", 1e-8, y[1], p.CalcYana(1, p.Xf))
+		sol.Free()
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/utl"
+)
+
+func TestEvents01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Events01. falling body: terminal event at impact")
+
+	// y = [height, velocity]
+	grav, h0 := 9.81, 10.0
+	fcn := func(f la.Vector, h, x float64, y la.Vector) {
+		f[0] = y[1]
+		f[1] = -grav
+	}
+	jac := func(dfdy *la.Triplet, h, x float64, y la.Vector) {
+		if dfdy.Max() == 0 {
+			dfdy.Init(2, 2, 1)
+		}
+		dfdy.Start()
+		dfdy.Put(0, 1, 1)
+	}
+	ground := func(x float64, y la.Vector) float64 {
+		return y[0]
+	}
+	tImpact := math.Sqrt(2 * h0 / grav)
+
+	for _, method := range []string{"dopri5", "dopri8", "radau5", "ndf", "fweuler", "bweuler"} {
+		conf := NewConfig(method, "")
+		conf.SetStepOut(true, nil)
+		conf.AddEvent(ground, -1, true)
+		tol := 1e-9
+		switch method {
+		case "ndf": // low order at the beginning
+			tol = 1e-3
+		case "fweuler", "bweuler":
+			conf.SetFixedH(0.01, 5)
+			tol = 0.1
+		}
+		sol := NewSolver(2, conf, fcn, jac, nil)
+		y := la.Vector{h0, 0}
+		sol.Solve(y, 0, 5)
+		io.Pforan("%8s: events = %v  nsteps = %d\n", method, sol.Out.EventX, sol.Stat.Nsteps)
+		chk.Bool(tst, "EventStop", sol.Out.EventStop, true)
+		chk.Int(tst, "number of events", len(sol.Out.EventX), 1)
+		chk.Ints(tst, "EventIdx", sol.Out.EventIdx, []int{0})
+		chk.Float64(tst, method+": x(impact)", tol, sol.Out.EventX[0], tImpact)
+		chk.Float64(tst, method+": y(impact)", 1e-10, sol.Out.EventY[0][0], 0)
+		chk.Float64(tst, method+": v(impact)", tol*grav, sol.Out.EventY[0][1], -grav*tImpact)
+		chk.Array(tst, method+": final y", 1e-17, y, sol.Out.EventY[0])
+		X := sol.Out.GetStepX()
+		chk.Float64(tst, method+": last step x", 1e-17, X[len(X)-1], sol.Out.EventX[0])
+		sol.Free()
+	}
+}
+
+func TestEvents02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Events02. oscillator: direction filters and non-terminal events")
+
+	// y = [sin(x), cos(x)]
+	fcn := func(f la.Vector, h, x float64, y la.Vector) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+	g := func(x float64, y la.Vector) float64 {
+		return y[0]
+	}
+	half := func(x float64, y la.Vector) float64 {
+		return y[1] - 0.5
+	}
+
+	for _, method := range []string{"dopri5", "radau5"} {
+		conf := NewConfig(method, "")
+		conf.SetTol(1e-8)
+		conf.AddEvent(g, 0, false)
+		conf.AddEvent(g, +1, false)
+		conf.AddEvent(g, -1, false)
+		conf.AddEvent(half, +1, false)
+		sol := NewSolver(2, conf, fcn, nil, nil)
+		y := la.Vector{0, 1}
+		sol.Solve(y, 0, 10)
+		io.Pforan("%s: idx = %v\n", method, sol.Out.EventIdx)
+		io.Pforan("%s: x   = %v\n", method, sol.Out.EventX)
+
+		// g = 0 at x = 0 is not an event
+		π := math.Pi
+		chk.Bool(tst, "EventStop", sol.Out.EventStop, false)
+		chk.Ints(tst, "EventIdx", sol.Out.EventIdx, []int{0, 2, 3, 0, 1, 0, 2})
+		chk.Array(tst, "EventX", 1e-6, sol.Out.EventX, []float64{π, π, 5 * π / 3, 2 * π, 2 * π, 3 * π, 3 * π})
+		for i, ye := range sol.Out.EventY {
+			x := sol.Out.EventX[i]
+			chk.Array(tst, io.Sf("y @ %.6f", x), 1e-6, ye, []float64{math.Sin(x), math.Cos(x)})
+		}
+		chk.Array(tst, "y(10)", 1e-6, y, []float64{math.Sin(10), math.Cos(10)})
+		sol.Free()
+	}
+}
+
+func TestEvents03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Events03. bouncing ball: restart from terminal events")
+
+	// y = [height, velocity]
+	grav, h0, e := 9.81, 1.0, 0.8
+	fcn := func(f la.Vector, h, x float64, y la.Vector) {
+		f[0] = y[1]
+		f[1] = -grav
+	}
+	conf := NewConfig("dopri5", "")
+	conf.AddEvent(func(x float64, y la.Vector) float64 { return y[0] }, -1, true)
+	sol := NewSolver(2, conf, fcn, nil, nil)
+	defer sol.Free()
+
+	// analytical times of impact
+	nbounces := 5
+	tref := make([]float64, nbounces)
+	tref[0] = math.Sqrt(2 * h0 / grav)
+	v := grav * tref[0]
+	for k := 1; k < nbounces; k++ {
+		v *= e
+		tref[k] = tref[k-1] + 2*v/grav
+	}
+
+	// restart after each bounce
+	x, xf := 0.0, 10.0
+	y := la.Vector{h0, 0}
+	times := make([]float64, nbounces)
+	for k := 0; k < nbounces; k++ {
+		sol.Solve(y, x, xf)
+		if !sol.Out.EventStop {
+			tst.Errorf("bounce %d did not happen\n", k)
+			return
+		}
+		x = sol.Out.EventX[len(sol.Out.EventX)-1]
+		times[k] = x
+		y[1] = -e * y[1]
+	}
+	io.Pforan("times = %v\n", times)
+	chk.Int(tst, "number of events", len(sol.Out.EventX), nbounces)
+	chk.Array(tst, "times of impact", 1e-9, times, tref)
+}
+
+func TestEvents04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Events04. dense output before a terminal event")
+
+	// y = [height, velocity]
+	grav, h0 := 9.81, 10.0
+	fcn := func(f la.Vector, h, x float64, y la.Vector) {
+		f[0] = y[1]
+		f[1] = -grav
+	}
+	ground := func(x float64, y la.Vector) float64 {
+		return y[0]
+	}
+	tImpact := math.Sqrt(2 * h0 / grav)
+
+	// dense output at 0, 0.1, ..., 1.4 and at the impact
+	xref := utl.LinSpace(0, 1.4, 15)
+	xref = append(xref, tImpact)
+	for _, method := range []string{"dopri5", "dopri8", "radau5", "rodas", "ndf"} {
+		conf := NewConfig(method, "")
+		conf.SetTol(1e-10)
+		var xdense []float64
+		conf.SetDenseOut(true, 0.1, 5, func(istep int, h, x float64, y la.Vector, xout float64, yout la.Vector) (stop bool) {
+			xdense = append(xdense, xout)
+			return
+		})
+		conf.AddEvent(ground, -1, true)
+		sol := NewSolver(2, conf, fcn, nil, nil)
+		y := la.Vector{h0, 0}
+		sol.Solve(y, 0, 5)
+		X := sol.Out.GetDenseX()
+		io.Pforan("%8s: X = %.3f\n", method, X)
+		chk.Bool(tst, "EventStop", sol.Out.EventStop, true)
+		chk.Array(tst, method+": dense X", 1e-9, X, xref)
+		chk.Array(tst, method+": dense function X", 1e-9, xdense, xref)
+		Y := sol.Out.GetDenseY(0)
+		for i, x := range X {
+			chk.Float64(tst, io.Sf("%s: y(%.3f)", method, x), 1e-7, Y[i], h0-grav*x*x/2)
+		}
+		sol.Free()
+	}
+
+	// explicit Runge-Kutta methods without dense output
+	defer func() {
+		if err := recover(); err != nil {
+			if chk.Verbose {
+				io.Pf("OK, caught the following message:\n\n\t%v\n", err)
+			}
+		} else {
+			tst.Errorf("\n\tTEST FAILED. NewSolver with events and rk4 should have panicked\n")
+		}
+	}()
+	conf := NewConfig("rk4", "")
+	conf.AddEvent(ground, -1, true)
+	NewSolver(2, conf, fcn, nil, nil)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/lei006/gomath/chk"
 	"github.com/lei006/gomath/io"
 	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/num"
 )
 
 func TestRadau501a(tst *testing.T) {
@@ -126,3 +127,56 @@ func readRefData(fn string) (o *refData) {
 	}
 	return
 }
+
+func TestRadau503(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Radau503. numerical Jacobian with column colouring")
+
+	// reaction-diffusion: y' = (y[i-1] - 2 y[i] + y[i+1]) / Δx² - y[i]²
+	n := 30
+	dx := 1.0 / float64(n+1)
+	nfcn := 0
+	fcn := func(f la.Vector, h, x float64, y la.Vector) {
+		nfcn++
+		for i := 0; i < n; i++ {
+			yl, yr := 0.0, 1.0 // boundary conditions
+			if i > 0 {
+				yl = y[i-1]
+			}
+			if i < n-1 {
+				yr = y[i+1]
+			}
+			f[i] = (yl-2*y[i]+yr)/(dx*dx) - y[i]*y[i]
+		}
+	}
+	var rows, cols []int
+	for i := 0; i < n; i++ {
+		for j := i - 1; j <= i+1; j++ {
+			if j >= 0 && j < n {
+				rows = append(rows, i)
+				cols = append(cols, j)
+			}
+		}
+	}
+
+	// solve with and without sparsity pattern
+	var ys [2]la.Vector
+	var nfcns, njevals [2]int
+	for k, colour := range []bool{false, true} {
+		conf := NewConfig("radau5", "")
+		if colour {
+			conf.JacSparsity = num.NewJacSparsity(n, n, rows, cols)
+		}
+		sol := NewSolver(n, conf, fcn, nil, nil)
+		nfcn = 0
+		ys[k] = la.NewVector(n)
+		sol.Solve(ys[k], 0, 0.5)
+		nfcns[k], njevals[k] = nfcn, sol.Stat.Njeval
+		io.Pforan("colour=%v: nfcn = %d  njeval = %d  nsteps = %d\n", colour, nfcn, sol.Stat.Njeval, sol.Stat.Nsteps)
+		sol.Free()
+	}
+	chk.Array(tst, "y: colour == reference", 1e-15, ys[1], ys[0])
+	chk.Int(tst, "njeval", njevals[1], njevals[0])
+	chk.Int(tst, "nfcn: n - 3 evaluations saved per Jacobian", nfcns[0]-nfcns[1], njevals[0]*(n-3))
+}
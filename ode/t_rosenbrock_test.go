@@ -0,0 +1,253 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lei006/gomath/chk"
+	"github.com/lei006/gomath/io"
+	"github.com/lei006/gomath/la"
+)
+
+var rosenbrockMethods = []string{"ros3p", "rodas3", "ros34pw2", "rodas"}
+
+// rosenbrockAdaptive holds the methods with embedded error estimators (variable steps)
+var rosenbrockAdaptive = []string{"rodas3", "ros34pw2", "rodas"}
+
+func TestRosenbrock01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Rosenbrock01. convergence order with fixed steps (non-autonomous)")
+
+	// y' = -y + x² ⋅ exp(-x) + sin(y) - sin(ya(x)) with ya = exp(-x) ⋅ (1 + x³/3)
+	ya := func(x float64) float64 { return math.Exp(-x) * (1 + x*x*x/3) }
+	fcn := func(f la.Vector, h, x float64, y la.Vector) {
+		f[0] = -y[0] + x*x*math.Exp(-x) + math.Sin(y[0]) - math.Sin(ya(x))
+	}
+	jac := func(dfdy *la.Triplet, h, x float64, y la.Vector) {
+		if dfdy.Max() == 0 {
+			dfdy.Init(1, 1, 1)
+		}
+		dfdy.Start()
+		dfdy.Put(0, 0, -1+math.Cos(y[0]))
+	}
+
+	xf := 2.0
+	for _, method := range rosenbrockMethods {
+		var errs []float64
+		for _, h := range []float64{0.04, 0.02, 0.01} {
+			conf := NewConfig(method, "")
+			conf.SetFixedH(h, xf)
+			sol := NewSolver(1, conf, fcn, jac, nil)
+			y := la.Vector{1}
+			sol.Solve(y, 0, xf)
+			errs = append(errs, math.Abs(y[0]-ya(xf)))
+			sol.Free()
+		}
+		sol := NewSolver(1, NewConfig(method, ""), fcn, jac, nil)
+		p := sol.rkm.(*Rosenbrock).P
+		sol.Free()
+		order := math.Log2(errs[1] / errs[2])
+		io.Pforan("%8s: errors = %v  order = %.3f\n", method, errs, order)
+		chk.Bool(tst, method+": order", order > float64(p)-0.15, true)
+	}
+}
+
+func TestRosenbrock02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Rosenbrock02. Van der Pol and Robertson versus Radau5")
+
+	// reference solutions
+	probs := []func() *Problem{
+		func() *Problem { return ProbVanDerPol(0, false) },
+		ProbRobertson,
+	}
+	for k, prob := range probs {
+		p := prob()
+		conf := NewConfig("radau5", "")
+		conf.SetTols(1e-10, 1e-10)
+		conf.IniH = 1e-6
+		conf.NmaxSS = 100000
+		sol := NewSolver(p.Ndim, conf, p.Fcn, p.Jac, nil)
+		yref := p.Y.GetCopy()
+		sol.Solve(yref, 0, p.Xf)
+		sol.Free()
+
+		// Rosenbrock methods
+		for _, method := range rosenbrockAdaptive {
+			p = prob()
+			conf = NewConfig(method, "")
+			conf.SetTols(1e-8, 1e-6)
+			conf.IniH = 1e-6
+			conf.NmaxSS = 100000
+			conf.Autonomous = true
+			sol = NewSolver(p.Ndim, conf, p.Fcn, p.Jac, nil)
+			sol.Solve(p.Y, 0, p.Xf)
+			io.Pforan("%d %8s: nfeval=%4d njeval=%4d naccepted=%4d nrejected=%3d ndecomp=%4d\n", k, method,
+				sol.Stat.Nfeval, sol.Stat.Njeval, sol.Stat.Naccepted, sol.Stat.Nrejected, sol.Stat.Ndecomp)
+			chk.Array(tst, method+": y(xf)", 1e-4, p.Y, yref)
+			chk.Int(tst, method+": Nfeval", sol.Stat.Nfeval, sol.Stat.Nsteps*(sol.rkm.(*Rosenbrock).Nstg-1)+sol.Stat.Naccepted)
+			if method != "ros34pw2" {
+				chk.Int(tst, method+": one decomposition per step", sol.Stat.Ndecomp, sol.Stat.Nsteps)
+			}
+			sol.Free()
+		}
+	}
+}
+
+func TestRosenbrock03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Rosenbrock03. dense output and mass matrix")
+
+	// dense output of Hairer-Wanner Eq (1.1)
+	p := ProbHwEq11()
+	for _, method := range rosenbrockAdaptive {
+		conf := NewConfig(method, "")
+		conf.SetTols(1e-8, 1e-8)
+		conf.NmaxSS = 100000
+		conf.SetDenseOut(true, 0.1, p.Xf, nil)
+		sol := NewSolver(p.Ndim, conf, p.Fcn, p.Jac, nil)
+		y := la.Vector{0}
+		sol.Solve(y, 0, p.Xf)
+		io.Pforan("%8s: naccepted = %d\n", method, sol.Stat.Naccepted)
+		X := sol.Out.GetDenseX()
+		Y := sol.Out.GetDenseY(0)
+		for i, x := range X {
+			chk.Float64(tst, io.Sf("%s: y(%g)", method, x), 1e-5, Y[i], p.CalcYana(0, x))
+		}
+		sol.Free()
+	}
+
+	// transistor amplifier
+	p = ProbHwAmplifier()
+	conf := NewConfig("radau5", "")
+	conf.IniH = 1e-6
+	conf.SetTols(1e-11, 1e-8)
+	sol := NewSolver(p.Ndim, conf, p.Fcn, p.Jac, p.M)
+	yref := p.Y.GetCopy()
+	sol.Solve(yref, 0, p.Xf)
+	sol.Free()
+	for _, method := range rosenbrockAdaptive {
+		p = ProbHwAmplifier()
+		conf = NewConfig(method, "")
+		conf.IniH = 1e-6
+		conf.NmaxSS = 100000
+		conf.SetTols(1e-11, 1e-6)
+		sol = NewSolver(p.Ndim, conf, p.Fcn, p.Jac, p.M)
+		sol.Solve(p.Y, 0, p.Xf)
+		io.Pforan("%8s: nfeval=%d naccepted=%d nrejected=%d\n", method, sol.Stat.Nfeval, sol.Stat.Naccepted, sol.Stat.Nrejected)
+		chk.Array(tst, method+": y(xf)", 1e-5, p.Y, yref)
+		sol.Free()
+	}
+}
+
+func TestRosenbrock04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Rosenbrock04. W-method: reuse of the Jacobian on a stiff problem")
+
+	// reference solution
+	p := ProbVanDerPol(0, false)
+	conf := NewConfig("radau5", "")
+	conf.SetTols(1e-10, 1e-10)
+	conf.IniH = 1e-6
+	conf.NmaxSS = 100000
+	sol := NewSolver(p.Ndim, conf, p.Fcn, p.Jac, nil)
+	yref := p.Y.GetCopy()
+	sol.Solve(yref, 0, p.Xf)
+	sol.Free()
+
+	// analytical and numerical Jacobians
+	for _, numJac := range []bool{false, true} {
+		p = ProbVanDerPol(0, false)
+		jac := p.Jac
+		if numJac {
+			jac = nil
+		}
+		conf = NewConfig("ros34pw2", "")
+		conf.SetTols(1e-8, 1e-6)
+		conf.IniH = 1e-6
+		conf.NmaxSS = 100000
+		conf.Autonomous = true
+		sol = NewSolver(p.Ndim, conf, p.Fcn, jac, nil)
+		sol.Solve(p.Y, 0, p.Xf)
+		io.Pforan("numJac=%v: njeval=%4d naccepted=%4d nrejected=%3d ndecomp=%4d\n", numJac,
+			sol.Stat.Njeval, sol.Stat.Naccepted, sol.Stat.Nrejected, sol.Stat.Ndecomp)
+		chk.Array(tst, "y(xf)", 1e-4, p.Y, yref)
+		chk.Bool(tst, "nrejected ≤ 2% of naccepted", 50*sol.Stat.Nrejected <= sol.Stat.Naccepted, true)
+		chk.Bool(tst, "njeval ≤ 40% of nsteps", 5*sol.Stat.Njeval <= 2*sol.Stat.Nsteps, true)
+		sol.Free()
+	}
+}
+
+func TestRosenbrock05(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Rosenbrock05. linear problems with variable steps")
+
+	// y'' = -y ⇒ y = [sin(x), cos(x)] and y' = -y ⇒ y = exp(-x)
+	oscillator := func(f la.Vector, h, x float64, y la.Vector) {
+		f[0] = y[1]
+		f[1] = -y[0]
+	}
+	decay := func(f la.Vector, h, x float64, y la.Vector) {
+		f[0] = -y[0]
+	}
+	xf := 10.0
+	for _, method := range rosenbrockAdaptive {
+		for _, tol := range []float64{1e-4, 1e-8} {
+			conf := NewConfig(method, "")
+			conf.SetTol(tol)
+			conf.NmaxSS = 100000
+			conf.Autonomous = true
+			sol := NewSolver(2, conf, oscillator, nil, nil)
+			y := la.Vector{0, 1}
+			sol.Solve(y, 0, xf)
+			err := y.NormDiff([]float64{math.Sin(xf), math.Cos(xf)})
+			io.Pforan("%8s: tol = %.0e  oscillator: naccepted = %4d  error = %.2e", method, tol, sol.Stat.Naccepted, err)
+			chk.Bool(tst, io.Sf("%s: oscillator error(tol=%g)", method, tol), err < 100*tol, true)
+			sol.Free()
+
+			conf = NewConfig(method, "")
+			conf.SetTol(tol)
+			conf.NmaxSS = 100000
+			conf.Autonomous = true
+			sol = NewSolver(1, conf, decay, nil, nil)
+			y = la.Vector{1}
+			sol.Solve(y, 0, xf)
+			err = math.Abs(y[0] - math.Exp(-xf))
+			io.Pforan("  decay: naccepted = %4d  error = %.2e\n", sol.Stat.Naccepted, err)
+			chk.Bool(tst, io.Sf("%s: decay error(tol=%g)", method, tol), err < 10*tol, true)
+			sol.Free()
+		}
+	}
+
+	// ros3p with fixed steps
+	conf := NewConfig("ros3p", "")
+	conf.SetFixedH(0.01, xf)
+	sol := NewSolver(2, conf, oscillator, nil, nil)
+	y := la.Vector{0, 1}
+	sol.Solve(y, 0, xf)
+	sol.Free()
+	chk.Array(tst, "ros3p: fixed steps", 1e-6, y, []float64{math.Sin(xf), math.Cos(xf)})
+
+	// ros3p cannot be used with variable steps
+	defer func() {
+		if err := recover(); err != nil {
+			if chk.Verbose {
+				io.Pf("OK, caught the following message:\n\n\t%v\n", err)
+			}
+		} else {
+			tst.Errorf("\n\tTEST FAILED. ros3p with variable steps should have panicked\n")
+		}
+	}()
+	conf = NewConfig("ros3p", "")
+	sol = NewSolver(2, conf, oscillator, nil, nil)
+	sol.Solve(la.Vector{0, 1}, 0, xf)
+}
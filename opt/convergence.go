@@ -5,6 +5,7 @@ import (
 
 	"github.com/lei006/gomath/fun"
 	"github.com/lei006/gomath/la"
+	"github.com/lei006/gomath/num"
 	"github.com/lei006/gomath/utl"
 )
 
@@ -36,11 +37,16 @@ type Convergence struct {
 }
 
 // InitConvergence initialize convergence parameters
+//
+//	NOTE: if Gfcn is nil, the gradient is computed numerically by num.Gradient
 func (o *Convergence) InitConvergence(Ffcn fun.Sv, Gfcn fun.Vv) {
 	o.Ffcn = func(x la.Vector) float64 {
 		o.NumFeval++
 		return Ffcn(x)
 	}
+	if Gfcn == nil { // numerical gradient
+		Gfcn = num.GradientFunc(o.Ffcn)
+	}
 	o.Gfcn = func(g, x la.Vector) {
 		o.NumGeval++
 		Gfcn(g, x)
@@ -105,3 +105,20 @@ func TestConjGrad04(tst *testing.T) {
 	// run test
 	runConjGradTest(tst, "conjgrad04", p, x0, 3.68e-13, 1.06e-6)
 }
+
+func TestConjGrad05(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ConjGrad05. numerical gradient (Gfcn == nil)")
+
+	// problem without gradient
+	p := Factory.SimpleQuadratic3d()
+	p.Gfcn = nil
+	p.Hfcn = nil
+
+	// initial point
+	x0 := la.NewVectorSlice([]float64{1, 2, 3})
+
+	// run test
+	runConjGradTest(tst, "conjgrad05", p, x0, 1e-15, 1e-8)
+}